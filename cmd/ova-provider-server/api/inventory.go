@@ -4,7 +4,9 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/cmd/ova-provider-server/hyperv"
 	"github.com/kubev2v/forklift/cmd/ova-provider-server/inventory"
+	"github.com/kubev2v/forklift/cmd/ova-provider-server/settings"
 )
 
 const (
@@ -28,12 +30,21 @@ func (h InventoryHandler) AddRoutes(e *gin.Engine) {
 
 // VMs godoc
 // @summary List all VMs structs that can be extracted from all OVAs/OVFs in the catalog.
-// @description List all VMs structs that can be extracted from all OVAs/OVFs in the catalog.
+// @description List all VMs structs that can be extracted from all OVAs/OVFs (or Hyper-V exports) in the catalog.
 // @tags inventory
 // @produce json
 // @success 200 {array} ova.VM
 // @router /vms [get]
 func (h InventoryHandler) VMs(ctx *gin.Context) {
+	if Settings.CatalogType == settings.CatalogHyperV {
+		vms, err := hyperv.Scan(Settings.CatalogPath)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, vms)
+		return
+	}
 	envelopes, paths := inventory.ScanForAppliances(Settings.CatalogPath)
 	vms := inventory.ConvertToVmStruct(envelopes, paths)
 	ctx.JSON(http.StatusOK, vms)
@@ -47,6 +58,15 @@ func (h InventoryHandler) VMs(ctx *gin.Context) {
 // @success 200 {array} ova.VmNetwork
 // @router /networks [get]
 func (h InventoryHandler) Networks(ctx *gin.Context) {
+	if Settings.CatalogType == settings.CatalogHyperV {
+		vms, err := hyperv.Scan(Settings.CatalogPath)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, hyperv.Networks(vms))
+		return
+	}
 	envelopes, _ := inventory.ScanForAppliances(Settings.CatalogPath)
 	networks := inventory.ConvertToNetworkStruct(envelopes)
 	ctx.JSON(http.StatusOK, networks)
@@ -60,6 +80,15 @@ func (h InventoryHandler) Networks(ctx *gin.Context) {
 // @success 200 {array} ova.VmDisk
 // @router /disks [get]
 func (h InventoryHandler) Disks(ctx *gin.Context) {
+	if Settings.CatalogType == settings.CatalogHyperV {
+		vms, err := hyperv.Scan(Settings.CatalogPath)
+		if err != nil {
+			_ = ctx.Error(err)
+			return
+		}
+		ctx.JSON(http.StatusOK, hyperv.Disks(vms))
+		return
+	}
 	envelopes, paths := inventory.ScanForAppliances(Settings.CatalogPath)
 	disks := inventory.ConvertToDiskStruct(envelopes, paths)
 	ctx.JSON(http.StatusOK, disks)
@@ -0,0 +1,328 @@
+package hyperv
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// Well known device GUIDs found in the configuration.
+const (
+	IDEControllerGUID  = "83f8638b-8dca-4152-9eda-2ca8b33039b4"
+	SCSIControllerGUID = "d422512d-2bf2-4752-809d-7b82b5fcb1b4"
+)
+
+// Drive types.
+const (
+	DriveVHD = "VHD"
+)
+
+var (
+	controllerRegex = regexp.MustCompile(`^controller(\d+)$`)
+	driveRegex      = regexp.MustCompile(`^drive(\d+)$`)
+)
+
+// Element of the (schemaless) Hyper-V configuration.
+// Every setting is an element with a `type` attribute
+// and the value as character data.
+type Node struct {
+	XMLName xml.Name
+	Type    string `xml:"type,attr"`
+	Value   string `xml:",chardata"`
+	Nodes   []Node `xml:",any"`
+}
+
+// Find a child by name.
+func (r *Node) Child(name string) (child *Node) {
+	for i := range r.Nodes {
+		n := &r.Nodes[i]
+		if strings.EqualFold(n.XMLName.Local, name) {
+			child = n
+			return
+		}
+	}
+	return
+}
+
+// Find a descendant by path.
+func (r *Node) Path(path ...string) (node *Node) {
+	node = r
+	for _, name := range path {
+		node = node.Child(name)
+		if node == nil {
+			return
+		}
+	}
+	return
+}
+
+// Find the first descendant (depth first) with the name.
+func (r *Node) Find(name string) (node *Node) {
+	for i := range r.Nodes {
+		n := &r.Nodes[i]
+		if strings.EqualFold(n.XMLName.Local, name) {
+			node = n
+			return
+		}
+		node = n.Find(name)
+		if node != nil {
+			return
+		}
+	}
+	return
+}
+
+// Trimmed value.
+func (r *Node) String() string {
+	return strings.TrimSpace(r.Value)
+}
+
+// Value as integer.
+func (r *Node) Int() (n int64) {
+	n, _ = strconv.ParseInt(r.String(), 10, 64)
+	return
+}
+
+// Value as boolean.
+func (r *Node) Bool() (b bool) {
+	b, _ = strconv.ParseBool(r.String())
+	return
+}
+
+// String value at path.
+func (r *Node) StringAt(path ...string) (s string) {
+	node := r.Path(path...)
+	if node != nil {
+		s = node.String()
+	}
+	return
+}
+
+// Integer value at path.
+func (r *Node) IntAt(path ...string) (n int64) {
+	node := r.Path(path...)
+	if node != nil {
+		n = node.Int()
+	}
+	return
+}
+
+// Drive referenced by the configuration.
+type Drive struct {
+	Bus        string
+	Controller int
+	Unit       int
+	Path       string
+}
+
+// Hyper-V VM configuration.
+type Config struct {
+	Node
+}
+
+// Read the configuration XML.
+// Exported configurations are UTF-16 encoded with a BOM.
+func ReadConfig(path string) (config *Config, err error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	config, err = ParseConfig(b)
+	if err != nil {
+		err = fmt.Errorf("%s: %w", path, err)
+	}
+	return
+}
+
+// Parse the configuration XML.
+func ParseConfig(b []byte) (config *Config, err error) {
+	decoded := transform.NewReader(
+		bytes.NewReader(b),
+		unicode.BOMOverride(unicode.UTF8.NewDecoder()))
+	decoder := xml.NewDecoder(decoded)
+	// Already decoded to UTF-8.
+	decoder.CharsetReader = func(_ string, in io.Reader) (io.Reader, error) {
+		return in, nil
+	}
+	config = &Config{}
+	err = decoder.Decode(&config.Node)
+	if err != nil {
+		config = nil
+		return
+	}
+	if config.Path("properties") == nil {
+		err = fmt.Errorf("not a Hyper-V configuration")
+		config = nil
+	}
+	return
+}
+
+// VM UUID.
+func (r *Config) UUID() string {
+	return strings.ToLower(
+		strings.Trim(
+			r.StringAt("properties", "global_id"),
+			"{}"))
+}
+
+// VM name.
+func (r *Config) Name() string {
+	return r.StringAt("properties", "name")
+}
+
+// VM generation.
+// The configuration subtype is 0 for generation 1 and 1 for generation 2.
+func (r *Config) Generation() int {
+	if r.IntAt("properties", "subtype") == 1 {
+		return 2
+	}
+	return 1
+}
+
+// Firmware.
+func (r *Config) Firmware() string {
+	if r.Generation() == 2 {
+		return EFI
+	}
+	return BIOS
+}
+
+// Secure boot enabled.
+func (r *Config) SecureBoot() (enabled bool) {
+	if r.Generation() != 2 {
+		return
+	}
+	node := r.Find("secure_boot_enabled")
+	if node != nil {
+		enabled = node.Bool()
+	}
+	return
+}
+
+// Number of virtual processors.
+func (r *Config) CpuCount() int32 {
+	n := r.IntAt("settings", "processors", "count")
+	if n == 0 {
+		n = 1
+	}
+	return int32(n)
+}
+
+// Startup memory (MB).
+func (r *Config) MemoryMB() int32 {
+	return int32(r.IntAt("settings", "memory", "bank", "size"))
+}
+
+// Dynamic memory enabled.
+func (r *Config) DynamicMemory() (enabled bool) {
+	node := r.Path("settings", "memory", "bank", "dynamic_memory_enabled")
+	if node != nil {
+		enabled = node.Bool()
+	}
+	return
+}
+
+// Virtual hard disk drives ordered as found in
+// the configuration.
+func (r *Config) Drives() (drives []Drive) {
+	for i := range r.Nodes {
+		device := &r.Nodes[i]
+		bus := r.bus(device.XMLName.Local)
+		if bus == "" {
+			continue
+		}
+		for j := range device.Nodes {
+			controller := &device.Nodes[j]
+			matched := controllerRegex.FindStringSubmatch(controller.XMLName.Local)
+			if matched == nil {
+				continue
+			}
+			cn, _ := strconv.Atoi(matched[1])
+			for k := range controller.Nodes {
+				drive := &controller.Nodes[k]
+				matched = driveRegex.FindStringSubmatch(drive.XMLName.Local)
+				if matched == nil {
+					continue
+				}
+				if !strings.EqualFold(drive.StringAt("type"), DriveVHD) {
+					continue
+				}
+				path := drive.StringAt("pathname")
+				if path == "" {
+					continue
+				}
+				unit, _ := strconv.Atoi(matched[1])
+				drives = append(
+					drives,
+					Drive{
+						Bus:        bus,
+						Controller: cn,
+						Unit:       unit,
+						Path:       path,
+					})
+			}
+		}
+	}
+	return
+}
+
+// Network adapters.
+// Adapters are identified by the channel instance GUID.
+func (r *Config) NICs() (nics []NIC) {
+	for i := range r.Nodes {
+		device := &r.Nodes[i]
+		if device.Child("ChannelInstanceGuid") == nil {
+			continue
+		}
+		name := device.StringAt("friendly_name")
+		if name == "" {
+			name = fmt.Sprintf("Network Adapter %d", len(nics)+1)
+		}
+		nics = append(
+			nics,
+			NIC{
+				Name:    name,
+				MAC:     FormatMAC(device.StringAt("address")),
+				Network: device.StringAt("Connection", "AltSwitchName"),
+			})
+	}
+	return
+}
+
+// Bus by controller device element name.
+// Device elements are named: _<guid>_<instance>_.
+func (r *Config) bus(element string) (bus string) {
+	element = strings.ToLower(element)
+	switch {
+	case strings.Contains(element, IDEControllerGUID):
+		bus = IDE
+	case strings.Contains(element, SCSIControllerGUID):
+		bus = SCSI
+	}
+	return
+}
+
+// Format MAC address as colon separated.
+// Hyper-V stores addresses as 12 hex digits.
+func FormatMAC(address string) (mac string) {
+	address = strings.ToLower(
+		strings.NewReplacer("-", "", ":", "").Replace(
+			strings.TrimSpace(address)))
+	if len(address) != 12 || address == "000000000000" {
+		return
+	}
+	parts := []string{}
+	for i := 0; i < len(address); i += 2 {
+		parts = append(parts, address[i:i+2])
+	}
+	mac = strings.Join(parts, ":")
+	return
+}
@@ -0,0 +1,221 @@
+package hyperv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// File extensions.
+const (
+	ExtVHDX = ".vhdx"
+	ExtVHD  = ".vhd"
+)
+
+// VHDX layout.
+// See: [MS-VHDX] Virtual Hard Disk v2 (VHDX) File Format.
+const (
+	vhdxSignature         = "vhdxfile"
+	vhdxRegionSignature   = "regi"
+	vhdxMetaSignature     = "metadata"
+	vhdxRegionTableOffset = 0x30000
+	vhdxEntrySize         = 32
+	vhdxMaxEntries        = 2047
+	// Metadata region.
+	vhdxMetadataRegion = "8B7CA206-4790-4B9A-B8FE-575F050F886E"
+	// Virtual disk size metadata item.
+	vhdxVirtualDiskSize = "2FA54224-CD1B-4876-B211-5DBED83BF4B8"
+)
+
+// VHD layout.
+// The (512 byte) footer is at the end of the file.
+const (
+	vhdCookie          = "conectix"
+	vhdFooterSize      = 512
+	vhdCurrentSizeOffs = 48
+)
+
+// Disk format by file extension.
+func DiskFormat(path string) (format string) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ExtVHDX:
+		format = VHDX
+	case ExtVHD:
+		format = VHD
+	}
+	return
+}
+
+// Virtual (guest visible) size of a VHD or VHDX.
+func VirtualSize(path string) (size int64, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	switch DiskFormat(path) {
+	case VHDX:
+		size, err = vhdxVirtualSize(f)
+	case VHD:
+		size, err = vhdVirtualSize(f)
+	default:
+		err = fmt.Errorf("%s: unsupported disk format", path)
+	}
+	if err != nil {
+		err = fmt.Errorf("%s: %w", path, err)
+	}
+	return
+}
+
+// Read the virtual size from the VHDX metadata region.
+func vhdxVirtualSize(f io.ReaderAt) (size int64, err error) {
+	signature := make([]byte, len(vhdxSignature))
+	_, err = f.ReadAt(signature, 0)
+	if err != nil {
+		return
+	}
+	if string(signature) != vhdxSignature {
+		err = fmt.Errorf("not a VHDX")
+		return
+	}
+	regionOffset, _, err := findEntry(
+		f,
+		vhdxRegionTableOffset,
+		vhdxRegionSignature,
+		16,
+		vhdxMetadataRegion,
+		func(entry []byte) (int64, int64) {
+			return int64(binary.LittleEndian.Uint64(entry[16:24])),
+				int64(binary.LittleEndian.Uint32(entry[24:28]))
+		})
+	if err != nil {
+		return
+	}
+	itemOffset, itemLength, err := findEntry(
+		f,
+		regionOffset,
+		vhdxMetaSignature,
+		32,
+		vhdxVirtualDiskSize,
+		func(entry []byte) (int64, int64) {
+			return int64(binary.LittleEndian.Uint32(entry[16:20])),
+				int64(binary.LittleEndian.Uint32(entry[20:24]))
+		})
+	if err != nil {
+		return
+	}
+	if itemLength < 8 {
+		err = fmt.Errorf("virtual disk size: invalid length")
+		return
+	}
+	b := make([]byte, 8)
+	_, err = f.ReadAt(b, regionOffset+itemOffset)
+	if err != nil {
+		return
+	}
+	size = int64(binary.LittleEndian.Uint64(b))
+	return
+}
+
+// Find a table entry by GUID.
+// Region and metadata tables are laid out as a header
+// (signature and entry count) followed by 32 byte entries
+// that begin with the GUID.
+func findEntry(
+	f io.ReaderAt,
+	offset int64,
+	signature string,
+	headerSize int64,
+	guid string,
+	decode func(entry []byte) (int64, int64)) (entryOffset, entryLength int64, err error) {
+	//
+	header := make([]byte, headerSize)
+	_, err = f.ReadAt(header, offset)
+	if err != nil {
+		return
+	}
+	if string(header[:len(signature)]) != signature {
+		err = fmt.Errorf("%s: signature not found", signature)
+		return
+	}
+	var count int
+	switch signature {
+	case vhdxRegionSignature:
+		count = int(binary.LittleEndian.Uint32(header[8:12]))
+	default:
+		count = int(binary.LittleEndian.Uint16(header[10:12]))
+	}
+	if count > vhdxMaxEntries {
+		err = fmt.Errorf("%s: invalid entry count", signature)
+		return
+	}
+	id, err := guidBytes(guid)
+	if err != nil {
+		return
+	}
+	entry := make([]byte, vhdxEntrySize)
+	for i := 0; i < count; i++ {
+		_, err = f.ReadAt(entry, offset+headerSize+int64(i*vhdxEntrySize))
+		if err != nil {
+			return
+		}
+		if bytes.Equal(entry[:16], id) {
+			entryOffset, entryLength = decode(entry)
+			return
+		}
+	}
+	err = fmt.Errorf("%s: entry %s not found", signature, guid)
+	return
+}
+
+// Read the virtual size from the VHD footer.
+func vhdVirtualSize(f *os.File) (size int64, err error) {
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+	if info.Size() < vhdFooterSize {
+		err = fmt.Errorf("not a VHD")
+		return
+	}
+	footer := make([]byte, vhdFooterSize)
+	_, err = f.ReadAt(footer, info.Size()-vhdFooterSize)
+	if err != nil {
+		return
+	}
+	if string(footer[:len(vhdCookie)]) != vhdCookie {
+		err = fmt.Errorf("not a VHD")
+		return
+	}
+	size = int64(binary.BigEndian.Uint64(footer[vhdCurrentSizeOffs : vhdCurrentSizeOffs+8]))
+	return
+}
+
+// GUID as stored on disk.
+// The first three fields are little endian.
+func guidBytes(s string) (b []byte, err error) {
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return
+	}
+	b = make([]byte, 16)
+	copy(b, id[:])
+	reverse(b[0:4])
+	reverse(b[4:6])
+	reverse(b[6:8])
+	return
+}
+
+func reverse(b []byte) {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+}
@@ -0,0 +1,232 @@
+//nolint:errcheck
+package hyperv
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/text/encoding/unicode"
+)
+
+const configXML = `<?xml version="1.0" encoding="UTF-16" standalone="yes"?>
+<configuration>
+  <properties>
+    <global_id type="string">{7D5B0E1B-6A1C-4C2B-9C62-2F2A0D5B8E11}</global_id>
+    <name type="string">web-01</name>
+    <subtype type="integer">1</subtype>
+  </properties>
+  <settings>
+    <memory>
+      <bank>
+        <dynamic_memory_enabled type="bool">False</dynamic_memory_enabled>
+        <size type="integer">4096</size>
+      </bank>
+    </memory>
+    <processors>
+      <count type="integer">4</count>
+    </processors>
+    <secure_boot_enabled type="bool">True</secure_boot_enabled>
+  </settings>
+  <_d422512d-2bf2-4752-809d-7b82b5fcb1b4_0_>
+    <controller0>
+      <drive0>
+        <pathname type="string">C:\Hyper-V\web-01\Virtual Hard Disks\web-01.vhdx</pathname>
+        <type type="string">VHD</type>
+      </drive0>
+      <drive1>
+        <pathname type="string">C:\Hyper-V\web-01\Virtual Hard Disks\data.vhd</pathname>
+        <type type="string">VHD</type>
+      </drive1>
+      <drive2>
+        <pathname type="string">C:\iso\install.iso</pathname>
+        <type type="string">ISO</type>
+      </drive2>
+    </controller0>
+  </_d422512d-2bf2-4752-809d-7b82b5fcb1b4_0_>
+  <_8f2f4b1a-6f7e-4a3b-9c1d-0e5f6a7b8c9d_0_>
+    <address type="string">00155D010203</address>
+    <ChannelInstanceGuid type="string">{2A3E5C0B-0000-0000-0000-000000000000}</ChannelInstanceGuid>
+    <Connection>
+      <AltSwitchName type="string">External</AltSwitchName>
+    </Connection>
+    <friendly_name type="string">Network Adapter</friendly_name>
+  </_8f2f4b1a-6f7e-4a3b-9c1d-0e5f6a7b8c9d_0_>
+</configuration>
+`
+
+func TestScan(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	root := t.TempDir()
+	export := filepath.Join(root, "web-01")
+	writeConfig(g, filepath.Join(export, VirtualMachinesDir, "7D5B0E1B-6A1C-4C2B-9C62-2F2A0D5B8E11.xml"), configXML)
+	writeVHDX(g, filepath.Join(export, VirtualHardDisksDir, "web-01.vhdx"), 10<<30)
+	writeVHD(g, filepath.Join(export, VirtualHardDisksDir, "data.vhd"), 1<<30)
+
+	vms, err := Scan(root)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(vms).To(HaveLen(1))
+	vm := vms[0]
+	g.Expect(vm.UUID).To(Equal("7d5b0e1b-6a1c-4c2b-9c62-2f2a0d5b8e11"))
+	g.Expect(vm.Name).To(Equal("web-01"))
+	g.Expect(vm.Generation).To(Equal(2))
+	g.Expect(vm.Firmware).To(Equal(EFI))
+	g.Expect(vm.SecureBoot).To(BeTrue())
+	g.Expect(vm.CpuCount).To(Equal(int32(4)))
+	g.Expect(vm.MemoryMB).To(Equal(int32(4096)))
+	g.Expect(vm.DynamicMemory).To(BeFalse())
+
+	g.Expect(vm.Disks).To(HaveLen(2))
+	g.Expect(vm.Disks[0].Name).To(Equal("web-01.vhdx"))
+	g.Expect(vm.Disks[0].Format).To(Equal(VHDX))
+	g.Expect(vm.Disks[0].Bus).To(Equal(SCSI))
+	g.Expect(vm.Disks[0].Capacity).To(Equal(int64(10 << 30)))
+	g.Expect(vm.Disks[0].FilePath).To(Equal(filepath.Join(export, VirtualHardDisksDir, "web-01.vhdx")))
+	g.Expect(vm.Disks[1].Format).To(Equal(VHD))
+	g.Expect(vm.Disks[1].Unit).To(Equal(1))
+	g.Expect(vm.Disks[1].Capacity).To(Equal(int64(1 << 30)))
+
+	g.Expect(vm.NICs).To(HaveLen(1))
+	g.Expect(vm.NICs[0].MAC).To(Equal("00:15:5d:01:02:03"))
+	g.Expect(vm.NICs[0].Network).To(Equal("External"))
+	g.Expect(Networks(vms)).To(HaveLen(1))
+	g.Expect(Networks(vms)[0].ID).To(Equal(ID("External")))
+	g.Expect(Disks(vms)).To(HaveLen(2))
+}
+
+func TestFindConfigs(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	tests := []struct {
+		name     string
+		files    []string
+		expected []string
+	}{
+		{
+			name: "export layout",
+			files: []string{
+				"vm1/Virtual Machines/a.xml",
+				"group/vm2/Virtual Machines/b.XML",
+			},
+			expected: []string{
+				"group/vm2/Virtual Machines/b.XML",
+				"vm1/Virtual Machines/a.xml",
+			},
+		},
+		{
+			name: "not in virtual machines directory",
+			files: []string{
+				"vm1/a.xml",
+				"vm1/Snapshots/b.xml",
+			},
+			expected: nil,
+		},
+		{
+			name: "too deep",
+			files: []string{
+				"a/b/vm1/Virtual Machines/a.xml",
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, f := range tc.files {
+				path := filepath.Join(root, f)
+				os.MkdirAll(filepath.Dir(path), 0755)
+				os.WriteFile(path, []byte{}, 0644)
+			}
+			configs, err := findConfigs(root)
+			g.Expect(err).ToNot(HaveOccurred())
+			var relative []string
+			for _, path := range configs {
+				r, _ := filepath.Rel(root, path)
+				relative = append(relative, r)
+			}
+			g.Expect(relative).To(Equal(tc.expected))
+		})
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	config, err := ParseConfig([]byte(`<configuration><properties><subtype type="integer">0</subtype></properties>` +
+		`<_83f8638b-8dca-4152-9eda-2ca8b33039b4_0_><controller1><drive0>` +
+		`<pathname type="string">D:\disk.vhdx</pathname><type type="string">VHD</type>` +
+		`</drive0></controller1></_83f8638b-8dca-4152-9eda-2ca8b33039b4_0_></configuration>`))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(config.Generation()).To(Equal(1))
+	g.Expect(config.Firmware()).To(Equal(BIOS))
+	g.Expect(config.SecureBoot()).To(BeFalse())
+	g.Expect(config.CpuCount()).To(Equal(int32(1)))
+	g.Expect(config.Drives()).To(Equal([]Drive{{Bus: IDE, Controller: 1, Unit: 0, Path: `D:\disk.vhdx`}}))
+
+	_, err = ParseConfig([]byte(`<Envelope/>`))
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestFormatMAC(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	g.Expect(FormatMAC("00155D0A0B0C")).To(Equal("00:15:5d:0a:0b:0c"))
+	g.Expect(FormatMAC("00-15-5D-0A-0B-0C")).To(Equal("00:15:5d:0a:0b:0c"))
+	g.Expect(FormatMAC("000000000000")).To(BeEmpty())
+	g.Expect(FormatMAC("")).To(BeEmpty())
+}
+
+// Write UTF-16 configuration aged past the copy grace period.
+func writeConfig(g *WithT, path string, content string) {
+	g.Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+	encoder := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder()
+	b, err := encoder.Bytes([]byte(content))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(os.WriteFile(path, b, 0644)).To(Succeed())
+	old := time.Now().Add(-time.Hour)
+	g.Expect(os.Chtimes(path, old, old)).To(Succeed())
+}
+
+// Write a minimal VHDX with the metadata region
+// describing the virtual size.
+func writeVHDX(g *WithT, path string, size uint64) {
+	g.Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+	const metadataOffset = 0x40000
+	const itemOffset = 0x10000
+	b := make([]byte, metadataOffset+itemOffset+8)
+	copy(b, vhdxSignature)
+	// Region table.
+	region := b[vhdxRegionTableOffset:]
+	copy(region, vhdxRegionSignature)
+	binary.LittleEndian.PutUint32(region[8:], 1)
+	id, err := guidBytes(vhdxMetadataRegion)
+	g.Expect(err).ToNot(HaveOccurred())
+	copy(region[16:], id)
+	binary.LittleEndian.PutUint64(region[32:], metadataOffset)
+	binary.LittleEndian.PutUint32(region[40:], itemOffset+8)
+	// Metadata table.
+	metadata := b[metadataOffset:]
+	copy(metadata, vhdxMetaSignature)
+	binary.LittleEndian.PutUint16(metadata[10:], 1)
+	id, err = guidBytes(vhdxVirtualDiskSize)
+	g.Expect(err).ToNot(HaveOccurred())
+	copy(metadata[32:], id)
+	binary.LittleEndian.PutUint32(metadata[48:], itemOffset)
+	binary.LittleEndian.PutUint32(metadata[52:], 8)
+	binary.LittleEndian.PutUint64(metadata[itemOffset:], size)
+	g.Expect(os.WriteFile(path, b, 0644)).To(Succeed())
+}
+
+// Write a minimal (fixed) VHD footer.
+func writeVHD(g *WithT, path string, size uint64) {
+	g.Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+	b := make([]byte, vhdFooterSize)
+	copy(b, vhdCookie)
+	binary.BigEndian.PutUint64(b[vhdCurrentSizeOffs:], size)
+	g.Expect(os.WriteFile(path, b, 0644)).To(Succeed())
+}
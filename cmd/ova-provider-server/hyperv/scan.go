@@ -0,0 +1,219 @@
+package hyperv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Export layout.
+// An exported VM is a directory containing:
+//
+//	<name>/Virtual Machines/<guid>.xml
+//	<name>/Virtual Hard Disks/<disk>.vhdx
+const (
+	VirtualMachinesDir  = "Virtual Machines"
+	VirtualHardDisksDir = "Virtual Hard Disks"
+	ExtXML              = ".xml"
+	// Max depth of the configuration relative to the catalog.
+	MaxDepth = 4
+	// Files modified within this period are considered
+	// to still be copied.
+	CopyGracePeriod = 30 * time.Second
+)
+
+// Scan the catalog for exported VMs.
+func Scan(root string) (vms []VM, err error) {
+	configs, err := findConfigs(root)
+	if err != nil {
+		return
+	}
+	for _, path := range configs {
+		if !isFileComplete(path) {
+			log.Printf("Skipping %s: file still being copied\n", path)
+			continue
+		}
+		vm, vErr := buildVM(path)
+		if vErr != nil {
+			log.Printf("Error processing Hyper-V configuration %s: %v\n", path, vErr)
+			continue
+		}
+		vms = append(vms, *vm)
+	}
+	return
+}
+
+// Networks (virtual switches) referenced by the VMs.
+func Networks(vms []VM) (networks []VmNetwork) {
+	found := map[string]bool{}
+	for _, vm := range vms {
+		for _, network := range vm.Networks {
+			if found[network.ID] {
+				continue
+			}
+			found[network.ID] = true
+			networks = append(networks, network)
+		}
+	}
+	return
+}
+
+// Disks attached to the VMs.
+func Disks(vms []VM) (disks []VmDisk) {
+	for _, vm := range vms {
+		disks = append(disks, vm.Disks...)
+	}
+	return
+}
+
+// Build the VM for the configuration.
+func buildVM(path string) (vm *VM, err error) {
+	config, err := ReadConfig(path)
+	if err != nil {
+		return
+	}
+	exportPath := filepath.Dir(filepath.Dir(path))
+	vm = &VM{
+		UUID:          config.UUID(),
+		Name:          config.Name(),
+		ExportPath:    exportPath,
+		ConfigPath:    path,
+		Generation:    config.Generation(),
+		Firmware:      config.Firmware(),
+		SecureBoot:    config.SecureBoot(),
+		CpuCount:      config.CpuCount(),
+		MemoryMB:      config.MemoryMB(),
+		DynamicMemory: config.DynamicMemory(),
+	}
+	if vm.UUID == "" {
+		vm.UUID = ID(path)
+	}
+	if vm.Name == "" {
+		vm.Name = filepath.Base(exportPath)
+	}
+	for _, drive := range config.Drives() {
+		disk := buildDisk(exportPath, drive)
+		vm.StorageUsed += disk.PopulatedSize
+		vm.Disks = append(vm.Disks, disk)
+	}
+	found := map[string]bool{}
+	for _, nic := range config.NICs() {
+		vm.NICs = append(vm.NICs, nic)
+		if nic.Network == "" || found[nic.Network] {
+			continue
+		}
+		found[nic.Network] = true
+		vm.Networks = append(
+			vm.Networks,
+			VmNetwork{
+				ID:          ID(nic.Network),
+				Name:        nic.Network,
+				Description: "Hyper-V virtual switch",
+			})
+	}
+	return
+}
+
+// Build the disk for the drive.
+// The recorded path is on the Hyper-V host so the image
+// is resolved by name within the export directory.
+func buildDisk(exportPath string, drive Drive) (disk VmDisk) {
+	name := windowsBase(drive.Path)
+	disk = VmDisk{
+		Name:       name,
+		SourcePath: drive.Path,
+		Format:     DiskFormat(name),
+		Bus:        drive.Bus,
+		Controller: drive.Controller,
+		Unit:       drive.Unit,
+	}
+	disk.FilePath = resolveDisk(exportPath, name)
+	disk.ID = ID(disk.FilePath)
+	info, err := os.Stat(disk.FilePath)
+	if err != nil {
+		log.Printf("Disk %s not found in %s\n", name, exportPath)
+		return
+	}
+	disk.PopulatedSize = info.Size()
+	disk.Capacity, err = VirtualSize(disk.FilePath)
+	if err != nil {
+		log.Printf("Error reading disk %s: %v\n", disk.FilePath, err)
+		disk.Capacity = disk.PopulatedSize
+	}
+	return
+}
+
+// Resolve the disk image within the export.
+func resolveDisk(exportPath, name string) (path string) {
+	path = filepath.Join(exportPath, VirtualHardDisksDir, name)
+	if _, err := os.Stat(path); err == nil {
+		return
+	}
+	_ = filepath.WalkDir(exportPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if strings.EqualFold(d.Name(), name) {
+			path = p
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return
+}
+
+// Find configuration files.
+func findConfigs(root string) (configs []string, err error) {
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, _ := filepath.Rel(root, path)
+		depth := len(strings.Split(relative, string(filepath.Separator)))
+		if d.IsDir() {
+			if depth >= MaxDepth && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.EqualFold(filepath.Ext(d.Name()), ExtXML) {
+			return nil
+		}
+		if !strings.EqualFold(filepath.Base(filepath.Dir(path)), VirtualMachinesDir) {
+			return nil
+		}
+		configs = append(configs, path)
+		return nil
+	})
+	sort.Strings(configs)
+	return
+}
+
+// isFileComplete checks that the file was not modified
+// within the grace period and is not empty.
+func isFileComplete(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return time.Since(info.ModTime()) > CopyGracePeriod && info.Size() > 0
+}
+
+// Base name of a path recorded on the (windows) host.
+func windowsBase(path string) string {
+	path = strings.ReplaceAll(path, "\\", "/")
+	return filepath.Base(path)
+}
+
+// Stable ID for the key.
+func ID(key string) (id string) {
+	hash := sha256.Sum256([]byte(key))
+	id = hex.EncodeToString(hash[:])[:36]
+	return
+}
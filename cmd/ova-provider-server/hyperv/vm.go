@@ -0,0 +1,69 @@
+package hyperv
+
+// Firmware.
+const (
+	BIOS = "bios"
+	EFI  = "efi"
+)
+
+// Disk formats.
+const (
+	VHDX = "vhdx"
+	VHD  = "vhd"
+)
+
+// Disk buses.
+const (
+	IDE  = "ide"
+	SCSI = "scsi"
+)
+
+// Hyper-V virtual machine extracted from an exported configuration.
+type VM struct {
+	UUID string
+	Name string
+	// Path to the export directory.
+	ExportPath string
+	// Path to the configuration XML.
+	ConfigPath    string
+	Generation    int
+	Firmware      string
+	SecureBoot    bool
+	CpuCount      int32
+	MemoryMB      int32
+	DynamicMemory bool
+	StorageUsed   int64
+	NICs          []NIC
+	Disks         []VmDisk
+	Networks      []VmNetwork
+}
+
+// Virtual Disk.
+type VmDisk struct {
+	ID   string
+	Name string
+	// Absolute path to the disk image.
+	FilePath string
+	// Path as recorded in the VM configuration.
+	SourcePath    string
+	Format        string
+	Bus           string
+	Controller    int
+	Unit          int
+	Capacity      int64
+	PopulatedSize int64
+}
+
+// Virtual network adapter.
+type NIC struct {
+	Name    string
+	MAC     string
+	Network string
+}
+
+// Virtual switch.
+type VmNetwork struct {
+	ID          string
+	Name        string
+	Description string
+}
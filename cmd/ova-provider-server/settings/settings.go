@@ -5,6 +5,12 @@ import (
 	"strconv"
 )
 
+// Catalog types.
+const (
+	CatalogOVA    = "ova"
+	CatalogHyperV = "hyperv"
+)
+
 // Environment variables.
 const (
 	EnvApplianceEndpoints = "APPLIANCE_ENDPOINTS"
@@ -14,6 +20,7 @@ const (
 	EnvProviderNamespace  = "PROVIDER_NAMESPACE"
 	EnvProviderName       = "PROVIDER_NAME"
 	EnvProviderVerb       = "PROVIDER_VERB"
+	EnvProviderType       = "PROVIDER_TYPE"
 	EnvTokenCacheTTL      = "TOKEN_CACHE_TTL"
 )
 
@@ -32,6 +39,8 @@ type OVASettings struct {
 	}
 	// Path to OVA appliance directory
 	CatalogPath string
+	// Catalog type (ova|hyperv)
+	CatalogType string
 	// Port to serve on
 	Port string
 	// Provider details
@@ -52,6 +61,12 @@ func (r *OVASettings) Load() (err error) {
 	} else {
 		r.CatalogPath = "/ova"
 	}
+	s, found = os.LookupEnv(EnvProviderType)
+	if found && s == CatalogHyperV {
+		r.CatalogType = CatalogHyperV
+	} else {
+		r.CatalogType = CatalogOVA
+	}
 	s, found = os.LookupEnv(EnvPort)
 	if found {
 		r.Port = s
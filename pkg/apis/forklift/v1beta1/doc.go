@@ -37,6 +37,8 @@ const (
 	ConditionFailed    = "Failed"
 	ConditionBlocked   = "Blocked"
 	ConditionDeleted   = "Deleted"
+	ConditionWaiting   = "WaitingForWindow"
+	ConditionPaused    = "Paused"
 )
 
 // Condition categories
@@ -83,6 +85,7 @@ const (
 	PhaseStoreInitialSnapshotDeltas        = "StoreInitialSnapshotDeltas"
 	PhaseStorePowerState                   = "StorePowerState"
 	PhaseStoreSnapshotDeltas               = "StoreSnapshotDeltas"
+	PhaseValidateTarget                    = "ValidateTarget"
 	PhaseWaitForDataVolumesStatus          = "WaitForDataVolumesStatus"
 	PhaseWaitForFinalDataVolumesStatus     = "WaitForFinalDataVolumesStatus"
 	PhaseWaitForFinalSnapshot              = "WaitForFinalSnapshot"
@@ -94,6 +97,13 @@ const (
 	PhaseWaitForSnapshot                   = "WaitForSnapshot"
 )
 
+// Rollback phases.
+const (
+	PhaseRollbackCleanup        = "RollbackCleanup"
+	PhaseRollbackPowerOnSource  = "RollbackPowerOnSource"
+	PhaseRollbackWaitForPowerOn = "RollbackWaitForPowerOn"
+)
+
 // Step/task phases.
 const (
 	StepStarted   = "Started"
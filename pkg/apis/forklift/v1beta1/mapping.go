@@ -105,6 +105,25 @@ type StoragePair struct {
 	Destination DestinationStorage `json:"destination"`
 	// Offload Plugin
 	OffloadPlugin *OffloadPlugin `json:"offloadPlugin,omitempty"`
+	// Limits on concurrent disk transfers.
+	// +optional
+	Limits *StorageLimits `json:"limits,omitempty"`
+}
+
+// Limits on concurrent disk transfers.
+// Enforced by the scheduler across all plans sharing
+// the source provider. Zero (or unset) means unlimited.
+type StorageLimits struct {
+	// Maximum number of disks transferred concurrently
+	// from the source storage (datastore, storage domain, ...).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxSourceInFlight int `json:"maxSourceInFlight,omitempty"`
+	// Maximum number of disks transferred concurrently
+	// to the destination storage class.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxDestinationInFlight int `json:"maxDestinationInFlight,omitempty"`
 }
 
 // Mapped storage destination.
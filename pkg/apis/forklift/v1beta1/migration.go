@@ -30,6 +30,20 @@ type MigrationSpec struct {
 	Plan core.ObjectReference `json:"plan" ref:"Plan"`
 	// List of VMs which will have their imports canceled.
 	Cancel []ref.Ref `json:"cancel,omitempty"`
+	// List of VMs which will have their migrations paused.
+	// A paused VM does not advance to the next phase. Warm precopies
+	// are not scheduled while paused. Transfers already running on the
+	// destination (cold DataVolumes and volume populators) cannot be
+	// suspended and run to completion, so a VM paused while transferring
+	// disks keeps occupying scheduler capacity. Removing the VM from the
+	// list resumes the migration from the recorded phase.
+	Pause []ref.Ref `json:"pause,omitempty"`
+	// Retry only the failed VMs of the plan. Succeeded and canceled
+	// VMs are preserved. A failed VM resumes from the last completed
+	// pipeline step and reuses the disks already transferred. Warm and
+	// rolled back VMs are migrated from the beginning.
+	// +optional
+	RetryFailed bool `json:"retryFailed,omitempty"`
 	// Date and time to finalize a warm migration.
 	// If present, this will override the value set on the Plan.
 	Cutover *meta.Time `json:"cutover,omitempty"`
@@ -58,6 +72,25 @@ func (r *MigrationSpec) Canceled(ref ref.Ref) (found bool) {
 	return
 }
 
+// Paused indicates whether a VM ref is present
+// in the list of VM refs to be paused.
+func (r *MigrationSpec) Paused(ref ref.Ref) (found bool) {
+	if ref.ID == "" {
+		return
+	}
+	for _, vm := range r.Pause {
+		if vm.ID == "" {
+			continue
+		}
+		if vm.ID == ref.ID {
+			found = true
+			return
+		}
+	}
+
+	return
+}
+
 // MigrationStatus defines the observed state of Migration
 type MigrationStatus struct {
 	plan.Timed `json:",inline"`
@@ -68,6 +101,10 @@ type MigrationStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// VM status
 	VMs []*plan.VMStatus `json:"vms,omitempty"`
+	// Next time VMs may be started when waiting
+	// for a migration window.
+	// +optional
+	NextStart *meta.Time `json:"nextStart,omitempty"`
 }
 
 // +genclient
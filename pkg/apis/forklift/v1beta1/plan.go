@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	"context"
+	"strings"
 
 	k8snet "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
@@ -119,7 +120,13 @@ type PlanSpec struct {
 	// Resource mapping.
 	Map plan.Map `json:"map"`
 	// List of VMs.
-	VMs []plan.VM `json:"vms"`
+	// +optional
+	VMs []plan.VM `json:"vms,omitempty"`
+	// Select the VMs by an inventory query rather than listing them.
+	// The selected VMs are migrated in addition to the listed VMs and
+	// are reported on the status.
+	// +optional
+	VMSelector *plan.VMSelector `json:"vmSelector,omitempty"`
 	// Whether this is a warm migration.
 	// Deprecated: this field will be deprecated in 2.10. Use Type instead.
 	Warm bool `json:"warm,omitempty"`
@@ -127,6 +134,15 @@ type PlanSpec struct {
 	TransferNetwork *core.ObjectReference `json:"transferNetwork,omitempty"`
 	// Whether this plan should be archived.
 	Archived bool `json:"archived,omitempty"`
+	// Render the target objects of the VMs without creating them.
+	// The manifests and the itinerary of each VM are stored on the
+	// `<plan>-dry-run-<vm>` ConfigMap labeled with the plan UID and
+	// the VM ID. The `<plan>-dry-run` ConfigMap stores the objects
+	// shared by the VMs and lists the ConfigMaps of the VMs. The
+	// ConfigMaps are rendered again when the plan changes. The values
+	// of the secrets are redacted. The plan is not executed.
+	// +optional
+	DryRun bool `json:"dryRun,omitempty"`
 	// Preserve the CPU model and flags the VM runs with in its oVirt cluster.
 	PreserveClusterCPUModel bool `json:"preserveClusterCpuModel,omitempty"`
 	// Preserve static IPs of VMs in vSphere
@@ -134,6 +150,11 @@ type PlanSpec struct {
 	PreserveStaticIPs bool `json:"preserveStaticIPs,omitempty"`
 	// Deprecated: this field will be deprecated in 2.8.
 	DiskBus cnv.DiskBus `json:"diskBus,omitempty"`
+	// Hardware overrides of the target VMs.
+	// Applied after the hardware of the source VM is mapped
+	// and overridden by the hardware overrides of the VM.
+	// +optional
+	Hardware *plan.Hardware `json:"hardware,omitempty"`
 	// PVCNameTemplate is a template for generating PVC names for VM disks.
 	// Generated names must be valid DNS-1123 labels (lowercase alphanumerics, '-' allowed, max 63 chars).
 	// It follows Go template syntax and has access to the following variables:
@@ -211,6 +232,15 @@ type PlanSpec struct {
 	//
 	// +optional
 	DeleteVmOnFailMigration bool `json:"deleteVmOnFailMigration,omitempty"`
+	// RollbackOnFailure controls whether a VM migration that fails (or is canceled)
+	// after the source VM has been powered off is rolled back. The rollback removes
+	// the target VM and its disks, removes leftover snapshots and restores the
+	// power state of the source VM. The rollback is reported as its own pipeline step.
+	//
+	// Note: If the Plan-level option is set to true, the VM-level option will be ignored.
+	//
+	// +optional
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
 	// InstallLegacyDrivers determines whether to install legacy windows drivers in the VM.
 	//The following Vm's are lack of SHA-2 support and need legacy drivers:
 	// Windows XP (all)
@@ -253,6 +283,45 @@ type PlanSpec struct {
 	// - false: No inspection is performed before disk transfer.
 	// +kubebuilder:default:=true
 	RunPreflightInspection bool `json:"runPreflightInspection,omitempty"`
+	// Schedule constrains when the migration of VMs may be started.
+	// Outside of the windows (or within a blackout) no VM is started
+	// and warm precopies are paused during blackouts.
+	// +optional
+	Schedule *plan.Schedule `json:"schedule,omitempty"`
+	// Migration waves.
+	// Ordered groups of VMs. VMs are assigned to a wave using `vms[].wave`
+	// and are only started once all the waves the wave depends on have succeeded.
+	// VMs not assigned to a wave are not ordered.
+	// +optional
+	Waves []plan.Wave `json:"waves,omitempty"`
+	// Order in which the VMs are started.
+	// - "priority" (default): Higher `vms[].priority` first, then the order of the VM list.
+	// - "smallestFirst": Smallest total disk size first.
+	// - "largestFirst": Largest total disk size first.
+	// - "source": The order of the VM list.
+	// Pending VMs of other plans sharing the source provider with
+	// a higher priority are started first regardless of the ordering.
+	// +optional
+	// +kubebuilder:validation:Enum=priority;smallestFirst;largestFirst;source
+	Ordering plan.Ordering `json:"ordering,omitempty"`
+	// Replication mode (warm only).
+	// The precopies recur on the replication interval until the cutover
+	// is set, keeping a warm replica of the VMs on the destination. Failed
+	// precopies are retried on the next interval instead of failing the VM
+	// and the precopy history is compacted.
+	// +optional
+	Replication *plan.Replication `json:"replication,omitempty"`
+	// Post-migration validation of the target VMs.
+	// The migrated VM is booted (when the target power state allows), then
+	// the VM readiness, the guest agent connection, the expected IPs and
+	// the configured TCP and HTTP probes are checked. The results are
+	// reported by the TargetValidation pipeline step.
+	// +optional
+	TargetValidation *plan.TargetValidation `json:"targetValidation,omitempty"`
+	// Propagation of source VM metadata (vSphere tags and custom
+	// attributes) to the labels and annotations of the target VMs.
+	// +optional
+	TargetMetadata []plan.MetadataMapping `json:"targetMetadata,omitempty"`
 }
 
 // Find a planned VM.
@@ -277,6 +346,9 @@ type PlanStatus struct {
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// Migration
 	Migration plan.MigrationStatus `json:"migration,omitempty"`
+	// VMs selected by the VM selector.
+	// +optional
+	Selector *plan.SelectorStatus `json:"selector,omitempty"`
 }
 
 // +genclient
@@ -305,6 +377,11 @@ func (p *Plan) IsWarm() bool {
 	return p.Spec.Warm || p.Spec.Type == MigrationWarm
 }
 
+// Warm plan in replication mode.
+func (p *Plan) IsReplicating() bool {
+	return p.IsWarm() && p.Spec.Replication != nil
+}
+
 // If the plan calls for the vm to be cold migrated to the local cluster, we can
 // just use virt-v2v directly to convert the vm while copying data over. In other
 // cases, we use CDI to transfer disks to the destination cluster and then use
@@ -323,19 +400,59 @@ func (p *Plan) ShouldUseV2vForTransfer() (bool, error) {
 	case VSphere:
 		// The virt-v2v transferes all disks attached to the VM. If we want to skip the shared disks so we don't transfer
 		// them multiple times we need to manage the transfer using KubeVirt CDI DataVolumes and v2v-in-place.
+		// The disks excluded on the VMs are skipped by passing the selected disks to virt-v2v.
 		return !p.IsWarm() && // The Warm Migraiton needs to use CDI to manage the snapshot delta
 				destination.IsHost() && // We can't monitor progress from the guest converison pod on the remote clusters
 				p.Spec.MigrateSharedDisks && // virt-v2v migrates all disks, to skip shared we need to control the disk selection
 				!p.Spec.SkipGuestConversion && // virt-v2v always converts the guest, to perform RawCopyMode we need to copy just disks via CDI
 				p.Spec.Type != MigrationOnlyConversion, // For only v2v-in-place conversion, we don't want to populate disks by v2v
 			nil
-	case Ova:
+	case Ova, HyperV:
 		return true, nil
 	default:
 		return false, nil
 	}
 }
 
+// Determine whether any of the plan VMs select the disks migrated.
+func (p *Plan) SelectsDisks() bool {
+	for _, vm := range p.Spec.VMs {
+		if vm.Disks != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Add the VMs selected by the VM selector that are not listed.
+// The selected VMs are added to the (in-memory) list of VMs.
+func (p *Plan) AddSelectedVMs() {
+	if p.Status.Selector == nil {
+		return
+	}
+	for _, ref := range p.Status.Selector.VMs {
+		if !p.listed(ref) {
+			p.Spec.VMs = append(p.Spec.VMs, plan.VM{Ref: ref})
+		}
+	}
+}
+
+// Determine whether the VM is listed on the plan.
+// The listed VMs may be referenced by name.
+func (p *Plan) listed(ref ref.Ref) bool {
+	for _, vm := range p.Spec.VMs {
+		if vm.ID == ref.ID {
+			return true
+		}
+		if vm.ID == "" && vm.Name != "" {
+			if vm.Name == ref.Name || strings.HasSuffix(vm.Name, "/"+ref.Name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (r *Plan) DestinationHasUdnNetwork(client k8sclient.Client) bool {
 	key := k8sclient.ObjectKey{
 		Name: r.Spec.TargetNamespace,
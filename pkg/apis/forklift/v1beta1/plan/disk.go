@@ -0,0 +1,119 @@
+package plan
+
+import (
+	"strings"
+)
+
+// Disk selector.
+// Selects the disks of the VM that are migrated. The disks not
+// selected are left behind: they are not transferred and not
+// attached to the target VM.
+type DiskSelector struct {
+	// Only the disks matching any of the rules are migrated.
+	// All disks are included when empty.
+	// +optional
+	Include []DiskMatch `json:"include,omitempty"`
+	// The disks matching any of the rules are not migrated.
+	// Takes precedence over the included disks.
+	// +optional
+	Exclude []DiskMatch `json:"exclude,omitempty"`
+}
+
+// Disk match rule.
+// A disk matches when all the specified fields match. The file
+// and label support the `*` wildcard.
+type DiskMatch struct {
+	// Disk key. The device key (vSphere), disk ID (oVirt, OVA,
+	// Hyper-V), volume ID (OpenStack) or volume name (OpenShift).
+	// +optional
+	Key string `json:"key,omitempty"`
+	// Disk file. The backing file (vSphere), file path (OVA,
+	// Hyper-V) or the PVC/DataVolume name (OpenShift).
+	// +optional
+	File string `json:"file,omitempty"`
+	// Disk label. The device label, e.g. "Hard disk 2" (vSphere)
+	// or the disk name.
+	// +optional
+	Label string `json:"label,omitempty"`
+	// Disk bus, e.g. scsi, sata, ide or virtio.
+	// +optional
+	Bus string `json:"bus,omitempty"`
+	// Index of the disk on the source VM (0-based).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Index *int `json:"index,omitempty"`
+}
+
+// Source VM disk described for the selection.
+type SourceDisk struct {
+	Key   string
+	File  string
+	Label string
+	Bus   string
+	Index int
+}
+
+// Determine if the disk matches.
+func (r *DiskMatch) Match(disk SourceDisk) bool {
+	if r.Key != "" && r.Key != disk.Key {
+		return false
+	}
+	if r.File != "" && !wildcard(r.File, disk.File) {
+		return false
+	}
+	if r.Label != "" && !wildcard(r.Label, disk.Label) {
+		return false
+	}
+	if r.Bus != "" && !strings.EqualFold(r.Bus, disk.Bus) {
+		return false
+	}
+	if r.Index != nil && *r.Index != disk.Index {
+		return false
+	}
+
+	return true
+}
+
+// Determine if the disk is selected.
+func (r *DiskSelector) Selected(disk SourceDisk) bool {
+	for i := range r.Exclude {
+		if r.Exclude[i].Match(disk) {
+			return false
+		}
+	}
+	if len(r.Include) == 0 {
+		return true
+	}
+	for i := range r.Include {
+		if r.Include[i].Match(disk) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match a value with a pattern.
+// The `*` in the pattern matches any sequence of characters.
+// Other characters, such as the brackets in vSphere datastore
+// paths, match literally.
+func wildcard(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	last := len(parts) - 1
+	for _, part := range parts[1:last] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+
+	return strings.HasSuffix(value, parts[last])
+}
@@ -0,0 +1,137 @@
+package plan
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// Firmware types.
+const (
+	FirmwareBIOS = "bios"
+	FirmwareEFI  = "efi"
+)
+
+// Hardware overrides.
+// Applied to the target VM after the hardware of the source
+// VM is mapped. The fields not specified keep the mapped values.
+type Hardware struct {
+	// CPU topology and placement.
+	// +optional
+	CPU *CPU `json:"cpu,omitempty"`
+	// Memory.
+	// +optional
+	Memory *Memory `json:"memory,omitempty"`
+	// NIC models.
+	// +optional
+	NICs []NICOverride `json:"nics,omitempty"`
+	// Disk buses.
+	// +optional
+	Disks []DiskOverride `json:"disks,omitempty"`
+	// Machine type, e.g. q35.
+	// +optional
+	MachineType string `json:"machineType,omitempty"`
+	// Firmware.
+	// +optional
+	Firmware *Firmware `json:"firmware,omitempty"`
+}
+
+// CPU overrides.
+type CPU struct {
+	// Number of sockets.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Sockets uint32 `json:"sockets,omitempty"`
+	// Number of cores per socket.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Cores uint32 `json:"cores,omitempty"`
+	// Number of threads per core.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Threads uint32 `json:"threads,omitempty"`
+	// Pin the vCPUs to dedicated host CPUs.
+	// +optional
+	DedicatedCPUPlacement *bool `json:"dedicatedCpuPlacement,omitempty"`
+}
+
+// Memory overrides.
+type Memory struct {
+	// Memory of the guest.
+	// +optional
+	Guest *resource.Quantity `json:"guest,omitempty"`
+	// Memory overcommit percentage. The memory requested by
+	// the VM is the guest memory divided by the overcommit, e.g.
+	// 150 requests 2/3 of the guest memory.
+	// +kubebuilder:validation:Minimum=100
+	// +optional
+	Overcommit int `json:"overcommit,omitempty"`
+}
+
+// NIC override.
+// Applied to the NIC with the MAC address or index. Applied to
+// all NICs when neither is specified.
+type NICOverride struct {
+	// MAC address of the NIC.
+	// +optional
+	MAC string `json:"mac,omitempty"`
+	// Index of the NIC on the target VM (0-based).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Index *int `json:"index,omitempty"`
+	// NIC model, e.g. virtio or e1000e.
+	Model string `json:"model"`
+}
+
+// Disk override.
+// Applied to the disk with the index. Applied to all disks
+// when the index is not specified.
+type DiskOverride struct {
+	// Index of the disk on the target VM (0-based).
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Index *int `json:"index,omitempty"`
+	// Disk bus.
+	// +kubebuilder:validation:Enum=virtio;sata;scsi
+	Bus cnv.DiskBus `json:"bus"`
+}
+
+// Firmware overrides.
+type Firmware struct {
+	// Firmware type.
+	// +kubebuilder:validation:Enum=bios;efi
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Enable secure boot. Requires EFI.
+	// +optional
+	SecureBoot *bool `json:"secureBoot,omitempty"`
+}
+
+// Hardware with the overrides applied.
+// The fields of the override replace the fields of the
+// receiver. The NIC and disk overrides are appended so
+// the more specific ones are applied last.
+func (r *Hardware) With(override *Hardware) *Hardware {
+	if r == nil {
+		return override
+	}
+	merged := r.DeepCopy()
+	if override == nil {
+		return merged
+	}
+	if override.CPU != nil {
+		merged.CPU = override.CPU.DeepCopy()
+	}
+	if override.Memory != nil {
+		merged.Memory = override.Memory.DeepCopy()
+	}
+	merged.NICs = append(merged.NICs, override.NICs...)
+	merged.Disks = append(merged.Disks, override.Disks...)
+	if override.MachineType != "" {
+		merged.MachineType = override.MachineType
+	}
+	if override.Firmware != nil {
+		merged.Firmware = override.Firmware.DeepCopy()
+	}
+
+	return merged
+}
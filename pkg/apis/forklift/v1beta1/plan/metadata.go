@@ -0,0 +1,34 @@
+package plan
+
+// Sources of VM metadata.
+const (
+	// Tag category (vSphere).
+	MetadataTag = "Tag"
+	// Custom attribute (vSphere).
+	MetadataAttribute = "Attribute"
+)
+
+// Targets of VM metadata.
+const (
+	MetadataLabel      = "Label"
+	MetadataAnnotation = "Annotation"
+)
+
+// Propagation of source VM metadata to the target VM.
+// The value of a tag category (the names of the tags attached
+// in the category) or of a custom attribute is set as a label or
+// an annotation on the target VirtualMachine. The names of multiple
+// tags in the category are joined by ",". Label values are sanitized.
+type MetadataMapping struct {
+	// Source of the value.
+	// +kubebuilder:validation:Enum=Tag;Attribute
+	Source string `json:"source"`
+	// Tag category or custom attribute name.
+	Name string `json:"name"`
+	// Target of the value.
+	// +kubebuilder:validation:Enum=Label;Annotation
+	Target string `json:"target"`
+	// Label or annotation key.
+	// +optional
+	Key string `json:"key,omitempty"`
+}
@@ -3,6 +3,7 @@ package plan
 import (
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -37,6 +38,26 @@ type MigrationStatus struct {
 	History []Snapshot `json:"history,omitempty"`
 	// VM status
 	VMs []*VMStatus `json:"vms,omitempty"`
+	// Next time VMs may be started when waiting
+	// for a migration window.
+	// +optional
+	NextStart *meta.Time `json:"nextStart,omitempty"`
+	// Wave status.
+	// +optional
+	Waves []WaveStatus `json:"waves,omitempty"`
+}
+
+// Find a wave status by name.
+func (r *MigrationStatus) FindWave(name string) (wave *WaveStatus, found bool) {
+	for i := range r.Waves {
+		if r.Waves[i].Name == name {
+			wave = &r.Waves[i]
+			found = true
+			break
+		}
+	}
+
+	return
 }
 
 // The active snapshot.
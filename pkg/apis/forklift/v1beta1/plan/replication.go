@@ -0,0 +1,58 @@
+package plan
+
+import (
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Replication defaults.
+const (
+	DefaultRetention        = 10
+	DefaultFailureThreshold = 3
+)
+
+// Replication.
+// Recurring warm sync without cutover. The precopies continue
+// on the schedule until the cutover is set on the migration.
+type Replication struct {
+	// Interval between precopies. Overrides the global precopy interval.
+	// Example: "4h".
+	// +optional
+	Interval *meta.Duration `json:"interval,omitempty"`
+	// Number of precopies retained in the VM status.
+	// Older precopies are compacted. Defaults to 10.
+	// +optional
+	// +kubebuilder:validation:Minimum=2
+	Retention int `json:"retention,omitempty"`
+	// Number of consecutive failed precopies after which
+	// the replication is reported as degraded. Defaults to 3.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+}
+
+// Precopy interval.
+// The default is used when not set.
+func (r *Replication) PrecopyInterval(defaultInterval time.Duration) time.Duration {
+	if r.Interval != nil && r.Interval.Duration > 0 {
+		return r.Interval.Duration
+	}
+	return defaultInterval
+}
+
+// Number of precopies retained.
+func (r *Replication) RetainedPrecopies() int {
+	if r.Retention > 0 {
+		return max(r.Retention, 2)
+	}
+	return DefaultRetention
+}
+
+// Number of consecutive failures tolerated.
+func (r *Replication) Threshold() int {
+	if r.FailureThreshold > 0 {
+		return r.FailureThreshold
+	}
+	return DefaultFailureThreshold
+}
@@ -0,0 +1,22 @@
+package plan
+
+import "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+
+// VM retried by a migration.
+type RetriedVM struct {
+	// VM reference.
+	ref.Ref `json:",inline"`
+	// Phase in which the VM failed.
+	// +optional
+	FailedPhase string `json:"failedPhase,omitempty"`
+	// Reasons the VM failed.
+	// +optional
+	Reasons []string `json:"reasons,omitempty"`
+	// Phase from which the VM is resumed.
+	// Empty when the VM is migrated from the beginning.
+	// +optional
+	ResumedPhase string `json:"resumedPhase,omitempty"`
+	// Number of transferred disks reused.
+	// +optional
+	ReusedDisks int `json:"reusedDisks,omitempty"`
+}
@@ -0,0 +1,41 @@
+package plan
+
+import meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// Migration schedule.
+// Constrains when the migration of VMs may be started.
+type Schedule struct {
+	// IANA time zone (e.g. "Europe/Prague") used to evaluate
+	// the windows. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// Windows during which the migration of VMs may be started.
+	// When empty, VMs may be started at any time outside of blackouts.
+	// VMs already started are not interrupted when a window closes.
+	// +optional
+	Windows []Window `json:"windows,omitempty"`
+	// Blackouts during which no VM is started and warm
+	// precopies are paused.
+	// +optional
+	Blackouts []Blackout `json:"blackouts,omitempty"`
+}
+
+// Execution window.
+type Window struct {
+	// Start of the window as a standard (5 field) cron expression.
+	// Example: "0 22 * * 1-5" (weekdays at 22:00).
+	Start string `json:"start"`
+	// Duration of the window. Example: "6h".
+	Duration meta.Duration `json:"duration"`
+}
+
+// Blackout period.
+type Blackout struct {
+	// Start of the blackout.
+	Start meta.Time `json:"start"`
+	// End of the blackout.
+	End meta.Time `json:"end"`
+	// Reason.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
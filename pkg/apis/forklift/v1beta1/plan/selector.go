@@ -0,0 +1,61 @@
+package plan
+
+import "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+
+// SelectorMode defines when the VMs selected by a VM selector are resolved.
+type SelectorMode string
+
+const (
+	// Resolved on each reconcile until the migration is started.
+	SelectorSync SelectorMode = "Sync"
+	// Resolved when the plan is created or updated. The resolved
+	// VMs are kept when the inventory changes.
+	SelectorFreeze SelectorMode = "Freeze"
+)
+
+// VM selector.
+// Selects the VMs of the (vSphere) source provider matching all
+// of the specified criteria. The selected VMs are migrated in
+// addition to the VMs listed on the plan. A VM both selected and
+// listed is migrated as listed.
+type VMSelector struct {
+	// Inventory path of a folder, cluster or host.
+	// Selects the VMs within the folder (and its sub-folders)
+	// or running on the hosts of the cluster or on the host.
+	// Examples: "/DC0/vm/prod", "/DC0/host/cluster0".
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Regular expression matched against the VM name.
+	// Example: "^web-[0-9]+$".
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Power state. Example: "poweredOn".
+	// +optional
+	PowerState string `json:"powerState,omitempty"`
+	// Guest OS identifier or full name. Example: "rhel8_64Guest".
+	// +optional
+	GuestOS string `json:"guestOS,omitempty"`
+	// Tags (category:name) attached to the VM.
+	// All of the tags must be attached.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+	// ID or name of a datastore backing a disk of the VM.
+	// +optional
+	Datastore string `json:"datastore,omitempty"`
+	// When the selected VMs are resolved.
+	// - "Sync" (default): On each reconcile until the migration is started.
+	// - "Freeze": When the plan is created or updated.
+	// The selected VMs are frozen once the migration is started.
+	// +optional
+	// +kubebuilder:validation:Enum=Sync;Freeze
+	Mode SelectorMode `json:"mode,omitempty"`
+}
+
+// Status of the VM selector.
+type SelectorStatus struct {
+	// The plan generation the VMs were resolved for.
+	Generation int64 `json:"generation,omitempty"`
+	// The selected VMs.
+	// +optional
+	VMs []ref.Ref `json:"vms,omitempty"`
+}
@@ -38,6 +38,9 @@ type Snapshot struct {
 	Map SnapshotMap `json:"map"`
 	// Migration
 	Migration SnapshotRef `json:"migration"`
+	// Failed VMs retried by the migration.
+	// +optional
+	Retried []RetriedVM `json:"retried,omitempty"`
 }
 
 // Populate the ref using the specified (meta) object.
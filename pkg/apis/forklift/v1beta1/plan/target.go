@@ -0,0 +1,82 @@
+package plan
+
+import (
+	"time"
+
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Target validation failure policies.
+const (
+	// The failed checks are reported as a warning.
+	TargetValidationWarn = "Warn"
+	// The VM migration fails.
+	TargetValidationFail = "Fail"
+	// The VM migration fails and is rolled back.
+	TargetValidationRollback = "Rollback"
+)
+
+// Target validation defaults.
+const (
+	DefaultTargetValidationTimeout = 10 * time.Minute
+)
+
+// Post-migration validation of the target VM.
+// The VM is validated once it is running on the destination,
+// only when the target power state allows it to be powered on.
+type TargetValidation struct {
+	// Time to wait for the VM to become ready, the guest agent
+	// to connect and the checks to pass. Defaults to 10m.
+	// +optional
+	Timeout *meta.Duration `json:"timeout,omitempty"`
+	// Check the IPs reported by the guest agent include the
+	// IPs reported by the source guest (vSphere only).
+	// +optional
+	ExpectedIPs bool `json:"expectedIPs,omitempty"`
+	// TCP ports probed on the VM.
+	// +optional
+	Ports []int32 `json:"ports,omitempty"`
+	// HTTP endpoint probed on the VM.
+	// +optional
+	HTTP *HTTPProbe `json:"http,omitempty"`
+	// Action taken when a check fails.
+	// Defaults to Warn.
+	// +optional
+	// +kubebuilder:validation:Enum=Warn;Fail;Rollback
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+}
+
+// HTTP probe.
+type HTTPProbe struct {
+	// Port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+	// Path. Defaults to "/".
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Scheme. Defaults to HTTP.
+	// +optional
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	Scheme string `json:"scheme,omitempty"`
+	// Expected status codes.
+	// Any status from 200 to 399 is expected when not set.
+	// +optional
+	ExpectedStatus []int `json:"expectedStatus,omitempty"`
+}
+
+// Time to wait for the checks to pass.
+func (r *TargetValidation) TimeoutDuration() time.Duration {
+	if r.Timeout != nil && r.Timeout.Duration > 0 {
+		return r.Timeout.Duration
+	}
+	return DefaultTargetValidationTimeout
+}
+
+// Action taken when a check fails.
+func (r *TargetValidation) Policy() string {
+	if r.FailurePolicy == "" {
+		return TargetValidationWarn
+	}
+	return r.FailurePolicy
+}
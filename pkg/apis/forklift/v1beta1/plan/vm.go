@@ -3,6 +3,7 @@ package plan
 import (
 	"fmt"
 	"path"
+	"time"
 
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
@@ -28,6 +29,20 @@ const (
 	TargetPowerStateAuto TargetPowerState = "auto"
 )
 
+// Ordering defines the order in which the VMs of a plan are started.
+type Ordering string
+
+const (
+	// Higher priority first, then the order of the VM list.
+	OrderPriority Ordering = "priority"
+	// Smallest total disk size first.
+	OrderSmallestFirst Ordering = "smallestFirst"
+	// Largest total disk size first.
+	OrderLargestFirst Ordering = "largestFirst"
+	// The order of the VM list.
+	OrderSource Ordering = "source"
+)
+
 func (r *HookRef) String() string {
 	return fmt.Sprintf(
 		"%s @%s",
@@ -53,9 +68,18 @@ type VM struct {
 	// Choose the primary disk the VM boots from
 	// +optional
 	RootDisk string `json:"rootDisk,omitempty"`
+	// Select the disks that are migrated.
+	// All disks are migrated by default. The root disk
+	// cannot be excluded.
+	// +optional
+	Disks *DiskSelector `json:"disks,omitempty"`
 	// Selected InstanceType that will override the VM properties.
 	// +optional
 	InstanceType string `json:"instanceType,omitempty"`
+	// Hardware overrides of the target VM.
+	// Override the plan hardware overrides.
+	// +optional
+	Hardware *Hardware `json:"hardware,omitempty"`
 	// PVCNameTemplate is a template for generating PVC names for VM disks.
 	// Generated names must be valid DNS-1123 labels (lowercase alphanumerics, '-' allowed, max 63 chars).
 	// It follows Go template syntax and has access to the following variables:
@@ -126,6 +150,21 @@ type VM struct {
 	//
 	// +optional
 	DeleteVmOnFailMigration bool `json:"deleteVmOnFailMigration,omitempty"`
+	// RollbackOnFailure controls whether the migration of the VM is rolled back
+	// when it fails (or is canceled) after the source VM has been powered off.
+	//
+	// Note: If the Plan-level option is set to true, the VM-level option will be ignored.
+	//
+	// +optional
+	RollbackOnFailure bool `json:"rollbackOnFailure,omitempty"`
+	// Name of the migration wave the VM belongs to.
+	// +optional
+	Wave string `json:"wave,omitempty"`
+	// Migration priority.
+	// VMs with a higher priority are started first. The priority
+	// also applies across the plans sharing the source provider.
+	// +optional
+	Priority int `json:"priority,omitempty"`
 }
 
 // Find a Hook for the specified step.
@@ -141,6 +180,11 @@ func (r *VM) FindHook(step string) (ref HookRef, found bool) {
 	return
 }
 
+// Determine if the disk is selected for migration.
+func (r *VM) DiskSelected(disk SourceDisk) bool {
+	return r.Disks == nil || r.Disks.Selected(disk)
+}
+
 // VM Status
 type VMStatus struct {
 	Timed `json:",inline"`
@@ -173,6 +217,36 @@ type Warm struct {
 	ConsecutiveFailures int        `json:"consecutiveFailures"`
 	NextPrecopyAt       *meta.Time `json:"nextPrecopyAt,omitempty"`
 	Precopies           []Precopy  `json:"precopies,omitempty"`
+	// Snapshot time of the last successful precopy. The
+	// destination disks are consistent with the source as of this time.
+	RecoveryPoint *meta.Time `json:"recoveryPoint,omitempty"`
+	// Recovery point objective.
+	// Time since the recovery point, in minutes precision.
+	RPO *meta.Duration `json:"rpo,omitempty"`
+	// Number of precopies removed from the history.
+	Compacted int `json:"compacted,omitempty"`
+}
+
+// Compact the precopy history.
+// Only the most recent precopies are retained.
+func (r *Warm) Compact(retained int) {
+	retained = max(retained, 1)
+	n := len(r.Precopies) - retained
+	if n <= 0 {
+		return
+	}
+	r.Precopies = append([]Precopy{}, r.Precopies[n:]...)
+	r.Compacted += n
+}
+
+// Update the RPO.
+func (r *Warm) UpdateRPO(now time.Time) {
+	if r.RecoveryPoint == nil {
+		return
+	}
+	r.RPO = &meta.Duration{
+		Duration: now.Sub(r.RecoveryPoint.Time).Round(time.Minute),
+	}
 }
 
 type VMPowerState string
@@ -0,0 +1,73 @@
+package plan
+
+import meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// Wave phases.
+const (
+	// Waiting for the waves it depends on.
+	WavePending = "Pending"
+	// The VMs may be started.
+	WaveRunning = "Running"
+	// Waiting for the migrated VMs to become healthy.
+	WaveVerifying = "Verifying"
+	WaveSucceeded = "Succeeded"
+	WaveFailed    = "Failed"
+	// A wave it depends on has failed.
+	WaveBlocked = "Blocked"
+)
+
+// Migration wave.
+// An ordered group of VMs.
+type Wave struct {
+	// Name.
+	Name string `json:"name"`
+	// Waves that must succeed before the
+	// VMs in this wave are started.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// Maximum number of VMs in the wave migrated at once.
+	// The provider limits still apply. Zero means unlimited.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxInFlight int `json:"maxInFlight,omitempty"`
+	// Wait until the migrated VMs are healthy (ready) on the
+	// destination before the wave is considered succeeded.
+	// +optional
+	WaitUntilHealthy bool `json:"waitUntilHealthy,omitempty"`
+	// Time to wait for the migrated VMs to become healthy.
+	// Defaults to 30m.
+	// +optional
+	HealthTimeout *meta.Duration `json:"healthTimeout,omitempty"`
+}
+
+// Wave status.
+type WaveStatus struct {
+	Timed `json:",inline"`
+	// Name.
+	Name string `json:"name"`
+	// Phase.
+	Phase string `json:"phase"`
+	// Explains the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Number of VMs in the wave.
+	VMs int `json:"vms"`
+	// Number of VMs being migrated.
+	Running int `json:"running"`
+	// Number of VMs migrated successfully.
+	Succeeded int `json:"succeeded"`
+	// Number of VMs that failed to migrate.
+	Failed int `json:"failed"`
+	// Number of VMs canceled.
+	Canceled int `json:"canceled"`
+}
+
+// The wave has finished.
+func (r *WaveStatus) Done() bool {
+	switch r.Phase {
+	case WaveSucceeded, WaveFailed, WaveBlocked:
+		return true
+	default:
+		return false
+	}
+}
@@ -40,6 +40,8 @@ const (
 	OpenStack ProviderType = "openstack"
 	// OVA
 	Ova ProviderType = "ova"
+	// Hyper-V
+	HyperV ProviderType = "hyperv"
 )
 
 var ProviderTypes = []ProviderType{
@@ -48,6 +50,7 @@ var ProviderTypes = []ProviderType{
 	OVirt,
 	OpenStack,
 	Ova,
+	HyperV,
 }
 
 func (t ProviderType) String() string {
@@ -171,7 +174,13 @@ func (p *Provider) HasReconciled() bool {
 
 // This provider requires VM guest conversion.
 func (p *Provider) RequiresConversion() bool {
-	return p.Type() == VSphere || p.Type() == Ova
+	return p.Type() == VSphere || p.Type() == Ova || p.Type() == HyperV
+}
+
+// This provider serves the inventory from exported
+// appliances on a share (OVA/Hyper-V).
+func (p *Provider) UsesApplianceServer() bool {
+	return p.Type() == Ova || p.Type() == HyperV
 }
 
 // This provider support the vddk aio parameters.
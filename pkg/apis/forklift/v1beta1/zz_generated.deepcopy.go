@@ -326,6 +326,11 @@ func (in *MigrationSpec) DeepCopyInto(out *MigrationSpec) {
 		*out = make([]ref.Ref, len(*in))
 		copy(*out, *in)
 	}
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = make([]ref.Ref, len(*in))
+		copy(*out, *in)
+	}
 	if in.Cutover != nil {
 		in, out := &in.Cutover, &out.Cutover
 		*out = (*in).DeepCopy()
@@ -358,6 +363,10 @@ func (in *MigrationStatus) DeepCopyInto(out *MigrationStatus) {
 			}
 		}
 	}
+	if in.NextStart != nil {
+		in, out := &in.NextStart, &out.NextStart
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStatus.
@@ -912,16 +921,53 @@ func (in *PlanSpec) DeepCopyInto(out *PlanSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.VMSelector != nil {
+		in, out := &in.VMSelector, &out.VMSelector
+		*out = new(plan.VMSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TransferNetwork != nil {
 		in, out := &in.TransferNetwork, &out.TransferNetwork
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.Hardware != nil {
+		in, out := &in.Hardware, &out.Hardware
+		*out = new(plan.Hardware)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InstallLegacyDrivers != nil {
 		in, out := &in.InstallLegacyDrivers, &out.InstallLegacyDrivers
 		*out = new(bool)
 		**out = **in
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(plan.Schedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]plan.Wave, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Replication != nil {
+		in, out := &in.Replication, &out.Replication
+		*out = new(plan.Replication)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetValidation != nil {
+		in, out := &in.TargetValidation, &out.TargetValidation
+		*out = new(plan.TargetValidation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TargetMetadata != nil {
+		in, out := &in.TargetMetadata, &out.TargetMetadata
+		*out = make([]plan.MetadataMapping, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanSpec.
@@ -939,6 +985,11 @@ func (in *PlanStatus) DeepCopyInto(out *PlanStatus) {
 	*out = *in
 	in.Conditions.DeepCopyInto(&out.Conditions)
 	in.Migration.DeepCopyInto(&out.Migration)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(plan.SelectorStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PlanStatus.
@@ -1059,6 +1110,21 @@ func (in *ProviderStatus) DeepCopy() *ProviderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageLimits) DeepCopyInto(out *StorageLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageLimits.
+func (in *StorageLimits) DeepCopy() *StorageLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageMap) DeepCopyInto(out *StorageMap) {
 	*out = *in
@@ -1152,6 +1218,11 @@ func (in *StoragePair) DeepCopyInto(out *StoragePair) {
 		*out = new(OffloadPlugin)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(StorageLimits)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StoragePair.
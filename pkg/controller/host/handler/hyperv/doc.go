@@ -0,0 +1,22 @@
+package hyperv
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/watch/handler"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+// Handler factory.
+func New(
+	client client.Client,
+	channel chan event.GenericEvent,
+	provider *api.Provider) (h *Handler, err error) {
+	//
+	b, err := handler.New(client, channel, provider)
+	if err != nil {
+		return
+	}
+	h = &Handler{Handler: b}
+	return
+}
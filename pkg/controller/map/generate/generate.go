@@ -0,0 +1,316 @@
+package generate
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Destination network types.
+const (
+	Pod     = "pod"
+	Multus  = "multus"
+	Ignored = "ignored"
+)
+
+// Rules used to propose the mappings.
+// The rules are evaluated in order and the first
+// matching rule is used.
+type Rules struct {
+	// Network rules.
+	Networks []NetworkRule `json:"networks,omitempty"`
+	// Storage rules.
+	Storage []StorageRule `json:"storage,omitempty"`
+}
+
+// Validate the rules.
+func (r *Rules) Validate() (err error) {
+	for i := range r.Networks {
+		rule := &r.Networks[i]
+		_, err = regexp.Compile(rule.Name)
+		if err != nil {
+			err = liberr.Wrap(err, "rule", "networks", "index", i)
+			return
+		}
+		switch rule.Destination.Type {
+		case "", Pod, Multus, Ignored:
+		default:
+			err = liberr.New(
+				"destination type not valid",
+				"rule",
+				"networks",
+				"index",
+				i,
+				"type",
+				rule.Destination.Type)
+			return
+		}
+	}
+	for i := range r.Storage {
+		rule := &r.Storage[i]
+		for _, expr := range []string{rule.Name, rule.BackingDevice} {
+			_, err = regexp.Compile(expr)
+			if err != nil {
+				err = liberr.Wrap(err, "rule", "storage", "index", i)
+				return
+			}
+		}
+		if rule.Destination.StorageClass == "" {
+			err = liberr.New(
+				"destination storage class not specified",
+				"rule",
+				"storage",
+				"index",
+				i)
+			return
+		}
+	}
+
+	return
+}
+
+// Network rule.
+// All specified criteria must match the source network. A rule
+// without criteria matches all networks.
+type NetworkRule struct {
+	// Regex matched against the source network name.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// VLAN ID of the source network.
+	// +optional
+	VLAN string `json:"vlan,omitempty"`
+	// Annotation of the network attachment definitions with the VLAN ID.
+	// The network is mapped to the NAD annotated with the VLAN ID of the
+	// source network. The NAD is searched in the destination namespace
+	// when specified.
+	// +optional
+	VLANAnnotation string `json:"vlanAnnotation,omitempty"`
+	// Destination network. The name may reference the groups
+	// of the name regex, e.g. `$1`.
+	// +optional
+	Destination api.DestinationNetwork `json:"destination,omitempty"`
+}
+
+// Storage rule.
+// All specified criteria must match the source storage. A rule
+// without criteria matches all storage.
+type StorageRule struct {
+	// Regex matched against the source storage name: vSphere datastore,
+	// oVirt storage domain or OpenStack volume type.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Type of the source storage: vSphere datastore type (VMFS, NFS, vsan, ...)
+	// or oVirt storage type (nfs, iscsi, fcp, ...). Case insensitive.
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Regex matched against the backing devices of the source datastore.
+	// +optional
+	BackingDevice string `json:"backingDevice,omitempty"`
+	// Destination storage.
+	Destination api.DestinationStorage `json:"destination"`
+}
+
+// Source network.
+type Network struct {
+	ref.Ref
+	// VLAN ID.
+	VLAN string
+}
+
+// Source storage.
+type Storage struct {
+	ref.Ref
+	// Storage type.
+	Type string
+	// Backing devices.
+	BackingDevices []string
+}
+
+// Network attachment definition on the destination.
+type NAD struct {
+	Namespace   string
+	Name        string
+	Annotations map[string]string
+}
+
+// Destination inventory.
+// Used to propose only existing destinations.
+type Destination struct {
+	// Network attachment definitions.
+	NADs []NAD
+	// Storage class names.
+	StorageClasses []string
+}
+
+// Source not matched by any rule.
+type Unmapped struct {
+	// Networks.
+	Networks []ref.Ref `json:"networks,omitempty"`
+	// Storage.
+	Storage []ref.Ref `json:"storage,omitempty"`
+}
+
+// Map generator.
+type Generator struct {
+	// Rules.
+	Rules Rules
+	// Destination inventory.
+	Destination Destination
+}
+
+// Propose the network mappings.
+// The rules must be valid.
+func (r *Generator) Networks(networks []Network) (mapped []api.NetworkPair, unmapped []ref.Ref) {
+	sort.Slice(networks, func(i, j int) bool {
+		return networks[i].ID < networks[j].ID
+	})
+	for _, network := range networks {
+		matched := false
+		for i := range r.Rules.Networks {
+			destination, found := r.network(&r.Rules.Networks[i], &network)
+			if found {
+				mapped = append(
+					mapped,
+					api.NetworkPair{
+						Source:      r.source(network.Ref),
+						Destination: destination,
+					})
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unmapped = append(unmapped, network.Ref)
+		}
+	}
+
+	return
+}
+
+// Propose the storage mappings.
+// The rules must be valid.
+func (r *Generator) Storage(storage []Storage) (mapped []api.StoragePair, unmapped []ref.Ref) {
+	sort.Slice(storage, func(i, j int) bool {
+		return storage[i].ID+storage[i].Name < storage[j].ID+storage[j].Name
+	})
+	for _, ds := range storage {
+		matched := false
+		for i := range r.Rules.Storage {
+			rule := &r.Rules.Storage[i]
+			if r.storage(rule, &ds) {
+				mapped = append(
+					mapped,
+					api.StoragePair{
+						Source:      r.source(ds.Ref),
+						Destination: rule.Destination,
+					})
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			unmapped = append(unmapped, ds.Ref)
+		}
+	}
+
+	return
+}
+
+// Match the network rule.
+// Returns the destination when matched.
+func (r *Generator) network(rule *NetworkRule, network *Network) (destination api.DestinationNetwork, matched bool) {
+	expr := regexp.MustCompile(rule.Name)
+	groups := expr.FindStringSubmatchIndex(network.Name)
+	if groups == nil {
+		return
+	}
+	if rule.VLAN != "" && rule.VLAN != network.VLAN {
+		return
+	}
+	destination = rule.Destination
+	if destination.Type == "" {
+		destination.Type = Multus
+	}
+	if destination.Name != "" {
+		destination.Name = string(expr.ExpandString(nil, destination.Name, network.Name, groups))
+	}
+	if rule.VLANAnnotation != "" {
+		if network.VLAN == "" {
+			return
+		}
+		for _, nad := range r.Destination.NADs {
+			if destination.Namespace != "" && destination.Namespace != nad.Namespace {
+				continue
+			}
+			if nad.Annotations[rule.VLANAnnotation] == network.VLAN {
+				destination.Type = Multus
+				destination.Namespace = nad.Namespace
+				destination.Name = nad.Name
+				matched = true
+				return
+			}
+		}
+		return
+	}
+	switch destination.Type {
+	case Multus:
+		for _, nad := range r.Destination.NADs {
+			if nad.Namespace == destination.Namespace && nad.Name == destination.Name {
+				matched = true
+				return
+			}
+		}
+	default:
+		destination.Namespace = ""
+		destination.Name = ""
+		matched = true
+	}
+
+	return
+}
+
+// Match the storage rule.
+func (r *Generator) storage(rule *StorageRule, storage *Storage) (matched bool) {
+	if !regexp.MustCompile(rule.Name).MatchString(storage.Name) {
+		return
+	}
+	if rule.Type != "" && !strings.EqualFold(rule.Type, storage.Type) {
+		return
+	}
+	if rule.BackingDevice != "" {
+		expr := regexp.MustCompile(rule.BackingDevice)
+		found := false
+		for _, device := range storage.BackingDevices {
+			if expr.MatchString(device) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return
+		}
+	}
+	for _, name := range r.Destination.StorageClasses {
+		if name == rule.Destination.StorageClass {
+			matched = true
+			break
+		}
+	}
+
+	return
+}
+
+// Source reference used in the map.
+// Referenced by ID when known.
+func (r *Generator) source(in ref.Ref) (out ref.Ref) {
+	if in.ID != "" {
+		out.ID = in.ID
+	} else {
+		out.Name = in.Name
+	}
+	return
+}
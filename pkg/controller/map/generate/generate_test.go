@@ -0,0 +1,160 @@
+package generate
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/onsi/gomega"
+)
+
+func destination() Destination {
+	return Destination{
+		NADs: []NAD{
+			{Namespace: "net", Name: "prod"},
+			{Namespace: "net", Name: "vlan-10", Annotations: map[string]string{"vlan": "10"}},
+			{Namespace: "other", Name: "vlan-20", Annotations: map[string]string{"vlan": "20"}},
+		},
+		StorageClasses: []string{"ceph-rbd", "nfs-csi"},
+	}
+}
+
+func TestNetworks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	generator := &Generator{
+		Rules: Rules{
+			Networks: []NetworkRule{
+				{VLANAnnotation: "vlan"},
+				{
+					Name: "^(prod)-.*$",
+					Destination: api.DestinationNetwork{
+						Type:      Multus,
+						Namespace: "net",
+						Name:      "$1",
+					},
+				},
+				{
+					Name:        "^mgmt$",
+					Destination: api.DestinationNetwork{Type: Pod},
+				},
+			},
+		},
+		Destination: destination(),
+	}
+	mapped, unmapped := generator.Networks(
+		[]Network{
+			{Ref: ref.Ref{ID: "n1", Name: "dmz"}, VLAN: "10"},
+			{Ref: ref.Ref{ID: "n2", Name: "prod-a"}},
+			{Ref: ref.Ref{ID: "n3", Name: "mgmt"}, VLAN: "30"},
+			{Ref: ref.Ref{ID: "n4", Name: "test-a"}},
+			{Ref: ref.Ref{ID: "n5", Name: "vlan-20"}, VLAN: "20"},
+		})
+	g.Expect(mapped).To(gomega.Equal([]api.NetworkPair{
+		{
+			Source:      ref.Ref{ID: "n1"},
+			Destination: api.DestinationNetwork{Type: Multus, Namespace: "net", Name: "vlan-10"},
+		},
+		{
+			Source:      ref.Ref{ID: "n2"},
+			Destination: api.DestinationNetwork{Type: Multus, Namespace: "net", Name: "prod"},
+		},
+		{
+			Source:      ref.Ref{ID: "n3"},
+			Destination: api.DestinationNetwork{Type: Pod},
+		},
+		{
+			Source:      ref.Ref{ID: "n5"},
+			Destination: api.DestinationNetwork{Type: Multus, Namespace: "other", Name: "vlan-20"},
+		},
+	}))
+	g.Expect(unmapped).To(gomega.Equal([]ref.Ref{{ID: "n4", Name: "test-a"}}))
+}
+
+func TestNetworkNotFound(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	generator := &Generator{
+		Rules: Rules{
+			Networks: []NetworkRule{
+				{
+					Name: ".*",
+					Destination: api.DestinationNetwork{
+						Namespace: "net",
+						Name:      "missing",
+					},
+				},
+				{
+					VLANAnnotation: "vlan",
+					Destination:    api.DestinationNetwork{Namespace: "net"},
+				},
+			},
+		},
+		Destination: destination(),
+	}
+	mapped, unmapped := generator.Networks(
+		[]Network{
+			{Ref: ref.Ref{ID: "n1", Name: "a"}, VLAN: "20"},
+		})
+	g.Expect(mapped).To(gomega.BeEmpty())
+	g.Expect(unmapped).To(gomega.HaveLen(1))
+}
+
+func TestStorage(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	generator := &Generator{
+		Rules: Rules{
+			Storage: []StorageRule{
+				{
+					BackingDevice: "^naa\\.6000",
+					Destination:   api.DestinationStorage{StorageClass: "ceph-rbd"},
+				},
+				{
+					Type:        "nfs",
+					Destination: api.DestinationStorage{StorageClass: "nfs-csi"},
+				},
+				{
+					Name:        "^glance$",
+					Destination: api.DestinationStorage{StorageClass: "missing"},
+				},
+			},
+		},
+		Destination: destination(),
+	}
+	mapped, unmapped := generator.Storage(
+		[]Storage{
+			{Ref: ref.Ref{ID: "ds1", Name: "san"}, Type: "VMFS", BackingDevices: []string{"naa.60001"}},
+			{Ref: ref.Ref{ID: "ds2", Name: "nas"}, Type: "NFS"},
+			{Ref: ref.Ref{Name: "glance"}},
+		})
+	g.Expect(mapped).To(gomega.Equal([]api.StoragePair{
+		{
+			Source:      ref.Ref{ID: "ds1"},
+			Destination: api.DestinationStorage{StorageClass: "ceph-rbd"},
+		},
+		{
+			Source:      ref.Ref{ID: "ds2"},
+			Destination: api.DestinationStorage{StorageClass: "nfs-csi"},
+		},
+	}))
+	g.Expect(unmapped).To(gomega.Equal([]ref.Ref{{Name: "glance"}}))
+}
+
+func TestValidate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	rules := Rules{
+		Networks: []NetworkRule{{Name: "("}},
+	}
+	g.Expect(rules.Validate()).ToNot(gomega.Succeed())
+	rules = Rules{
+		Networks: []NetworkRule{{Destination: api.DestinationNetwork{Type: "bridge"}}},
+	}
+	g.Expect(rules.Validate()).ToNot(gomega.Succeed())
+	rules = Rules{
+		Storage: []StorageRule{{Name: "ds"}},
+	}
+	g.Expect(rules.Validate()).ToNot(gomega.Succeed())
+	rules = Rules{
+		Networks: []NetworkRule{{Name: "^vm-(.*)$"}},
+		Storage:  []StorageRule{{Type: "nfs", Destination: api.DestinationStorage{StorageClass: "nfs-csi"}}},
+	}
+	g.Expect(rules.Validate()).To(gomega.Succeed())
+}
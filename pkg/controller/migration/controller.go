@@ -227,6 +227,10 @@ func (r *Reconciler) reflectPlan(plan *api.Plan, migration *api.Migration) {
 			Durable:  true,
 		})
 	}
+	if cnd := snapshot.FindCondition(api.ConditionWaiting); cnd != nil {
+		migration.Status.SetCondition(*cnd)
+	}
+	migration.Status.NextStart = plan.Status.Migration.NextStart
 	migration.Status.VMs = plan.Status.Migration.VMs
 }
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	plancnt "github.com/kubev2v/forklift/pkg/controller/plan"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
@@ -124,7 +125,9 @@ func (r *Reconciler) validate(migration *api.Migration) (plan *api.Plan, err err
 	if err != nil {
 		return
 	}
-	for _, ref := range migration.Spec.Cancel {
+	refs := append([]refapi.Ref{}, migration.Spec.Cancel...)
+	refs = append(refs, migration.Spec.Pause...)
+	for _, ref := range refs {
 		_, err = inventory.VM(&ref)
 		if err != nil {
 			if errors.As(err, &web.NotFoundError{}) {
@@ -3,7 +3,6 @@ package ova
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/labeler"
@@ -41,6 +40,7 @@ const (
 	ProviderNamespace  = "PROVIDER_NAMESPACE"
 	ProviderName       = "PROVIDER_NAME"
 	CatalogPath        = "CATALOG_PATH"
+	ProviderType       = "PROVIDER_TYPE"
 	ApplianceEndpoints = "APPLIANCE_ENDPOINTS"
 	AuthRequired       = "AUTH_REQUIRED"
 )
@@ -97,7 +97,7 @@ func (r *Builder) ProviderServer(provider *api.Provider) (server *api.OVAProvide
 }
 
 func (r *Builder) PersistentVolume(provider *api.Provider) (pv *core.PersistentVolume) {
-	segments := strings.Split(provider.Spec.URL, ":")
+	share := Share{URL: provider.Spec.URL}
 	pv = &core.PersistentVolume{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: r.prefix(provider),
@@ -110,12 +110,8 @@ func (r *Builder) PersistentVolume(provider *api.Provider) (pv *core.PersistentV
 			AccessModes: []core.PersistentVolumeAccessMode{
 				core.ReadOnlyMany,
 			},
-			PersistentVolumeSource: core.PersistentVolumeSource{
-				NFS: &core.NFSVolumeSource{
-					Server: segments[0],
-					Path:   segments[1],
-				},
-			},
+			PersistentVolumeSource:        share.VolumeSource(provider, r.volumeHandle(provider)),
+			MountOptions:                  share.MountOptions(),
 			PersistentVolumeReclaimPolicy: core.PersistentVolumeReclaimRetain,
 		},
 	}
@@ -210,6 +206,10 @@ func (r *Builder) PodSpec(provider *api.Provider, pvc *core.PersistentVolumeClai
 						Name:  CatalogPath,
 						Value: r.nfsMountPath(),
 					},
+					{
+						Name:  ProviderType,
+						Value: provider.Type().String(),
+					},
 					{
 						Name:  ApplianceEndpoints,
 						Value: r.applianceEndpoints(provider),
@@ -283,6 +283,11 @@ func (r *Builder) containerImage() string {
 	return Settings.Providers.OVA.Pod.ContainerImage
 }
 
+// Unique (CSI) volume handle for the server PV.
+func (r *Builder) volumeHandle(provider *api.Provider) string {
+	return fmt.Sprintf("%s-%s", provider.UID, r.OVAProviderServer.UID)
+}
+
 func (r *Builder) nfsMountPath() string {
 	return NFSVolumeMountPath
 }
@@ -0,0 +1,94 @@
+package ova
+
+import (
+	"regexp"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	core "k8s.io/api/core/v1"
+)
+
+// SMB CSI driver.
+const (
+	SMBDriver       = "smb.csi.k8s.io"
+	SMBSourceAttr   = "source"
+	SMBScheme       = "smb://"
+	SMBUNCPrefix    = "//"
+	SMBMountOptions = "dir_mode=0555,file_mode=0444"
+)
+
+var (
+	nfsRegex = regexp.MustCompile(`^[^:/]+:\/[^:].*$`)
+	smbRegex = regexp.MustCompile(`^(smb:)?\/\/[^/]+\/[^/].*$`)
+)
+
+// Share referenced by the provider URL.
+// Supported formats:
+//
+//	NFS: <server>:/<path>
+//	SMB: smb://<server>/<share>[/<path>] or //<server>/<share>[/<path>]
+//
+// SMB shares are mounted using the SMB CSI driver with
+// the provider secret (username, password) used to
+// stage the volume.
+type Share struct {
+	URL string
+}
+
+// The URL references an NFS export.
+func (r Share) IsNFS() bool {
+	return !r.IsSMB() && nfsRegex.MatchString(r.URL)
+}
+
+// The URL references an SMB share.
+func (r Share) IsSMB() bool {
+	return smbRegex.MatchString(r.URL)
+}
+
+// The URL is valid.
+func (r Share) Valid() bool {
+	return r.IsNFS() || r.IsSMB()
+}
+
+// UNC (//server/share) path of an SMB share.
+func (r Share) UNC() string {
+	return SMBUNCPrefix + strings.TrimPrefix(
+		strings.TrimPrefix(r.URL, SMBScheme),
+		SMBUNCPrefix)
+}
+
+// Persistent volume source for the share.
+// The handle must be unique per PV.
+func (r Share) VolumeSource(provider *api.Provider, handle string) (source core.PersistentVolumeSource) {
+	if r.IsSMB() {
+		source.CSI = &core.CSIPersistentVolumeSource{
+			Driver:       SMBDriver,
+			VolumeHandle: handle,
+			ReadOnly:     true,
+			VolumeAttributes: map[string]string{
+				SMBSourceAttr: r.UNC(),
+			},
+			NodeStageSecretRef: &core.SecretReference{
+				Name:      provider.Spec.Secret.Name,
+				Namespace: provider.Spec.Secret.Namespace,
+			},
+		}
+		return
+	}
+	segments := strings.SplitN(r.URL, ":", 2)
+	source.NFS = &core.NFSVolumeSource{
+		Server: segments[0],
+	}
+	if len(segments) > 1 {
+		source.NFS.Path = segments[1]
+	}
+	return
+}
+
+// Mount options for the share.
+func (r Share) MountOptions() (options []string) {
+	if r.IsSMB() {
+		options = strings.Split(SMBMountOptions, ",")
+	}
+	return
+}
@@ -0,0 +1,63 @@
+package base
+
+import (
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/plan/util"
+)
+
+// Select the disks of the plan VM that are migrated.
+// The disks are described in source order. Returns the keys
+// of the selected disks. All disks are selected when the VM
+// is not on the plan.
+func SelectDisks(vm *planapi.VM, disks []planapi.SourceDisk) (selected map[string]bool) {
+	selected = make(map[string]bool)
+	for _, disk := range disks {
+		if vm == nil || vm.DiskSelected(disk) {
+			selected[disk.Key] = true
+		}
+	}
+
+	return
+}
+
+// Root disk of the plan VM relative to the selected disks.
+// The RootDisk is the device of the root disk on the source VM,
+// e.g. /dev/sdc. The device is shifted by the number of disks
+// preceding it that are not selected.
+func SelectedRootDisk(vm *planapi.VM, disks []planapi.SourceDisk) string {
+	if vm == nil || vm.RootDisk == "" {
+		return ""
+	}
+	root := util.GetBootDiskNumber(vm.RootDisk)
+	shift := 0
+	for _, disk := range disks {
+		if disk.Index < root && !vm.DiskSelected(disk) {
+			shift++
+		}
+	}
+
+	return util.ShiftDevice(vm.RootDisk, -shift)
+}
+
+// Validate the disk selection of the plan VM.
+// At least one disk must be selected and the root disk, when
+// specified, cannot be excluded.
+func ValidDiskSelection(vm *planapi.VM, disks []planapi.SourceDisk) bool {
+	if vm == nil || vm.Disks == nil || len(disks) == 0 {
+		return true
+	}
+	root := -1
+	if vm.RootDisk != "" {
+		root = util.GetBootDiskNumber(vm.RootDisk)
+	}
+	selected := false
+	for _, disk := range disks {
+		if vm.DiskSelected(disk) {
+			selected = true
+		} else if disk.Index == root {
+			return false
+		}
+	}
+
+	return selected
+}
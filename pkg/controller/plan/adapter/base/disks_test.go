@@ -0,0 +1,138 @@
+package base
+
+import (
+	"testing"
+
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+)
+
+func testDisks() []planapi.SourceDisk {
+	return []planapi.SourceDisk{
+		{Key: "2000", File: "[ds1] vm/vm.vmdk", Label: "Hard disk 1", Bus: "scsi", Index: 0},
+		{Key: "2001", File: "[ds1] vm/vm_1.vmdk", Label: "Hard disk 2", Bus: "scsi", Index: 1},
+		{Key: "3000", File: "[ds2] vm/vm_2.vmdk", Label: "Hard disk 3", Bus: "sata", Index: 2},
+	}
+}
+
+func TestSelectDisks(t *testing.T) {
+	index := 1
+	tests := []struct {
+		name     string
+		disks    *planapi.DiskSelector
+		expected []string
+	}{
+		{
+			name:     "all disks by default",
+			expected: []string{"2000", "2001", "3000"},
+		},
+		{
+			name: "exclude by key",
+			disks: &planapi.DiskSelector{
+				Exclude: []planapi.DiskMatch{{Key: "2001"}},
+			},
+			expected: []string{"2000", "3000"},
+		},
+		{
+			name: "include by file wildcard",
+			disks: &planapi.DiskSelector{
+				Include: []planapi.DiskMatch{{File: "[ds1] *"}},
+			},
+			expected: []string{"2000", "2001"},
+		},
+		{
+			name: "exclude takes precedence",
+			disks: &planapi.DiskSelector{
+				Include: []planapi.DiskMatch{{Bus: "SCSI"}},
+				Exclude: []planapi.DiskMatch{{Index: &index}},
+			},
+			expected: []string{"2000"},
+		},
+		{
+			name: "all fields must match",
+			disks: &planapi.DiskSelector{
+				Exclude: []planapi.DiskMatch{{Label: "Hard disk *", Bus: "sata"}},
+			},
+			expected: []string{"2000", "2001"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm := &planapi.VM{Disks: tt.disks}
+			selected := SelectDisks(vm, testDisks())
+			if len(selected) != len(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, selected)
+			}
+			for _, key := range tt.expected {
+				if !selected[key] {
+					t.Errorf("expected disk %s to be selected", key)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectedRootDisk(t *testing.T) {
+	vm := &planapi.VM{
+		RootDisk: "/dev/sdc",
+		Disks: &planapi.DiskSelector{
+			Exclude: []planapi.DiskMatch{{Key: "2001"}},
+		},
+	}
+	if rootDisk := SelectedRootDisk(vm, testDisks()); rootDisk != "/dev/sdb" {
+		t.Errorf("expected /dev/sdb, got %s", rootDisk)
+	}
+	vm.RootDisk = "/dev/sda"
+	if rootDisk := SelectedRootDisk(vm, testDisks()); rootDisk != "/dev/sda" {
+		t.Errorf("expected /dev/sda, got %s", rootDisk)
+	}
+}
+
+func TestValidDiskSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		vm       *planapi.VM
+		expected bool
+	}{
+		{
+			name:     "no selection",
+			vm:       &planapi.VM{RootDisk: "/dev/sda"},
+			expected: true,
+		},
+		{
+			name: "data disk excluded",
+			vm: &planapi.VM{
+				RootDisk: "/dev/sda",
+				Disks: &planapi.DiskSelector{
+					Exclude: []planapi.DiskMatch{{Key: "3000"}},
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "root disk excluded",
+			vm: &planapi.VM{
+				RootDisk: "/dev/sdb",
+				Disks: &planapi.DiskSelector{
+					Exclude: []planapi.DiskMatch{{Key: "2001"}},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "no disk selected",
+			vm: &planapi.VM{
+				Disks: &planapi.DiskSelector{
+					Include: []planapi.DiskMatch{{Bus: "ide"}},
+				},
+			},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ok := ValidDiskSelection(tt.vm, testDisks()); ok != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, ok)
+			}
+		})
+	}
+}
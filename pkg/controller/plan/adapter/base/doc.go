@@ -235,6 +235,8 @@ type Validator interface {
 	PVCNameTemplate(vmRef ref.Ref, pvcNameTemplate string) (bool, error)
 	// Validate guest tools installation and status (e.g., VMware Tools, VirtIO drivers).
 	GuestToolsInstalled(vmRef ref.Ref) (ok bool, err error)
+	// Validate that the disk selection keeps the root disk and at least one disk.
+	DiskSelection(vmRef ref.Ref) (ok bool, err error)
 }
 
 // DestinationClient API.
@@ -0,0 +1,533 @@
+package hyperv
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/hyperv"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/hyperv"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	"github.com/kubev2v/forklift/pkg/settings"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/api/core/v1"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+)
+
+// Firmware types
+const (
+	BIOS = "bios"
+	EFI  = "efi"
+)
+
+// Bus types
+const (
+	Virtio = "virtio"
+)
+
+// Input types
+const (
+	Tablet = "tablet"
+)
+
+// Network types
+const (
+	Pod     = "pod"
+	Multus  = "multus"
+	Ignored = "ignored"
+)
+
+// Template labels
+const (
+	TemplateOSLabel       = "os.template.kubevirt.io/%s"
+	TemplateWorkloadLabel = "workload.template.kubevirt.io/server"
+	TemplateFlavorLabel   = "flavor.template.kubevirt.io/medium"
+)
+
+// Operating Systems
+const (
+	Unknown = "unknown"
+)
+
+// Source reported to virt-v2v.
+const (
+	Source = "hyperv"
+)
+
+// Hyper-V builder.
+type Builder struct {
+	*plancontext.Context
+}
+
+// Create DataVolume certificate configmap.
+// No-op for Hyper-V.
+func (r *Builder) ConfigMap(_ ref.Ref, _ *core.Secret, _ *core.ConfigMap) (err error) {
+	return
+}
+
+// Build the virt-v2v pod environment.
+// The disks are passed as a list, root (first) disk first,
+// and are read by virt-v2v from the share mounted in the pod.
+func (r *Builder) PodEnvironment(vmRef ref.Ref, sourceSecret *core.Secret) (env []core.EnvVar, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	rootDisk := r.selectDisks(vm)
+	if planVM, found := r.Plan.Spec.FindVM(vmRef); found && rootDisk != planVM.RootDisk {
+		env = append(env, core.EnvVar{
+			Name:  "V2V_RootDisk",
+			Value: rootDisk,
+		})
+	}
+
+	env = append(
+		env,
+		core.EnvVar{
+			Name:  "V2V_vmName",
+			Value: vm.Name,
+		},
+		core.EnvVar{
+			Name:  "V2V_diskPath",
+			Value: getDiskSourcePaths(vm.Disks),
+		},
+		core.EnvVar{
+			Name:  "V2V_source",
+			Value: Source,
+		})
+
+	return
+}
+
+// Build the DataVolume credential secret.
+func (r *Builder) Secret(vmRef ref.Ref, in, object *core.Secret) (err error) {
+	return
+}
+
+// Create DataVolume specs for the VM.
+func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *core.ConfigMap, dvTemplate *cdi.DataVolume, vddkConfigMap *core.ConfigMap) (dvs []cdi.DataVolume, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	r.selectDisks(vm)
+
+	storageMapIn := r.Context.Map.Storage.Spec.Map
+	for i := range storageMapIn {
+		mapped := &storageMapIn[i]
+		ref := mapped.Source
+		storage := &model.Storage{}
+		fErr := r.Source.Inventory.Find(storage, ref)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		for _, disk := range vm.Disks {
+			if disk.ID == storage.ID {
+				dv := r.mapDataVolume(disk, mapped.Destination, dvTemplate)
+				dvs = append(dvs, *dv)
+			}
+		}
+	}
+
+	return
+}
+
+func (r *Builder) mapDataVolume(disk hyperv.Disk, destination v1beta1.DestinationStorage, dvTemplate *cdi.DataVolume) (dv *cdi.DataVolume) {
+	storageClass := destination.StorageClass
+	dvSource := cdi.DataVolumeSource{
+		Blank: &cdi.DataVolumeBlankImage{},
+	}
+	dvSpec := cdi.DataVolumeSpec{
+		Source: &dvSource,
+		Storage: &cdi.StorageSpec{
+			Resources: core.VolumeResourceRequirements{
+				Requests: core.ResourceList{
+					core.ResourceStorage: *resource.NewQuantity(disk.Capacity, resource.BinarySI),
+				},
+			},
+			StorageClassName: &storageClass,
+		},
+	}
+	// set the access mode and volume mode if they were specified in the storage map.
+	// otherwise, let the storage profile decide the default values.
+	if destination.AccessMode != "" {
+		dvSpec.Storage.AccessModes = []core.PersistentVolumeAccessMode{destination.AccessMode}
+	}
+	if destination.VolumeMode != "" {
+		dvSpec.Storage.VolumeMode = &destination.VolumeMode
+	}
+
+	dv = dvTemplate.DeepCopy()
+	dv.Spec = dvSpec
+	if dv.ObjectMeta.Annotations == nil {
+		dv.ObjectMeta.Annotations = make(map[string]string)
+	}
+	dv.ObjectMeta.Annotations[planbase.AnnDiskSource] = disk.FilePath
+	return
+}
+
+// Create the destination Kubevirt VM.
+func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec, persistentVolumeClaims []*core.PersistentVolumeClaim, usesInstanceType bool, sortVolumesByLibvirt bool) (err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	r.selectDisks(vm)
+
+	if object.Template == nil {
+		object.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
+	}
+	r.mapDisks(vm, persistentVolumeClaims, object)
+	r.mapFirmware(vm, vmRef, object)
+	r.mapInput(object)
+	if !usesInstanceType {
+		r.mapCPU(vm, object)
+		r.mapMemory(vm, object)
+	}
+	err = r.mapNetworks(vm, object)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+func (r *Builder) mapNetworks(vm *model.VM, object *cnv.VirtualMachineSpec) (err error) {
+	var kNetworks []cnv.Network
+	var kInterfaces []cnv.Interface
+
+	numNetworks := 0
+	hasUDN := r.Plan.DestinationHasUdnNetwork(r.Destination)
+	netMapIn := r.Context.Map.Network.Spec.Map
+	for i := range netMapIn {
+		mapped := &netMapIn[i]
+
+		// Skip network mappings with destination type 'Ignored'
+		if mapped.Destination.Type == Ignored {
+			continue
+		}
+
+		ref := mapped.Source
+		network := &model.Network{}
+		fErr := r.Source.Inventory.Find(network, ref)
+		if fErr != nil {
+			err = fErr
+			return
+		}
+
+		for _, nic := range vm.NICs {
+			if nic.Network != network.Name {
+				continue
+			}
+			networkName := fmt.Sprintf("net-%v", numNetworks)
+			numNetworks++
+			kNetwork := cnv.Network{
+				Name: networkName,
+			}
+			kInterface := cnv.Interface{
+				Name:  networkName,
+				Model: Virtio,
+			}
+			if !hasUDN || settings.Settings.UdnSupportsMac {
+				kInterface.MacAddress = nic.MAC
+			}
+			switch mapped.Destination.Type {
+			case Pod:
+				kNetwork.Pod = &cnv.PodNetwork{}
+				if hasUDN {
+					kInterface.Binding = &cnv.PluginBinding{
+						Name: planbase.UdnL2bridge,
+					}
+				} else {
+					kInterface.Masquerade = &cnv.InterfaceMasquerade{}
+				}
+			case Multus:
+				kNetwork.Multus = &cnv.MultusNetwork{
+					NetworkName: path.Join(mapped.Destination.Namespace, mapped.Destination.Name),
+				}
+				kInterface.Bridge = &cnv.InterfaceBridge{}
+			}
+			kNetworks = append(kNetworks, kNetwork)
+			kInterfaces = append(kInterfaces, kInterface)
+		}
+	}
+	object.Template.Spec.Networks = kNetworks
+	object.Template.Spec.Domain.Devices.Interfaces = kInterfaces
+	return
+}
+
+func (r *Builder) mapInput(object *cnv.VirtualMachineSpec) {
+	tablet := cnv.Input{
+		Type: Tablet,
+		Name: Tablet,
+		Bus:  Virtio,
+	}
+	object.Template.Spec.Domain.Devices.Inputs = []cnv.Input{tablet}
+}
+
+// Hyper-V reports the (startup) memory in MB.
+func (r *Builder) mapMemory(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	reservation := resource.NewQuantity(int64(vm.MemoryMB)*(1<<20), resource.BinarySI)
+	object.Template.Spec.Domain.Memory = &cnv.Memory{Guest: reservation}
+}
+
+// Hyper-V does not expose a socket topology.
+func (r *Builder) mapCPU(vm *model.VM, object *cnv.VirtualMachineSpec) {
+	cpuCount := vm.CpuCount
+	if cpuCount < 1 {
+		cpuCount = 1
+	}
+	object.Template.Spec.Domain.CPU = &cnv.CPU{
+		Sockets: uint32(cpuCount),
+		Cores:   1,
+	}
+}
+
+func (r *Builder) mapFirmware(vm *model.VM, vmRef ref.Ref, object *cnv.VirtualMachineSpec) {
+	virtV2VFirmware := vm.Firmware
+	if virtV2VFirmware == "" {
+		for _, vmConf := range r.Migration.Status.VMs {
+			if vmConf.ID == vmRef.ID {
+				virtV2VFirmware = vmConf.Firmware
+				break
+			}
+		}
+	}
+
+	firmware := &cnv.Firmware{
+		Serial: vm.UUID,
+	}
+
+	switch virtV2VFirmware {
+	case BIOS:
+		firmware.Bootloader = &cnv.Bootloader{BIOS: &cnv.BIOS{}}
+	default:
+		// Generation 2 VMs boot using UEFI.
+		firmware.Bootloader = &cnv.Bootloader{
+			EFI: &cnv.EFI{
+				SecureBoot: &vm.SecureBoot,
+			}}
+		if vm.SecureBoot {
+			object.Template.Spec.Domain.Features = &cnv.Features{
+				SMM: &cnv.FeatureState{
+					Enabled: &vm.SecureBoot,
+				},
+			}
+		}
+	}
+	object.Template.Spec.Domain.Firmware = firmware
+}
+
+func (r *Builder) mapDisks(vm *model.VM, persistentVolumeClaims []*core.PersistentVolumeClaim, object *cnv.VirtualMachineSpec) {
+	var kVolumes []cnv.Volume
+	var kDisks []cnv.Disk
+
+	pvcMap := make(map[string]*core.PersistentVolumeClaim)
+	for i := range persistentVolumeClaims {
+		pvc := persistentVolumeClaims[i]
+		if source, ok := pvc.Annotations[planbase.AnnDiskSource]; ok {
+			pvcMap[source] = pvc
+		}
+	}
+	for i, disk := range vm.Disks {
+		pvc, found := pvcMap[disk.FilePath]
+		if !found {
+			continue
+		}
+		volumeName := fmt.Sprintf("vol-%v", i)
+		volume := cnv.Volume{
+			Name: volumeName,
+			VolumeSource: cnv.VolumeSource{
+				PersistentVolumeClaim: &cnv.PersistentVolumeClaimVolumeSource{
+					PersistentVolumeClaimVolumeSource: core.PersistentVolumeClaimVolumeSource{
+						ClaimName: pvc.Name,
+					},
+				},
+			},
+		}
+		kubevirtDisk := cnv.Disk{
+			Name: volumeName,
+			DiskDevice: cnv.DiskDevice{
+				Disk: &cnv.DiskTarget{
+					Bus: Virtio,
+				},
+			},
+		}
+		kVolumes = append(kVolumes, volume)
+		kDisks = append(kDisks, kubevirtDisk)
+	}
+	object.Template.Spec.Volumes = kVolumes
+	object.Template.Spec.Domain.Devices.Disks = kDisks
+}
+
+// Build tasks.
+func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	r.selectDisks(vm)
+	for _, disk := range vm.Disks {
+		mB := disk.Capacity / 0x100000
+		list = append(
+			list,
+			&plan.Task{
+				Name: disk.FilePath,
+				Progress: libitr.Progress{
+					Total: mB,
+				},
+				Annotations: map[string]string{
+					"unit": "MB",
+				},
+			})
+	}
+
+	return
+}
+
+func (r *Builder) PreferenceName(vmRef ref.Ref, configMap *core.ConfigMap) (name string, err error) {
+	// The guest OS is not reported by the Hyper-V configuration.
+	err = liberr.New("preferences are not used by this provider")
+	return
+}
+
+func (r *Builder) ConfigMaps(vmRef ref.Ref) (list []core.ConfigMap, err error) {
+	return nil, nil
+}
+
+func (r *Builder) Secrets(vmRef ref.Ref) (list []core.Secret, err error) {
+	return nil, nil
+}
+
+func (r *Builder) TemplateLabels(vmRef ref.Ref) (labels map[string]string, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+
+	os := Unknown
+
+	labels = make(map[string]string)
+	labels[fmt.Sprintf(TemplateOSLabel, os)] = "true"
+	labels[TemplateWorkloadLabel] = "true"
+	labels[TemplateFlavorLabel] = "true"
+
+	return
+}
+
+func (r *Builder) ResolveDataVolumeIdentifier(dv *cdi.DataVolume) string {
+	return dv.ObjectMeta.Annotations[planbase.AnnDiskSource]
+}
+
+// Return a stable identifier for a PersistentDataVolume.
+func (r *Builder) ResolvePersistentVolumeClaimIdentifier(pvc *core.PersistentVolumeClaim) string {
+	return ""
+}
+
+// Disk paths passed to virt-v2v, separated
+// by the path list separator.
+func getDiskSourcePaths(disks []hyperv.Disk) string {
+	paths := []string{}
+	for _, disk := range disks {
+		paths = append(paths, disk.FilePath)
+	}
+	return strings.Join(paths, string(filepath.ListSeparator))
+}
+
+// Describe the VM disks for the disk selection.
+func sourceDisks(vm *model.VM) (disks []plan.SourceDisk) {
+	for i, disk := range vm.Disks {
+		disks = append(
+			disks,
+			plan.SourceDisk{
+				Key:   disk.ID,
+				File:  disk.FilePath,
+				Label: disk.Name,
+				Bus:   disk.Bus,
+				Index: i,
+			})
+	}
+
+	return
+}
+
+// Remove the VM disks that are not selected on the plan VM.
+// Returns the root disk relative to the selected disks.
+func (r *Builder) selectDisks(vm *model.VM) (rootDisk string) {
+	planVM, found := r.Plan.Spec.FindVM(ref.Ref{ID: vm.ID})
+	if !found {
+		return
+	}
+	disks := sourceDisks(vm)
+	rootDisk = planbase.SelectedRootDisk(planVM, disks)
+	selected := planbase.SelectDisks(planVM, disks)
+	var kept []hyperv.Disk
+	for _, disk := range vm.Disks {
+		if selected[disk.ID] {
+			kept = append(kept, disk)
+		}
+	}
+	vm.Disks = kept
+
+	return
+}
+
+// Build LUN PVs.
+func (r *Builder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolume, err error) {
+	// do nothing
+	return
+}
+
+// Build LUN PVCs.
+func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.PersistentVolumeClaim, err error) {
+	// do nothing
+	return
+}
+
+func (r *Builder) SupportsVolumePopulators() bool {
+	return false
+}
+
+func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string, secretName string) (pvcs []*core.PersistentVolumeClaim, err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+func (r *Builder) PrePopulateActions(c planbase.Client, vmRef ref.Ref) (ready bool, err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+func (r *Builder) PopulatorTransferredBytes(persistentVolumeClaim *core.PersistentVolumeClaim) (transferredBytes int64, err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+func (r *Builder) SetPopulatorDataSourceLabels(vmRef ref.Ref, pvcs []*core.PersistentVolumeClaim) (err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
+
+func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskName string, err error) {
+	err = planbase.VolumePopulatorNotSupportedError
+	return
+}
@@ -0,0 +1,134 @@
+package hyperv
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/hyperv"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/hyperv"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+func TestMapFirmware(t *testing.T) {
+	tests := []struct {
+		name               string
+		firmware           string
+		secureBoot         bool
+		expectedBootloader *cnv.Bootloader
+		expectedSMM        *cnv.FeatureState
+	}{
+		{
+			name:       "generation 2 with SecureBoot",
+			firmware:   EFI,
+			secureBoot: true,
+			expectedBootloader: &cnv.Bootloader{
+				EFI: &cnv.EFI{
+					SecureBoot: boolPtr(true),
+				},
+			},
+			expectedSMM: &cnv.FeatureState{
+				Enabled: boolPtr(true),
+			},
+		},
+		{
+			name:       "generation 2 without SecureBoot",
+			firmware:   EFI,
+			secureBoot: false,
+			expectedBootloader: &cnv.Bootloader{
+				EFI: &cnv.EFI{
+					SecureBoot: boolPtr(false),
+				},
+			},
+		},
+		{
+			name:     "generation 1",
+			firmware: BIOS,
+			expectedBootloader: &cnv.Bootloader{
+				BIOS: &cnv.BIOS{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := &Builder{
+				Context: &plancontext.Context{
+					Log:       logging.WithName("test"),
+					Migration: &api.Migration{},
+				},
+			}
+			vm := &model.VM{
+				UUID:       "test-vm-id",
+				Firmware:   tt.firmware,
+				SecureBoot: tt.secureBoot,
+			}
+			vmSpec := &cnv.VirtualMachineSpec{
+				Template: &cnv.VirtualMachineInstanceTemplateSpec{},
+			}
+
+			builder.mapFirmware(vm, ref.Ref{ID: "test-vm-id"}, vmSpec)
+
+			firmware := vmSpec.Template.Spec.Domain.Firmware
+			if firmware == nil {
+				t.Fatal("Firmware should be set but is nil")
+			}
+			if firmware.Serial != vm.UUID {
+				t.Errorf("Serial mismatch: want %s, got %s", vm.UUID, firmware.Serial)
+			}
+			if diff := cmp.Diff(tt.expectedBootloader, firmware.Bootloader); diff != "" {
+				t.Errorf("Bootloader mismatch (-want +got):\n%s", diff)
+			}
+			var actualSMM *cnv.FeatureState
+			if vmSpec.Template.Spec.Domain.Features != nil {
+				actualSMM = vmSpec.Template.Spec.Domain.Features.SMM
+			}
+			if diff := cmp.Diff(tt.expectedSMM, actualSMM); diff != "" {
+				t.Errorf("SMM mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMapCPUAndMemory(t *testing.T) {
+	builder := &Builder{}
+	vm := &model.VM{
+		CpuCount: 4,
+		MemoryMB: 2048,
+	}
+	vmSpec := &cnv.VirtualMachineSpec{
+		Template: &cnv.VirtualMachineInstanceTemplateSpec{},
+	}
+
+	builder.mapCPU(vm, vmSpec)
+	builder.mapMemory(vm, vmSpec)
+
+	expectedCPU := &cnv.CPU{Sockets: 4, Cores: 1}
+	if diff := cmp.Diff(expectedCPU, vmSpec.Template.Spec.Domain.CPU); diff != "" {
+		t.Errorf("CPU mismatch (-want +got):\n%s", diff)
+	}
+	expectedMemory := resource.MustParse("2Gi")
+	if vmSpec.Template.Spec.Domain.Memory.Guest.Cmp(expectedMemory) != 0 {
+		t.Errorf("Memory mismatch: want %s, got %s", expectedMemory.String(), vmSpec.Template.Spec.Domain.Memory.Guest.String())
+	}
+}
+
+func TestGetDiskSourcePaths(t *testing.T) {
+	disks := []hyperv.Disk{
+		{FilePath: "/ova/web/Virtual Hard Disks/web.vhdx"},
+		{FilePath: "/ova/web/Virtual Hard Disks/data.vhdx"},
+	}
+	expected := "/ova/web/Virtual Hard Disks/web.vhdx:/ova/web/Virtual Hard Disks/data.vhdx"
+	if actual := getDiskSourcePaths(disks); actual != expected {
+		t.Errorf("Disk paths mismatch: want %s, got %s", expected, actual)
+	}
+}
+
+// Helper function
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -102,8 +102,16 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *v1.Secret, configMap *v1.Co
 		storageMap[storage.Source.Name] = storage.Destination
 	}
 
+	selected, err := r.selectedClaims(vmRef)
+	if err != nil {
+		return nil, err
+	}
+
 	dataVolumes := []cdi.DataVolume{}
 	for _, volume := range vmExport.Status.Links.External.Volumes {
+		if selected != nil && !selected[volume.Name] {
+			continue
+		}
 		// Get PVC
 		pvc := &core.PersistentVolumeClaim{}
 		err = r.sourceClient.Get(context.TODO(), client.ObjectKey{Namespace: vmRef.Namespace, Name: volume.Name}, pvc)
@@ -139,6 +147,90 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *v1.Secret, configMap *v1.Co
 	return dataVolumes, nil
 }
 
+// Describe the VM disks for the disk selection.
+// The disks are the volumes backed by a PVC or a DataVolume.
+func sourceDisks(vm *cnv.VirtualMachine) (disks []planapi.SourceDisk) {
+	bus := map[string]string{}
+	for _, disk := range vm.Spec.Template.Spec.Domain.Devices.Disks {
+		if disk.Disk != nil {
+			bus[disk.Name] = string(disk.Disk.Bus)
+		}
+	}
+	for _, vol := range vm.Spec.Template.Spec.Volumes {
+		var claimName string
+		switch {
+		case vol.PersistentVolumeClaim != nil:
+			claimName = vol.PersistentVolumeClaim.ClaimName
+		case vol.DataVolume != nil:
+			claimName = vol.DataVolume.Name
+		default:
+			continue
+		}
+		disks = append(
+			disks,
+			planapi.SourceDisk{
+				Key:   vol.Name,
+				File:  claimName,
+				Label: vol.Name,
+				Bus:   bus[vol.Name],
+				Index: len(disks),
+			})
+	}
+
+	return
+}
+
+// Remove the VM volumes, and the disks using them, that
+// are not selected on the plan VM.
+func (r *Builder) selectVolumes(vmRef ref.Ref, vm *cnv.VirtualMachine) {
+	planVM, found := r.Plan.Spec.FindVM(vmRef)
+	if !found || planVM.Disks == nil {
+		return
+	}
+	selected := planbase.SelectDisks(planVM, sourceDisks(vm))
+	spec := &vm.Spec.Template.Spec
+	removed := map[string]bool{}
+	volumes := []cnv.Volume{}
+	for _, vol := range spec.Volumes {
+		if (vol.PersistentVolumeClaim != nil || vol.DataVolume != nil) && !selected[vol.Name] {
+			removed[vol.Name] = true
+			continue
+		}
+		volumes = append(volumes, vol)
+	}
+	spec.Volumes = volumes
+	disks := []cnv.Disk{}
+	for _, disk := range spec.Domain.Devices.Disks {
+		if !removed[disk.Name] {
+			disks = append(disks, disk)
+		}
+	}
+	spec.Domain.Devices.Disks = disks
+}
+
+// Names of the source VM PVCs that are selected on the plan VM.
+// Returns nil when the plan VM has no disk selection.
+func (r *Builder) selectedClaims(vmRef ref.Ref) (claims map[string]bool, err error) {
+	planVM, found := r.Plan.Spec.FindVM(vmRef)
+	if !found || planVM.Disks == nil {
+		return
+	}
+	vm := &cnv.VirtualMachine{}
+	err = r.sourceClient.Get(context.TODO(), client.ObjectKey{Namespace: vmRef.Namespace, Name: vmRef.Name}, vm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	claims = map[string]bool{}
+	for _, disk := range sourceDisks(vm) {
+		if planVM.DiskSelected(disk) {
+			claims[disk.File] = true
+		}
+	}
+
+	return
+}
+
 func getExportURL(virtualMachineExportVolumeFormat []export.VirtualMachineExportVolumeFormat) (url string) {
 	for _, format := range virtualMachineExportVolumeFormat {
 		if format.Format == export.KubeVirtGz || format.Format == export.ArchiveGz {
@@ -212,6 +304,7 @@ func (r *Builder) Tasks(vmRef ref.Ref) (list []*planapi.Task, err error) {
 	if err != nil {
 		return nil, liberr.Wrap(err)
 	}
+	r.selectVolumes(vmRef, vm)
 
 	for _, vol := range vm.Spec.Template.Spec.Volumes {
 		var size resource.Quantity
@@ -277,6 +370,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 		return liberr.Wrap(err)
 	}
 
+	r.selectVolumes(vmRef, sourceVm)
 	targetVmSpec := sourceVm.Spec.DeepCopy()
 	object.Template = targetVmSpec.Template
 	r.mapNetworks(sourceVm, targetVmSpec)
@@ -126,6 +126,9 @@ func (r *Validator) PodNetwork(vmRef ref.Ref) (ok bool, err error) {
 }
 
 // WarmMigration implements base.Validator
+// The VM export serves the disks over HTTP, which CDI imports
+// in full: the DataVolume checkpoints (precopies) are supported
+// only for the VDDK and ImageIO sources.
 func (r *Validator) WarmMigration() bool {
 	return false
 }
@@ -179,6 +182,23 @@ func (r *Validator) InvalidDiskSizes(vmRef ref.Ref) ([]string, error) {
 	return []string{}, nil
 }
 
+// Validate the disk selection.
+func (r *Validator) DiskSelection(vmRef ref.Ref) (ok bool, err error) {
+	planVM, found := r.Plan.Spec.FindVM(vmRef)
+	if !found || planVM.Disks == nil {
+		ok = true
+		return
+	}
+	vm := &inventory.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	ok = planbase.ValidDiskSelection(planVM, sourceDisks(&vm.Object))
+	return
+}
+
 func (r *Validator) MacConflicts(vmRef ref.Ref) ([]planbase.MacConflict, error) {
 	// Only check MAC conflicts for live migrations
 	// For cold migrations, the source VM is shut down, so no conflicts occur
@@ -87,3 +87,23 @@ func TestMacConflicts_BehaviorDocumentation(t *testing.T) {
 		})
 	}
 }
+
+func TestWarmMigration_NotSupported(t *testing.T) {
+	validator := &Validator{
+		log: logging.WithName("test").WithValues("test", "warm-migration"),
+		Context: &plancontext.Context{
+			Plan: &api.Plan{
+				Spec: api.PlanSpec{
+					Type: api.MigrationWarm,
+				},
+			},
+		},
+	}
+
+	if validator.WarmMigration() {
+		t.Error("Warm migration should not be supported by the OpenShift source")
+	}
+	if validator.MigrationType() {
+		t.Error("Warm migration type should not be supported by the OpenShift source")
+	}
+}
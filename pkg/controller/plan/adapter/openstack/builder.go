@@ -255,6 +255,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, vmSpec *cnv.VirtualMachineSpec,
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	r.selectDisks(vm)
 
 	if vmSpec.Template == nil {
 		vmSpec.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
@@ -668,7 +669,9 @@ func (r *Builder) Tasks(vmRef ref.Ref) (tasks []*plan.Task, err error) {
 	err = r.Source.Inventory.Find(workload, vmRef)
 	if err != nil {
 		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
 	}
+	r.selectDisks(workload)
 
 	taskMap := map[string]int64{}
 	imageID := workload.ImageID
@@ -871,7 +874,20 @@ func (r *Builder) Secret(_ ref.Ref, in, secret *core.Secret) (err error) {
 	return
 }
 
-func (r *Builder) PodEnvironment(_ ref.Ref, _ *core.Secret) (env []core.EnvVar, err error) {
+func (r *Builder) PodEnvironment(vmRef ref.Ref, _ *core.Secret) (env []core.EnvVar, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	rootDisk := r.selectDisks(vm)
+	if planVM, found := r.Plan.Spec.FindVM(vmRef); found && rootDisk != planVM.RootDisk {
+		env = append(env, core.EnvVar{
+			Name:  "V2V_RootDisk",
+			Value: rootDisk,
+		})
+	}
 	return
 }
 
@@ -898,6 +914,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 		err = liberr.Wrap(err)
 		return
 	}
+	r.selectDisks(workload)
 	images, err := r.getImagesFromVolumes(workload)
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -1295,6 +1312,7 @@ func (r *Builder) SetPopulatorDataSourceLabels(vmRef ref.Ref, pvcs []*core.Persi
 		err = liberr.Wrap(err)
 		return
 	}
+	r.selectDisks(workload)
 	var images []*model.Image
 	for _, volume := range workload.Volumes {
 		lookupName := getImageFromVolumeName(r.Context, vmRef.ID, volume.ID)
@@ -1363,3 +1381,39 @@ func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskNam
 	taskName = image.Name
 	return
 }
+
+// Describe the VM volumes for the disk selection.
+func sourceDisks(vm *model.Workload) (disks []plan.SourceDisk) {
+	for i, volume := range vm.Volumes {
+		disks = append(
+			disks,
+			plan.SourceDisk{
+				Key:   volume.ID,
+				Label: volume.Name,
+				Index: i,
+			})
+	}
+
+	return
+}
+
+// Remove the VM volumes that are not selected on the plan VM.
+// Returns the root disk relative to the selected disks.
+func (r *Builder) selectDisks(vm *model.Workload) (rootDisk string) {
+	planVM, found := r.Plan.Spec.FindVM(ref.Ref{ID: vm.ID})
+	if !found {
+		return
+	}
+	disks := sourceDisks(vm)
+	rootDisk = planbase.SelectedRootDisk(planVM, disks)
+	selected := planbase.SelectDisks(planVM, disks)
+	var kept []model.Volume
+	for _, volume := range vm.Volumes {
+		if selected[volume.ID] {
+			kept = append(kept, volume)
+		}
+	}
+	vm.Volumes = kept
+
+	return
+}
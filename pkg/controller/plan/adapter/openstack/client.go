@@ -7,6 +7,7 @@ import (
 
 	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
 	"github.com/kubev2v/forklift/pkg/controller/plan/util"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
@@ -199,6 +200,10 @@ func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	err = r.selectVolumes(vmRef, vm)
+	if err != nil {
+		return
+	}
 	ready, err = r.ensureVmSnapshot(vm)
 	if err != nil || !ready {
 		return
@@ -218,6 +223,29 @@ func (r *Client) PreTransferActions(vmRef ref.Ref) (ready bool, err error) {
 	return
 }
 
+// Remove the attached volumes that are not selected on the plan VM.
+func (r *Client) selectVolumes(vmRef ref.Ref, vm *libclient.VM) (err error) {
+	planVM, found := r.Context.Plan.Spec.FindVM(vmRef)
+	if !found || planVM.Disks == nil {
+		return
+	}
+	workload := &model.Workload{}
+	err = r.Context.Source.Inventory.Find(workload, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	selected := planbase.SelectDisks(planVM, sourceDisks(workload))
+	kept := vm.AttachedVolumes[:0]
+	for _, volume := range vm.AttachedVolumes {
+		if selected[volume.ID] {
+			kept = append(kept, volume)
+		}
+	}
+	vm.AttachedVolumes = kept
+	return
+}
+
 func (r *Client) getVM(vmRef ref.Ref) (vm *libclient.VM, err error) {
 	if vmRef.ID == "" && vmRef.Name == "" {
 		err = NameOrIDRequiredError
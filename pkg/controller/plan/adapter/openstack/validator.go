@@ -89,6 +89,23 @@ func (r *Validator) InvalidDiskSizes(vmRef ref.Ref) ([]string, error) {
 	return invalidDisks, nil
 }
 
+// Validate the disk selection.
+func (r *Validator) DiskSelection(vmRef ref.Ref) (ok bool, err error) {
+	planVM, found := r.Plan.Spec.FindVM(vmRef)
+	if !found || planVM.Disks == nil {
+		ok = true
+		return
+	}
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	ok = planbase.ValidDiskSelection(planVM, sourceDisks(vm))
+	return
+}
+
 func (r *Validator) MacConflicts(vmRef ref.Ref) ([]planbase.MacConflict, error) {
 	// Get source VM using common helper
 	vm, err := planbase.FindSourceVM[model.Workload](r.Source.Inventory, vmRef)
@@ -2,11 +2,9 @@ package ova
 
 import (
 	"fmt"
-	"math"
 	"path"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
 
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
@@ -82,6 +80,23 @@ func (r *Builder) PodEnvironment(vmRef ref.Ref, sourceSecret *core.Secret) (env
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	diskCount := len(vm.Disks)
+	rootDisk := r.selectDisks(vm)
+	// The virt-v2v converts the whole OVA unless the disks are listed.
+	if len(vm.Disks) < diskCount {
+		for i, disk := range vm.Disks {
+			env = append(env, core.EnvVar{
+				Name:  fmt.Sprintf("V2V_disk_%d", i),
+				Value: disk.Name,
+			})
+		}
+	}
+	if planVM, found := r.Plan.Spec.FindVM(vmRef); found && rootDisk != planVM.RootDisk {
+		env = append(env, core.EnvVar{
+			Name:  "V2V_RootDisk",
+			Value: rootDisk,
+		})
+	}
 
 	env = append(
 		env,
@@ -114,6 +129,7 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *cor
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	r.selectDisks(vm)
 
 	storageMapIn := r.Context.Map.Storage.Spec.Map
 	for i := range storageMapIn {
@@ -191,6 +207,8 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 		return
 	}
 
+	r.selectDisks(vm)
+
 	if object.Template == nil {
 		object.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
 	}
@@ -401,6 +419,7 @@ func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	r.selectDisks(vm)
 	for _, disk := range vm.Disks {
 		mB := disk.Capacity / 0x100000
 		list = append(
@@ -473,6 +492,43 @@ func getDiskFullPath(disk *ova.Disk) string {
 	return disk.FilePath + "::" + disk.Name
 }
 
+// Describe the VM disks for the disk selection.
+func sourceDisks(vm *model.VM) (disks []plan.SourceDisk) {
+	for i, disk := range vm.Disks {
+		disks = append(
+			disks,
+			plan.SourceDisk{
+				Key:   disk.ID,
+				File:  disk.FilePath,
+				Label: disk.Name,
+				Index: i,
+			})
+	}
+
+	return
+}
+
+// Remove the VM disks that are not selected on the plan VM.
+// Returns the root disk relative to the selected disks.
+func (r *Builder) selectDisks(vm *model.VM) (rootDisk string) {
+	planVM, found := r.Plan.Spec.FindVM(ref.Ref{ID: vm.ID})
+	if !found {
+		return
+	}
+	disks := sourceDisks(vm)
+	rootDisk = planbase.SelectedRootDisk(planVM, disks)
+	selected := planbase.SelectDisks(planVM, disks)
+	var kept []ova.Disk
+	for _, disk := range vm.Disks {
+		if selected[disk.ID] {
+			kept = append(kept, disk)
+		}
+	}
+	vm.Disks = kept
+
+	return
+}
+
 func getDiskSourcePath(filePath string) string {
 	if strings.HasSuffix(filePath, ".ova") {
 		return filePath
@@ -481,38 +537,7 @@ func getDiskSourcePath(filePath string) string {
 }
 
 func getResourceCapacity(capacity int64, units string) (int64, error) {
-	if strings.ToLower(units) == "megabytes" {
-		return capacity * (1 << 20), nil
-	}
-	items := strings.Split(units, "*")
-	for i := range items {
-		item := strings.TrimSpace(items[i])
-		if i == 0 && len(item) > 0 && item != "byte" {
-			return 0, fmt.Errorf("units '%s' are invalid, only 'byte' is supported", units)
-		}
-		if i == 0 {
-			continue
-		}
-		num, err := strconv.Atoi(item)
-		if err == nil {
-			capacity = capacity * int64(num)
-			continue
-		}
-		nums := strings.Split(item, "^")
-		if len(nums) != 2 {
-			return 0, fmt.Errorf("units '%s' are invalid, item is invalid: %s", units, item)
-		}
-		base, err := strconv.Atoi(nums[0])
-		if err != nil {
-			return 0, fmt.Errorf("units '%s' are invalid, base component is invalid: %s", units, item)
-		}
-		pow, err := strconv.Atoi(nums[1])
-		if err != nil {
-			return 0, fmt.Errorf("units '%s' are invalid, pow component is invalid: %s", units, item)
-		}
-		capacity = capacity * int64(math.Pow(float64(base), float64(pow)))
-	}
-	return capacity, nil
+	return ova.ResourceCapacity(capacity, units)
 }
 
 // Build LUN PVs.
@@ -0,0 +1,61 @@
+package ova
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/ova"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
+)
+
+func TestSelectDisks(t *testing.T) {
+	index := 1
+	builder := &Builder{
+		Context: &plancontext.Context{
+			Plan: &api.Plan{
+				Spec: api.PlanSpec{
+					VMs: []plan.VM{
+						{
+							Ref:      ref.Ref{ID: "vm-1"},
+							RootDisk: "/dev/sdc",
+							Disks: &plan.DiskSelector{
+								Exclude: []plan.DiskMatch{{Index: &index}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	newVM := func() *model.VM {
+		vm := &model.VM{}
+		vm.ID = "vm-1"
+		for _, name := range []string{"disk1.vmdk", "disk2.vmdk", "disk3.vmdk"} {
+			disk := ova.Disk{FilePath: "/ova/web.ova"}
+			disk.ID = name
+			disk.Name = name
+			vm.Disks = append(vm.Disks, disk)
+		}
+		return vm
+	}
+
+	vm := newVM()
+	rootDisk := builder.selectDisks(vm)
+	if rootDisk != "/dev/sdb" {
+		t.Errorf("root disk: expected /dev/sdb, got %s", rootDisk)
+	}
+	if len(vm.Disks) != 2 || vm.Disks[0].Name != "disk1.vmdk" || vm.Disks[1].Name != "disk3.vmdk" {
+		t.Errorf("unexpected disks selected: %v", vm.Disks)
+	}
+
+	// Not on the plan.
+	vm = newVM()
+	vm.ID = "vm-2"
+	builder.selectDisks(vm)
+	if len(vm.Disks) != 3 {
+		t.Errorf("expected all disks selected, got %d", len(vm.Disks))
+	}
+}
@@ -175,7 +175,20 @@ func (r *Builder) fetchOVirtCACert() (cert []byte, err error) {
 	return certPEM, nil
 }
 
-func (r *Builder) PodEnvironment(_ ref.Ref, _ *core.Secret) (env []core.EnvVar, err error) {
+func (r *Builder) PodEnvironment(vmRef ref.Ref, _ *core.Secret) (env []core.EnvVar, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	rootDisk := r.selectDisks(vm)
+	if planVM, found := r.Plan.Spec.FindVM(vmRef); found && rootDisk != planVM.RootDisk {
+		env = append(env, core.EnvVar{
+			Name:  "V2V_RootDisk",
+			Value: rootDisk,
+		})
+	}
 	return
 }
 
@@ -196,6 +209,7 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, configMap *cor
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	r.selectDisks(vm)
 	url := r.Source.Provider.Spec.URL
 
 	dsMapIn := r.Context.Map.Storage.Spec.Map
@@ -275,6 +289,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	r.selectDisks(vm)
 
 	if object.Template == nil {
 		object.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
@@ -545,7 +560,9 @@ func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 	err = r.Source.Inventory.Find(vm, vmRef)
 	if err != nil {
 		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
 	}
+	r.selectDisks(vm)
 	for _, da := range vm.DiskAttachments {
 		// We don't add a task for LUNs because we don't copy their content but rather assume we can connect to
 		// the LUNs that are used in the source environment also from the target environment.
@@ -634,6 +651,7 @@ func (r *Builder) LunPersistentVolumes(vmRef ref.Ref) (pvs []core.PersistentVolu
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	r.selectDisks(vm)
 	for _, da := range vm.DiskAttachments {
 		if da.Disk.StorageType == "lun" {
 			volMode := core.PersistentVolumeBlock
@@ -698,6 +716,7 @@ func (r *Builder) LunPersistentVolumeClaims(vmRef ref.Ref) (pvcs []core.Persiste
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	r.selectDisks(vm)
 	for _, da := range vm.DiskAttachments {
 		if da.Disk.StorageType == "lun" {
 			sc := ""
@@ -751,6 +770,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 		err = liberr.Wrap(err)
 		return
 	}
+	r.selectDisks(workload)
 
 	var sdToStorageClass map[string]string
 	for _, diskAttachment := range workload.DiskAttachments {
@@ -979,6 +999,7 @@ func (r *Builder) SetPopulatorDataSourceLabels(vmRef ref.Ref, pvcs []*core.Persi
 	if err != nil {
 		return
 	}
+	r.selectDisks(ovirtVm)
 	var diskIds []string
 	for _, da := range ovirtVm.DiskAttachments {
 		diskIds = append(diskIds, da.Disk.ID)
@@ -1022,3 +1043,40 @@ func (r *Builder) GetPopulatorTaskName(pvc *core.PersistentVolumeClaim) (taskNam
 	taskName = pvc.Annotations[planbase.AnnDiskSource]
 	return
 }
+
+// Describe the VM disks for the disk selection.
+func sourceDisks(vm *model.Workload) (disks []plan.SourceDisk) {
+	for i, da := range vm.DiskAttachments {
+		disks = append(
+			disks,
+			plan.SourceDisk{
+				Key:   da.Disk.ID,
+				Label: da.Disk.Name,
+				Bus:   da.Interface,
+				Index: i,
+			})
+	}
+
+	return
+}
+
+// Remove the VM disks that are not selected on the plan VM.
+// Returns the root disk relative to the selected disks.
+func (r *Builder) selectDisks(vm *model.Workload) (rootDisk string) {
+	planVM, found := r.Plan.Spec.FindVM(ref.Ref{ID: vm.ID})
+	if !found {
+		return
+	}
+	disks := sourceDisks(vm)
+	rootDisk = planbase.SelectedRootDisk(planVM, disks)
+	selected := planbase.SelectDisks(planVM, disks)
+	var kept []model.XDiskAttachment
+	for _, da := range vm.DiskAttachments {
+		if selected[da.Disk.ID] {
+			kept = append(kept, da)
+		}
+	}
+	vm.DiskAttachments = kept
+
+	return
+}
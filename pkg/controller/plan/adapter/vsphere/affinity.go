@@ -0,0 +1,113 @@
+package vsphere
+
+import (
+	"fmt"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// DRS rule translation.
+const (
+	// Label (prefix) identifying the members of a DRS rule.
+	// The value is the UID of the plan so only the VMs migrated
+	// by the same plan are matched.
+	LabelDrsRule = "drs.forklift.konveyor.io/"
+	// Topology of the translated rules.
+	TopologyHostname = "kubernetes.io/hostname"
+	// Weight of preferred (non-mandatory) rules.
+	DrsRuleWeight = 100
+)
+
+// Map the DRS VM-VM affinity and anti-affinity rules to pod
+// affinity and anti-affinity between the VMs migrated by the plan.
+// Mandatory rules are required, the others are preferred. Rules
+// without other members in the plan are skipped. The VM-Host rules
+// are not mapped since the hosts have no equivalent on the destination.
+func (r *Builder) mapAffinity(vm *model.VM, host *model.Host, object *cnv.VirtualMachineSpec) {
+	var affinity *core.Affinity
+	for _, rule := range vm.DrsRules {
+		if rule.Kind != vsphere.DrsAffinity && rule.Kind != vsphere.DrsAntiAffinity {
+			continue
+		}
+		if !rule.Enabled || !r.planned(vm.ID, rule.VMs) {
+			continue
+		}
+		key := LabelDrsRule + fmt.Sprintf("%s-%d", host.Cluster, rule.Key)
+		term := core.PodAffinityTerm{
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					key: string(r.Plan.UID),
+				},
+			},
+			TopologyKey: TopologyHostname,
+		}
+		if affinity == nil {
+			affinity = &core.Affinity{}
+			if object.Template.Spec.Affinity != nil {
+				// The affinity may be shared with the plan.
+				affinity = object.Template.Spec.Affinity.DeepCopy()
+			}
+		}
+		switch rule.Kind {
+		case vsphere.DrsAffinity:
+			if affinity.PodAffinity == nil {
+				affinity.PodAffinity = &core.PodAffinity{}
+			}
+			pod := affinity.PodAffinity
+			if rule.Mandatory {
+				pod.RequiredDuringSchedulingIgnoredDuringExecution = append(
+					pod.RequiredDuringSchedulingIgnoredDuringExecution,
+					term)
+			} else {
+				pod.PreferredDuringSchedulingIgnoredDuringExecution = append(
+					pod.PreferredDuringSchedulingIgnoredDuringExecution,
+					core.WeightedPodAffinityTerm{
+						Weight:          DrsRuleWeight,
+						PodAffinityTerm: term,
+					})
+			}
+		case vsphere.DrsAntiAffinity:
+			if affinity.PodAntiAffinity == nil {
+				affinity.PodAntiAffinity = &core.PodAntiAffinity{}
+			}
+			pod := affinity.PodAntiAffinity
+			if rule.Mandatory {
+				pod.RequiredDuringSchedulingIgnoredDuringExecution = append(
+					pod.RequiredDuringSchedulingIgnoredDuringExecution,
+					term)
+			} else {
+				pod.PreferredDuringSchedulingIgnoredDuringExecution = append(
+					pod.PreferredDuringSchedulingIgnoredDuringExecution,
+					core.WeightedPodAffinityTerm{
+						Weight:          DrsRuleWeight,
+						PodAffinityTerm: term,
+					})
+			}
+		}
+		if object.Template.ObjectMeta.Labels == nil {
+			object.Template.ObjectMeta.Labels = map[string]string{}
+		}
+		object.Template.ObjectMeta.Labels[key] = string(r.Plan.UID)
+	}
+	if affinity != nil {
+		object.Template.Spec.Affinity = affinity
+	}
+}
+
+// Determine whether another member of the rule is in the plan.
+func (r *Builder) planned(vmID string, members []vsphere.Ref) bool {
+	for _, member := range members {
+		if member.ID == vmID {
+			continue
+		}
+		if _, found := r.Plan.Spec.FindVM(ref.Ref{ID: member.ID}); found {
+			return true
+		}
+	}
+	return false
+}
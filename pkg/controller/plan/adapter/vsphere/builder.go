@@ -206,9 +206,26 @@ func (r *Builder) PodEnvironment(vmRef ref.Ref, sourceSecret *core.Secret) (env
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	diskCount := len(vm.Disks)
+	rootDisk := r.selectDisks(vm)
 	if !r.Context.Plan.Spec.MigrateSharedDisks {
 		vm.RemoveSharedDisks()
 	}
+	// The virt-v2v converts all disks unless the disks are listed.
+	if len(vm.Disks) < diskCount {
+		for i, disk := range vm.Disks {
+			env = append(env, core.EnvVar{
+				Name:  fmt.Sprintf("V2V_disk_%d", i),
+				Value: disk.File,
+			})
+		}
+	}
+	if planVM := r.getPlanVM(vm); planVM != nil && rootDisk != planVM.RootDisk {
+		env = append(env, core.EnvVar{
+			Name:  "V2V_RootDisk",
+			Value: rootDisk,
+		})
+	}
 	macsToIps := ""
 	if r.Plan.Spec.PreserveStaticIPs {
 		macsToIps, err = r.mapMacStaticIps(vm)
@@ -490,6 +507,7 @@ func (r *Builder) DataVolumes(vmRef ref.Ref, secret *core.Secret, _ *core.Config
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	r.selectDisks(vm)
 	if !r.Context.Plan.Spec.MigrateSharedDisks {
 		vm.RemoveSharedDisks()
 	}
@@ -676,6 +694,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 				vmRef.String()))
 		return
 	}
+	rootDisk := r.selectDisks(vm)
 	if !r.Context.Plan.Spec.MigrateSharedDisks {
 		sharedPVCs, missingDiskPVCs, err := findSharedPVCs(r.Destination.Client, vm, r.Plan.Spec.TargetNamespace)
 		if err != nil {
@@ -698,7 +717,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 	if object.Template == nil {
 		object.Template = &cnv.VirtualMachineInstanceTemplateSpec{}
 	}
-	err = r.mapDisks(vm, vmRef, persistentVolumeClaims, object, sortVolumesByLibvirt)
+	err = r.mapDisks(vm, vmRef, rootDisk, persistentVolumeClaims, object, sortVolumesByLibvirt)
 	if err != nil {
 		return
 	}
@@ -710,6 +729,7 @@ func (r *Builder) VirtualMachine(vmRef ref.Ref, object *cnv.VirtualMachineSpec,
 	r.mapClock(host, object)
 	r.mapInput(object)
 	r.mapTpm(vm, object)
+	r.mapAffinity(vm, host, object)
 	err = r.mapNetworks(vm, object)
 	if err != nil {
 		return
@@ -955,7 +975,7 @@ func (r *Builder) sortedDisksAsVmware(disks []vsphere.Disk) []vsphere.Disk {
 	return r.sortedDisksByBusses(disks, buses)
 }
 
-func (r *Builder) mapDisks(vm *model.VM, vmRef ref.Ref, persistentVolumeClaims []*core.PersistentVolumeClaim, object *cnv.VirtualMachineSpec, sortByLibvirt bool) error {
+func (r *Builder) mapDisks(vm *model.VM, vmRef ref.Ref, rootDisk string, persistentVolumeClaims []*core.PersistentVolumeClaim, object *cnv.VirtualMachineSpec, sortByLibvirt bool) error {
 	var kVolumes []cnv.Volume
 	var kDisks []cnv.Disk
 	var disks []vsphere.Disk
@@ -976,13 +996,7 @@ func (r *Builder) mapDisks(vm *model.VM, vmRef ref.Ref, persistentVolumeClaims [
 		}
 	}
 
-	var bootDisk int
-	for _, vmConf := range r.Plan.Spec.VMs {
-		if vmConf.ID == vmRef.ID {
-			bootDisk = utils.GetBootDiskNumber(vmConf.RootDisk)
-			break
-		}
-	}
+	bootDisk := utils.GetBootDiskNumber(rootDisk)
 
 	for i, disk := range disks {
 		// If the user creates in middle of migration snapshot the disk file name gets the snapshot suffix.
@@ -1090,6 +1104,7 @@ func (r *Builder) Tasks(vmRef ref.Ref) (list []*plan.Task, err error) {
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	r.selectDisks(vm)
 	if !r.Context.Plan.Spec.MigrateSharedDisks {
 		vm.RemoveSharedDisks()
 	}
@@ -1314,6 +1329,7 @@ func (r *Builder) PopulatorVolumes(vmRef ref.Ref, annotations map[string]string,
 		err = liberr.Wrap(err, "vm", vmRef.String())
 		return
 	}
+	r.selectDisks(vm)
 
 	// Get a list of existing PVCs to avoid creating duplicates
 	pvcLabels := map[string]string{
@@ -1635,6 +1651,45 @@ func (r *Builder) getPlanVM(vm *model.VM) *plan.VM {
 	return nil
 }
 
+// Describe the VM disks for the disk selection.
+// The index is the position of the disk in the VMware order.
+func (r *Builder) sourceDisks(vm *model.VM) (disks []plan.SourceDisk) {
+	for i, disk := range r.sortedDisksAsVmware(vm.Disks) {
+		disks = append(
+			disks,
+			plan.SourceDisk{
+				Key:   strconv.Itoa(int(disk.Key)),
+				File:  disk.File,
+				Label: disk.Label,
+				Bus:   disk.Bus,
+				Index: i,
+			})
+	}
+
+	return
+}
+
+// Remove the VM disks that are not selected on the plan VM.
+// Returns the root disk relative to the selected disks.
+func (r *Builder) selectDisks(vm *model.VM) (rootDisk string) {
+	planVM := r.getPlanVM(vm)
+	if planVM == nil {
+		return
+	}
+	disks := r.sourceDisks(vm)
+	rootDisk = planbase.SelectedRootDisk(planVM, disks)
+	selected := planbase.SelectDisks(planVM, disks)
+	var kept []vsphere.Disk
+	for _, disk := range vm.Disks {
+		if selected[strconv.Itoa(int(disk.Key))] {
+			kept = append(kept, disk)
+		}
+	}
+	vm.Disks = kept
+
+	return
+}
+
 // getPlanVMStatus get the plan VM status for the given vsphere VM
 func (r *Builder) getPlanVMStatus(vm *model.VM) *plan.VMStatus {
 	if r.Plan == nil || r.Plan.Status.Migration.VMs == nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	v1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
 	container "github.com/kubev2v/forklift/pkg/controller/provider/container/vsphere"
@@ -21,6 +22,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
+	cnv "kubevirt.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -264,6 +266,105 @@ var _ = Describe("vSphere builder", func() {
 		})
 	})
 
+	Context("mapAffinity", func() {
+		var builder *Builder
+		host := &model.Host{Cluster: "domain-c1"}
+		BeforeEach(func() {
+			builder = createBuilder()
+			builder.Plan.UID = "plan-uid"
+			builder.Plan.Spec.VMs = append(builder.Plan.Spec.VMs, planapi.VM{Ref: ref.Ref{ID: "vm-2"}})
+		})
+		newSpec := func() *cnv.VirtualMachineSpec {
+			return &cnv.VirtualMachineSpec{Template: &cnv.VirtualMachineInstanceTemplateSpec{}}
+		}
+
+		It("should map the rules with members in the plan", func() {
+			vm := &model.VM{}
+			vm.ID = "test-vm-id"
+			vm.DrsRules = []vsphere.DrsRule{
+				{
+					Key:       1,
+					Kind:      vsphere.DrsAffinity,
+					Enabled:   true,
+					Mandatory: true,
+					VMs:       []vsphere.Ref{{ID: "test-vm-id"}, {ID: "vm-2"}},
+				},
+				{
+					Key:     2,
+					Kind:    vsphere.DrsAntiAffinity,
+					Enabled: true,
+					VMs:     []vsphere.Ref{{ID: "test-vm-id"}, {ID: "vm-2"}},
+				},
+			}
+			object := newSpec()
+			builder.mapAffinity(vm, host, object)
+
+			affinity := object.Template.Spec.Affinity
+			Expect(affinity).NotTo(BeNil())
+			Expect(affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+			term := affinity.PodAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0]
+			Expect(term.TopologyKey).To(Equal(TopologyHostname))
+			Expect(term.LabelSelector.MatchLabels).To(Equal(map[string]string{LabelDrsRule + "domain-c1-1": "plan-uid"}))
+			Expect(affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(HaveLen(1))
+			weighted := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[0]
+			Expect(weighted.Weight).To(Equal(int32(DrsRuleWeight)))
+			Expect(object.Template.ObjectMeta.Labels).To(Equal(map[string]string{
+				LabelDrsRule + "domain-c1-1": "plan-uid",
+				LabelDrsRule + "domain-c1-2": "plan-uid",
+			}))
+		})
+
+		It("should skip the rules without other members in the plan", func() {
+			vm := &model.VM{}
+			vm.ID = "test-vm-id"
+			vm.DrsRules = []vsphere.DrsRule{
+				{
+					Key:     1,
+					Kind:    vsphere.DrsAffinity,
+					Enabled: true,
+					VMs:     []vsphere.Ref{{ID: "test-vm-id"}, {ID: "vm-3"}},
+				},
+				{
+					Key:  2,
+					Kind: vsphere.DrsAntiAffinity,
+					VMs:  []vsphere.Ref{{ID: "test-vm-id"}, {ID: "vm-2"}},
+				},
+				{
+					Key:     3,
+					Kind:    vsphere.DrsVmHostAffinity,
+					Enabled: true,
+					VMs:     []vsphere.Ref{{ID: "test-vm-id"}, {ID: "vm-2"}},
+				},
+			}
+			object := newSpec()
+			builder.mapAffinity(vm, host, object)
+
+			Expect(object.Template.Spec.Affinity).To(BeNil())
+			Expect(object.Template.ObjectMeta.Labels).To(BeEmpty())
+		})
+
+		It("should not modify the affinity shared with the plan", func() {
+			shared := &core.Affinity{NodeAffinity: &core.NodeAffinity{}}
+			vm := &model.VM{}
+			vm.ID = "test-vm-id"
+			vm.DrsRules = []vsphere.DrsRule{
+				{
+					Key:     1,
+					Kind:    vsphere.DrsAntiAffinity,
+					Enabled: true,
+					VMs:     []vsphere.Ref{{ID: "test-vm-id"}, {ID: "vm-2"}},
+				},
+			}
+			object := newSpec()
+			object.Template.Spec.Affinity = shared
+			builder.mapAffinity(vm, host, object)
+
+			Expect(shared.PodAntiAffinity).To(BeNil())
+			Expect(object.Template.Spec.Affinity.NodeAffinity).NotTo(BeNil())
+			Expect(object.Template.Spec.Affinity.PodAntiAffinity).NotTo(BeNil())
+		})
+	})
+
 	builder := createBuilder()
 	DescribeTable("should", func(vm *model.VM, outputMap string) {
 		Expect(builder.mapMacStaticIps(vm)).Should(Equal(outputMap))
@@ -0,0 +1,291 @@
+package capacity
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	utils "github.com/kubev2v/forklift/pkg/controller/plan/util"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	ocpweb "github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Disk requirement.
+type Disk struct {
+	// Source storage.
+	// The storage ref must have the ID or Name set.
+	Storage ref.Ref
+	// Size in bytes.
+	Size int64
+}
+
+// VM requirement.
+type VM struct {
+	// Number of vCPUs.
+	CPU int64
+	// Memory in bytes.
+	Memory int64
+	// Disks.
+	Disks []Disk
+}
+
+// Provider specific sizing of a VM.
+type Sizer interface {
+	// Determine the resources required by the VM.
+	VM(vmRef ref.Ref) (vm *VM, err error)
+}
+
+// Resources required by the plan.
+type Requirements struct {
+	// Total CPU.
+	CPU resource.Quantity `json:"cpu"`
+	// Total memory.
+	Memory resource.Quantity `json:"memory"`
+	// Total storage, including the overhead.
+	Storage resource.Quantity `json:"storage"`
+	// Storage by destination storage class, including the overhead.
+	StorageClasses map[string]resource.Quantity `json:"storageClasses"`
+	// CPU of the largest VM.
+	LargestCPU resource.Quantity `json:"largestCpu"`
+	// Memory of the largest VM.
+	LargestMemory resource.Quantity `json:"largestMemory"`
+}
+
+// Limits of the destination cluster.
+type Limits struct {
+	// Target node selector.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+	// Target affinity.
+	Affinity *core.Affinity `json:"affinity,omitempty"`
+	// Capacity available in the target namespace. Only the nodes
+	// matching the node selector and the required node affinity
+	// are counted.
+	Available *ocpweb.Capacity `json:"available"`
+}
+
+// Capacity assessment of the plan.
+type Assessment struct {
+	// Resources required by the plan VMs.
+	Required *Requirements `json:"required"`
+	// Limits of the destination cluster.
+	Limits Limits `json:"limits"`
+	// Description of each resource that is not sufficient.
+	Shortfalls []string `json:"shortfalls"`
+}
+
+// Resolved storage pair.
+type pair struct {
+	// Resolved source storage.
+	source ref.Ref
+	// Destination storage class.
+	destination string
+	// Destination volume mode.
+	volumeMode core.PersistentVolumeMode
+}
+
+// Capacity planner.
+// Sums the resources required by the plan VMs and compares
+// them with the capacity available on the destination cluster.
+type Planner struct {
+	*plancontext.Context
+	// Provider specific sizer.
+	Sizer Sizer
+}
+
+// Build the planner.
+func New(ctx *plancontext.Context) (planner *Planner, err error) {
+	planner = &Planner{Context: ctx}
+	switch ctx.Source.Provider.Type() {
+	case api.VSphere:
+		planner.Sizer = &VSphere{Context: ctx}
+	case api.OVirt:
+		planner.Sizer = &OVirt{Context: ctx}
+	case api.OpenStack:
+		planner.Sizer = &OpenStack{Context: ctx}
+	case api.OpenShift:
+		planner.Sizer = &OpenShift{Context: ctx}
+	case api.Ova:
+		planner.Sizer = &Ova{Context: ctx}
+	case api.HyperV:
+		planner.Sizer = &HyperV{Context: ctx}
+	default:
+		err = liberr.New("unsupported provider type.", "type", ctx.Source.Provider.Type())
+	}
+
+	return
+}
+
+// Sum the resources required by the plan VMs.
+// Disks on unmapped storage are ignored.
+func (r *Planner) Required() (required *Requirements, err error) {
+	pairs, err := r.resolve()
+	if err != nil {
+		return
+	}
+	required = &Requirements{
+		StorageClasses: make(map[string]resource.Quantity),
+	}
+	for _, vmRef := range r.Plan.Spec.VMs {
+		var vm *VM
+		vm, err = r.Sizer.VM(vmRef.Ref)
+		if err != nil {
+			if errors.As(err, &web.NotFoundError{}) || errors.As(err, &web.RefNotUniqueError{}) {
+				err = nil
+				continue
+			}
+			return
+		}
+		cpu := resource.NewQuantity(vm.CPU, resource.DecimalSI)
+		memory := resource.NewQuantity(vm.Memory, resource.BinarySI)
+		required.CPU.Add(*cpu)
+		required.Memory.Add(*memory)
+		if cpu.Cmp(required.LargestCPU) > 0 {
+			required.LargestCPU = *cpu
+		}
+		if memory.Cmp(required.LargestMemory) > 0 {
+			required.LargestMemory = *memory
+		}
+		for _, disk := range vm.Disks {
+			for _, p := range pairs {
+				if !matches(disk.Storage, p.source) {
+					continue
+				}
+				size := resource.NewQuantity(
+					utils.CalculateSpaceWithOverhead(disk.Size, &p.volumeMode),
+					resource.BinarySI)
+				total := required.StorageClasses[p.destination]
+				total.Add(*size)
+				required.StorageClasses[p.destination] = total
+				required.Storage.Add(*size)
+				break
+			}
+		}
+	}
+
+	return
+}
+
+// Assess the capacity of the destination cluster for the plan.
+// The client is used to read the quotas and the nodes of the
+// destination cluster.
+func (r *Planner) AssessPlan(client k8sclient.Client) (assessment *Assessment, err error) {
+	required, err := r.Required()
+	if err != nil {
+		return
+	}
+	spec := &r.Plan.Spec
+	available, err := ocpweb.BuildCapacity(
+		client,
+		spec.TargetNamespace,
+		spec.TargetNodeSelector,
+		spec.TargetAffinity)
+	if err != nil {
+		return
+	}
+	assessment = &Assessment{
+		Required: required,
+		Limits: Limits{
+			NodeSelector: spec.TargetNodeSelector,
+			Affinity:     spec.TargetAffinity,
+			Available:    available,
+		},
+		Shortfalls: r.Assess(required, available),
+	}
+
+	return
+}
+
+// Compare the requirements with the capacity available
+// on the destination cluster. Returns a description of
+// each resource that is not sufficient.
+func (r *Planner) Assess(required *Requirements, available *ocpweb.Capacity) (shortfalls []string) {
+	exceeds := func(kind string, need resource.Quantity, have *resource.Quantity) {
+		if have != nil && need.Cmp(*have) > 0 {
+			shortfalls = append(
+				shortfalls,
+				fmt.Sprintf(
+					"%s: %s required, %s available.",
+					kind,
+					need.String(),
+					have.String()))
+		}
+	}
+	quota := available.Quota
+	exceeds("Quota CPU", required.CPU, quota.CPU)
+	exceeds("Quota memory", required.Memory, quota.Memory)
+	exceeds("Quota storage", required.Storage, quota.Storage)
+	classes := []string{}
+	for sc := range required.StorageClasses {
+		classes = append(classes, sc)
+	}
+	sort.Strings(classes)
+	for _, sc := range classes {
+		if remaining, found := quota.StorageClasses[sc]; found {
+			exceeds(
+				fmt.Sprintf("Quota storage (class: %s)", sc),
+				required.StorageClasses[sc],
+				&remaining)
+		}
+	}
+	if nodes := available.Nodes; nodes != nil {
+		if nodes.Count == 0 {
+			shortfalls = append(shortfalls, "Nodes: no schedulable node matches the node selector and affinity.")
+			return
+		}
+		exceeds("Nodes CPU", required.CPU, &nodes.CPU)
+		exceeds("Nodes memory", required.Memory, &nodes.Memory)
+		exceeds("Largest node CPU", required.LargestCPU, &nodes.LargestCPU)
+		exceeds("Largest node memory", required.LargestMemory, &nodes.LargestMemory)
+	}
+
+	return
+}
+
+// Resolve the source storage of the storage map pairs.
+// Pairs that cannot be resolved are ignored.
+func (r *Planner) resolve() (pairs []pair, err error) {
+	if r.Map.Storage == nil {
+		return
+	}
+	for _, p := range r.Map.Storage.Spec.Map {
+		source := p.Source
+		_, err = r.Source.Inventory.Storage(&source)
+		if err != nil {
+			if errors.As(err, &web.NotFoundError{}) || errors.As(err, &web.RefNotUniqueError{}) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err, "storage", source.String())
+			return
+		}
+		volumeMode := p.Destination.VolumeMode
+		if volumeMode == "" {
+			volumeMode = core.PersistentVolumeFilesystem
+		}
+		pairs = append(
+			pairs,
+			pair{
+				source:      source,
+				destination: p.Destination.StorageClass,
+				volumeMode:  volumeMode,
+			})
+	}
+
+	return
+}
+
+// The storage refs match.
+// Compared by ID when both are set, otherwise by name.
+func matches(a, b ref.Ref) bool {
+	if a.ID != "" && b.ID != "" {
+		return a.ID == b.ID
+	}
+	return a.Name != "" && a.Name == b.Name
+}
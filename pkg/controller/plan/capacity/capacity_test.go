@@ -0,0 +1,165 @@
+package capacity
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	ocpweb "github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Sizer returning the same VM.
+type sizer struct {
+	vm VM
+}
+
+func (r *sizer) VM(vmRef ref.Ref) (vm *VM, err error) {
+	vm = &r.vm
+	return
+}
+
+func quantity(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func TestAssess(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	planner := &Planner{}
+	required := &Requirements{
+		CPU:     resource.MustParse("12"),
+		Memory:  resource.MustParse("48Gi"),
+		Storage: resource.MustParse("600Gi"),
+		StorageClasses: map[string]resource.Quantity{
+			"fast": resource.MustParse("100Gi"),
+			"slow": resource.MustParse("500Gi"),
+		},
+		LargestCPU:    resource.MustParse("8"),
+		LargestMemory: resource.MustParse("32Gi"),
+	}
+
+	// Enough capacity.
+	available := &ocpweb.Capacity{
+		Nodes: &ocpweb.NodeCapacity{
+			Count:         2,
+			CPU:           resource.MustParse("32"),
+			Memory:        resource.MustParse("128Gi"),
+			LargestCPU:    resource.MustParse("16"),
+			LargestMemory: resource.MustParse("64Gi"),
+		},
+	}
+	g.Expect(planner.Assess(required, available)).To(gomega.BeEmpty())
+
+	// Not enough quota.
+	available.Quota = ocpweb.QuotaCapacity{
+		CPU:     quantity("10"),
+		Storage: quantity("1Ti"),
+		StorageClasses: map[string]resource.Quantity{
+			"fast": resource.MustParse("50Gi"),
+		},
+	}
+	g.Expect(planner.Assess(required, available)).To(gomega.Equal([]string{
+		"Quota CPU: 12 required, 10 available.",
+		"Quota storage (class: fast): 100Gi required, 50Gi available.",
+	}))
+
+	// The largest VM does not fit on any node.
+	available.Quota = ocpweb.QuotaCapacity{}
+	available.Nodes.LargestMemory = resource.MustParse("16Gi")
+	g.Expect(planner.Assess(required, available)).To(gomega.Equal([]string{
+		"Largest node memory: 32Gi required, 16Gi available.",
+	}))
+
+	// No matching nodes.
+	available.Nodes = &ocpweb.NodeCapacity{}
+	g.Expect(planner.Assess(required, available)).To(gomega.Equal([]string{
+		"Nodes: no schedulable node matches the node selector and affinity.",
+	}))
+
+	// Nodes unknown.
+	available.Nodes = nil
+	g.Expect(planner.Assess(required, available)).To(gomega.BeEmpty())
+}
+
+func TestMatches(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(matches(ref.Ref{ID: "1"}, ref.Ref{ID: "1", Name: "a"})).To(gomega.BeTrue())
+	g.Expect(matches(ref.Ref{ID: "1"}, ref.Ref{ID: "2", Name: "a"})).To(gomega.BeFalse())
+	g.Expect(matches(ref.Ref{Name: "a"}, ref.Ref{ID: "2", Name: "a"})).To(gomega.BeTrue())
+	g.Expect(matches(ref.Ref{}, ref.Ref{ID: "2"})).To(gomega.BeFalse())
+}
+
+func TestAssessPlan(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	affinity := &core.Affinity{
+		NodeAffinity: &core.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &core.NodeSelector{
+				NodeSelectorTerms: []core.NodeSelectorTerm{
+					{
+						MatchExpressions: []core.NodeSelectorRequirement{
+							{
+								Key:      "zone",
+								Operator: core.NodeSelectorOpIn,
+								Values:   []string{"east"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	plan := &api.Plan{}
+	plan.Spec.TargetNamespace = "target"
+	plan.Spec.TargetAffinity = affinity
+	plan.Spec.VMs = []planapi.VM{
+		{Ref: ref.Ref{ID: "vm-1"}},
+		{Ref: ref.Ref{ID: "vm-2"}},
+	}
+	planner := &Planner{
+		Context: &plancontext.Context{Plan: plan},
+		Sizer:   &sizer{vm: VM{CPU: 4, Memory: 8 * GiB}},
+	}
+	node := func(name, zone string) *core.Node {
+		return &core.Node{
+			ObjectMeta: meta.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{"zone": zone},
+			},
+			Status: core.NodeStatus{
+				Conditions: []core.NodeCondition{
+					{Type: core.NodeReady, Status: core.ConditionTrue},
+				},
+				Allocatable: core.ResourceList{
+					core.ResourceCPU:    resource.MustParse("6"),
+					core.ResourceMemory: resource.MustParse("64Gi"),
+				},
+			},
+		}
+	}
+	client := fake.NewClientBuilder().WithObjects(
+		node("east-1", "east"),
+		node("west-1", "west"),
+	).Build()
+
+	assessment, err := planner.AssessPlan(client)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(assessment.Required.CPU.String()).To(gomega.Equal("8"))
+	g.Expect(assessment.Required.Memory.String()).To(gomega.Equal("16Gi"))
+	g.Expect(assessment.Limits.Affinity).To(gomega.Equal(affinity))
+	nodes := assessment.Limits.Available.Nodes
+	g.Expect(nodes.ClusterWide).To(gomega.BeFalse())
+	g.Expect(nodes.Count).To(gomega.Equal(1))
+	g.Expect(assessment.Shortfalls).To(gomega.Equal([]string{
+		"Nodes CPU: 8 required, 6 available.",
+	}))
+}
@@ -0,0 +1,250 @@
+package capacity
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/hyperv"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ovirt"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+)
+
+const (
+	MiB = int64(1024 * 1024)
+	GiB = 1024 * MiB
+)
+
+// vSphere sizer.
+type VSphere struct {
+	*plancontext.Context
+}
+
+// Determine the resources required by the VM.
+func (r *VSphere) VM(vmRef ref.Ref) (vm *VM, err error) {
+	model := &vsphere.VM{}
+	err = r.Source.Inventory.Find(model, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	vm = &VM{
+		CPU:    int64(model.CpuCount),
+		Memory: int64(model.MemoryMB) * MiB,
+	}
+	for _, disk := range model.Disks {
+		vm.Disks = append(
+			vm.Disks,
+			Disk{
+				Storage: ref.Ref{ID: disk.Datastore.ID},
+				Size:    disk.Capacity,
+			})
+	}
+
+	return
+}
+
+// oVirt sizer.
+type OVirt struct {
+	*plancontext.Context
+}
+
+// Determine the resources required by the VM.
+// Direct LUNs are not transferred.
+func (r *OVirt) VM(vmRef ref.Ref) (vm *VM, err error) {
+	model := &ovirt.Workload{}
+	err = r.Source.Inventory.Find(model, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	vm = &VM{
+		CPU:    int64(model.CpuSockets) * int64(model.CpuCores) * int64(model.CpuThreads),
+		Memory: model.Memory,
+	}
+	for _, da := range model.DiskAttachments {
+		if da.Disk.StorageType == "lun" {
+			continue
+		}
+		vm.Disks = append(
+			vm.Disks,
+			Disk{
+				Storage: ref.Ref{ID: da.Disk.StorageDomain},
+				Size:    da.Disk.ProvisionedSize,
+			})
+	}
+
+	return
+}
+
+// OpenStack sizer.
+type OpenStack struct {
+	*plancontext.Context
+}
+
+// Determine the resources required by the VM.
+// Image based VMs are transferred from glance.
+func (r *OpenStack) VM(vmRef ref.Ref) (vm *VM, err error) {
+	model := &openstack.Workload{}
+	err = r.Source.Inventory.Find(model, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	vm = &VM{
+		CPU:    int64(model.Flavor.VCPUs),
+		Memory: int64(model.Flavor.RAM) * MiB,
+	}
+	for _, volume := range model.Volumes {
+		for _, volumeType := range model.VolumeTypes {
+			if volumeType.Name == volume.VolumeType {
+				vm.Disks = append(
+					vm.Disks,
+					Disk{
+						Storage: ref.Ref{ID: volumeType.ID},
+						Size:    int64(volume.Size) * GiB,
+					})
+				break
+			}
+		}
+	}
+	if model.ImageID != "" {
+		size := model.Image.VirtualSize
+		if size == 0 {
+			size = model.Image.SizeBytes
+		}
+		if flavorSize := int64(model.Flavor.Disk) * GiB; flavorSize > size {
+			size = flavorSize
+		}
+		vm.Disks = append(
+			vm.Disks,
+			Disk{
+				Storage: ref.Ref{Name: api.GlanceSource},
+				Size:    size,
+			})
+	}
+
+	return
+}
+
+// OpenShift sizer.
+type OpenShift struct {
+	*plancontext.Context
+}
+
+// Determine the resources required by the VM.
+// The disks are the PVCs referenced by the VM template.
+func (r *OpenShift) VM(vmRef ref.Ref) (vm *VM, err error) {
+	model := &ocp.VM{}
+	err = r.Source.Inventory.Find(model, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	vm = &VM{}
+	template := model.Object.Spec.Template
+	if template == nil {
+		return
+	}
+	domain := &template.Spec.Domain
+	vm.CPU = 1
+	if cpu := domain.CPU; cpu != nil {
+		vm.CPU = int64(max(cpu.Cores, 1)) * int64(max(cpu.Sockets, 1)) * int64(max(cpu.Threads, 1))
+	}
+	if domain.Memory != nil && domain.Memory.Guest != nil {
+		vm.Memory = domain.Memory.Guest.Value()
+	} else if memory, found := domain.Resources.Requests[core.ResourceMemory]; found {
+		vm.Memory = memory.Value()
+	}
+	for _, vol := range template.Spec.Volumes {
+		var pvcName string
+		switch {
+		case vol.PersistentVolumeClaim != nil:
+			pvcName = vol.PersistentVolumeClaim.ClaimName
+		case vol.DataVolume != nil:
+			pvcName = vol.DataVolume.Name
+		default:
+			continue
+		}
+		pvc := &ocp.PersistentVolumeClaim{}
+		err = r.Source.Inventory.Find(pvc, ref.Ref{Namespace: model.Namespace, Name: pvcName})
+		if err != nil {
+			err = liberr.Wrap(err, "pvc", pvcName)
+			return
+		}
+		if pvc.Object.Spec.StorageClassName == nil {
+			continue
+		}
+		size := pvc.Object.Spec.Resources.Requests[core.ResourceStorage]
+		vm.Disks = append(
+			vm.Disks,
+			Disk{
+				Storage: ref.Ref{Name: *pvc.Object.Spec.StorageClassName},
+				Size:    size.Value(),
+			})
+	}
+
+	return
+}
+
+// OVA sizer.
+type Ova struct {
+	*plancontext.Context
+}
+
+// Determine the resources required by the VM.
+func (r *Ova) VM(vmRef ref.Ref) (vm *VM, err error) {
+	model := &ova.VM{}
+	err = r.Source.Inventory.Find(model, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	vm = &VM{
+		CPU:    int64(model.CpuCount),
+		Memory: int64(model.MemoryMB) * MiB,
+	}
+	for _, disk := range model.Disks {
+		vm.Disks = append(
+			vm.Disks,
+			Disk{
+				Storage: ref.Ref{ID: disk.ID},
+				Size:    disk.Capacity,
+			})
+	}
+
+	return
+}
+
+// Hyper-V sizer.
+type HyperV struct {
+	*plancontext.Context
+}
+
+// Determine the resources required by the VM.
+func (r *HyperV) VM(vmRef ref.Ref) (vm *VM, err error) {
+	model := &hyperv.VM{}
+	err = r.Source.Inventory.Find(model, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	vm = &VM{
+		CPU:    int64(model.CpuCount),
+		Memory: int64(model.MemoryMB) * MiB,
+	}
+	for _, disk := range model.Disks {
+		vm.Disks = append(
+			vm.Disks,
+			Disk{
+				Storage: ref.Ref{ID: disk.ID},
+				Size:    disk.Capacity,
+			})
+	}
+
+	return
+}
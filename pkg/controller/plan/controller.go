@@ -18,6 +18,7 @@ package plan
 
 import (
 	"context"
+	"fmt"
 	"path"
 	"sort"
 	"time"
@@ -186,6 +187,7 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 	if err != nil {
 		if k8serr.IsNotFound(err) {
 			r.Log.Info("Plan deleted.")
+			modifiedFound.delete(request.NamespacedName)
 			err = nil
 		}
 		return
@@ -245,6 +247,19 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		r.archive(plan)
 	}
 
+	// Dry run.
+	if plan.Spec.DryRun {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     DryRun,
+			Status:   True,
+			Category: api.CategoryAdvisory,
+			Reason:   UserRequested,
+			Message: fmt.Sprintf(
+				"The target objects are rendered on the ConfigMap %s and the VM ConfigMaps it lists. The plan is not executed.",
+				DryRunConfigMapName(plan)),
+		})
+	}
+
 	// Ready condition.
 	if !plan.Status.HasBlockerCondition() && !plan.Status.HasCondition(Archived) && !plan.Status.HasCondition(ValidatingVDDK) {
 		plan.Status.SetCondition(libcnd.Condition{
@@ -381,6 +396,14 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 		return
 	}
 	//
+	// Dry run.
+	// The target objects are rendered and no migration is run.
+	if plan.Spec.DryRun {
+		err = r.dryRun(ctx)
+		reQ = base.SlowReQ
+		return
+	}
+	//
 	// Find and validate the current (active) migration.
 	migration, err := r.activeMigration(plan)
 	if err != nil {
@@ -462,7 +485,7 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	//
 	// Run the migration.
 	snapshot.BeginStagingConditions()
-	runner = Migration{Context: ctx}
+	runner = Migration{Context: ctx, Recorder: r.EventRecorder}
 	reQ, err = runner.Run()
 	if err != nil {
 		return
@@ -470,7 +493,7 @@ func (r *Reconciler) execute(plan *api.Plan) (reQ time.Duration, err error) {
 	snapshot.EndStagingConditions()
 
 	// Reflect the active snapshot status on the plan.
-	for _, t := range []string{Executing, Succeeded, Failed, Canceled} {
+	for _, t := range []string{Executing, Succeeded, Failed, Canceled, api.ConditionWaiting} {
 		if cnd := snapshot.FindCondition(t); cnd != nil {
 			r.Log.V(2).Info(
 				"Snapshot condition copied to plan.",
@@ -0,0 +1,621 @@
+package plan
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	migbase "github.com/kubev2v/forklift/pkg/controller/plan/migrator/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// Annotation on the dry-run ConfigMaps: the plan generation rendered.
+	AnnDryRunGeneration = "forklift.konveyor.io/dry-run-generation"
+)
+
+// Dry-run ConfigMap keys.
+const (
+	// Rendered manifests.
+	DryRunManifestsKey = "manifests.yaml"
+	// Itinerary of the VM.
+	DryRunItineraryKey = "itinerary.yaml"
+	// Rendering error.
+	DryRunErrorKey = "error"
+	// ConfigMaps of the VMs by VM ID.
+	DryRunVMsKey = "vms.yaml"
+)
+
+// Name of the dry-run ConfigMap of the plan.
+func DryRunConfigMapName(plan *api.Plan) string {
+	return plan.Name + "-dry-run"
+}
+
+// Name of the dry-run ConfigMap of a plan VM.
+func DryRunVMConfigMapName(plan *api.Plan, id string) string {
+	name := strings.Map(
+		func(c rune) rune {
+			switch {
+			case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '.':
+				return c
+			case c >= 'A' && c <= 'Z':
+				return c - 'A' + 'a'
+			default:
+				return '-'
+			}
+		},
+		id)
+	return DryRunConfigMapName(plan) + "-" + strings.Trim(name, "-.")
+}
+
+// Itinerary of a VM rendered by the dry run.
+type DryRunItinerary struct {
+	// Itinerary name.
+	Name string `json:"name,omitempty"`
+	// Phases.
+	Phases []string `json:"phases"`
+	// Pipeline steps.
+	Pipeline []DryRunStep `json:"pipeline"`
+}
+
+// Pipeline step rendered by the dry run.
+type DryRunStep struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// Render the target objects of the plan VMs.
+// The builders are run against the live inventory with clients
+// that record the objects instead of writing them. Returns the
+// data of the plan dry-run ConfigMap: the objects shared by the
+// VMs, and the data of the VM dry-run ConfigMaps keyed by the
+// VM ID: the manifests, the itinerary and the rendering error.
+func (r *Migration) DryRun() (data map[string]string, vmData map[string]map[string]string, err error) {
+	defer func() {
+		if r.provider != nil {
+			r.provider.Close()
+		}
+	}()
+	destination := &dryRunClient{Client: r.Destination.Client}
+	r.Destination.Client = destination
+	host := &dryRunClient{Client: r.Context.Client}
+	r.Context.Client = host
+	err = r.init()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	data = map[string]string{}
+	vmData = map[string]map[string]string{}
+	err = r.kubevirt.EnsureNamespace()
+	if err == nil {
+		err = r.kubevirt.EnsureExtraV2vConfConfigMap()
+	}
+	if err != nil {
+		data[DryRunErrorKey] = liberr.Unwrap(err).Error()
+		err = nil
+	}
+	err = r.dryRunManifests(data, destination, host)
+	if err != nil {
+		return
+	}
+	for _, vm := range r.Plan.Spec.VMs {
+		rendered := map[string]string{}
+		vmData[vm.ID] = rendered
+		itinerary, iErr := r.dryRunItinerary(vm)
+		if iErr != nil {
+			rendered[DryRunErrorKey] = liberr.Unwrap(iErr).Error()
+			continue
+		}
+		var content []byte
+		content, err = yaml.Marshal(itinerary)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		rendered[DryRunItineraryKey] = string(content)
+		rErr := r.dryRunVM(vm, itinerary)
+		if rErr != nil {
+			rendered[DryRunErrorKey] = liberr.Unwrap(rErr).Error()
+		}
+		err = r.dryRunManifests(rendered, destination, host)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Itinerary the VM would follow.
+func (r *Migration) dryRunItinerary(vm plan.VM) (itinerary *DryRunItinerary, err error) {
+	itr := r.migrator.Itinerary(vm)
+	phases, err := itr.List()
+	if err != nil {
+		return
+	}
+	pipeline, err := r.migrator.Pipeline(vm)
+	if err != nil {
+		return
+	}
+	itinerary = &DryRunItinerary{Name: itr.Name}
+	for _, phase := range phases {
+		itinerary.Phases = append(itinerary.Phases, phase.Name)
+	}
+	for _, step := range pipeline {
+		itinerary.Pipeline = append(
+			itinerary.Pipeline,
+			DryRunStep{
+				Name:        step.Name,
+				Description: step.Description,
+			})
+	}
+
+	return
+}
+
+// Render the target objects of the VM.
+// Follows the phases of the itinerary that create objects.
+func (r *Migration) dryRunVM(planVM plan.VM, itinerary *DryRunItinerary) (err error) {
+	vm := &plan.VMStatus{VM: planVM}
+	if r.Plan.IsWarm() {
+		vm.Warm = &plan.Warm{}
+	}
+	vm.Pipeline, err = r.migrator.Pipeline(planVM)
+	if err != nil {
+		return
+	}
+	err = r.dryRunName(vm)
+	if err != nil {
+		return
+	}
+	has := func(phase string) bool {
+		return slices.Contains(itinerary.Phases, phase)
+	}
+	step := func(name string) *plan.Step {
+		if step, found := vm.FindStep(name); found {
+			return step
+		}
+		return &plan.Step{}
+	}
+	if has(api.PhaseCreateDataVolumes) {
+		if r.builder.SupportsVolumePopulators() {
+			var pvcs []*core.PersistentVolumeClaim
+			pvcs, err = r.kubevirt.PopulatorVolumes(vm.Ref)
+			if err != nil {
+				return
+			}
+			err = r.kubevirt.EnsurePopulatorVolumes(vm, pvcs)
+			if err != nil {
+				return
+			}
+		}
+		if r.Plan.IsWarm() || !r.builder.SupportsVolumePopulators() {
+			var dataVolumes []cdi.DataVolume
+			dataVolumes, err = r.kubevirt.DataVolumes(vm)
+			if err != nil {
+				return
+			}
+			err = r.kubevirt.EnsureDataVolumes(vm, dataVolumes)
+			if err != nil {
+				return
+			}
+			destination := r.Destination.Client.(*dryRunClient)
+			for _, object := range destination.objects {
+				if dv, cast := object.(*cdi.DataVolume); cast {
+					destination.derive(dryRunClaim(dv))
+				}
+			}
+		}
+	}
+	if has(api.PhasePreflightInspection) {
+		_, err = r.ensureGuestInspectionPod(vm, step(migbase.PreflightInspection))
+		if err != nil {
+			return
+		}
+	}
+	if has(api.PhaseCreateGuestConversionPod) {
+		_, err = r.ensureGuestConversionPod(vm, step(migbase.ImageConversion))
+		if err != nil {
+			return
+		}
+	}
+	if has(api.PhaseCreateVM) {
+		err = r.kubevirt.EnsureVM(vm)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Name of the target VM.
+// Follows the naming of the started phase.
+func (r *Migration) dryRunName(vm *plan.VMStatus) (err error) {
+	if vm.TargetName != "" {
+		vm.NewName = vm.TargetName
+		return
+	}
+	if errs := k8svalidation.IsDNS1123Subdomain(vm.Name); len(errs) > 0 {
+		vm.NewName, err = r.kubevirt.changeVmNameDNS1123(vm.Name, r.Plan.Spec.TargetNamespace)
+	}
+	return
+}
+
+// Render the objects recorded since the last call as YAML manifests.
+// The values of the secrets are redacted.
+func (r *Migration) dryRunManifests(data map[string]string, clients ...*dryRunClient) (err error) {
+	var docs []string
+	for _, recorder := range clients {
+		for _, object := range recorder.objects[recorder.rendered:] {
+			object = object.DeepCopyObject().(client.Object)
+			dryRunRedact(object)
+			gvk, gErr := apiutil.GVKForObject(object, recorder.Scheme())
+			if gErr == nil {
+				object.GetObjectKind().SetGroupVersionKind(gvk)
+			}
+			var content []byte
+			content, err = yaml.Marshal(object)
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+			docs = append(docs, string(content))
+		}
+		recorder.rendered = len(recorder.objects)
+	}
+	if len(docs) > 0 {
+		data[DryRunManifestsKey] = strings.Join(docs, "---\n")
+	}
+	return
+}
+
+// Redact the values of a secret.
+// Only the keys are rendered.
+func dryRunRedact(object client.Object) {
+	secret, cast := object.(*core.Secret)
+	if !cast {
+		return
+	}
+	for key := range secret.Data {
+		secret.Data[key] = []byte{}
+	}
+	for key := range secret.StringData {
+		secret.StringData[key] = ""
+	}
+}
+
+// PVC created by CDI for the DataVolume.
+func dryRunClaim(dv *cdi.DataVolume) *core.PersistentVolumeClaim {
+	return &core.PersistentVolumeClaim{
+		ObjectMeta: meta.ObjectMeta{
+			Name:        dv.Name,
+			Namespace:   dv.Namespace,
+			Labels:      dv.Labels,
+			Annotations: dv.Annotations,
+		},
+	}
+}
+
+// Render the dry run of the plan.
+// The dry-run ConfigMaps are rendered again when the plan changes.
+func (r *Reconciler) dryRun(ctx *plancontext.Context) (err error) {
+	plan := ctx.Plan
+	generation := strconv.FormatInt(plan.Generation, 10)
+	configMap := &core.ConfigMap{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: plan.Namespace,
+			Name:      DryRunConfigMapName(plan),
+		},
+		configMap)
+	if err != nil && !k8serr.IsNotFound(err) {
+		err = liberr.Wrap(err)
+		return
+	}
+	if err == nil && configMap.Annotations[AnnDryRunGeneration] == generation {
+		return
+	}
+	runner := Migration{Context: ctx}
+	data, vmData, err := runner.DryRun()
+	if err != nil {
+		return
+	}
+	err = r.saveDryRun(plan, data, vmData)
+	if err != nil {
+		return
+	}
+	r.Log.Info(
+		"Dry run rendered.",
+		"configMap",
+		DryRunConfigMapName(plan),
+		"generation",
+		generation)
+
+	return
+}
+
+// Save the dry run of the plan.
+// Each VM is rendered on its own ConfigMap, labeled with the plan
+// and the VM, so that the size of a ConfigMap does not depend on
+// the number of VMs. The ConfigMaps of the VMs no longer on the
+// plan are deleted. The plan ConfigMap, listing the ConfigMaps of
+// the VMs, is saved last so the dry run is rendered again when
+// interrupted.
+func (r *Reconciler) saveDryRun(plan *api.Plan, data map[string]string, vmData map[string]map[string]string) (err error) {
+	names := map[string]string{}
+	for id, rendered := range vmData {
+		name := DryRunVMConfigMapName(plan, id)
+		names[id] = name
+		labels := map[string]string{
+			kPlan:     string(plan.UID),
+			kVM:       id,
+			kResource: ResourceDryRun,
+		}
+		err = r.saveDryRunConfigMap(plan, name, labels, rendered)
+		if err != nil {
+			return
+		}
+	}
+	list := &core.ConfigMapList{}
+	err = r.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			Namespace: plan.Namespace,
+			LabelSelector: k8slabels.SelectorFromSet(map[string]string{
+				kPlan:     string(plan.UID),
+				kResource: ResourceDryRun,
+			}),
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		stale := &list.Items[i]
+		if _, found := vmData[stale.Labels[kVM]]; found {
+			continue
+		}
+		err = r.Delete(context.TODO(), stale)
+		if err != nil && !k8serr.IsNotFound(err) {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = nil
+	}
+	content, err := yaml.Marshal(names)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	planData := map[string]string{DryRunVMsKey: string(content)}
+	for key, value := range data {
+		planData[key] = value
+	}
+	err = r.saveDryRunConfigMap(plan, DryRunConfigMapName(plan), nil, planData)
+	return
+}
+
+// Create or update a dry-run ConfigMap owned by the plan.
+func (r *Reconciler) saveDryRunConfigMap(plan *api.Plan, name string, labels map[string]string, data map[string]string) (err error) {
+	configMap := &core.ConfigMap{}
+	err = r.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: plan.Namespace,
+			Name:      name,
+		},
+		configMap)
+	found := err == nil
+	if err != nil && !k8serr.IsNotFound(err) {
+		err = liberr.Wrap(err)
+		return
+	}
+	configMap.Namespace = plan.Namespace
+	configMap.Name = name
+	if configMap.Annotations == nil {
+		configMap.Annotations = map[string]string{}
+	}
+	configMap.Annotations[AnnDryRunGeneration] = strconv.FormatInt(plan.Generation, 10)
+	if configMap.Labels == nil {
+		configMap.Labels = map[string]string{}
+	}
+	for key, value := range labels {
+		configMap.Labels[key] = value
+	}
+	configMap.OwnerReferences = []meta.OwnerReference{
+		{
+			APIVersion: api.SchemeGroupVersion.String(),
+			Kind:       "Plan",
+			Name:       plan.Name,
+			UID:        plan.UID,
+		},
+	}
+	configMap.Data = data
+	if found {
+		err = r.Update(context.TODO(), configMap)
+	} else {
+		err = r.Create(context.TODO(), configMap)
+	}
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Client that records the objects written instead of writing
+// them. The recorded objects are found by the reads so the
+// objects created by a phase are seen by the following phases.
+type dryRunClient struct {
+	client.Client
+	// Recorded objects.
+	objects []client.Object
+	// Objects derived from the recorded objects, e.g. the PVCs
+	// created by CDI for the DataVolumes. Found but not rendered.
+	derived []client.Object
+	// Number of objects rendered.
+	rendered int
+}
+
+// Record the object.
+// The name is generated as the API server would.
+func (r *dryRunClient) Create(ctx context.Context, object client.Object, opts ...client.CreateOption) (err error) {
+	if object.GetName() == "" && object.GetGenerateName() != "" {
+		object.SetName(object.GetGenerateName() + utilrand.String(5))
+	}
+	r.objects = append(r.objects, object.DeepCopyObject().(client.Object))
+	return
+}
+
+// Record the update of a recorded object.
+func (r *dryRunClient) Update(ctx context.Context, object client.Object, opts ...client.UpdateOption) (err error) {
+	for i, recorded := range r.objects {
+		if r.match(recorded, object) {
+			r.objects[i] = object.DeepCopyObject().(client.Object)
+		}
+	}
+	return
+}
+
+// Patches are not recorded.
+func (r *dryRunClient) Patch(ctx context.Context, object client.Object, patch client.Patch, opts ...client.PatchOption) (err error) {
+	return
+}
+
+// Deletes are not recorded.
+func (r *dryRunClient) Delete(ctx context.Context, object client.Object, opts ...client.DeleteOption) (err error) {
+	return
+}
+
+// Deletes are not recorded.
+func (r *dryRunClient) DeleteAllOf(ctx context.Context, object client.Object, opts ...client.DeleteAllOfOption) (err error) {
+	return
+}
+
+// Status updates are not recorded.
+func (r *dryRunClient) Status() client.SubResourceWriter {
+	return &dryRunSubResource{}
+}
+
+// Sub-resource writes are not recorded.
+func (r *dryRunClient) SubResource(name string) client.SubResourceClient {
+	return &dryRunSubResource{SubResourceClient: r.Client.SubResource(name)}
+}
+
+// Get the object.
+// Falls back to the recorded objects.
+func (r *dryRunClient) Get(ctx context.Context, key client.ObjectKey, object client.Object, opts ...client.GetOption) (err error) {
+	err = r.Client.Get(ctx, key, object, opts...)
+	if !k8serr.IsNotFound(err) {
+		return
+	}
+	for _, recorded := range r.recorded() {
+		if reflect.TypeOf(recorded) == reflect.TypeOf(object) &&
+			recorded.GetNamespace() == key.Namespace &&
+			recorded.GetName() == key.Name {
+			reflect.ValueOf(object).Elem().Set(reflect.ValueOf(recorded.DeepCopyObject()).Elem())
+			err = nil
+			return
+		}
+	}
+	return
+}
+
+// List the objects.
+// The recorded objects matching the options are appended.
+func (r *dryRunClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) (err error) {
+	err = r.Client.List(ctx, list, opts...)
+	if err != nil {
+		return
+	}
+	items, err := apimeta.ExtractList(list)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	itemsField := reflect.ValueOf(list).Elem().FieldByName("Items")
+	if !itemsField.IsValid() {
+		return
+	}
+	itemType := reflect.PointerTo(itemsField.Type().Elem())
+	options := &client.ListOptions{}
+	options.ApplyOptions(opts)
+	for _, recorded := range r.recorded() {
+		if reflect.TypeOf(recorded) != itemType {
+			continue
+		}
+		if options.Namespace != "" && recorded.GetNamespace() != options.Namespace {
+			continue
+		}
+		if options.LabelSelector != nil && !options.LabelSelector.Matches(k8slabels.Set(recorded.GetLabels())) {
+			continue
+		}
+		items = append(items, recorded.DeepCopyObject())
+	}
+	err = apimeta.SetList(list, items)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+	return
+}
+
+// Record a derived object.
+func (r *dryRunClient) derive(object client.Object) {
+	for _, derived := range r.derived {
+		if r.match(derived, object) {
+			return
+		}
+	}
+	r.derived = append(r.derived, object)
+}
+
+// Recorded and derived objects.
+func (r *dryRunClient) recorded() (objects []client.Object) {
+	objects = append(objects, r.objects...)
+	objects = append(objects, r.derived...)
+	return
+}
+
+// Match objects by type, namespace and name.
+func (r *dryRunClient) match(a, b client.Object) bool {
+	return reflect.TypeOf(a) == reflect.TypeOf(b) &&
+		a.GetNamespace() == b.GetNamespace() &&
+		a.GetName() == b.GetName()
+}
+
+// Sub-resource client that does not write.
+type dryRunSubResource struct {
+	client.SubResourceClient
+}
+
+func (r *dryRunSubResource) Create(ctx context.Context, object client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	return nil
+}
+
+func (r *dryRunSubResource) Update(ctx context.Context, object client.Object, opts ...client.SubResourceUpdateOption) error {
+	return nil
+}
+
+func (r *dryRunSubResource) Patch(ctx context.Context, object client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return nil
+}
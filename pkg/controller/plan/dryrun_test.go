@@ -0,0 +1,195 @@
+package plan
+
+import (
+	"context"
+	"encoding/base64"
+
+	v1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("dry run", func() {
+	var recorder *dryRunClient
+
+	ginkgo.BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		_ = v1.AddToScheme(scheme)
+		existing := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "existing",
+				Namespace: "test",
+				Labels:    map[string]string{"vmID": "vm-1"},
+			},
+		}
+		recorder = &dryRunClient{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(existing).Build(),
+		}
+	})
+
+	ginkgo.It("should record the objects instead of creating them", func() {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "plan-vm-1-",
+				Namespace:    "test",
+				Labels:       map[string]string{"vmID": "vm-1"},
+			},
+		}
+		Expect(recorder.Create(context.TODO(), secret)).To(Succeed())
+		Expect(secret.Name).To(HavePrefix("plan-vm-1-"))
+		Expect(recorder.objects).To(HaveLen(1))
+
+		// Not created.
+		err := recorder.Client.Get(context.TODO(), client.ObjectKeyFromObject(secret), &v1.Secret{})
+		Expect(err).To(HaveOccurred())
+
+		// Found by the reads.
+		found := &v1.Secret{}
+		Expect(recorder.Get(context.TODO(), client.ObjectKeyFromObject(secret), found)).To(Succeed())
+		Expect(found.Name).To(Equal(secret.Name))
+		list := &v1.SecretList{}
+		Expect(recorder.List(
+			context.TODO(),
+			list,
+			&client.ListOptions{
+				Namespace:     "test",
+				LabelSelector: k8slabels.SelectorFromSet(map[string]string{"vmID": "vm-1"}),
+			})).To(Succeed())
+		Expect(list.Items).To(HaveLen(2))
+		Expect(recorder.List(
+			context.TODO(),
+			list,
+			&client.ListOptions{
+				LabelSelector: k8slabels.SelectorFromSet(map[string]string{"vmID": "vm-2"}),
+			})).To(Succeed())
+		Expect(list.Items).To(BeEmpty())
+	})
+
+	ginkgo.It("should not write updates and deletes", func() {
+		existing := &v1.Secret{}
+		key := client.ObjectKey{Namespace: "test", Name: "existing"}
+		Expect(recorder.Get(context.TODO(), key, existing)).To(Succeed())
+		existing.Labels["vmID"] = "vm-2"
+		Expect(recorder.Update(context.TODO(), existing)).To(Succeed())
+		Expect(recorder.Delete(context.TODO(), existing)).To(Succeed())
+		Expect(recorder.Client.Get(context.TODO(), key, existing)).To(Succeed())
+		Expect(existing.Labels["vmID"]).To(Equal("vm-1"))
+		Expect(recorder.objects).To(BeEmpty())
+	})
+
+	ginkgo.It("should render the recorded objects once", func() {
+		configMap := &v1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "vm-1", Namespace: "test"}}
+		Expect(recorder.Create(context.TODO(), configMap)).To(Succeed())
+		runner := &Migration{}
+		data := map[string]string{}
+		Expect(runner.dryRunManifests(data, recorder)).To(Succeed())
+		Expect(data[DryRunManifestsKey]).To(ContainSubstring("kind: ConfigMap"))
+		Expect(data[DryRunManifestsKey]).To(ContainSubstring("apiVersion: v1"))
+		data = map[string]string{}
+		Expect(runner.dryRunManifests(data, recorder)).To(Succeed())
+		Expect(data).ToNot(HaveKey(DryRunManifestsKey))
+	})
+
+	ginkgo.It("should not render the secret values", func() {
+		secret := &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "vm-1", Namespace: "test"},
+			Data: map[string][]byte{
+				"accessKeyId": []byte("administrator@vsphere.local"),
+				"secretKey":   []byte("p4ssw0rd"),
+			},
+			StringData: map[string]string{
+				"cacert": "-----BEGIN CERTIFICATE-----",
+			},
+		}
+		Expect(recorder.Create(context.TODO(), secret)).To(Succeed())
+		runner := &Migration{}
+		data := map[string]string{}
+		Expect(runner.dryRunManifests(data, recorder)).To(Succeed())
+		Expect(data[DryRunManifestsKey]).To(ContainSubstring("kind: Secret"))
+		Expect(data[DryRunManifestsKey]).To(ContainSubstring("accessKeyId"))
+		Expect(data[DryRunManifestsKey]).To(ContainSubstring("cacert"))
+		for _, value := range []string{"administrator@vsphere.local", "p4ssw0rd", "BEGIN CERTIFICATE"} {
+			Expect(data[DryRunManifestsKey]).ToNot(ContainSubstring(value))
+			Expect(data[DryRunManifestsKey]).ToNot(ContainSubstring(base64.StdEncoding.EncodeToString([]byte(value))))
+		}
+		// The recorded secret is not modified.
+		Expect(recorder.objects[0].(*v1.Secret).Data["secretKey"]).To(Equal([]byte("p4ssw0rd")))
+	})
+
+	ginkgo.It("should build valid VM ConfigMap names", func() {
+		plan := &v1beta1.Plan{ObjectMeta: metav1.ObjectMeta{Name: "plan"}}
+		Expect(DryRunVMConfigMapName(plan, "vm-42")).To(Equal("plan-dry-run-vm-42"))
+		Expect(DryRunVMConfigMapName(plan, "ns/VM_1:")).To(Equal("plan-dry-run-ns-vm-1"))
+	})
+
+	ginkgo.It("should save a ConfigMap per VM", func() {
+		plan := &v1beta1.Plan{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "plan",
+				Namespace:  "test",
+				UID:        "plan-uid",
+				Generation: 2,
+			},
+		}
+		stale := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      DryRunVMConfigMapName(plan, "vm-3"),
+				Namespace: "test",
+				Labels: map[string]string{
+					kPlan:     "plan-uid",
+					kVM:       "vm-3",
+					kResource: ResourceDryRun,
+				},
+			},
+		}
+		reconciler := createFakeReconciler(stale)
+		Expect(reconciler.saveDryRun(
+			plan,
+			map[string]string{DryRunManifestsKey: "kind: Namespace"},
+			map[string]map[string]string{
+				"vm-1": {DryRunManifestsKey: "kind: VirtualMachine"},
+				"vm-2": {DryRunErrorKey: "failed"},
+			})).To(Succeed())
+
+		// The stale ConfigMap is deleted.
+		list := &v1.ConfigMapList{}
+		Expect(reconciler.List(
+			context.TODO(),
+			list,
+			&client.ListOptions{
+				Namespace: "test",
+				LabelSelector: k8slabels.SelectorFromSet(map[string]string{
+					kPlan:     "plan-uid",
+					kResource: ResourceDryRun,
+				}),
+			})).To(Succeed())
+		Expect(list.Items).To(HaveLen(2))
+		for _, configMap := range list.Items {
+			Expect(configMap.Name).To(Equal(DryRunVMConfigMapName(plan, configMap.Labels[kVM])))
+			Expect(configMap.Annotations[AnnDryRunGeneration]).To(Equal("2"))
+			Expect(configMap.OwnerReferences).To(HaveLen(1))
+		}
+		vm := &v1.ConfigMap{}
+		Expect(reconciler.Get(
+			context.TODO(),
+			client.ObjectKey{Namespace: "test", Name: "plan-dry-run-vm-1"},
+			vm)).To(Succeed())
+		Expect(vm.Data[DryRunManifestsKey]).To(Equal("kind: VirtualMachine"))
+
+		// The plan ConfigMap lists the VM ConfigMaps.
+		configMap := &v1.ConfigMap{}
+		Expect(reconciler.Get(
+			context.TODO(),
+			client.ObjectKey{Namespace: "test", Name: DryRunConfigMapName(plan)},
+			configMap)).To(Succeed())
+		Expect(configMap.Data[DryRunManifestsKey]).To(Equal("kind: Namespace"))
+		Expect(configMap.Data[DryRunVMsKey]).To(ContainSubstring("vm-2: plan-dry-run-vm-2"))
+		Expect(configMap.Annotations[AnnDryRunGeneration]).To(Equal("2"))
+	})
+})
@@ -0,0 +1,123 @@
+package plan
+
+import (
+	"strings"
+
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// Hardware overrides of the VM.
+// The overrides of the VM are applied over the overrides of the plan.
+func (r *KubeVirt) hardware(vm *planapi.VM) *planapi.Hardware {
+	return r.Plan.Spec.Hardware.With(vm.Hardware)
+}
+
+// Apply the hardware overrides to the target VM.
+// Called after the builder mapped the hardware of the source VM.
+func applyHardware(hardware *planapi.Hardware, object *cnv.VirtualMachineSpec) {
+	if hardware == nil || object.Template == nil {
+		return
+	}
+	domain := &object.Template.Spec.Domain
+	if hardware.CPU != nil {
+		applyCPU(hardware.CPU, domain)
+	}
+	if hardware.Memory != nil {
+		applyMemory(hardware.Memory, domain)
+	}
+	for _, nic := range hardware.NICs {
+		for i := range domain.Devices.Interfaces {
+			iface := &domain.Devices.Interfaces[i]
+			if nic.MAC != "" && !strings.EqualFold(nic.MAC, iface.MacAddress) {
+				continue
+			}
+			if nic.Index != nil && *nic.Index != i {
+				continue
+			}
+			iface.Model = nic.Model
+		}
+	}
+	for _, disk := range hardware.Disks {
+		for i := range domain.Devices.Disks {
+			target := domain.Devices.Disks[i].DiskDevice.Disk
+			if target == nil {
+				continue
+			}
+			if disk.Index != nil && *disk.Index != i {
+				continue
+			}
+			target.Bus = disk.Bus
+		}
+	}
+	if hardware.MachineType != "" {
+		domain.Machine = &cnv.Machine{Type: hardware.MachineType}
+	}
+	if hardware.Firmware != nil {
+		applyFirmware(hardware.Firmware, domain)
+	}
+}
+
+// Apply the CPU overrides.
+func applyCPU(cpu *planapi.CPU, domain *cnv.DomainSpec) {
+	if domain.CPU == nil {
+		domain.CPU = &cnv.CPU{}
+	}
+	if cpu.Sockets > 0 {
+		domain.CPU.Sockets = cpu.Sockets
+	}
+	if cpu.Cores > 0 {
+		domain.CPU.Cores = cpu.Cores
+	}
+	if cpu.Threads > 0 {
+		domain.CPU.Threads = cpu.Threads
+	}
+	if cpu.DedicatedCPUPlacement != nil {
+		domain.CPU.DedicatedCPUPlacement = *cpu.DedicatedCPUPlacement
+	}
+}
+
+// Apply the memory overrides.
+// With overcommit, the VM requests a share of the guest memory.
+func applyMemory(memory *planapi.Memory, domain *cnv.DomainSpec) {
+	if memory.Guest != nil {
+		guest := memory.Guest.DeepCopy()
+		domain.Memory = &cnv.Memory{Guest: &guest}
+	}
+	if memory.Overcommit <= 100 || domain.Memory == nil || domain.Memory.Guest == nil {
+		return
+	}
+	request := domain.Memory.Guest.Value() * 100 / int64(memory.Overcommit)
+	if domain.Resources.Requests == nil {
+		domain.Resources.Requests = core.ResourceList{}
+	}
+	domain.Resources.Requests[core.ResourceMemory] = *resource.NewQuantity(request, resource.BinarySI)
+}
+
+// Apply the firmware overrides.
+// Secure boot requires the SMM feature.
+func applyFirmware(firmware *planapi.Firmware, domain *cnv.DomainSpec) {
+	if domain.Firmware == nil {
+		domain.Firmware = &cnv.Firmware{}
+	}
+	switch firmware.Type {
+	case planapi.FirmwareEFI:
+		domain.Firmware.Bootloader = &cnv.Bootloader{EFI: &cnv.EFI{}}
+	case planapi.FirmwareBIOS:
+		domain.Firmware.Bootloader = &cnv.Bootloader{BIOS: &cnv.BIOS{}}
+	}
+	bootloader := domain.Firmware.Bootloader
+	if firmware.SecureBoot == nil || bootloader == nil || bootloader.EFI == nil {
+		return
+	}
+	secureBoot := *firmware.SecureBoot
+	bootloader.EFI.SecureBoot = &secureBoot
+	if secureBoot {
+		if domain.Features == nil {
+			domain.Features = &cnv.Features{}
+		}
+		domain.Features.SMM = &cnv.FeatureState{Enabled: &secureBoot}
+	}
+}
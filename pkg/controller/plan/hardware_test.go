@@ -0,0 +1,118 @@
+package plan
+
+import (
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+var _ = ginkgo.Describe("hardware overrides", func() {
+	var object *cnv.VirtualMachineSpec
+
+	ginkgo.BeforeEach(func() {
+		guest := resource.MustParse("4Gi")
+		object = &cnv.VirtualMachineSpec{
+			Template: &cnv.VirtualMachineInstanceTemplateSpec{
+				Spec: cnv.VirtualMachineInstanceSpec{
+					Domain: cnv.DomainSpec{
+						CPU:    &cnv.CPU{Sockets: 2, Cores: 1},
+						Memory: &cnv.Memory{Guest: &guest},
+						Firmware: &cnv.Firmware{
+							Serial:     "serial",
+							Bootloader: &cnv.Bootloader{BIOS: &cnv.BIOS{}},
+						},
+						Devices: cnv.Devices{
+							Interfaces: []cnv.Interface{
+								{Name: "net-0", MacAddress: "00:50:56:00:00:01", Model: "e1000e"},
+								{Name: "net-1", MacAddress: "00:50:56:00:00:02", Model: "e1000e"},
+							},
+							Disks: []cnv.Disk{
+								{Name: "vol-0", DiskDevice: cnv.DiskDevice{Disk: &cnv.DiskTarget{Bus: cnv.DiskBusSATA}}},
+								{Name: "vol-1", DiskDevice: cnv.DiskDevice{Disk: &cnv.DiskTarget{Bus: cnv.DiskBusSATA}}},
+							},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	ginkgo.It("should keep the mapped hardware without overrides", func() {
+		expected := object.DeepCopy()
+		applyHardware(nil, object)
+		Expect(object).To(Equal(expected))
+	})
+
+	ginkgo.It("should override the CPU and memory", func() {
+		dedicated := true
+		guest := resource.MustParse("8Gi")
+		applyHardware(
+			&planapi.Hardware{
+				CPU:    &planapi.CPU{Cores: 4, DedicatedCPUPlacement: &dedicated},
+				Memory: &planapi.Memory{Guest: &guest, Overcommit: 200},
+			},
+			object)
+		domain := object.Template.Spec.Domain
+		Expect(domain.CPU.Sockets).To(BeEquivalentTo(2))
+		Expect(domain.CPU.Cores).To(BeEquivalentTo(4))
+		Expect(domain.CPU.DedicatedCPUPlacement).To(BeTrue())
+		Expect(domain.Memory.Guest.String()).To(Equal("8Gi"))
+		request := domain.Resources.Requests[core.ResourceMemory]
+		Expect(request.String()).To(Equal("4Gi"))
+	})
+
+	ginkgo.It("should override the NIC models and disk buses", func() {
+		index := 1
+		applyHardware(
+			&planapi.Hardware{
+				NICs: []planapi.NICOverride{
+					{Model: "virtio"},
+					{MAC: "00:50:56:00:00:02", Model: "rtl8139"},
+				},
+				Disks: []planapi.DiskOverride{
+					{Index: &index, Bus: cnv.DiskBusVirtio},
+				},
+			},
+			object)
+		devices := object.Template.Spec.Domain.Devices
+		Expect(devices.Interfaces[0].Model).To(Equal("virtio"))
+		Expect(devices.Interfaces[1].Model).To(Equal("rtl8139"))
+		Expect(devices.Disks[0].Disk.Bus).To(Equal(cnv.DiskBusSATA))
+		Expect(devices.Disks[1].Disk.Bus).To(Equal(cnv.DiskBusVirtio))
+	})
+
+	ginkgo.It("should override the machine type and firmware", func() {
+		secureBoot := true
+		applyHardware(
+			&planapi.Hardware{
+				MachineType: "q35",
+				Firmware:    &planapi.Firmware{Type: planapi.FirmwareEFI, SecureBoot: &secureBoot},
+			},
+			object)
+		domain := object.Template.Spec.Domain
+		Expect(domain.Machine.Type).To(Equal("q35"))
+		Expect(domain.Firmware.Serial).To(Equal("serial"))
+		Expect(*domain.Firmware.Bootloader.EFI.SecureBoot).To(BeTrue())
+		Expect(*domain.Features.SMM.Enabled).To(BeTrue())
+	})
+
+	ginkgo.It("should apply the VM overrides over the plan overrides", func() {
+		plan := &planapi.Hardware{
+			MachineType: "q35",
+			CPU:         &planapi.CPU{Sockets: 1},
+			NICs:        []planapi.NICOverride{{Model: "virtio"}},
+		}
+		vm := &planapi.Hardware{
+			CPU:  &planapi.CPU{Sockets: 4},
+			NICs: []planapi.NICOverride{{MAC: "00:50:56:00:00:01", Model: "e1000e"}},
+		}
+		merged := plan.With(vm)
+		Expect(merged.MachineType).To(Equal("q35"))
+		Expect(merged.CPU.Sockets).To(BeEquivalentTo(4))
+		Expect(merged.NICs).To(HaveLen(2))
+		Expect(plan.CPU.Sockets).To(BeEquivalentTo(1))
+	})
+})
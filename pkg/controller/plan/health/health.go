@@ -0,0 +1,257 @@
+package health
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	core "k8s.io/api/core/v1"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// Checks.
+const (
+	Ready       = "Ready"
+	ExpectedIPs = "ExpectedIPs"
+	HTTP        = "HTTP"
+	PortPrefix  = "Port-"
+)
+
+// Check results.
+// Reported as the reason of the check task.
+const (
+	Passed  = "Passed"
+	Failed  = "Failed"
+	Skipped = "Skipped"
+)
+
+// Annotations.
+const (
+	// Details of a check that did not pass.
+	AnnDetail = "detail"
+)
+
+// Probe timeout.
+const ProbeTimeout = 3 * time.Second
+
+// Build the check tasks.
+func Tasks(spec *plan.TargetValidation) (tasks []*plan.Task) {
+	add := func(name, description string) {
+		tasks = append(
+			tasks,
+			&plan.Task{
+				Name:        name,
+				Description: description,
+				Progress:    libitr.Progress{Total: 1},
+			})
+	}
+	add(Ready, "VM ready and guest agent connected.")
+	if spec.ExpectedIPs {
+		add(ExpectedIPs, "Guest reports the source IPs.")
+	}
+	for _, port := range spec.Ports {
+		add(
+			PortPrefix+strconv.Itoa(int(port)),
+			fmt.Sprintf("TCP port %d is open.", port))
+	}
+	if spec.HTTP != nil {
+		add(HTTP, "HTTP endpoint responds.")
+	}
+
+	return
+}
+
+// Result of a check.
+type Result struct {
+	// The check passed.
+	Passed bool
+	// The check is not applicable.
+	Skipped bool
+	// Explains why the check did not pass.
+	Detail string
+}
+
+// Target VM checker.
+type Checker struct {
+	// Validation spec.
+	Spec *plan.TargetValidation
+	// The VMI, nil when not (yet) created.
+	VMI *cnv.VirtualMachineInstance
+	// IPs reported by the source guest.
+	// Nil when not known.
+	SourceIPs []string
+	// Reason the probes are skipped.
+	// The probes are run when empty.
+	SkipProbes string
+}
+
+// Run a check.
+func (r *Checker) Check(name string) (result Result) {
+	if name != Ready {
+		if ready := r.ready(); !ready.Passed {
+			result.Detail = "The VM is not ready."
+			return
+		}
+	}
+	switch {
+	case name == Ready:
+		result = r.ready()
+	case name == ExpectedIPs:
+		result = r.expectedIPs()
+	case name == HTTP:
+		result = r.http()
+	case strings.HasPrefix(name, PortPrefix):
+		port, err := strconv.Atoi(strings.TrimPrefix(name, PortPrefix))
+		if err != nil {
+			result.Detail = err.Error()
+			return
+		}
+		result = r.tcp(port)
+	default:
+		result.Skipped = true
+		result.Detail = fmt.Sprintf("Check '%s' unknown.", name)
+	}
+
+	return
+}
+
+// The VMI is ready and the guest agent is connected.
+func (r *Checker) ready() (result Result) {
+	if r.VMI == nil {
+		result.Detail = "The VMI has not been created."
+		return
+	}
+	conditions := map[cnv.VirtualMachineInstanceConditionType]bool{}
+	for _, cnd := range r.VMI.Status.Conditions {
+		conditions[cnd.Type] = cnd.Status == core.ConditionTrue
+	}
+	switch {
+	case !conditions[cnv.VirtualMachineInstanceReady]:
+		result.Detail = "The VMI is not ready."
+	case !conditions[cnv.VirtualMachineInstanceAgentConnected]:
+		result.Detail = "The guest agent is not connected."
+	default:
+		result.Passed = true
+	}
+
+	return
+}
+
+// The guest reports the IPs reported by the source guest.
+func (r *Checker) expectedIPs() (result Result) {
+	if r.SourceIPs == nil {
+		result.Skipped = true
+		result.Detail = "The source guest IPs are not known."
+		return
+	}
+	reported := []string{}
+	for _, iface := range r.VMI.Status.Interfaces {
+		reported = append(reported, iface.IPs...)
+		if iface.IP != "" {
+			reported = append(reported, iface.IP)
+		}
+	}
+	missing := []string{}
+	for _, ip := range r.SourceIPs {
+		if !slices.Contains(reported, ip) {
+			missing = append(missing, ip)
+		}
+	}
+	if len(missing) > 0 {
+		result.Detail = fmt.Sprintf("IPs not reported by the guest: %s.", strings.Join(missing, ", "))
+		return
+	}
+	result.Passed = true
+	return
+}
+
+// The TCP port is open.
+func (r *Checker) tcp(port int) (result Result) {
+	address, blocked := r.address()
+	if blocked != nil {
+		result = *blocked
+		return
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(address, strconv.Itoa(port)), ProbeTimeout)
+	if err != nil {
+		result.Detail = err.Error()
+		return
+	}
+	_ = conn.Close()
+	result.Passed = true
+	return
+}
+
+// The HTTP endpoint responds with an expected status.
+func (r *Checker) http() (result Result) {
+	address, blocked := r.address()
+	if blocked != nil {
+		result = *blocked
+		return
+	}
+	probe := r.Spec.HTTP
+	scheme := "http"
+	if strings.EqualFold(probe.Scheme, "HTTPS") {
+		scheme = "https"
+	}
+	path := probe.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	url := fmt.Sprintf(
+		"%s://%s%s",
+		scheme,
+		net.JoinHostPort(address, strconv.Itoa(int(probe.Port))),
+		path)
+	client := &http.Client{
+		Timeout: ProbeTimeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				// The guest certificate is not trusted.
+				InsecureSkipVerify: true,
+			},
+		},
+	}
+	response, err := client.Get(url)
+	if err != nil {
+		result.Detail = err.Error()
+		return
+	}
+	_ = response.Body.Close()
+	var expected bool
+	if len(probe.ExpectedStatus) > 0 {
+		expected = slices.Contains(probe.ExpectedStatus, response.StatusCode)
+	} else {
+		expected = response.StatusCode >= 200 && response.StatusCode < 400
+	}
+	if !expected {
+		result.Detail = fmt.Sprintf("%s returned status %d.", url, response.StatusCode)
+		return
+	}
+	result.Passed = true
+	return
+}
+
+// The address of the VM probed.
+// The first IP reported on an interface.
+func (r *Checker) address() (address string, blocked *Result) {
+	if r.SkipProbes != "" {
+		blocked = &Result{Skipped: true, Detail: r.SkipProbes}
+		return
+	}
+	for _, iface := range r.VMI.Status.Interfaces {
+		if iface.IP != "" {
+			address = iface.IP
+			return
+		}
+	}
+	blocked = &Result{Detail: "The VM has no IP."}
+	return
+}
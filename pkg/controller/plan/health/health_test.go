@@ -0,0 +1,112 @@
+package health
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+func vmi(ready, agent bool, ip string) *cnv.VirtualMachineInstance {
+	status := func(b bool) core.ConditionStatus {
+		if b {
+			return core.ConditionTrue
+		}
+		return core.ConditionFalse
+	}
+	return &cnv.VirtualMachineInstance{
+		Status: cnv.VirtualMachineInstanceStatus{
+			Conditions: []cnv.VirtualMachineInstanceCondition{
+				{Type: cnv.VirtualMachineInstanceReady, Status: status(ready)},
+				{Type: cnv.VirtualMachineInstanceAgentConnected, Status: status(agent)},
+			},
+			Interfaces: []cnv.VirtualMachineInstanceNetworkInterface{
+				{IP: ip, IPs: []string{ip, "192.168.1.10"}},
+			},
+		},
+	}
+}
+
+func TestTasks(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	tasks := Tasks(&plan.TargetValidation{
+		ExpectedIPs: true,
+		Ports:       []int32{22, 443},
+		HTTP:        &plan.HTTPProbe{Port: 80},
+	})
+	names := []string{}
+	for _, task := range tasks {
+		names = append(names, task.Name)
+	}
+	g.Expect(names).To(gomega.Equal([]string{Ready, ExpectedIPs, "Port-22", "Port-443", HTTP}))
+}
+
+func TestReady(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	checker := &Checker{Spec: &plan.TargetValidation{}}
+	g.Expect(checker.Check(Ready).Passed).To(gomega.BeFalse())
+	checker.VMI = vmi(true, false, "127.0.0.1")
+	result := checker.Check(Ready)
+	g.Expect(result.Passed).To(gomega.BeFalse())
+	g.Expect(result.Detail).To(gomega.Equal("The guest agent is not connected."))
+	g.Expect(checker.Check("Port-22").Detail).To(gomega.Equal("The VM is not ready."))
+	checker.VMI = vmi(true, true, "127.0.0.1")
+	g.Expect(checker.Check(Ready).Passed).To(gomega.BeTrue())
+}
+
+func TestExpectedIPs(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	checker := &Checker{
+		Spec: &plan.TargetValidation{ExpectedIPs: true},
+		VMI:  vmi(true, true, "10.0.0.5"),
+	}
+	g.Expect(checker.Check(ExpectedIPs).Skipped).To(gomega.BeTrue())
+	checker.SourceIPs = []string{"192.168.1.10"}
+	g.Expect(checker.Check(ExpectedIPs).Passed).To(gomega.BeTrue())
+	checker.SourceIPs = []string{"192.168.1.10", "192.168.1.11"}
+	result := checker.Check(ExpectedIPs)
+	g.Expect(result.Passed).To(gomega.BeFalse())
+	g.Expect(result.Detail).To(gomega.Equal("IPs not reported by the guest: 192.168.1.11."))
+}
+
+func TestProbes(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	_, p, _ := net.SplitHostPort(server.Listener.Addr().String())
+	port, _ := strconv.Atoi(p)
+
+	checker := &Checker{
+		Spec: &plan.TargetValidation{
+			HTTP: &plan.HTTPProbe{Port: int32(port), Path: "healthz"},
+		},
+		VMI: vmi(true, true, "127.0.0.1"),
+	}
+	g.Expect(checker.Check(PortPrefix + p).Passed).To(gomega.BeTrue())
+	g.Expect(checker.Check(HTTP).Passed).To(gomega.BeTrue())
+	checker.Spec.HTTP.Path = "/missing"
+	g.Expect(checker.Check(HTTP).Passed).To(gomega.BeFalse())
+	checker.Spec.HTTP.ExpectedStatus = []int{http.StatusNotFound}
+	g.Expect(checker.Check(HTTP).Passed).To(gomega.BeTrue())
+
+	checker.SkipProbes = "The destination is not reachable."
+	result := checker.Check(HTTP)
+	g.Expect(result.Skipped).To(gomega.BeTrue())
+	g.Expect(result.Detail).To(gomega.Equal(checker.SkipProbes))
+}
@@ -0,0 +1,258 @@
+package plan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	vsmodel "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The VM changes of a plan are queried
+// (in the inventory) at most once per interval.
+const ModifiedInterval = time.Minute
+
+// Fields changed by the migration itself: the power state, the
+// guest runtime reported while powered on and the snapshots.
+var migrationFields = map[api.ProviderType]map[string]bool{
+	api.VSphere: {
+		"PowerState":    true,
+		"Snapshot":      true,
+		"StorageUsed":   true,
+		"IpAddress":     true,
+		"HostName":      true,
+		"GuestNetworks": true,
+	},
+	api.OVirt: {
+		"Status":    true,
+		"Snapshots": true,
+	},
+}
+
+// VM changes found (cached) by plan.
+var modifiedFound = modifiedCache{}
+
+// Cache of the VM changes found by plan.
+type modifiedCache struct {
+	mutex sync.Mutex
+	found map[types.NamespacedName]modifiedEntry
+}
+
+// VM changes found for a plan.
+type modifiedEntry struct {
+	// Plan UID.
+	uid types.UID
+	// Changes made since.
+	since time.Time
+	// Queried.
+	checked time.Time
+	// Condition items.
+	items []string
+}
+
+// Get the changes found for the plan since the time.
+// Returns false when not found or expired.
+func (r *modifiedCache) get(plan *api.Plan, since time.Time) (items []string, found bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	entry, found := r.found[namespacedName(plan)]
+	if !found ||
+		entry.uid != plan.UID ||
+		!entry.since.Equal(since) ||
+		time.Since(entry.checked) > ModifiedInterval {
+		found = false
+		return
+	}
+	items = entry.items
+	return
+}
+
+// Set the changes found for the plan.
+func (r *modifiedCache) set(plan *api.Plan, since time.Time, items []string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.found == nil {
+		r.found = make(map[types.NamespacedName]modifiedEntry)
+	}
+	r.found[namespacedName(plan)] = modifiedEntry{
+		uid:     plan.UID,
+		since:   since,
+		checked: time.Now(),
+		items:   items,
+	}
+}
+
+// Delete the changes found for the plan.
+func (r *modifiedCache) delete(name types.NamespacedName) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.found, name)
+}
+
+// Validate the VMs have not been modified (in the inventory)
+// since the plan was validated. The changes are reported since
+// the plan became ready so they are reported throughout a (warm)
+// migration. The fields changed by the migration itself are
+// ignored. Only providers retaining the change history are
+// validated. Plans succeeded or archived are not validated.
+// The inventory is queried at most once per interval and the
+// inventory errors are logged without failing the validation.
+func (r *Reconciler) validateModified(plan *api.Plan) {
+	if plan.Status.HasAnyCondition(Succeeded, Archived) {
+		modifiedFound.delete(namespacedName(plan))
+		return
+	}
+	switch plan.Referenced.Provider.Source.Type() {
+	case api.VSphere, api.OVirt:
+	default:
+		return
+	}
+	ready := plan.Status.FindCondition(libcnd.Ready)
+	if ready == nil {
+		return
+	}
+	since := ready.LastTransitionTime.Time
+	items, found := modifiedFound.get(plan, since)
+	if !found {
+		var err error
+		items, err = r.modifiedItems(plan, since)
+		if err != nil {
+			r.Log.Error(
+				err,
+				"VM changes not validated.",
+				"plan",
+				plan.Name,
+				"namespace",
+				plan.Namespace)
+		}
+		modifiedFound.set(plan, since, items)
+	}
+	if len(items) > 0 {
+		plan.Status.SetCondition(
+			libcnd.Condition{
+				Type:     VMModified,
+				Status:   True,
+				Reason:   Modified,
+				Category: api.CategoryWarn,
+				Message:  "VMs have been modified since the plan was validated.",
+				Items:    items,
+			})
+	}
+}
+
+// Query the inventory for the VMs changed since the time.
+// The changes found before an error are returned.
+func (r *Reconciler) modifiedItems(plan *api.Plan, since time.Time) (items []string, err error) {
+	provider := plan.Referenced.Provider.Source
+	inventory, err := web.NewClient(provider)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, vm := range plan.Spec.VMs {
+		ref := vm.Ref
+		if ref.ID == "" {
+			_, fErr := inventory.VM(&ref)
+			if fErr != nil {
+				continue
+			}
+		}
+		history := &web.History{Since: &since}
+		err = inventory.Get(history, ref.ID)
+		if err != nil {
+			if errors.As(err, &web.NotFoundError{}) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err)
+			return
+		}
+		item := modifiedItem(ref, history, provider.Type())
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return
+}
+
+// Build the condition item naming the fields changed.
+// The fields changed by the migration are not named.
+func modifiedItem(ref refapi.Ref, history *web.History, provider api.ProviderType) (item string) {
+	fields := []string{}
+	found := map[string]bool{}
+	for _, change := range history.Changes {
+		for _, f := range change.Fields {
+			if found[f.Name] || migrationChanged(provider, f) {
+				continue
+			}
+			found[f.Name] = true
+			fields = append(fields, f.Name)
+		}
+	}
+	if len(fields) > 0 {
+		item = fmt.Sprintf(
+			"%schanged:%s",
+			ref.String(),
+			strings.Join(fields, ","))
+	}
+	return
+}
+
+// The field is changed by the migration.
+// The vSphere disks are changed by the migration when
+// only the (snapshot delta) files have changed.
+func migrationChanged(provider api.ProviderType, f model.FieldChange) bool {
+	if migrationFields[provider][f.Name] {
+		return true
+	}
+	if provider == api.VSphere && f.Name == "Disks" {
+		return sameDisks(f.Old, f.New)
+	}
+	return false
+}
+
+// The vSphere disks are the same but the files.
+// Changes without the values are not the same.
+func sameDisks(old, new interface{}) bool {
+	if old == nil || new == nil {
+		return false
+	}
+	decode := func(value interface{}) (disks []vsmodel.Disk, err error) {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return
+		}
+		err = json.Unmarshal(b, &disks)
+		for i := range disks {
+			disks[i].File = ""
+			disks[i].ParentFile = ""
+		}
+		return
+	}
+	oldDisks, err := decode(old)
+	if err != nil {
+		return false
+	}
+	newDisks, err := decode(new)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldDisks, newDisks)
+}
+
+// Namespaced name of the plan.
+func namespacedName(plan *api.Plan) types.NamespacedName {
+	return types.NamespacedName{Namespace: plan.Namespace, Name: plan.Name}
+}
@@ -0,0 +1,90 @@
+package plan
+
+import (
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	vsmodel "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("VM modified", func() {
+	ref := refapi.Ref{ID: "vm-1", Name: "web"}
+
+	ginkgo.It("should name the fields changed", func() {
+		history := &web.History{
+			Changes: []base.Change{
+				{
+					Action: model.ChangeUpdated,
+					Fields: []model.FieldChange{{Name: "Disks"}, {Name: "Host"}},
+				},
+			},
+		}
+		Expect(modifiedItem(ref, history, api.VSphere)).To(Equal(ref.String() + "changed:Disks,Host"))
+	})
+
+	ginkgo.It("should not report a VM not changed", func() {
+		Expect(modifiedItem(ref, &web.History{}, api.VSphere)).To(BeEmpty())
+	})
+
+	ginkgo.It("should not report the fields changed by the migration", func() {
+		disk := vsmodel.Disk{Key: 2000, File: "[ds0] web/web.vmdk", Capacity: 1024}
+		delta := disk
+		delta.File = "[ds0] web/web-000001.vmdk"
+		delta.ParentFile = disk.File
+		history := &web.History{
+			Changes: []base.Change{
+				{
+					Action: model.ChangeUpdated,
+					Fields: []model.FieldChange{
+						{Name: "PowerState"},
+						{Name: "Snapshot"},
+						{
+							Name: "Disks",
+							Old:  []vsmodel.Disk{disk},
+							New:  []vsmodel.Disk{delta},
+						},
+					},
+				},
+			},
+		}
+		Expect(modifiedItem(ref, history, api.VSphere)).To(BeEmpty())
+		// Resized.
+		delta.Capacity = 2048
+		history.Changes[0].Fields[2].New = []vsmodel.Disk{delta}
+		Expect(modifiedItem(ref, history, api.VSphere)).To(Equal(ref.String() + "changed:Disks"))
+		// Not changed by the migration from oVirt.
+		Expect(modifiedItem(ref, history, api.OVirt)).To(Equal(ref.String() + "changed:PowerState,Snapshot,Disks"))
+	})
+
+	ginkgo.It("should cache the changes found", func() {
+		cache := modifiedCache{}
+		plan := &api.Plan{}
+		plan.Namespace = "test"
+		plan.Name = "plan"
+		plan.UID = "1"
+		since := time.Now()
+		_, found := cache.get(plan, since)
+		Expect(found).To(BeFalse())
+		cache.set(plan, since, []string{"changed"})
+		items, found := cache.get(plan, since)
+		Expect(found).To(BeTrue())
+		Expect(items).To(ConsistOf("changed"))
+		// Ready again.
+		_, found = cache.get(plan, since.Add(time.Second))
+		Expect(found).To(BeFalse())
+		// Recreated.
+		recreated := plan.DeepCopy()
+		recreated.UID = "2"
+		_, found = cache.get(recreated, since)
+		Expect(found).To(BeFalse())
+		cache.delete(namespacedName(plan))
+		_, found = cache.get(plan, since)
+		Expect(found).To(BeFalse())
+	})
+})
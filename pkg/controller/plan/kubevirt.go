@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,6 +22,7 @@ import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/ova"
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter"
 	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	inspectionparser "github.com/kubev2v/forklift/pkg/controller/plan/adapter/vsphere"
@@ -116,6 +118,7 @@ const (
 const (
 	ResourceVMConfig   = "vm-config"
 	ResourceVDDKConfig = "vddk-config"
+	ResourceDryRun     = "dry-run"
 )
 
 // User
@@ -630,6 +633,73 @@ func (r *KubeVirt) DeleteVM(vm *plan.VMStatus) (err error) {
 	return
 }
 
+// Determine whether the migrated VM is healthy.
+// A running VM must be ready. A VM that is not
+// expected to run is healthy once created.
+func (r *KubeVirt) VMHealthy(vm *plan.VMStatus) (healthy bool, err error) {
+	vmLabels := r.vmAllButMigrationLabels(vm.Ref)
+	list := &cnv.VirtualMachineList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: k8slabels.SelectorFromSet(vmLabels),
+			Namespace:     r.Plan.Spec.TargetNamespace,
+		},
+	)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, object := range list.Items {
+		healthy = object.Status.Ready ||
+			object.Status.PrintableStatus == cnv.VirtualMachineStatusStopped
+	}
+
+	return
+}
+
+// Get the VMI of the migrated VM.
+// Returns nil when the VM is not running.
+func (r *KubeVirt) GetVMI(vm *plan.VMStatus) (vmi *cnv.VirtualMachineInstance, err error) {
+	vmLabels := r.vmAllButMigrationLabels(vm.Ref)
+	list := &cnv.VirtualMachineList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: k8slabels.SelectorFromSet(vmLabels),
+			Namespace:     r.Plan.Spec.TargetNamespace,
+		},
+	)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if len(list.Items) == 0 {
+		return
+	}
+	object := &cnv.VirtualMachineInstance{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: list.Items[0].Namespace,
+			Name:      list.Items[0].Name,
+		},
+		object)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	vmi = object
+
+	return
+}
+
 func (r *KubeVirt) DataVolumes(vm *plan.VMStatus) (dataVolumes []cdi.DataVolume, err error) {
 	labels := r.vmLabels(vm.Ref)
 	labels[kDV] = "true"
@@ -993,7 +1063,7 @@ func (r *KubeVirt) setKvmOnPodSpec(podSpec *core.PodSpec) {
 		return
 	}
 	switch *r.Plan.Provider.Source.Spec.Type {
-	case api.VSphere, api.Ova:
+	case api.VSphere, api.Ova, api.HyperV:
 		if podSpec.NodeSelector == nil {
 			podSpec.NodeSelector = make(map[string]string)
 		}
@@ -1193,7 +1263,7 @@ func (r *KubeVirt) UpdateVmByConvertedConfig(vm *plan.VMStatus, pod *core.Pod, s
 	defer resp.Body.Close()
 
 	switch r.Source.Provider.Type() {
-	case api.Ova:
+	case api.Ova, api.HyperV:
 		vmConf, err := io.ReadAll(resp.Body)
 		if err != nil {
 			return liberr.Wrap(err)
@@ -1635,6 +1705,11 @@ func (r *KubeVirt) virtualMachine(vm *plan.VMStatus, sortVolumesByLibvirt bool)
 		object.ObjectMeta.Annotations = annotations
 	}
 
+	err = r.propagateMetadata(vm, object)
+	if err != nil {
+		return
+	}
+
 	// Assign the determined run strategy to the object
 	runStrategy := r.determineRunStrategy(vm)
 	object.Spec.RunStrategy = &runStrategy
@@ -1680,6 +1755,7 @@ func (r *KubeVirt) virtualMachine(vm *plan.VMStatus, sortVolumesByLibvirt bool)
 	if err != nil {
 		return
 	}
+	applyHardware(r.hardware(&vm.VM), &object.Spec)
 
 	return
 }
@@ -2179,7 +2255,11 @@ func (r *KubeVirt) getVirtV2vPod(vm *plan.VMStatus, vmVolumes []cnv.Volume, vddk
 			},
 		})
 	}
-	if vm.RootDisk != "" {
+	// The builder sets the root disk when it is shifted by the disk selection.
+	rootDiskSet := slices.ContainsFunc(environment, func(env core.EnvVar) bool {
+		return env.Name == "V2V_RootDisk"
+	})
+	if vm.RootDisk != "" && !rootDiskSet {
 		environment = append(environment,
 			core.EnvVar{
 				Name:  "V2V_RootDisk",
@@ -2476,7 +2556,7 @@ func (r *KubeVirt) podVolumeMounts(vmVolumes []cnv.Volume, vddkConfigmap *core.C
 	}
 
 	switch r.Source.Provider.Type() {
-	case api.Ova:
+	case api.Ova, api.HyperV:
 		pv := r.BuildPVForNFS(vm)
 		pv, err = r.EnsurePVForNFS(pv)
 		if err != nil {
@@ -3148,9 +3228,7 @@ func (r *KubeVirt) EnsurePVForNFS(pv *core.PersistentVolume) (out *core.Persiste
 
 func (r *KubeVirt) BuildPVForNFS(vm *plan.VMStatus) (pv *core.PersistentVolume) {
 	sourceProvider := r.Source.Provider
-	splitted := strings.Split(sourceProvider.Spec.URL, ":")
-	nfsServer := splitted[0]
-	nfsPath := splitted[1]
+	share := ova.Share{URL: sourceProvider.Spec.URL}
 	pvcNamePrefix := getEntityPrefixName("pv", r.Source.Provider.Name, r.Plan.Name)
 
 	pv = &core.PersistentVolume{
@@ -3165,12 +3243,10 @@ func (r *KubeVirt) BuildPVForNFS(vm *plan.VMStatus) (pv *core.PersistentVolume)
 			AccessModes: []core.PersistentVolumeAccessMode{
 				core.ReadOnlyMany,
 			},
-			PersistentVolumeSource: core.PersistentVolumeSource{
-				NFS: &core.NFSVolumeSource{
-					Path:   nfsPath,
-					Server: nfsServer,
-				},
-			},
+			PersistentVolumeSource: share.VolumeSource(
+				sourceProvider,
+				string(r.Migration.UID)+"-"+vm.ID),
+			MountOptions: share.MountOptions(),
 		},
 	}
 	return
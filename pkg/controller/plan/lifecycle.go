@@ -0,0 +1,136 @@
+package plan
+
+import (
+	"fmt"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/plan/notifier"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Event reasons.
+const (
+	EventVMStarted          = "VMStarted"
+	EventVMPhaseChanged     = "VMPhaseChanged"
+	EventVMFailed           = "VMFailed"
+	EventVMSucceeded        = "VMSucceeded"
+	EventVMCutoverPending   = "VMCutoverPending"
+	EventVMPrecopyDone      = "VMPrecopyDone"
+	EventVMPaused           = "VMPaused"
+	EventVMResumed          = "VMResumed"
+	EventMigrationSucceeded = "MigrationSucceeded"
+	EventMigrationFailed    = "MigrationFailed"
+	EventMigrationCanceled  = "MigrationCanceled"
+	EventMigrationStarted   = "MigrationStarted"
+)
+
+// VM lifecycle state observed before a phase
+// is executed. Compared with the state after the
+// phase is executed to emit the lifecycle events.
+type lifecycle struct {
+	phase     string
+	started   bool
+	failed    bool
+	succeeded bool
+	precopies int
+}
+
+// Observe the VM lifecycle state.
+func (r *Migration) observe(vm *plan.VMStatus) (state lifecycle) {
+	state = lifecycle{
+		phase:     vm.Phase,
+		started:   vm.MarkedStarted(),
+		failed:    vm.HasCondition(api.ConditionFailed),
+		succeeded: vm.HasCondition(api.ConditionSucceeded),
+	}
+	if vm.Warm != nil {
+		state.precopies = vm.Warm.Successes
+	}
+	return
+}
+
+// Emit the events for the VM lifecycle changes
+// since the state was observed.
+func (r *Migration) emit(vm *plan.VMStatus, before lifecycle) {
+	after := r.observe(vm)
+	if !before.started && after.started {
+		r.event(vm, core.EventTypeNormal, EventVMStarted, notifier.VMStarted, "The VM migration has started.")
+	}
+	if before.phase != after.phase {
+		r.event(
+			vm,
+			core.EventTypeNormal,
+			EventVMPhaseChanged,
+			notifier.VMPhaseChanged,
+			fmt.Sprintf("The VM migration phase changed from '%s' to '%s'.", before.phase, after.phase),
+			func(data *notifier.Data) {
+				data.PreviousPhase = before.phase
+			})
+	}
+	if after.precopies > before.precopies {
+		precopy := func(data *notifier.Data) {
+			data.Precopies = after.precopies
+		}
+		r.event(
+			vm,
+			core.EventTypeNormal,
+			EventVMPrecopyDone,
+			notifier.VMPrecopyDone,
+			fmt.Sprintf("The VM precopy (%d) is done.", after.precopies),
+			precopy)
+		if before.precopies == 0 && r.Migration.Spec.Cutover == nil {
+			r.event(
+				vm,
+				core.EventTypeNormal,
+				EventVMCutoverPending,
+				notifier.VMCutoverPending,
+				"The VM is ready for cutover.",
+				precopy)
+		}
+	}
+	if !before.failed && after.failed {
+		message := "The VM migration has FAILED."
+		if vm.Error != nil && len(vm.Error.Reasons) > 0 {
+			message = fmt.Sprintf("%s %s", message, vm.Error.Reasons[len(vm.Error.Reasons)-1])
+		}
+		r.event(vm, core.EventTypeWarning, EventVMFailed, notifier.VMFailed, message)
+	}
+	if !before.succeeded && after.succeeded {
+		r.event(vm, core.EventTypeNormal, EventVMSucceeded, notifier.VMSucceeded, "The VM migration has SUCCEEDED.")
+	}
+}
+
+// Record the VM event on the plan and the migration
+// and post the CloudEvent.
+func (r *Migration) event(vm *plan.VMStatus, kind, reason, cloudType, message string, options ...func(*notifier.Data)) {
+	r.record(kind, reason, fmt.Sprintf("VM %s: %s", vm.String(), message))
+	data := notifier.Data{
+		Namespace: r.Plan.Namespace,
+		Plan:      r.Plan.Name,
+		Migration: r.Migration.Name,
+		VMID:      vm.ID,
+		VMName:    vm.Name,
+		Phase:     vm.Phase,
+		Message:   message,
+	}
+	for _, option := range options {
+		option(&data)
+	}
+	notifier.Default.Notify(notifier.New(cloudType, data))
+}
+
+// Record the event on the plan and the migration.
+func (r *Migration) record(kind, reason, message string) {
+	if r.Recorder == nil {
+		return
+	}
+	objects := []runtime.Object{r.Plan}
+	if r.Migration != nil && r.Migration.UID != "" {
+		objects = append(objects, r.Migration)
+	}
+	for _, object := range objects {
+		r.Recorder.Event(object, kind, reason, message)
+	}
+}
@@ -0,0 +1,92 @@
+package plan
+
+import (
+	"regexp"
+	"strings"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// Characters not permitted in a label value.
+var labelValueInvalid = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// Propagate the metadata (tags and custom attributes)
+// of the source VM to the target VM as mapped by the plan.
+// Only vSphere VMs have tags and custom attributes.
+func (r *KubeVirt) propagateMetadata(vm *planapi.VMStatus, object *cnv.VirtualMachine) (err error) {
+	if len(r.Plan.Spec.TargetMetadata) == 0 || r.Source.Provider.Type() != api.VSphere {
+		return
+	}
+	source := &model.VM{}
+	err = r.Source.Inventory.Find(source, vm.Ref)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.Ref.String())
+		return
+	}
+	applyMetadata(r.Plan.Spec.TargetMetadata, source, object)
+	return
+}
+
+// Apply the metadata mappings to the target VM.
+// Mappings without a value on the source VM are skipped.
+// The labels and annotations already set are not overridden.
+func applyMetadata(mappings []planapi.MetadataMapping, source *model.VM, object *cnv.VirtualMachine) {
+	for _, mapping := range mappings {
+		var value string
+		switch mapping.Source {
+		case planapi.MetadataTag:
+			value = strings.Join(source.TagNames(mapping.Name), ",")
+		case planapi.MetadataAttribute:
+			value, _ = source.Attribute(mapping.Name)
+		}
+		if value == "" {
+			continue
+		}
+		key := metadataKey(mapping)
+		switch mapping.Target {
+		case planapi.MetadataLabel:
+			value = labelValue(value)
+			if value == "" {
+				continue
+			}
+			if object.Labels == nil {
+				object.Labels = make(map[string]string)
+			}
+			if _, found := object.Labels[key]; !found {
+				object.Labels[key] = value
+			}
+		case planapi.MetadataAnnotation:
+			if object.Annotations == nil {
+				object.Annotations = make(map[string]string)
+			}
+			if _, found := object.Annotations[key]; !found {
+				object.Annotations[key] = value
+			}
+		}
+	}
+}
+
+// The label or annotation key of the mapping.
+// Defaults to the tag category or attribute name.
+func metadataKey(mapping planapi.MetadataMapping) string {
+	if mapping.Key != "" {
+		return mapping.Key
+	}
+	return mapping.Name
+}
+
+// Sanitize a label value.
+// The invalid characters are replaced with "_" and the
+// value is trimmed to begin and end with an alphanumeric.
+func labelValue(value string) string {
+	value = labelValueInvalid.ReplaceAllString(value, "_")
+	if len(value) > k8svalidation.LabelValueMaxLength {
+		value = value[:k8svalidation.LabelValueMaxLength]
+	}
+	return strings.Trim(value, "._-")
+}
@@ -0,0 +1,57 @@
+package plan
+
+import (
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	vsphere "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+var _ = ginkgo.Describe("target metadata", func() {
+	source := &model.VM{
+		Tags: []vsphere.Tag{
+			{Category: "env", Name: "prod"},
+			{Category: "app", Name: "web"},
+			{Category: "app", Name: "db"},
+			{Category: "owner", Name: "Jane Doe"},
+		},
+		CustomAttributes: []vsphere.Attribute{
+			{Key: 1, Name: "cost-center", Value: "CC 1234"},
+			{Key: 2, Name: "team"},
+		},
+	}
+
+	ginkgo.It("should map the tags and attributes to labels and annotations", func() {
+		object := &cnv.VirtualMachine{}
+		object.Labels = map[string]string{"plan": "uid"}
+		applyMetadata(
+			[]planapi.MetadataMapping{
+				{Source: planapi.MetadataTag, Name: "env", Target: planapi.MetadataLabel, Key: "example.com/env"},
+				{Source: planapi.MetadataTag, Name: "app", Target: planapi.MetadataAnnotation},
+				{Source: planapi.MetadataTag, Name: "owner", Target: planapi.MetadataLabel},
+				{Source: planapi.MetadataTag, Name: "missing", Target: planapi.MetadataLabel},
+				{Source: planapi.MetadataAttribute, Name: "cost-center", Target: planapi.MetadataLabel},
+				{Source: planapi.MetadataAttribute, Name: "team", Target: planapi.MetadataAnnotation},
+				{Source: planapi.MetadataTag, Name: "env", Target: planapi.MetadataLabel, Key: "plan"},
+			},
+			source,
+			object)
+		Expect(object.Labels).To(Equal(map[string]string{
+			"plan":            "uid",
+			"example.com/env": "prod",
+			"owner":           "Jane_Doe",
+			"cost-center":     "CC_1234",
+		}))
+		Expect(object.Annotations).To(Equal(map[string]string{
+			"app": "web,db",
+		}))
+	})
+
+	ginkgo.It("should sanitize the label values", func() {
+		Expect(labelValue("a b/c")).To(Equal("a_b_c"))
+		Expect(labelValue("-value.")).To(Equal("value"))
+		Expect(labelValue("???")).To(BeEmpty())
+	})
+})
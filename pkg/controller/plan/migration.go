@@ -20,7 +20,9 @@ import (
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
 	"github.com/kubev2v/forklift/pkg/controller/plan/migrator"
+	"github.com/kubev2v/forklift/pkg/controller/plan/schedule"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler"
+	"github.com/kubev2v/forklift/pkg/controller/plan/wave"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
@@ -32,6 +34,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/tools/record"
 	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -68,12 +71,18 @@ type Migration struct {
 	vmMap VirtualMachineMap
 	// VM scheduler
 	scheduler scheduler.Scheduler
+	// Plan calendar (migration windows).
+	calendar *schedule.Calendar
+	// Migration waves.
+	waves *wave.Waves
 	// destination client.
 	destinationClient adapter.DestinationClient
 	// pvc converter
 	converter *adapter.Converter
 	// vm migrator
 	migrator migrator.Migrator
+	// Event recorder.
+	Recorder record.EventRecorder
 }
 
 // Type of migration.
@@ -101,6 +110,12 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 	}
 
 	r.resolveCanceledRefs()
+	r.updatePaused()
+
+	err = r.updateWaves()
+	if err != nil {
+		return
+	}
 
 	for _, vm := range r.runningVMs() {
 		err = r.execute(vm)
@@ -138,6 +153,15 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 		}
 	}
 
+	if wait := r.waitForWindow(); wait > reQ {
+		reQ = wait
+	}
+
+	err = r.updateWaves()
+	if err != nil {
+		return
+	}
+
 	completed, err := r.end()
 	if completed {
 		reQ = NoReQ
@@ -146,6 +170,92 @@ func (r *Migration) Run() (reQ time.Duration, err error) {
 	return
 }
 
+// Reflect the status of the migration waves and
+// cancel the VMs of waves blocked by a failed wave.
+func (r *Migration) updateWaves() (err error) {
+	err = r.waves.Update(r.kubevirt.VMHealthy)
+	if err != nil {
+		return
+	}
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if vm.MarkedStarted() {
+			continue
+		}
+		blocked, status := r.waves.Blocked(vm)
+		if !blocked {
+			continue
+		}
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     api.ConditionCanceled,
+				Status:   True,
+				Category: api.CategoryAdvisory,
+				Reason:   WaveBlocked,
+				Message:  fmt.Sprintf("The migration wave '%s' is blocked. %s", status.Name, status.Message),
+				Durable:  true,
+			})
+		vm.MarkCompleted()
+		r.Log.Info(
+			"Migration [BLOCKED]",
+			"vm",
+			vm.String(),
+			"wave",
+			status.Name)
+	}
+
+	return
+}
+
+// Reflect VMs waiting for the next migration window.
+// Returns the time until the window opens when no VM is running.
+func (r *Migration) waitForWindow() (reQ time.Duration) {
+	r.Plan.Status.Migration.NextStart = nil
+	now := time.Now()
+	if r.calendar.Open(now) {
+		return
+	}
+	waiting := false
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		if !vm.MarkedStarted() && !vm.MarkedCompleted() && !vm.HasCondition(api.ConditionCanceled) {
+			waiting = true
+			break
+		}
+	}
+	if !waiting {
+		return
+	}
+	cnd := libcnd.Condition{
+		Type:     api.ConditionWaiting,
+		Status:   True,
+		Category: api.CategoryAdvisory,
+		Message:  "The migration schedule will not open again.",
+	}
+	if r.calendar.InBlackout(now) {
+		cnd.Reason = Blackout
+	} else {
+		cnd.Reason = OutsideWindow
+	}
+	next, found := r.calendar.NextOpen(now)
+	if found {
+		nextStart := meta.NewTime(next)
+		r.Plan.Status.Migration.NextStart = &nextStart
+		cnd.Message = fmt.Sprintf(
+			"Waiting for the migration window, next start at %s.",
+			next.UTC().Format(time.RFC3339))
+		if len(r.runningVMs()) == 0 {
+			reQ = next.Sub(now)
+		}
+	}
+	snapshot := r.Plan.Status.Migration.ActiveSnapshot()
+	snapshot.SetCondition(cnd)
+	r.Log.Info(
+		"Waiting for the migration window.",
+		"nextStart",
+		r.Plan.Status.Migration.NextStart)
+
+	return
+}
+
 // Get/Build resources.
 func (r *Migration) init() (err error) {
 	adapter, err := adapter.New(r.Context.Source.Provider)
@@ -178,6 +288,14 @@ func (r *Migration) init() (err error) {
 	if err != nil {
 		return
 	}
+	r.calendar, err = schedule.New(r.Plan.Spec.Schedule)
+	if err != nil {
+		return
+	}
+	r.waves, err = wave.New(r.Plan)
+	if err != nil {
+		return
+	}
 	r.migrator, err = migrator.New(r.Context)
 	if err != nil {
 		return
@@ -202,6 +320,7 @@ func (r *Migration) begin() (err error) {
 			Message:  "The plan is EXECUTING.",
 			Durable:  true,
 		})
+	r.record(core.EventTypeNormal, EventMigrationStarted, "The plan is EXECUTING.")
 	err = r.kubevirt.EnsureNamespace()
 	if err != nil {
 		err = liberr.Wrap(err)
@@ -232,9 +351,38 @@ func (r *Migration) begin() (err error) {
 	//
 	// Add/Update.
 	list := []*plan.VMStatus{}
+	retry := r.Migration.Spec.RetryFailed
 	for _, vm := range r.Plan.Spec.VMs {
 		status := r.migrator.Status(vm)
-		if status.Phase != api.PhaseCompleted || status.HasAnyCondition(api.ConditionCanceled, api.ConditionFailed) {
+		status.Wave = vm.Wave
+		switch {
+		case retry && status.HasCondition(api.ConditionFailed):
+			pipeline, pErr := r.migrator.Pipeline(vm)
+			if pErr != nil {
+				err = liberr.Wrap(pErr)
+				return
+			}
+			retried, resumed, rErr := r.retry(status, pipeline)
+			if rErr != nil {
+				err = liberr.Wrap(rErr)
+				return
+			}
+			if !resumed {
+				r.migrator.Reset(status, pipeline)
+			}
+			snapshot.Retried = append(snapshot.Retried, retried)
+			log.Info(
+				"Pipeline resumed.",
+				"vm",
+				vm.String(),
+				"phase",
+				status.Phase)
+		case retry && status.Phase == api.PhaseCompleted:
+			log.Info(
+				"Pipeline preserved.",
+				"vm",
+				vm.String())
+		case status.Phase != api.PhaseCompleted || status.HasAnyCondition(api.ConditionCanceled, api.ConditionFailed):
 			pipeline, pErr := r.migrator.Pipeline(vm)
 			if pErr != nil {
 				err = liberr.Wrap(pErr)
@@ -245,7 +393,7 @@ func (r *Migration) begin() (err error) {
 				"Pipeline reset.",
 				"vm",
 				vm.String())
-		} else {
+		default:
 			log.Info(
 				"Pipeline preserved.",
 				"vm",
@@ -255,6 +403,7 @@ func (r *Migration) begin() (err error) {
 	}
 
 	r.Plan.Status.Migration.VMs = list
+	r.Plan.Status.Migration.Waves = nil
 
 	err = r.migrator.Begin()
 	if err != nil {
@@ -281,7 +430,7 @@ func (r *Migration) Archive() {
 	}
 
 	switch r.Plan.Provider.Source.Type() {
-	case api.Ova:
+	case api.Ova, api.HyperV:
 		if err := r.deletePvcPvForOva(); err != nil {
 			r.Log.Error(err, "Failed to clean up the PVC and PV for the OVA plan")
 		}
@@ -353,6 +502,9 @@ func (r *Migration) Cancel() error {
 
 	for _, vm := range r.Plan.Status.Migration.VMs {
 		if vm.HasCondition(api.ConditionCanceled) && !vm.MarkedCompleted() {
+			if !migrator.RollingBack(vm) && r.rollbackRequired(vm, vm.Phase) {
+				vm.Pipeline = append(vm.Pipeline, migrator.RollbackStep())
+			}
 			dontFailOnError := func(err error) bool {
 				if err != nil {
 					r.Log.Error(liberr.Wrap(err),
@@ -374,6 +526,9 @@ func (r *Migration) Cancel() error {
 			r.migrator.Complete(vm)
 			vm.MarkCompleted()
 			markStartedStepsCompleted(vm)
+			if step, found := vm.FindStep(migrator.Rollback); found {
+				step.Phase = api.StepCompleted
+			}
 		}
 	}
 
@@ -387,6 +542,51 @@ func (r *Migration) NextPhase(vm *plan.VMStatus) {
 	migrator.NextPhase(r.migrator, vm)
 }
 
+// Rollback is enabled for the VM and the source VM
+// has been powered off before the phase.
+func (r *Migration) rollbackRequired(vm *plan.VMStatus, phase string) bool {
+	validation := r.Plan.Spec.TargetValidation
+	if phase == api.PhaseValidateTarget && validation != nil && validation.Policy() == plan.TargetValidationRollback {
+		return true
+	}
+	if !r.Plan.Spec.RollbackOnFailure && !vm.RollbackOnFailure {
+		return false
+	}
+	return migrator.PoweredOff(r.migrator, vm, phase)
+}
+
+// Start rolling back a failed VM migration when required.
+// Returns true while the rollback is in progress.
+func (r *Migration) rollback(vm *plan.VMStatus) bool {
+	if migrator.RollingBack(vm) {
+		return vm.Phase != api.PhaseCompleted
+	}
+	if !r.rollbackRequired(vm, vm.Error.Phase) {
+		return false
+	}
+	markStartedStepsCompleted(vm)
+	vm.Pipeline = append(vm.Pipeline, migrator.RollbackStep())
+	vm.Phase = api.PhaseRollbackCleanup
+	r.Log.Info(
+		"Migration [ROLLBACK]",
+		"vm",
+		vm.String())
+	return true
+}
+
+// End the rollback when a rollback phase failed.
+// The source VM may need to be restored manually.
+func (r *Migration) rollbackFailed(vm *plan.VMStatus, step *plan.Step, err error) {
+	r.Log.Error(err,
+		"Rollback failed.",
+		"vm",
+		vm.String())
+	step.AddError(err.Error())
+	step.MarkCompleted()
+	step.Phase = api.StepCompleted
+	vm.Phase = api.PhaseCompleted
+}
+
 func markStartedStepsCompleted(vm *plan.VMStatus) {
 	for _, step := range vm.Pipeline {
 		if step.MarkedStarted() {
@@ -405,8 +605,10 @@ func (r *Migration) deletePopulatorPVCs(vm *plan.VMStatus) (err error) {
 // Delete left over migration resources associated with a VM.
 func (r *Migration) cleanup(vm *plan.VMStatus, failOnErr func(error) bool) error {
 	// If the migration fails and the DeleteVmOnFailMigration is enabled, clean up the VM.
-	// When DeleteVmOnFailMigration is disabled, VM resources are preserved on failure.
-	if !vm.HasCondition(api.ConditionSucceeded) && (r.Plan.Spec.DeleteVmOnFailMigration || vm.DeleteVmOnFailMigration) {
+	// When DeleteVmOnFailMigration is disabled, VM resources are preserved on failure
+	// unless the migration is being rolled back.
+	if !vm.HasCondition(api.ConditionSucceeded) &&
+		(r.Plan.Spec.DeleteVmOnFailMigration || vm.DeleteVmOnFailMigration || migrator.RollingBack(vm)) {
 		if err := r.kubevirt.DeleteVM(vm); failOnErr(err) {
 			return err
 		}
@@ -587,20 +789,24 @@ func (r *Migration) deleteValidateVddkJob() (err error) {
 	return
 }
 
-// Best effort attempt to resolve canceled refs.
+// Best effort attempt to resolve canceled and paused refs.
 func (r *Migration) resolveCanceledRefs() {
 	for i := range r.Context.Migration.Spec.Cancel {
 		// resolve the VM ref in place
 		ref := &r.Context.Migration.Spec.Cancel[i]
 		_, _ = r.Source.Inventory.VM(ref)
 	}
+	for i := range r.Context.Migration.Spec.Pause {
+		ref := &r.Context.Migration.Spec.Pause[i]
+		_, _ = r.Source.Inventory.VM(ref)
+	}
 }
 
 func (r *Migration) runningVMs() (vms []*plan.VMStatus) {
 	vms = make([]*plan.VMStatus, 0)
 	for i := range r.Plan.Status.Migration.VMs {
 		vm := r.Plan.Status.Migration.VMs[i]
-		if vm.Running() {
+		if vm.Running() && !vm.HasCondition(api.ConditionPaused) {
 			vms = append(vms, vm)
 		}
 	}
@@ -610,6 +816,8 @@ func (r *Migration) runningVMs() (vms []*plan.VMStatus) {
 // Steps a VM through the migration itinerary
 // and updates its status.
 func (r *Migration) execute(vm *plan.VMStatus) (err error) {
+	before := r.observe(vm)
+	defer r.emit(vm, before)
 	// check whether the VM has been canceled by the user
 	if r.Context.Migration.Spec.Canceled(vm.Ref) {
 		vm.SetCondition(
@@ -621,6 +829,9 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 				Message:  "The migration has been canceled.",
 				Durable:  true,
 			})
+		if !migrator.RollingBack(vm) && r.rollbackRequired(vm, vm.Phase) {
+			vm.Pipeline = append(vm.Pipeline, migrator.RollbackStep())
+		}
 		vm.Phase = api.PhaseCompleted
 		r.Log.Info(
 			"Migration [CANCELED]",
@@ -640,6 +851,11 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 		"vm",
 		vm)
 
+	// A VM resumed by a retry does not run the initial phase.
+	if !vm.MarkedStarted() && r.resumed(vm) {
+		vm.MarkStarted()
+	}
+
 	// delegate to a provider-specific implementation of a phase
 	// if one exists, otherwise run through the default implementation.
 	ok, err := r.migrator.ExecutePhase(vm)
@@ -843,6 +1059,18 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			}
 
 			r.NextPhase(vm)
+		case api.PhaseValidateTarget:
+			step, found := vm.FindStep(r.migrator.Step(vm))
+			if !found {
+				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
+				break
+			}
+			err = r.validateTarget(vm, step)
+			if err != nil {
+				step.AddError(err.Error())
+				err = nil
+				break
+			}
 		case api.PhaseCreateVM:
 			step, found := vm.FindStep(r.migrator.Step(vm))
 			if !found {
@@ -915,12 +1143,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			}
 			if step.MarkedCompleted() && !step.HasError() {
 				if r.Plan.IsWarm() {
-					now := meta.Now()
-					next := meta.NewTime(now.Add(time.Duration(Settings.PrecopyInterval) * time.Minute))
-					n := len(vm.Warm.Precopies)
-					vm.Warm.Precopies[n-1].End = &now
-					vm.Warm.NextPrecopyAt = &next
-					vm.Warm.Successes++
+					r.precopySucceeded(vm)
 				}
 				r.NextPhase(vm)
 			}
@@ -979,7 +1202,13 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			if r.Migration.Spec.Cutover != nil && !r.Migration.Spec.Cutover.After(time.Now()) {
 				vm.Phase = api.PhaseStorePowerState
 			} else if vm.Warm.NextPrecopyAt != nil && !vm.Warm.NextPrecopyAt.After(time.Now()) {
-				r.NextPhase(vm)
+				// Precopies are paused during a blackout.
+				if r.calendar.InBlackout(time.Now()) {
+					break
+				}
+				r.resumePrecopy(vm)
+			} else if r.Plan.IsReplicating() {
+				vm.Warm.UpdateRPO(time.Now())
 			}
 		case api.PhaseRemovePreviousSnapshot, api.PhaseRemovePenultimateSnapshot, api.PhaseRemoveFinalSnapshot:
 			step, found := vm.FindStep(r.migrator.Step(vm))
@@ -1240,7 +1469,7 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			}
 
 			switch r.Source.Provider.Type() {
-			case api.Ova, api.VSphere:
+			case api.Ova, api.HyperV, api.VSphere:
 				// fetch config from the conversion pod
 				pod, err := r.kubevirt.GetGuestConversionPod(vm)
 				if err != nil {
@@ -1304,6 +1533,54 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 					Phase:   step.Phase,
 				}
 			}
+		case api.PhaseRollbackCleanup:
+			step, found := vm.FindStep(r.migrator.Step(vm))
+			if !found {
+				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
+				break
+			}
+			err = r.cleanup(vm, func(err error) bool { return err != nil })
+			if err != nil {
+				r.rollbackFailed(vm, step, err)
+				err = nil
+				break
+			}
+			r.NextPhase(vm)
+		case api.PhaseRollbackPowerOnSource:
+			step, found := vm.FindStep(r.migrator.Step(vm))
+			if !found {
+				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
+				break
+			}
+			err = r.provider.PowerOn(vm.Ref)
+			if err != nil {
+				if errors.As(err, &web.ProviderNotReadyError{}) {
+					return
+				}
+				r.rollbackFailed(vm, step, err)
+				err = nil
+				break
+			}
+			r.NextPhase(vm)
+		case api.PhaseRollbackWaitForPowerOn:
+			step, found := vm.FindStep(r.migrator.Step(vm))
+			if !found {
+				vm.AddError(fmt.Sprintf("Step '%s' not found", r.migrator.Step(vm)))
+				break
+			}
+			var state plan.VMPowerState
+			state, err = r.provider.PowerState(vm.Ref)
+			if err != nil {
+				if errors.As(err, &web.ProviderNotReadyError{}) {
+					return
+				}
+				r.rollbackFailed(vm, step, err)
+				err = nil
+				break
+			}
+			if state == plan.VMPowerStateOn {
+				r.NextPhase(vm)
+			}
 		case api.PhaseCompleted:
 			vm.MarkCompleted()
 			r.Log.Info(
@@ -1362,11 +1639,18 @@ func (r *Migration) execute(vm *plan.VMStatus) (err error) {
 			})
 
 	} else if vm.Error != nil {
+		if r.retryPrecopy(vm) {
+			return
+		}
+		if r.rollback(vm) {
+			return
+		}
 		vm.Phase = api.PhaseCompleted
 
 		// Failed warm migration can't follow its planned itinerary to snapshot removal phase
 		// so we remove the snapshot here to prevent an orphaned snapshot.
-		if r.Plan.IsWarm() && !vm.HasCondition(api.ConditionFailed) {
+		// A rolled back migration has already removed it.
+		if r.Plan.IsWarm() && !vm.HasCondition(api.ConditionFailed) && !migrator.RollingBack(vm) {
 			r.removeLastWarmSnapshot(vm)
 		}
 
@@ -1424,6 +1708,7 @@ func (r *Migration) end() (completed bool, err error) {
 	if failed > 0 {
 		// if any VMs failed, the migration failed.
 		r.Log.Info("Migration [FAILED]")
+		r.record(core.EventTypeWarning, EventMigrationFailed, "The plan execution has FAILED.")
 		snapshot.SetCondition(
 			libcnd.Condition{
 				Type:     api.ConditionFailed,
@@ -1436,6 +1721,7 @@ func (r *Migration) end() (completed bool, err error) {
 		// if the migration didn't fail and at least one VM succeeded,
 		// then the migration succeeded.
 		r.Log.Info("Migration [SUCCEEDED]")
+		r.record(core.EventTypeNormal, EventMigrationSucceeded, "The plan execution has SUCCEEDED.")
 		snapshot.SetCondition(
 			libcnd.Condition{
 				Type:     api.ConditionSucceeded,
@@ -1449,6 +1735,7 @@ func (r *Migration) end() (completed bool, err error) {
 		// all the VMs are complete, then the migration must
 		// have been canceled.
 		r.Log.Info("Migration [CANCELED]")
+		r.record(core.EventTypeNormal, EventMigrationCanceled, "The plan execution has been CANCELED.")
 		snapshot.SetCondition(
 			libcnd.Condition{
 				Type:     api.ConditionCanceled,
@@ -1491,7 +1778,7 @@ func (r *Migration) ensureGuestConversionPod(vm *plan.VMStatus, step *plan.Step)
 	}
 
 	switch r.Source.Provider.Type() {
-	case api.Ova:
+	case api.Ova, api.HyperV:
 		ready, err = r.kubevirt.EnsureOVAVirtV2VPVCStatus(vm.ID)
 	case api.VSphere:
 		ready = true
@@ -1796,7 +2083,7 @@ func (r *Migration) updateConversionProgressV2vMonitor(pod *core.Pod, step *plan
 		}
 	}
 	step.ReflectTasks()
-	if step.Name == ImageConversion && someProgress && r.Source.Provider.Type() != api.Ova {
+	if step.Name == ImageConversion && someProgress && !r.Source.Provider.UsesApplianceServer() {
 		// Disk copying has already started. Transition from
 		// ConvertGuest to CopyDisksVirtV2V .
 		step.MarkCompleted()
@@ -19,6 +19,8 @@ var (
 	OpenstackImageMigration libitr.Flag = 0x20
 	VSphere                 libitr.Flag = 0x40
 	RunInspection           libitr.Flag = 0x80
+	RestorePowerOn          libitr.Flag = 0x100
+	ValidateTarget          libitr.Flag = 0x200
 )
 
 // Steps.
@@ -31,6 +33,8 @@ const (
 	DiskTransferV2v     = "DiskTransferV2v"
 	VMCreation          = "VirtualMachineCreation"
 	PreflightInspection = "PreflightInspection"
+	Rollback            = "Rollback"
+	TargetValidation    = "TargetValidation"
 	Unknown             = "Unknown"
 )
 
@@ -99,6 +103,9 @@ func NextPhase(migrator Migrator, vm *plan.VMStatus) {
 // next determines the next phase the VM should move to.
 func next(migrator Migrator, vm *plan.VMStatus) (next string) {
 	itinerary := migrator.Itinerary(vm.VM)
+	if RollingBack(vm) {
+		itinerary = RollbackItinerary(vm)
+	}
 	step, done, err := itinerary.Next(vm.Phase)
 	if done || err != nil {
 		next = api.PhaseCompleted
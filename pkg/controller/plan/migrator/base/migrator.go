@@ -7,6 +7,7 @@ import (
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/health"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
@@ -194,6 +195,18 @@ func (r *BaseMigrator) Pipeline(vm plan.VM) (pipeline []*plan.Step, err error) {
 						Progress:    libitr.Progress{Total: 1},
 					},
 				})
+		case api.PhaseValidateTarget:
+			pipeline = append(
+				pipeline,
+				&plan.Step{
+					Task: plan.Task{
+						Name:        TargetValidation,
+						Description: "Validate the migrated VM.",
+						Phase:       api.StepPending,
+						Progress:    libitr.Progress{Total: 1},
+					},
+					Tasks: health.Tasks(r.Context.Plan.Spec.TargetValidation),
+				})
 		case api.PhasePreflightInspection:
 			pipeline = append(
 				pipeline,
@@ -274,6 +287,8 @@ func (r *BaseMigrator) Step(status *plan.VMStatus) (step string) {
 		step = DiskTransferV2v
 	case api.PhaseCreateVM:
 		step = VMCreation
+	case api.PhaseValidateTarget:
+		step = TargetValidation
 	case api.PhasePreHook, api.PhasePostHook:
 		step = status.Phase
 	case api.PhaseStorePowerState, api.PhasePowerOffSource, api.PhaseWaitForPowerOff:
@@ -284,6 +299,8 @@ func (r *BaseMigrator) Step(status *plan.VMStatus) (step string) {
 		}
 	case api.PhasePreflightInspection:
 		step = PreflightInspection
+	case api.PhaseRollbackCleanup, api.PhaseRollbackPowerOnSource, api.PhaseRollbackWaitForPowerOn:
+		step = Rollback
 	default:
 		step = Unknown
 	}
@@ -327,6 +344,7 @@ func (r *BaseMigrator) warmItinerary() *libitr.Itinerary {
 			{Name: api.PhaseCreateGuestConversionPod, All: RequiresConversion},
 			{Name: api.PhaseConvertGuest, All: RequiresConversion},
 			{Name: api.PhaseCreateVM},
+			{Name: api.PhaseValidateTarget, All: ValidateTarget},
 			{Name: api.PhasePostHook, All: HasPostHook},
 			{Name: api.PhaseCompleted},
 		},
@@ -350,6 +368,7 @@ func (r *BaseMigrator) coldItinerary() *libitr.Itinerary {
 			{Name: api.PhaseCopyDisksVirtV2V, All: RequiresConversion},
 			{Name: api.PhaseConvertOpenstackSnapshot, All: OpenstackImageMigration},
 			{Name: api.PhaseCreateVM},
+			{Name: api.PhaseValidateTarget, All: ValidateTarget},
 			{Name: api.PhasePostHook, All: HasPostHook},
 			{Name: api.PhaseCompleted},
 		},
@@ -368,12 +387,90 @@ func (r *BaseMigrator) onlyConversionItinerary() *libitr.Itinerary {
 			{Name: api.PhaseCreateGuestConversionPod, All: RequiresConversion},
 			{Name: api.PhaseConvertGuest, All: RequiresConversion},
 			{Name: api.PhaseCreateVM},
+			{Name: api.PhaseValidateTarget, All: ValidateTarget},
 			{Name: api.PhasePostHook, All: HasPostHook},
 			{Name: api.PhaseCompleted},
 		},
 	}
 }
 
+// Rollback itinerary.
+// Removes the target resources and restores the
+// power state of the source VM after a failed migration.
+func RollbackItinerary(vm *plan.VMStatus) *libitr.Itinerary {
+	return &libitr.Itinerary{
+		Name: "Rollback",
+		Pipeline: libitr.Pipeline{
+			{Name: api.PhaseRollbackCleanup},
+			{Name: api.PhaseRollbackPowerOnSource, All: RestorePowerOn},
+			{Name: api.PhaseRollbackWaitForPowerOn, All: RestorePowerOn},
+			{Name: api.PhaseCompleted},
+		},
+		Predicate: &RollbackPredicate{vm: vm},
+	}
+}
+
+// Build the rollback pipeline step.
+func RollbackStep() *plan.Step {
+	step := &plan.Step{
+		Task: plan.Task{
+			Name:        Rollback,
+			Description: "Roll back the migration.",
+			Progress:    libitr.Progress{Total: 1},
+			Phase:       api.StepRunning,
+		},
+	}
+	step.MarkStarted()
+	return step
+}
+
+// The VM migration is being (or has been) rolled back.
+func RollingBack(vm *plan.VMStatus) bool {
+	_, found := vm.FindStep(Rollback)
+	return found
+}
+
+// The source VM has been powered off before the phase.
+// The phase follows the power off phase in the itinerary.
+func PoweredOff(itinerary *libitr.Itinerary, phase string) bool {
+	list, err := itinerary.List()
+	if err != nil {
+		return false
+	}
+	poweredOff := false
+	for _, step := range list {
+		if step.Name == phase {
+			return poweredOff
+		}
+		if step.Name == api.PhasePowerOffSource {
+			poweredOff = true
+		}
+	}
+
+	return false
+}
+
+// Rollback step predicate.
+type RollbackPredicate struct {
+	// VM status.
+	vm *plan.VMStatus
+}
+
+// Evaluate predicate flags.
+func (r *RollbackPredicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
+	switch flag {
+	case RestorePowerOn:
+		allowed = r.vm.RestorePowerState == plan.VMPowerStateOn
+	}
+
+	return
+}
+
+// Count of predicates.
+func (r *RollbackPredicate) Count() int {
+	return 0x10
+}
+
 // Step predicate.
 type BasePredicate struct {
 	// VM listed on the plan.
@@ -407,6 +504,8 @@ func (r *BasePredicate) Evaluate(flag libitr.Flag) (allowed bool, err error) {
 		allowed = r.context.Plan.IsSourceProviderVSphere()
 	case RunInspection:
 		allowed = r.context.Plan.ShouldRunPreflightInspection()
+	case ValidateTarget:
+		allowed = r.context.Plan.Spec.TargetValidation != nil
 	}
 
 	return
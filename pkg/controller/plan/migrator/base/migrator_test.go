@@ -0,0 +1,63 @@
+package base
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/onsi/gomega"
+)
+
+func TestPoweredOff(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	migrator := &BaseMigrator{}
+	cold := migrator.coldItinerary()
+	g.Expect(PoweredOff(cold, api.PhaseStarted)).To(gomega.BeFalse())
+	g.Expect(PoweredOff(cold, api.PhaseStorePowerState)).To(gomega.BeFalse())
+	g.Expect(PoweredOff(cold, api.PhasePowerOffSource)).To(gomega.BeFalse())
+	g.Expect(PoweredOff(cold, api.PhaseWaitForPowerOff)).To(gomega.BeTrue())
+	g.Expect(PoweredOff(cold, api.PhaseCreateVM)).To(gomega.BeTrue())
+	g.Expect(PoweredOff(cold, "Unknown")).To(gomega.BeFalse())
+
+	warm := migrator.warmItinerary()
+	g.Expect(PoweredOff(warm, api.PhaseCopyDisks)).To(gomega.BeFalse())
+	g.Expect(PoweredOff(warm, api.PhaseFinalize)).To(gomega.BeTrue())
+}
+
+func TestRollbackItinerary(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	names := func(vm *plan.VMStatus) (names []string) {
+		list, err := RollbackItinerary(vm).List()
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		for _, step := range list {
+			names = append(names, step.Name)
+		}
+		return
+	}
+
+	vm := &plan.VMStatus{RestorePowerState: plan.VMPowerStateOn}
+	g.Expect(names(vm)).To(gomega.Equal([]string{
+		api.PhaseRollbackCleanup,
+		api.PhaseRollbackPowerOnSource,
+		api.PhaseRollbackWaitForPowerOn,
+		api.PhaseCompleted,
+	}))
+	vm = &plan.VMStatus{RestorePowerState: plan.VMPowerStateOff}
+	g.Expect(names(vm)).To(gomega.Equal([]string{
+		api.PhaseRollbackCleanup,
+		api.PhaseCompleted,
+	}))
+}
+
+func TestRollingBack(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := &plan.VMStatus{}
+	g.Expect(RollingBack(vm)).To(gomega.BeFalse())
+	vm.Pipeline = append(vm.Pipeline, RollbackStep())
+	g.Expect(RollingBack(vm)).To(gomega.BeTrue())
+	vm.Phase = api.PhaseRollbackPowerOnSource
+	g.Expect((&BaseMigrator{}).Step(vm)).To(gomega.Equal(Rollback))
+}
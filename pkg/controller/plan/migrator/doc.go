@@ -13,6 +13,9 @@ import (
 
 type Migrator = base.Migrator
 
+// Rollback pipeline step.
+const Rollback = base.Rollback
+
 var log = logging.WithName("migrator")
 
 // New builds a new Migrator implementation from a plan context.
@@ -41,3 +44,21 @@ func New(context *plancontext.Context) (migrator Migrator, err error) {
 func NextPhase(m Migrator, vm *planapi.VMStatus) {
 	base.NextPhase(m, vm)
 }
+
+// RollingBack determines whether the VM migration is being
+// rolled back. Alias of base.RollingBack.
+func RollingBack(vm *planapi.VMStatus) bool {
+	return base.RollingBack(vm)
+}
+
+// RollbackStep builds the rollback pipeline step.
+// Alias of base.RollbackStep.
+func RollbackStep() *planapi.Step {
+	return base.RollbackStep()
+}
+
+// PoweredOff determines whether the source VM has been powered
+// off before the phase. Alias of base.PoweredOff.
+func PoweredOff(m Migrator, vm *planapi.VMStatus, phase string) bool {
+	return base.PoweredOff(m.Itinerary(vm.VM), phase)
+}
@@ -0,0 +1,254 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/settings"
+)
+
+// Package logger.
+var log = logging.WithName("notifier")
+
+// Application settings.
+var Settings = &settings.Settings
+
+// CloudEvents.
+const (
+	SpecVersion = "1.0"
+	ContentType = "application/cloudevents+json"
+	Source      = "/forklift/controller"
+)
+
+// Event types.
+const (
+	TypePrefix       = "io.konveyor.forklift."
+	VMStarted        = TypePrefix + "vm.started"
+	VMPhaseChanged   = TypePrefix + "vm.phase.changed"
+	VMFailed         = TypePrefix + "vm.failed"
+	VMSucceeded      = TypePrefix + "vm.succeeded"
+	VMCutoverPending = TypePrefix + "vm.cutover.pending"
+	VMPrecopyDone    = TypePrefix + "vm.precopy.done"
+)
+
+// CloudEvent (structured content mode).
+type Event struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Data      `json:"data"`
+}
+
+// Event data.
+type Data struct {
+	// Plan namespace.
+	Namespace string `json:"namespace"`
+	// Plan name.
+	Plan string `json:"plan"`
+	// Migration name.
+	Migration string `json:"migration,omitempty"`
+	// VM ID.
+	VMID string `json:"vmID,omitempty"`
+	// VM name.
+	VMName string `json:"vmName,omitempty"`
+	// Current phase.
+	Phase string `json:"phase,omitempty"`
+	// Previous phase.
+	PreviousPhase string `json:"previousPhase,omitempty"`
+	// Number of completed precopies.
+	Precopies int `json:"precopies,omitempty"`
+	// Message.
+	Message string `json:"message,omitempty"`
+}
+
+// Build a new event.
+func New(kind string, data Data) (event *Event) {
+	event = &Event{
+		SpecVersion:     SpecVersion,
+		ID:              uuid.NewString(),
+		Source:          Source,
+		Type:            kind,
+		Subject:         strings.Join([]string{data.Namespace, data.Plan, data.VMID}, "/"),
+		Time:            time.Now().UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+	return
+}
+
+// Notifier (singleton).
+var Default = &Notifier{}
+
+// Notifier.
+// Posts the events to the sink using a single worker
+// so the events are delivered in order. Events are
+// dropped when the queue is full.
+type Notifier struct {
+	// HTTP client.
+	Client *http.Client
+	// Event queue.
+	queue chan *Event
+	// Start the worker once.
+	once sync.Once
+}
+
+// Enabled.
+func (r *Notifier) Enabled() bool {
+	return Settings.Notifier.Enabled()
+}
+
+// Accept the event based on the filters.
+func (r *Notifier) Accept(event *Event) bool {
+	filter := Settings.Notifier.Filter
+	if len(filter.Namespaces) > 0 {
+		matched := false
+		for _, ns := range filter.Namespaces {
+			if ns == event.Data.Namespace {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(filter.Events) > 0 {
+		matched := false
+		for _, kind := range filter.Events {
+			if kind == event.Type || TypePrefix+kind == event.Type {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Queue the event for delivery.
+// Does not block.
+func (r *Notifier) Notify(event *Event) {
+	if !r.Enabled() || !r.Accept(event) {
+		return
+	}
+	r.once.Do(r.start)
+	select {
+	case r.queue <- event:
+	default:
+		log.Info(
+			"Queue full, event dropped.",
+			"type",
+			event.Type,
+			"subject",
+			event.Subject)
+	}
+}
+
+// Post the event to the sink.
+func (r *Notifier) Send(event *Event) (err error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request, err := http.NewRequest(http.MethodPost, Settings.Notifier.Sink, bytes.NewReader(body))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	request.Header.Set("Content-Type", ContentType)
+	client, err := r.client()
+	if err != nil {
+		return
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer response.Body.Close()
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		err = liberr.New(
+			fmt.Sprintf("sink replied: %s", response.Status),
+			"type",
+			event.Type)
+	}
+
+	return
+}
+
+// Start the worker.
+func (r *Notifier) start() {
+	r.queue = make(chan *Event, Settings.Notifier.Limit.Queue)
+	go func() {
+		for event := range r.queue {
+			err := r.Send(event)
+			if err != nil {
+				log.Error(
+					err,
+					"Event not delivered.",
+					"type",
+					event.Type,
+					"subject",
+					event.Subject)
+			}
+		}
+	}()
+}
+
+// Build the HTTP client as needed.
+func (r *Notifier) client() (client *http.Client, err error) {
+	if r.Client != nil {
+		client = r.Client
+		return
+	}
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 10 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       10 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	if len(Settings.Notifier.CA) > 0 {
+		pool := x509.NewCertPool()
+		ca, xErr := os.ReadFile(Settings.Notifier.CA)
+		if xErr != nil {
+			err = liberr.Wrap(xErr)
+			return
+		}
+		pool.AppendCertsFromPEM(ca)
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs: pool,
+		}
+	}
+	r.Client = &http.Client{
+		Transport: transport,
+		Timeout:   30 * time.Second,
+	}
+	client = r.Client
+
+	return
+}
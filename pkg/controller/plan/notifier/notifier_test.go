@@ -0,0 +1,81 @@
+package notifier
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/onsi/gomega"
+)
+
+func TestAccept(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	defer func() {
+		Settings.Notifier.Filter.Events = nil
+		Settings.Notifier.Filter.Namespaces = nil
+	}()
+	notifier := &Notifier{}
+	event := New(VMFailed, Data{Namespace: "ns1", Plan: "p1", VMID: "vm-1"})
+	g.Expect(notifier.Accept(event)).To(gomega.BeTrue())
+	Settings.Notifier.Filter.Events = []string{"vm.succeeded", VMFailed}
+	g.Expect(notifier.Accept(event)).To(gomega.BeTrue())
+	Settings.Notifier.Filter.Events = []string{"vm.failed"}
+	g.Expect(notifier.Accept(event)).To(gomega.BeTrue())
+	Settings.Notifier.Filter.Events = []string{"vm.succeeded"}
+	g.Expect(notifier.Accept(event)).To(gomega.BeFalse())
+	Settings.Notifier.Filter.Events = nil
+	Settings.Notifier.Filter.Namespaces = []string{"ns2"}
+	g.Expect(notifier.Accept(event)).To(gomega.BeFalse())
+	Settings.Notifier.Filter.Namespaces = []string{"ns2", "ns1"}
+	g.Expect(notifier.Accept(event)).To(gomega.BeTrue())
+}
+
+func TestNotify(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	received := make(chan *Event, 10)
+	contentType := make(chan string, 10)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			event := &Event{}
+			_ = json.NewDecoder(r.Body).Decode(event)
+			contentType <- r.Header.Get("Content-Type")
+			received <- event
+		}))
+	defer server.Close()
+	Settings.Notifier.Sink = server.URL
+	Settings.Notifier.Limit.Queue = 10
+	Settings.Notifier.Filter.Events = []string{"vm.cutover.pending"}
+	defer func() {
+		Settings.Notifier.Sink = ""
+		Settings.Notifier.Filter.Events = nil
+	}()
+	notifier := &Notifier{}
+	notifier.Notify(New(VMStarted, Data{Namespace: "ns1", Plan: "p1", VMID: "vm-1"}))
+	notifier.Notify(New(VMCutoverPending, Data{Namespace: "ns1", Plan: "p1", VMID: "vm-1", Precopies: 1}))
+	var event *Event
+	g.Eventually(received, time.Second*5).Should(gomega.Receive(&event))
+	g.Expect(<-contentType).To(gomega.Equal(ContentType))
+	g.Expect(event.SpecVersion).To(gomega.Equal(SpecVersion))
+	g.Expect(event.Type).To(gomega.Equal(VMCutoverPending))
+	g.Expect(event.Subject).To(gomega.Equal("ns1/p1/vm-1"))
+	g.Expect(event.Data.Precopies).To(gomega.Equal(1))
+	g.Consistently(received, time.Millisecond*200).ShouldNot(gomega.Receive())
+}
+
+func TestSendFailed(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	server := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+	defer server.Close()
+	Settings.Notifier.Sink = server.URL
+	defer func() {
+		Settings.Notifier.Sink = ""
+	}()
+	notifier := &Notifier{}
+	err := notifier.Send(New(VMSucceeded, Data{Namespace: "ns1", Plan: "p1"}))
+	g.Expect(err).To(gomega.HaveOccurred())
+}
@@ -0,0 +1,59 @@
+package plan
+
+import (
+	"fmt"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	core "k8s.io/api/core/v1"
+)
+
+// Reflect the VMs paused by the user.
+// A VM listed in the pause list of the migration stops advancing
+// and keeps its recorded phase until removed from the list.
+// Canceled and completed VMs are not paused.
+func (r *Migration) updatePaused() {
+	for _, vm := range r.Plan.Status.Migration.VMs {
+		paused := r.paused(vm)
+		switch {
+		case paused && !vm.HasCondition(api.ConditionPaused):
+			message := "The VM migration is paused."
+			if vm.MarkedStarted() {
+				message = fmt.Sprintf("The VM migration is paused in phase '%s'.", vm.Phase)
+			}
+			vm.SetCondition(
+				libcnd.Condition{
+					Type:     api.ConditionPaused,
+					Status:   True,
+					Category: api.CategoryAdvisory,
+					Reason:   UserRequested,
+					Message:  message,
+					Durable:  true,
+				})
+			r.record(core.EventTypeNormal, EventVMPaused, fmt.Sprintf("VM %s: %s", vm.String(), message))
+			r.Log.Info(
+				"Migration [PAUSED]",
+				"vm",
+				vm.String(),
+				"phase",
+				vm.Phase)
+		case !paused && vm.HasCondition(api.ConditionPaused):
+			vm.DeleteCondition(api.ConditionPaused)
+			r.record(core.EventTypeNormal, EventVMResumed, fmt.Sprintf("VM %s: The VM migration has resumed.", vm.String()))
+			r.Log.Info(
+				"Migration [RESUMED]",
+				"vm",
+				vm.String(),
+				"phase",
+				vm.Phase)
+		}
+	}
+}
+
+// The VM is paused by the user.
+func (r *Migration) paused(vm *plan.VMStatus) bool {
+	return r.Migration.Spec.Paused(vm.Ref) &&
+		!r.Migration.Spec.Canceled(vm.Ref) &&
+		!vm.MarkedCompleted()
+}
@@ -0,0 +1,140 @@
+package plan
+
+import (
+	"fmt"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// VM condition types.
+const (
+	ReplicationDegraded = "ReplicationDegraded"
+)
+
+// Step annotations.
+const (
+	// Phase resumed by the next precopy after a failure.
+	PrecopyRetryAnnotation = "precopyRetryPhase"
+)
+
+// Phases of the precopy loop that are retried on the next
+// precopy when replicating. Maps the failed phase to the
+// phase that is resumed.
+var precopyRetry = map[string]string{
+	api.PhaseRemovePreviousSnapshot:         api.PhaseRemovePreviousSnapshot,
+	api.PhaseWaitForPreviousSnapshotRemoval: api.PhaseRemovePreviousSnapshot,
+	api.PhaseCreateSnapshot:                 api.PhaseCreateSnapshot,
+	api.PhaseWaitForSnapshot:                api.PhaseCreateSnapshot,
+	api.PhaseStoreSnapshotDeltas:            api.PhaseStoreSnapshotDeltas,
+	api.PhaseAddCheckpoint:                  api.PhaseAddCheckpoint,
+}
+
+// Interval between precopies.
+func (r *Migration) precopyInterval() time.Duration {
+	interval := time.Duration(Settings.PrecopyInterval) * time.Minute
+	if r.Plan.IsReplicating() {
+		interval = r.Plan.Spec.Replication.PrecopyInterval(interval)
+	}
+	return interval
+}
+
+// Record a successful precopy.
+// The snapshot time of the precopy becomes the recovery point
+// and the precopy history is compacted when replicating.
+func (r *Migration) precopySucceeded(vm *plan.VMStatus) {
+	now := meta.Now()
+	next := meta.NewTime(now.Add(r.precopyInterval()))
+	n := len(vm.Warm.Precopies)
+	precopy := &vm.Warm.Precopies[n-1]
+	precopy.End = &now
+	vm.Warm.NextPrecopyAt = &next
+	vm.Warm.Successes++
+	vm.Warm.ConsecutiveFailures = 0
+	vm.Warm.RecoveryPoint = precopy.Start
+	vm.Warm.UpdateRPO(now.Time)
+	if r.Plan.IsReplicating() {
+		vm.DeleteCondition(ReplicationDegraded)
+		vm.Warm.Compact(r.Plan.Spec.Replication.RetainedPrecopies())
+	}
+}
+
+// Retry a failed precopy on the next interval when replicating.
+// The step error is cleared and the VM waits in the paused phase.
+// The replication is reported as degraded once the consecutive
+// failures reach the threshold. Returns true when retried.
+func (r *Migration) retryPrecopy(vm *plan.VMStatus) bool {
+	if !r.Plan.IsReplicating() || vm.Warm == nil || vm.Error == nil {
+		return false
+	}
+	if r.Migration.Spec.Cutover != nil {
+		return false
+	}
+	resume, found := precopyRetry[vm.Phase]
+	if !found {
+		return false
+	}
+	step, found := vm.FindStep(r.migrator.Step(vm))
+	if !found {
+		return false
+	}
+	reasons := vm.Error.Reasons
+	step.Error = nil
+	vm.Error = nil
+	if step.Annotations == nil {
+		step.Annotations = make(map[string]string)
+	}
+	step.Annotations[PrecopyRetryAnnotation] = resume
+	if resume == api.PhaseCreateSnapshot {
+		// Drop the incomplete precopy.
+		n := len(vm.Warm.Precopies)
+		if n > 0 && vm.Warm.Precopies[n-1].End == nil && vm.Phase == api.PhaseWaitForSnapshot {
+			vm.Warm.Precopies = vm.Warm.Precopies[:n-1]
+		}
+	}
+	next := meta.NewTime(time.Now().Add(r.precopyInterval()))
+	vm.Warm.NextPrecopyAt = &next
+	vm.Warm.ConsecutiveFailures++
+	threshold := r.Plan.Spec.Replication.Threshold()
+	if vm.Warm.ConsecutiveFailures >= threshold {
+		vm.SetCondition(
+			libcnd.Condition{
+				Type:     ReplicationDegraded,
+				Status:   True,
+				Category: api.CategoryCritical,
+				Message: fmt.Sprintf(
+					"%d consecutive precopies have failed.",
+					vm.Warm.ConsecutiveFailures),
+				Items: reasons,
+			})
+	}
+	r.Log.Info(
+		"Precopy failed, retrying on the next interval.",
+		"vm",
+		vm.String(),
+		"phase",
+		vm.Phase,
+		"failures",
+		vm.Warm.ConsecutiveFailures,
+		"next",
+		next)
+	vm.Phase = api.PhaseCopyingPaused
+	return true
+}
+
+// Resume the precopy loop.
+// A precopy retried after a failure resumes the failed phase.
+func (r *Migration) resumePrecopy(vm *plan.VMStatus) {
+	step, found := vm.FindStep(r.migrator.Step(vm))
+	if found {
+		if resume, retried := step.Annotations[PrecopyRetryAnnotation]; retried {
+			delete(step.Annotations, PrecopyRetryAnnotation)
+			vm.Phase = resume
+			return
+		}
+	}
+	r.NextPhase(vm)
+}
@@ -0,0 +1,221 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Formats.
+const (
+	JSON = "json"
+	CSV  = "csv"
+	HTML = "html"
+)
+
+// Content types.
+var ContentType = map[string]string{
+	JSON: "application/json",
+	CSV:  "text/csv",
+	HTML: "text/html; charset=utf-8",
+}
+
+// CSV header.
+// One row per VM per migration.
+var csvHeader = []string{
+	"plan",
+	"migration",
+	"vm",
+	"vmID",
+	"targetName",
+	"targetNamespace",
+	"status",
+	"phase",
+	"started",
+	"completed",
+	"duration",
+	"disks",
+	"totalMB",
+	"precopies",
+	"warnings",
+	"errors",
+}
+
+// Render the report in the specified format.
+func (r *Report) Render(format string, w io.Writer) (err error) {
+	switch strings.ToLower(format) {
+	case JSON, "":
+		err = r.JSON(w)
+	case CSV:
+		err = r.CSV(w)
+	case HTML:
+		err = r.HTML(w)
+	default:
+		err = liberr.New("format not supported.", "format", format)
+	}
+
+	return
+}
+
+// Render as JSON.
+func (r *Report) JSON(w io.Writer) (err error) {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(r)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Render as CSV.
+func (r *Report) CSV(w io.Writer) (err error) {
+	writer := csv.NewWriter(w)
+	err = writer.Write(csvHeader)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, m := range r.Migrations {
+		for _, vm := range m.VMs {
+			var totalMB int64
+			for _, disk := range vm.Disks {
+				totalMB += disk.SizeMB
+			}
+			err = writer.Write([]string{
+				r.Plan.Name,
+				m.Name,
+				vm.Name,
+				vm.ID,
+				vm.TargetName,
+				vm.TargetNamespace,
+				vm.Status,
+				vm.Phase,
+				timestamp(vm.Started),
+				timestamp(vm.Completed),
+				vm.Duration,
+				strconv.Itoa(len(vm.Disks)),
+				strconv.FormatInt(totalMB, 10),
+				strconv.Itoa(len(vm.Precopies)),
+				strings.Join(vm.Warnings, "; "),
+				strings.Join(vm.Errors, "; "),
+			})
+			if err != nil {
+				err = liberr.Wrap(err)
+				return
+			}
+		}
+	}
+	writer.Flush()
+	err = writer.Error()
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Render as a self-contained HTML document.
+func (r *Report) HTML(w io.Writer) (err error) {
+	err = htmlTemplate.Execute(w, r)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Format a timestamp.
+func timestamp(t *meta.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// HTML template.
+var htmlTemplate = template.Must(
+	template.New("report").Funcs(
+		template.FuncMap{
+			"timestamp": timestamp,
+			"lower":     strings.ToLower,
+		}).Parse(htmlSource))
+
+// HTML source.
+// Styles are inline so the document can be shared as a single file.
+const htmlSource = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Migration report: {{.Plan.Namespace}}/{{.Plan.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #151515; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.3em; margin-top: 2em; }
+table { border-collapse: collapse; margin: 1em 0; width: 100%; }
+th, td { border: 1px solid #d2d2d2; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f0f0f0; }
+details { margin: 0.5em 0; }
+.succeeded { color: #3e8635; }
+.failed { color: #c9190b; }
+.canceled, .running, .pending { color: #6a6e73; }
+.warning { color: #795600; }
+</style>
+</head>
+<body>
+<h1>Migration report: {{.Plan.Namespace}}/{{.Plan.Name}}</h1>
+<table>
+<tr><th>Source provider</th><td>{{.Source.Namespace}}/{{.Source.Name}}</td></tr>
+<tr><th>Destination provider</th><td>{{.Destination.Namespace}}/{{.Destination.Name}}</td></tr>
+<tr><th>Target namespace</th><td>{{.TargetNamespace}}</td></tr>
+<tr><th>Type</th><td>{{.Type}}</td></tr>
+<tr><th>Archived</th><td>{{.Archived}}</td></tr>
+<tr><th>Generated</th><td>{{timestamp .Generated.DeepCopy}}</td></tr>
+</table>
+{{range .Migrations}}
+<h2>Migration {{.Name}} <span class="{{lower .Status}}">{{.Status}}</span></h2>
+<p>Started: {{timestamp .Started}} Completed: {{timestamp .Completed}} Duration: {{.Duration}}</p>
+<table>
+<tr><th>VM</th><th>Target</th><th>Status</th><th>Phase</th><th>Started</th><th>Completed</th><th>Duration</th></tr>
+{{range .VMs}}
+<tr>
+<td>{{.Name}} ({{.ID}})</td>
+<td>{{.TargetNamespace}}/{{.TargetName}}</td>
+<td class="{{lower .Status}}">{{.Status}}</td>
+<td>{{.Phase}}</td>
+<td>{{timestamp .Started}}</td>
+<td>{{timestamp .Completed}}</td>
+<td>{{.Duration}}</td>
+</tr>
+<tr><td colspan="7">
+{{range .Errors}}<div class="failed">{{.}}</div>{{end}}
+{{range .Warnings}}<div class="warning">{{.}}</div>{{end}}
+{{if .Disks}}<details><summary>Disks ({{len .Disks}})</summary>
+<table><tr><th>Disk</th><th>Size (MB)</th></tr>
+{{range .Disks}}<tr><td>{{.Name}}</td><td>{{.SizeMB}}</td></tr>{{end}}
+</table></details>{{end}}
+{{if .Steps}}<details><summary>Steps ({{len .Steps}})</summary>
+<table><tr><th>Step</th><th>Phase</th><th>Started</th><th>Completed</th><th>Duration</th><th>Errors</th></tr>
+{{range .Steps}}<tr><td>{{.Name}}</td><td>{{.Phase}}</td><td>{{timestamp .Started}}</td><td>{{timestamp .Completed}}</td><td>{{.Duration}}</td><td>{{range .Errors}}<div class="failed">{{.}}</div>{{end}}</td></tr>{{end}}
+</table></details>{{end}}
+{{if .Precopies}}<details><summary>Precopies ({{len .Precopies}})</summary>
+<table><tr><th>Started</th><th>Completed</th><th>Duration</th></tr>
+{{range .Precopies}}<tr><td>{{timestamp .Started}}</td><td>{{timestamp .Completed}}</td><td>{{.Duration}}</td></tr>{{end}}
+</table></details>{{end}}
+</td></tr>
+{{end}}
+</table>
+{{else}}
+<p>No migrations.</p>
+{{end}}
+</body>
+</html>
+`
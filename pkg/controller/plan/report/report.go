@@ -0,0 +1,338 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Statuses.
+const (
+	Succeeded = "Succeeded"
+	Failed    = "Failed"
+	Canceled  = "Canceled"
+	Running   = "Running"
+	Paused    = "Paused"
+	Pending   = "Pending"
+)
+
+// Warning condition categories.
+// The conversion warnings are reported with the "Warning" category.
+var warnCategories = map[string]bool{
+	libcnd.Warn: true,
+	"Warning":   true,
+}
+
+// Migration report.
+type Report struct {
+	// Plan.
+	Plan Ref `json:"plan"`
+	// The plan is archived.
+	Archived bool `json:"archived"`
+	// Source provider.
+	Source Ref `json:"source"`
+	// Destination provider.
+	Destination Ref `json:"destination"`
+	// Target namespace.
+	TargetNamespace string `json:"targetNamespace"`
+	// Migration type.
+	Type string `json:"type"`
+	// Generated timestamp.
+	Generated meta.Time `json:"generated"`
+	// Migrations, oldest first.
+	Migrations []Migration `json:"migrations"`
+}
+
+// Object reference.
+type Ref struct {
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	UID       types.UID `json:"uid,omitempty"`
+}
+
+// Migration.
+type Migration struct {
+	Ref `json:",inline"`
+	// Status.
+	Status string `json:"status"`
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// Duration.
+	Duration string `json:"duration,omitempty"`
+	// VMs.
+	VMs []VM `json:"vms"`
+}
+
+// Migrated VM.
+type VM struct {
+	// Source VM ID.
+	ID string `json:"id"`
+	// Source VM name.
+	Name string `json:"name"`
+	// Source VM namespace (OpenShift only).
+	Namespace string `json:"namespace,omitempty"`
+	// Target VM name.
+	TargetName string `json:"targetName"`
+	// Target VM namespace.
+	TargetNamespace string `json:"targetNamespace"`
+	// Status.
+	Status string `json:"status"`
+	// Last phase.
+	Phase string `json:"phase"`
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// Duration.
+	Duration string `json:"duration,omitempty"`
+	// Transferred disks.
+	Disks []Disk `json:"disks,omitempty"`
+	// Pipeline steps.
+	Steps []Step `json:"steps,omitempty"`
+	// Warm migration precopies.
+	Precopies []Precopy `json:"precopies,omitempty"`
+	// Warnings.
+	Warnings []string `json:"warnings,omitempty"`
+	// Errors.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Transferred disk.
+type Disk struct {
+	// Name.
+	Name string `json:"name"`
+	// Size in MB.
+	SizeMB int64 `json:"sizeMB"`
+}
+
+// Pipeline step.
+type Step struct {
+	// Name.
+	Name string `json:"name"`
+	// Description.
+	Description string `json:"description,omitempty"`
+	// Phase.
+	Phase string `json:"phase,omitempty"`
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// Duration.
+	Duration string `json:"duration,omitempty"`
+	// Errors.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Warm migration precopy.
+type Precopy struct {
+	// Started timestamp.
+	Started *meta.Time `json:"started,omitempty"`
+	// Completed timestamp.
+	Completed *meta.Time `json:"completed,omitempty"`
+	// Duration.
+	Duration string `json:"duration,omitempty"`
+}
+
+// Build the report for a plan.
+// The migrations are the Migration CRs for the plan. When the
+// migrations have been deleted, the VMs reported in the plan
+// status are reported as the last migration.
+func Build(p *api.Plan, migrations []api.Migration) (report *Report) {
+	report = &Report{
+		Plan: Ref{
+			Namespace: p.Namespace,
+			Name:      p.Name,
+			UID:       p.UID,
+		},
+		Archived: p.Spec.Archived,
+		Source: Ref{
+			Namespace: p.Spec.Provider.Source.Namespace,
+			Name:      p.Spec.Provider.Source.Name,
+		},
+		Destination: Ref{
+			Namespace: p.Spec.Provider.Destination.Namespace,
+			Name:      p.Spec.Provider.Destination.Name,
+		},
+		TargetNamespace: p.Spec.TargetNamespace,
+		Type:            string(api.MigrationCold),
+		Generated:       meta.Now(),
+		Migrations:      []Migration{},
+	}
+	if p.Spec.Type != "" {
+		report.Type = string(p.Spec.Type)
+	} else if p.IsWarm() {
+		report.Type = string(api.MigrationWarm)
+	}
+	matched := []api.Migration{}
+	for i := range migrations {
+		if migrations[i].Match(p) {
+			matched = append(matched, migrations[i])
+		}
+	}
+	sort.SliceStable(
+		matched,
+		func(i, j int) bool {
+			return matched[i].CreationTimestamp.Before(&matched[j].CreationTimestamp)
+		})
+	for i := range matched {
+		m := &matched[i]
+		report.Migrations = append(
+			report.Migrations,
+			report.migration(
+				Ref{
+					Namespace: m.Namespace,
+					Name:      m.Name,
+					UID:       m.UID,
+				},
+				m.Status.Timed,
+				m.Status.VMs))
+	}
+	if len(report.Migrations) == 0 && len(p.Status.Migration.VMs) > 0 {
+		active := p.Status.Migration.ActiveSnapshot().Migration
+		report.Migrations = append(
+			report.Migrations,
+			report.migration(
+				Ref{
+					Namespace: active.Namespace,
+					Name:      active.Name,
+					UID:       active.UID,
+				},
+				p.Status.Migration.Timed,
+				p.Status.Migration.VMs))
+	}
+
+	return
+}
+
+// Build a migration.
+func (r *Report) migration(ref Ref, timed plan.Timed, vms []*plan.VMStatus) (m Migration) {
+	m = Migration{
+		Ref:       ref,
+		Started:   timed.Started,
+		Completed: timed.Completed,
+		Duration:  duration(timed.Started, timed.Completed),
+		VMs:       []VM{},
+	}
+	failed := 0
+	canceled := 0
+	for _, vm := range vms {
+		reported := r.vm(vm)
+		switch reported.Status {
+		case Failed:
+			failed++
+		case Canceled:
+			canceled++
+		}
+		m.VMs = append(m.VMs, reported)
+	}
+	switch {
+	case timed.Started == nil:
+		m.Status = Pending
+	case timed.Completed == nil:
+		m.Status = Running
+	case failed > 0:
+		m.Status = Failed
+	case canceled > 0 && canceled == len(vms):
+		m.Status = Canceled
+	default:
+		m.Status = Succeeded
+	}
+
+	return
+}
+
+// Build a VM.
+func (r *Report) vm(vm *plan.VMStatus) (reported VM) {
+	reported = VM{
+		ID:              vm.ID,
+		Name:            vm.Name,
+		Namespace:       vm.Namespace,
+		TargetName:      vm.Name,
+		TargetNamespace: r.TargetNamespace,
+		Phase:           vm.Phase,
+		Started:         vm.Started,
+		Completed:       vm.Completed,
+		Duration:        duration(vm.Started, vm.Completed),
+	}
+	if vm.NewName != "" {
+		reported.TargetName = vm.NewName
+	}
+	if vm.TargetName != "" {
+		reported.TargetName = vm.TargetName
+	}
+	switch {
+	case vm.HasCondition(api.ConditionSucceeded):
+		reported.Status = Succeeded
+	case vm.HasCondition(api.ConditionFailed):
+		reported.Status = Failed
+	case vm.HasCondition(api.ConditionCanceled):
+		reported.Status = Canceled
+	case vm.HasCondition(api.ConditionPaused):
+		reported.Status = Paused
+	case vm.Running():
+		reported.Status = Running
+	default:
+		reported.Status = Pending
+	}
+	for _, step := range vm.Pipeline {
+		reportedStep := Step{
+			Name:        step.Name,
+			Description: step.Description,
+			Phase:       step.Phase,
+			Started:     step.Started,
+			Completed:   step.Completed,
+			Duration:    duration(step.Started, step.Completed),
+		}
+		if step.Error != nil {
+			reportedStep.Errors = step.Error.Reasons
+		}
+		reported.Steps = append(reported.Steps, reportedStep)
+		if len(reported.Disks) == 0 && step.Annotations["unit"] == "MB" {
+			for _, task := range step.Tasks {
+				reported.Disks = append(
+					reported.Disks,
+					Disk{
+						Name:   task.Name,
+						SizeMB: task.Progress.Total,
+					})
+			}
+		}
+	}
+	if vm.Warm != nil {
+		for _, precopy := range vm.Warm.Precopies {
+			reported.Precopies = append(
+				reported.Precopies,
+				Precopy{
+					Started:   precopy.Start,
+					Completed: precopy.End,
+					Duration:  duration(precopy.Start, precopy.End),
+				})
+		}
+	}
+	for _, cnd := range vm.List {
+		if warnCategories[cnd.Category] {
+			reported.Warnings = append(reported.Warnings, fmt.Sprintf("%s: %s", cnd.Type, cnd.Message))
+		}
+	}
+	if vm.Error != nil {
+		reported.Errors = vm.Error.Reasons
+	}
+
+	return
+}
+
+// Duration between timestamps.
+func duration(started, completed *meta.Time) string {
+	if started == nil || completed == nil {
+		return ""
+	}
+	return completed.Sub(started.Time).Round(time.Second).String()
+}
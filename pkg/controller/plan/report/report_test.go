@@ -0,0 +1,176 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	libitr "github.com/kubev2v/forklift/pkg/lib/itinerary"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func at(minutes int) *meta.Time {
+	t := meta.NewTime(time.Date(2024, 1, 1, 0, minutes, 0, 0, time.UTC))
+	return &t
+}
+
+func vmStatus(id string, succeeded bool) *plan.VMStatus {
+	vm := &plan.VMStatus{
+		VM: plan.VM{
+			Ref: ref.Ref{ID: id, Name: "vm-" + id},
+		},
+		Phase: api.PhaseCompleted,
+		Timed: plan.Timed{Started: at(0), Completed: at(30)},
+		Pipeline: []*plan.Step{
+			{
+				Task: plan.Task{
+					Name:        "DiskTransfer",
+					Phase:       api.StepCompleted,
+					Annotations: map[string]string{"unit": "MB"},
+					Timed:       plan.Timed{Started: at(1), Completed: at(21)},
+				},
+				Tasks: []*plan.Task{
+					{Name: "disk-1", Progress: libitr.Progress{Total: 1024}},
+					{Name: "disk-2", Progress: libitr.Progress{Total: 2048}},
+				},
+			},
+		},
+	}
+	if succeeded {
+		vm.SetCondition(libcnd.Condition{Type: api.ConditionSucceeded, Status: libcnd.True})
+	} else {
+		vm.AddError("disk transfer failed.")
+		vm.SetCondition(libcnd.Condition{Type: api.ConditionFailed, Status: libcnd.True})
+	}
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     "ConversionHasWarnings",
+			Status:   libcnd.True,
+			Category: "Warning",
+			Message:  "driver missing.",
+		})
+	return vm
+}
+
+func testPlan() *api.Plan {
+	return &api.Plan{
+		ObjectMeta: meta.ObjectMeta{Namespace: "ns", Name: "plan"},
+		Spec: api.PlanSpec{
+			TargetNamespace: "target",
+			Archived:        true,
+			Provider:        providers(),
+		},
+	}
+}
+
+func providers() (p provider.Pair) {
+	p.Source = core.ObjectReference{Namespace: "ns", Name: "vsphere"}
+	p.Destination = core.ObjectReference{Namespace: "ns", Name: "host"}
+	return
+}
+
+func migration(name string, created int, vms ...*plan.VMStatus) api.Migration {
+	m := api.Migration{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace:         "ns",
+			Name:              name,
+			CreationTimestamp: *at(created),
+		},
+		Spec: api.MigrationSpec{
+			Plan: core.ObjectReference{Namespace: "ns", Name: "plan"},
+		},
+	}
+	m.Status.Started = at(created)
+	m.Status.Completed = at(created + 30)
+	m.Status.VMs = vms
+	return m
+}
+
+func TestBuild(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	other := migration("other", 0, vmStatus("3", true))
+	other.Spec.Plan.Name = "other"
+	report := Build(
+		testPlan(),
+		[]api.Migration{
+			migration("second", 60, vmStatus("1", true)),
+			migration("first", 0, vmStatus("1", false), vmStatus("2", true)),
+			other,
+		})
+	g.Expect(report.Archived).To(gomega.BeTrue())
+	g.Expect(report.Type).To(gomega.Equal(string(api.MigrationCold)))
+	g.Expect(report.Migrations).To(gomega.HaveLen(2))
+	first := report.Migrations[0]
+	g.Expect(first.Name).To(gomega.Equal("first"))
+	g.Expect(first.Status).To(gomega.Equal(Failed))
+	g.Expect(first.Duration).To(gomega.Equal("30m0s"))
+	vm := first.VMs[0]
+	g.Expect(vm.Status).To(gomega.Equal(Failed))
+	g.Expect(vm.TargetName).To(gomega.Equal("vm-1"))
+	g.Expect(vm.TargetNamespace).To(gomega.Equal("target"))
+	g.Expect(vm.Disks).To(gomega.Equal([]Disk{{Name: "disk-1", SizeMB: 1024}, {Name: "disk-2", SizeMB: 2048}}))
+	g.Expect(vm.Steps).To(gomega.HaveLen(1))
+	g.Expect(vm.Steps[0].Duration).To(gomega.Equal("20m0s"))
+	g.Expect(vm.Warnings).To(gomega.Equal([]string{"ConversionHasWarnings: driver missing."}))
+	g.Expect(vm.Errors).To(gomega.Equal([]string{"disk transfer failed."}))
+	g.Expect(report.Migrations[1].Name).To(gomega.Equal("second"))
+	g.Expect(report.Migrations[1].Status).To(gomega.Equal(Succeeded))
+}
+
+func TestBuildFromPlanStatus(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := testPlan()
+	p.Status.Migration.Timed = plan.Timed{Started: at(0)}
+	p.Status.Migration.VMs = []*plan.VMStatus{vmStatus("1", true)}
+	report := Build(p, nil)
+	g.Expect(report.Migrations).To(gomega.HaveLen(1))
+	g.Expect(report.Migrations[0].Status).To(gomega.Equal(Running))
+	g.Expect(report.Migrations[0].VMs[0].Status).To(gomega.Equal(Succeeded))
+}
+
+func TestRender(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	report := Build(
+		testPlan(),
+		[]api.Migration{
+			migration("first", 0, vmStatus("1", false), vmStatus("2", true)),
+		})
+
+	buf := &bytes.Buffer{}
+	g.Expect(report.Render(JSON, buf)).To(gomega.Succeed())
+	decoded := &Report{}
+	g.Expect(json.Unmarshal(buf.Bytes(), decoded)).To(gomega.Succeed())
+	g.Expect(decoded.Migrations[0].VMs).To(gomega.HaveLen(2))
+
+	buf.Reset()
+	g.Expect(report.Render(CSV, buf)).To(gomega.Succeed())
+	rows, err := csv.NewReader(buf).ReadAll()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(rows).To(gomega.HaveLen(3))
+	g.Expect(rows[0]).To(gomega.Equal(csvHeader))
+	g.Expect(rows[1][2]).To(gomega.Equal("vm-1"))
+	g.Expect(rows[1][6]).To(gomega.Equal(Failed))
+	g.Expect(rows[1][12]).To(gomega.Equal("3072"))
+
+	buf.Reset()
+	g.Expect(report.Render(HTML, buf)).To(gomega.Succeed())
+	html := buf.String()
+	g.Expect(html).To(gomega.HavePrefix("<!DOCTYPE html>"))
+	g.Expect(html).To(gomega.ContainSubstring("Migration report: ns/plan"))
+	g.Expect(html).To(gomega.ContainSubstring("disk transfer failed."))
+	g.Expect(html).ToNot(gomega.ContainSubstring("<script"))
+
+	g.Expect(report.Render("xml", buf)).ToNot(gomega.Succeed())
+}
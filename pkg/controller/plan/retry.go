@@ -0,0 +1,288 @@
+package plan
+
+import (
+	"context"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	"github.com/kubev2v/forklift/pkg/controller/plan/migrator"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Retry a failed VM.
+// The VM resumes from the first phase of the earliest pipeline step
+// that did not complete. The DataVolumes that completed the transfer
+// (or populated PVCs) are reused and adopted. Unless each disk has been
+// transferred, the VM resumes from the creation of the DataVolumes so the
+// others are recreated.
+// Returns false when the VM must be migrated from the beginning: warm
+// migrations (the precopy snapshot is removed on failure), rolled back
+// migrations and migrations that did not complete any step.
+func (r *Migration) retry(vm *plan.VMStatus, pipeline []*plan.Step) (retried plan.RetriedVM, resumed bool, err error) {
+	retried = plan.RetriedVM{Ref: vm.Ref}
+	if vm.Error != nil {
+		retried.FailedPhase = vm.Error.Phase
+		retried.Reasons = vm.Error.Reasons
+	}
+	if r.Plan.IsWarm() || migrator.RollingBack(vm) {
+		return
+	}
+	phases, err := r.migrator.Itinerary(vm.VM).List()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	stepOf := func(phase string) string {
+		return r.migrator.Step(&plan.VMStatus{VM: vm.VM, Phase: phase})
+	}
+	completed := func(name string) bool {
+		step, found := vm.FindStep(name)
+		return found && step.MarkedCompleted() && step.Error == nil
+	}
+	resume := -1
+	for i := range phases {
+		if !completed(stepOf(phases[i].Name)) {
+			resume = i
+			break
+		}
+	}
+	if resume <= 0 {
+		return
+	}
+	reused, err := r.reuseDisks(vm)
+	if err != nil {
+		return
+	}
+	retried.ReusedDisks = reused.count
+	intact := reused.intact
+	if !intact {
+		recreate := -1
+		for i := range phases {
+			if phases[i].Name == api.PhaseCreateDataVolumes {
+				recreate = i
+				break
+			}
+		}
+		if recreate < 0 {
+			return
+		}
+		if recreate < resume {
+			resume = recreate
+		}
+	}
+	// Preserve the steps completed before the resumed step.
+	resumedStep := stepOf(phases[resume].Name)
+	preserved := make(map[string]bool)
+	for _, phase := range phases[:resume] {
+		if name := stepOf(phase.Name); name != resumedStep {
+			preserved[name] = true
+		}
+	}
+	for i, step := range pipeline {
+		if !preserved[step.Name] {
+			continue
+		}
+		if current, found := vm.FindStep(step.Name); found {
+			pipeline[i] = current
+		}
+	}
+	vm.DeleteCondition(api.ConditionCanceled, api.ConditionFailed)
+	vm.MarkReset()
+	vm.Phase = phases[resume].Name
+	vm.Pipeline = pipeline
+	vm.Error = nil
+	retried.ResumedPhase = vm.Phase
+	resumed = true
+
+	return
+}
+
+// Disks reused by a retry.
+type reusedDisks struct {
+	// Number of transferred disks reused.
+	count int
+	// Each disk has been transferred.
+	intact bool
+}
+
+// Reuse the disks transferred by the failed migration.
+// The disks are transferred by DataVolumes or, when the builder
+// supports volume populators, by populated PVCs. The transferred
+// disks are adopted by the migration; the others are deleted. The
+// disks are intact only when each disk of the VM is transferred.
+func (r *Migration) reuseDisks(vm *plan.VMStatus) (reused reusedDisks, err error) {
+	tasks, err := r.builder.Tasks(vm.Ref)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	incomplete := 0
+	if r.builder.SupportsVolumePopulators() {
+		var pvcs []*core.PersistentVolumeClaim
+		pvcs, err = r.populatorPVCs(vm)
+		if err != nil {
+			return
+		}
+		for _, pvc := range pvcs {
+			if pvc.Status.Phase == core.ClaimBound {
+				err = r.adopt(pvc, false)
+				if err != nil {
+					return
+				}
+				reused.count++
+				continue
+			}
+			incomplete++
+			err = r.deleteIncomplete(vm, pvc)
+			if err != nil {
+				return
+			}
+		}
+	} else {
+		var dvs []ExtendedDataVolume
+		dvs, err = r.kubevirt.getDVs(vm)
+		if err != nil {
+			return
+		}
+		for _, dv := range dvs {
+			if dv.Status.Phase == cdi.Succeeded {
+				err = r.adoptDataVolume(dv.DataVolume)
+				if err != nil {
+					return
+				}
+				reused.count++
+				continue
+			}
+			incomplete++
+			err = r.deleteIncomplete(vm, dv.DataVolume)
+			if err != nil {
+				return
+			}
+		}
+	}
+	reused.intact = incomplete == 0 && reused.count >= len(tasks)
+
+	return
+}
+
+// Populator PVCs created for the VM by the plan.
+// The PVCs are labeled by the migration that created them
+// and annotated with the plan.
+func (r *Migration) populatorPVCs(vm *plan.VMStatus) (pvcs []*core.PersistentVolumeClaim, err error) {
+	list := &core.PersistentVolumeClaimList{}
+	err = r.Destination.Client.List(
+		context.TODO(),
+		list,
+		&client.ListOptions{
+			LabelSelector: k8slabels.SelectorFromSet(map[string]string{kVM: vm.ID}),
+			Namespace:     r.Plan.Spec.TargetNamespace,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range list.Items {
+		pvc := &list.Items[i]
+		if pvc.Annotations[kPlan] != string(r.Plan.UID) {
+			continue
+		}
+		if _, lun := pvc.Annotations["lun"]; lun {
+			continue
+		}
+		pvcs = append(pvcs, pvc)
+	}
+
+	return
+}
+
+// Adopt the DataVolume and its PVC.
+// The DataVolume is marked pre-populated so the transferred
+// disk is adopted rather than imported again.
+func (r *Migration) adoptDataVolume(dv *cdi.DataVolume) (err error) {
+	err = r.adopt(dv, true)
+	if err != nil {
+		return
+	}
+	pvc := &core.PersistentVolumeClaim{}
+	err = r.Destination.Client.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: dv.Namespace, Name: dv.Name},
+		pvc)
+	if err != nil {
+		if k8serr.IsNotFound(err) {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+		return
+	}
+	err = r.adopt(pvc, false)
+	return
+}
+
+// Adopt a transferred resource.
+// The resource is labeled with the migration so it is found
+// by the resumed phases.
+func (r *Migration) adopt(object client.Object, prePopulated bool) (err error) {
+	labels := object.GetLabels()
+	annotations := object.GetAnnotations()
+	if labels[kMigration] == string(r.Migration.UID) &&
+		(!prePopulated || annotations[planbase.AnnPrePopulated] == "true") {
+		return
+	}
+	patch := client.MergeFrom(object.DeepCopyObject().(client.Object))
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	labels[kMigration] = string(r.Migration.UID)
+	object.SetLabels(labels)
+	if prePopulated {
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[planbase.AnnPrePopulated] = "true"
+		object.SetAnnotations(annotations)
+	}
+	err = r.Destination.Client.Patch(context.TODO(), object, patch)
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Delete a resource that did not complete the transfer.
+func (r *Migration) deleteIncomplete(vm *plan.VMStatus, object client.Object) (err error) {
+	err = r.Destination.Client.Delete(context.TODO(), object)
+	if err != nil && !k8serr.IsNotFound(err) {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = nil
+	r.Log.Info(
+		"Incomplete disk deleted.",
+		"vm",
+		vm.String(),
+		"name",
+		object.GetName())
+
+	return
+}
+
+// Determine whether the VM is resumed by a retry.
+// The resumed VM does not run the initial (started) phase.
+func (r *Migration) resumed(vm *plan.VMStatus) bool {
+	snapshot := r.Plan.Status.Migration.ActiveSnapshot()
+	for _, retried := range snapshot.Retried {
+		if retried.Ref == vm.Ref && retried.ResumedPhase != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+//nolint:errcheck
+package plan
+
+import (
+	"context"
+
+	v1beta1 "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/plan/adapter"
+	planbase "github.com/kubev2v/forklift/pkg/controller/plan/adapter/base"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	cdi "kubevirt.io/containerized-data-importer-api/pkg/apis/core/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Builder transferring the VM disks.
+type retryBuilder struct {
+	adapter.Builder
+	disks      int
+	populators bool
+}
+
+func (r *retryBuilder) Tasks(vmRef ref.Ref) (tasks []*plan.Task, err error) {
+	for i := 0; i < r.disks; i++ {
+		tasks = append(tasks, &plan.Task{})
+	}
+	return
+}
+
+func (r *retryBuilder) SupportsVolumePopulators() bool {
+	return r.populators
+}
+
+var _ = ginkgo.Describe("retry", func() {
+	vm := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+	labels := map[string]string{
+		kMigration: "failed",
+		kPlan:      "plan",
+		kVM:        "vm-1",
+		kResource:  ResourceVMConfig,
+	}
+	dataVolume := func(name string, phase cdi.DataVolumePhase) *cdi.DataVolume {
+		return &cdi.DataVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+				Labels:    copyLabels(labels),
+			},
+			Status: cdi.DataVolumeStatus{Phase: phase},
+		}
+	}
+	claim := func(name string, phase v1.PersistentVolumeClaimPhase) *v1.PersistentVolumeClaim {
+		return &v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "test",
+				Labels:      copyLabels(labels),
+				Annotations: map[string]string{kPlan: "plan"},
+			},
+			Status: v1.PersistentVolumeClaimStatus{Phase: phase},
+		}
+	}
+
+	ginkgo.It("should not be intact when the DataVolumes are missing", func() {
+		migration := createRetryMigration(&retryBuilder{disks: 2})
+		reused, err := migration.reuseDisks(vm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reused.count).To(Equal(0))
+		Expect(reused.intact).To(BeFalse())
+	})
+
+	ginkgo.It("should adopt the completed DataVolumes", func() {
+		migration := createRetryMigration(
+			&retryBuilder{disks: 2},
+			dataVolume("disk-0", cdi.Succeeded),
+			dataVolume("disk-1", cdi.Succeeded),
+			claim("disk-0", v1.ClaimBound),
+			claim("disk-1", v1.ClaimBound))
+		reused, err := migration.reuseDisks(vm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reused.count).To(Equal(2))
+		Expect(reused.intact).To(BeTrue())
+		dv := &cdi.DataVolume{}
+		Expect(migration.Destination.Client.Get(
+			context.TODO(), client.ObjectKey{Namespace: "test", Name: "disk-0"}, dv)).To(Succeed())
+		Expect(dv.Labels[kMigration]).To(Equal("test"))
+		Expect(dv.Annotations[planbase.AnnPrePopulated]).To(Equal("true"))
+		pvc := &v1.PersistentVolumeClaim{}
+		Expect(migration.Destination.Client.Get(
+			context.TODO(), client.ObjectKey{Namespace: "test", Name: "disk-0"}, pvc)).To(Succeed())
+		Expect(pvc.Labels[kMigration]).To(Equal("test"))
+	})
+
+	ginkgo.It("should delete the incomplete DataVolumes on partial failure", func() {
+		migration := createRetryMigration(
+			&retryBuilder{disks: 2},
+			dataVolume("disk-0", cdi.Succeeded),
+			dataVolume("disk-1", cdi.Failed))
+		reused, err := migration.reuseDisks(vm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reused.count).To(Equal(1))
+		Expect(reused.intact).To(BeFalse())
+		err = migration.Destination.Client.Get(
+			context.TODO(), client.ObjectKey{Namespace: "test", Name: "disk-1"}, &cdi.DataVolume{})
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+	})
+
+	ginkgo.It("should not be intact when a disk has no DataVolume", func() {
+		migration := createRetryMigration(
+			&retryBuilder{disks: 2},
+			dataVolume("disk-0", cdi.Succeeded))
+		reused, err := migration.reuseDisks(vm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reused.count).To(Equal(1))
+		Expect(reused.intact).To(BeFalse())
+	})
+
+	ginkgo.It("should adopt the bound populator PVCs", func() {
+		migration := createRetryMigration(
+			&retryBuilder{disks: 2, populators: true},
+			claim("disk-0", v1.ClaimBound),
+			claim("disk-1", v1.ClaimBound))
+		reused, err := migration.reuseDisks(vm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reused.count).To(Equal(2))
+		Expect(reused.intact).To(BeTrue())
+		pvc := &v1.PersistentVolumeClaim{}
+		Expect(migration.Destination.Client.Get(
+			context.TODO(), client.ObjectKey{Namespace: "test", Name: "disk-1"}, pvc)).To(Succeed())
+		Expect(pvc.Labels[kMigration]).To(Equal("test"))
+	})
+
+	ginkgo.It("should delete the pending populator PVCs", func() {
+		other := claim("other", v1.ClaimPending)
+		other.Annotations[kPlan] = "other"
+		migration := createRetryMigration(
+			&retryBuilder{disks: 2, populators: true},
+			claim("disk-0", v1.ClaimBound),
+			claim("disk-1", v1.ClaimPending),
+			other)
+		reused, err := migration.reuseDisks(vm)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reused.count).To(Equal(1))
+		Expect(reused.intact).To(BeFalse())
+		err = migration.Destination.Client.Get(
+			context.TODO(), client.ObjectKey{Namespace: "test", Name: "disk-1"}, &v1.PersistentVolumeClaim{})
+		Expect(k8serr.IsNotFound(err)).To(BeTrue())
+		Expect(migration.Destination.Client.Get(
+			context.TODO(), client.ObjectKey{Namespace: "test", Name: "other"}, &v1.PersistentVolumeClaim{})).To(Succeed())
+	})
+
+	ginkgo.It("should only resume the retried VMs", func() {
+		migration := createRetryMigration(&retryBuilder{})
+		migration.Plan.Status.Migration.History = []plan.Snapshot{
+			{
+				Retried: []plan.RetriedVM{
+					{Ref: vm.Ref, ResumedPhase: v1beta1.PhaseCreateDataVolumes},
+					{Ref: ref.Ref{ID: "vm-2"}},
+				},
+			},
+		}
+		Expect(migration.resumed(vm)).To(BeTrue())
+		Expect(migration.resumed(&plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-2"}}})).To(BeFalse())
+		Expect(migration.resumed(&plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-3"}}})).To(BeFalse())
+	})
+})
+
+func copyLabels(labels map[string]string) (copied map[string]string) {
+	copied = make(map[string]string)
+	for k, v := range labels {
+		copied[k] = v
+	}
+	return
+}
+
+func createRetryMigration(builder adapter.Builder, objs ...runtime.Object) *Migration {
+	scheme := runtime.NewScheme()
+	_ = v1.AddToScheme(scheme)
+	_ = cdi.AddToScheme(scheme)
+	v1beta1.SchemeBuilder.AddToScheme(scheme)
+	client := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithRuntimeObjects(objs...).
+		Build()
+	ctx := &plancontext.Context{
+		Destination: plancontext.Destination{
+			Client: client,
+		},
+		Log:       KubeVirtLog,
+		Migration: createMigration(),
+		Plan: &v1beta1.Plan{
+			ObjectMeta: metav1.ObjectMeta{UID: "plan"},
+			Spec: v1beta1.PlanSpec{
+				Type:            "cold",
+				TargetNamespace: "test",
+			},
+		},
+		Client: client,
+	}
+	return &Migration{
+		Context:  ctx,
+		builder:  builder,
+		kubevirt: KubeVirt{Context: ctx},
+	}
+}
@@ -0,0 +1,169 @@
+package schedule
+
+import (
+	"strings"
+	"time"
+	// Time zones may not be available in the controller image.
+	_ "time/tzdata"
+
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/robfig/cron"
+)
+
+// Maximum number of transitions (window/blackout boundaries)
+// evaluated when searching for the next opening.
+const MaxTransitions = 1000
+
+// Execution window.
+type window struct {
+	// Start of the window.
+	start cron.Schedule
+	// Duration of the window.
+	duration time.Duration
+}
+
+// Calendar built from a plan schedule.
+// Determines when the migration of VMs may be started.
+// A nil (or empty) schedule is always open.
+type Calendar struct {
+	// Location used to evaluate windows.
+	location *time.Location
+	// Execution windows.
+	windows []window
+	// Blackout periods.
+	blackouts []planapi.Blackout
+}
+
+// Build a calendar for the schedule.
+func New(schedule *planapi.Schedule) (calendar *Calendar, err error) {
+	calendar = &Calendar{location: time.UTC}
+	if schedule == nil {
+		return
+	}
+	if schedule.TimeZone != "" {
+		calendar.location, err = time.LoadLocation(schedule.TimeZone)
+		if err != nil {
+			err = liberr.Wrap(err, "timeZone", schedule.TimeZone)
+			return
+		}
+	}
+	for _, w := range schedule.Windows {
+		if strings.HasPrefix(strings.TrimSpace(w.Start), "@every") {
+			err = liberr.New("window start must not be relative.", "start", w.Start)
+			return
+		}
+		var start cron.Schedule
+		start, err = cron.ParseStandard(w.Start)
+		if err != nil {
+			err = liberr.Wrap(err, "start", w.Start)
+			return
+		}
+		if w.Duration.Duration <= 0 {
+			err = liberr.New("window duration must be positive.", "start", w.Start)
+			return
+		}
+		calendar.windows = append(
+			calendar.windows,
+			window{
+				start:    start,
+				duration: w.Duration.Duration,
+			})
+	}
+	for _, b := range schedule.Blackouts {
+		if !b.End.After(b.Start.Time) {
+			err = liberr.New("blackout must end after it starts.", "start", b.Start.String())
+			return
+		}
+		calendar.blackouts = append(calendar.blackouts, b)
+	}
+
+	return
+}
+
+// The migration of VMs may be started.
+func (r *Calendar) Open(now time.Time) bool {
+	return r.InWindow(now) && !r.InBlackout(now)
+}
+
+// Within an execution window.
+// Always true when no windows are defined.
+func (r *Calendar) InWindow(now time.Time) bool {
+	if len(r.windows) == 0 {
+		return true
+	}
+	now = now.In(r.location)
+	for _, w := range r.windows {
+		// The first start after (now - duration) that is
+		// not after now is the start of the open window.
+		start := w.start.Next(now.Add(-w.duration))
+		if !start.IsZero() && !start.After(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Within a blackout.
+func (r *Calendar) InBlackout(now time.Time) bool {
+	_, found := r.blackout(now)
+	return found
+}
+
+// Find the next time the calendar is open.
+// Returns now when already open and found=false
+// when the calendar will not open again.
+func (r *Calendar) NextOpen(now time.Time) (next time.Time, found bool) {
+	next = now
+	for i := 0; i < MaxTransitions; i++ {
+		if b, inBlackout := r.blackout(next); inBlackout {
+			next = b.End.Time
+			continue
+		}
+		if !r.InWindow(next) {
+			start, hasStart := r.nextWindowStart(next)
+			if !hasStart {
+				return
+			}
+			next = start
+			continue
+		}
+		found = true
+		return
+	}
+
+	return
+}
+
+// Find the blackout (ending last) that contains the time.
+func (r *Calendar) blackout(now time.Time) (blackout planapi.Blackout, found bool) {
+	for _, b := range r.blackouts {
+		if now.Before(b.Start.Time) || !now.Before(b.End.Time) {
+			continue
+		}
+		if !found || b.End.After(blackout.End.Time) {
+			blackout = b
+			found = true
+		}
+	}
+
+	return
+}
+
+// Find the earliest window start after the time.
+func (r *Calendar) nextWindowStart(now time.Time) (next time.Time, found bool) {
+	now = now.In(r.location)
+	for _, w := range r.windows {
+		start := w.start.Next(now)
+		if start.IsZero() {
+			continue
+		}
+		if !found || start.Before(next) {
+			next = start
+			found = true
+		}
+	}
+
+	return
+}
@@ -0,0 +1,125 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestCalendarAlwaysOpen(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	calendar, err := New(nil)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	now := at("2025-03-04T10:00:00Z")
+	g.Expect(calendar.Open(now)).To(gomega.BeTrue())
+	next, found := calendar.NextOpen(now)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(next).To(gomega.Equal(now))
+}
+
+func TestCalendarWindow(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	// Weekdays, 22:00 - 04:00 in Prague (CET = UTC+1 in March).
+	calendar, err := New(&planapi.Schedule{
+		TimeZone: "Europe/Prague",
+		Windows: []planapi.Window{
+			{
+				Start:    "0 22 * * 1-5",
+				Duration: meta.Duration{Duration: 6 * time.Hour},
+			},
+		},
+	})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	// Tuesday.
+	g.Expect(calendar.Open(at("2025-03-04T10:00:00Z"))).To(gomega.BeFalse())
+	g.Expect(calendar.Open(at("2025-03-04T21:00:00Z"))).To(gomega.BeTrue())
+	g.Expect(calendar.Open(at("2025-03-05T02:59:59Z"))).To(gomega.BeTrue())
+	g.Expect(calendar.Open(at("2025-03-05T03:00:00Z"))).To(gomega.BeFalse())
+
+	next, found := calendar.NextOpen(at("2025-03-04T10:00:00Z"))
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(next.Equal(at("2025-03-04T21:00:00Z"))).To(gomega.BeTrue())
+
+	// Saturday: next window opens Monday.
+	next, found = calendar.NextOpen(at("2025-03-08T12:00:00Z"))
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(next.Equal(at("2025-03-10T21:00:00Z"))).To(gomega.BeTrue())
+}
+
+func TestCalendarBlackout(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	calendar, err := New(&planapi.Schedule{
+		Windows: []planapi.Window{
+			{
+				Start:    "0 20 * * *",
+				Duration: meta.Duration{Duration: 4 * time.Hour},
+			},
+		},
+		Blackouts: []planapi.Blackout{
+			{
+				Start:  meta.NewTime(at("2025-03-04T19:00:00Z")),
+				End:    meta.NewTime(at("2025-03-04T21:30:00Z")),
+				Reason: "Quarter close.",
+			},
+		},
+	})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	now := at("2025-03-04T20:30:00Z")
+	g.Expect(calendar.InWindow(now)).To(gomega.BeTrue())
+	g.Expect(calendar.InBlackout(now)).To(gomega.BeTrue())
+	g.Expect(calendar.Open(now)).To(gomega.BeFalse())
+
+	// Opens when the blackout ends within the window.
+	next, found := calendar.NextOpen(now)
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(next.Equal(at("2025-03-04T21:30:00Z"))).To(gomega.BeTrue())
+
+	// Blackout only.
+	calendar, err = New(&planapi.Schedule{
+		Blackouts: []planapi.Blackout{
+			{
+				Start: meta.NewTime(at("2025-03-04T00:00:00Z")),
+				End:   meta.NewTime(at("2025-03-05T00:00:00Z")),
+			},
+		},
+	})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(calendar.Open(at("2025-03-03T23:59:59Z"))).To(gomega.BeTrue())
+	g.Expect(calendar.Open(at("2025-03-04T12:00:00Z"))).To(gomega.BeFalse())
+	g.Expect(calendar.Open(at("2025-03-05T00:00:00Z"))).To(gomega.BeTrue())
+}
+
+func TestCalendarNotValid(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	schedules := []*planapi.Schedule{
+		{TimeZone: "Mars/Olympus"},
+		{Windows: []planapi.Window{{Start: "not cron", Duration: meta.Duration{Duration: time.Hour}}}},
+		{Windows: []planapi.Window{{Start: "@every 1h", Duration: meta.Duration{Duration: time.Hour}}}},
+		{Windows: []planapi.Window{{Start: "0 22 * * *"}}},
+		{Blackouts: []planapi.Blackout{{
+			Start: meta.NewTime(at("2025-03-05T00:00:00Z")),
+			End:   meta.NewTime(at("2025-03-04T00:00:00Z")),
+		}}},
+	}
+	for _, schedule := range schedules {
+		_, err := New(schedule)
+		g.Expect(err).To(gomega.HaveOccurred())
+	}
+}
@@ -4,11 +4,14 @@ import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/schedule"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/hyperv"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/ova"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/ovirt"
 	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/plan/wave"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	"github.com/kubev2v/forklift/pkg/settings"
 )
@@ -22,6 +25,36 @@ type Scheduler interface {
 
 // Scheduler factory.
 func New(ctx *plancontext.Context) (scheduler Scheduler, err error) {
+	scheduler, err = newProvider(ctx)
+	if err != nil {
+		return
+	}
+	waves, err := wave.New(ctx.Plan)
+	if err != nil {
+		return
+	}
+	if waves.Enabled() {
+		scheduler = &WaveScheduler{
+			Context: ctx,
+			New:     newProvider,
+			Waves:   waves,
+		}
+	}
+	calendar, err := schedule.New(ctx.Plan.Spec.Schedule)
+	if err != nil {
+		return
+	}
+	scheduler = &WindowScheduler{
+		Context:   ctx,
+		Scheduler: scheduler,
+		Calendar:  calendar,
+	}
+
+	return
+}
+
+// Provider scheduler factory.
+func newProvider(ctx *plancontext.Context) (scheduler Scheduler, err error) {
 	switch ctx.Source.Provider.Type() {
 	case api.VSphere:
 		scheduler = &vsphere.Scheduler{
@@ -48,6 +81,11 @@ func New(ctx *plancontext.Context) (scheduler Scheduler, err error) {
 			Context:     ctx,
 			MaxInFlight: settings.Settings.MaxInFlight,
 		}
+	case api.HyperV:
+		scheduler = &hyperv.Scheduler{
+			Context:     ctx,
+			MaxInFlight: settings.Settings.MaxInFlight,
+		}
 	default:
 		err = liberr.New("provider not supported.")
 	}
@@ -0,0 +1,141 @@
+package hyperv
+
+import (
+	"context"
+	"sync"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/order"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/throttle"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/hyperv"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Package level mutex to ensure that
+// multiple concurrent reconciles don't
+// attempt to schedule VMs into the same
+// slots.
+var mutex sync.Mutex
+
+// Conditions.
+const (
+	Canceled = "Canceled"
+	Paused   = "Paused"
+)
+
+// Scheduler for migrations from Hyper-V.
+type Scheduler struct {
+	*plancontext.Context
+	// Maximum number of VMs that can be
+	// migrated at once per provider.
+	MaxInFlight int
+}
+
+func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	planList := &api.PlanList{}
+	err = r.List(context.TODO(), planList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	inFlight := 0
+	for _, p := range planList.Items {
+		// ignore plans that aren't using the same source provider
+		if p.Spec.Provider.Source != r.Plan.Spec.Provider.Source {
+			continue
+		}
+
+		// skip plans that aren't being executed
+		snapshot := p.Status.Migration.ActiveSnapshot()
+		if !snapshot.HasCondition("Executing") {
+			continue
+		}
+
+		for _, vmStatus := range p.Status.Migration.VMs {
+			if throttle.InFlight(vmStatus) {
+				inFlight++
+			}
+		}
+	}
+
+	if inFlight >= r.MaxInFlight {
+		return
+	}
+
+	limiter, err := throttle.New(r.Context, r)
+	if err != nil {
+		return
+	}
+
+	ordering := order.New(r.Context, r, limiter)
+	vms, err := ordering.Sort(r.Plan.Status.Migration.VMs)
+	if err != nil {
+		return
+	}
+
+	for _, vmStatus := range vms {
+		if vmStatus.HasAnyCondition(Canceled, Paused) {
+			continue
+		}
+		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
+			var admitted bool
+			admitted, err = limiter.Admit(vmStatus.Ref)
+			if err != nil {
+				return
+			}
+			if !admitted {
+				continue
+			}
+			var yield bool
+			yield, err = ordering.Yield(vmStatus)
+			if err != nil {
+				return
+			}
+			if yield {
+				continue
+			}
+			vm = vmStatus
+			hasNext = true
+			return
+		}
+	}
+
+	return
+}
+
+// Find the source storage of each disk of the VM.
+func (r *Scheduler) DiskStorage(vmRef ref.Ref) (storage []ref.Ref, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		storage = append(storage, ref.Ref{ID: disk.ID})
+	}
+
+	return
+}
+
+// Total size (bytes) of the disks of the VM.
+func (r *Scheduler) DiskSize(vmRef ref.Ref) (size int64, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		size += disk.Capacity
+	}
+
+	return
+}
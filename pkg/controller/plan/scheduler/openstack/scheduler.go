@@ -6,7 +6,11 @@ import (
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/order"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/throttle"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 )
 
@@ -16,7 +20,11 @@ import (
 // slots.
 var mutex sync.Mutex
 
-const Canceled = "Canceled"
+// Conditions.
+const (
+	Canceled = "Canceled"
+	Paused   = "Paused"
+)
 
 // Scheduler for migrations from OpenStack.
 type Scheduler struct {
@@ -41,11 +49,38 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 		return
 	}
 
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
-		if vmStatus.HasCondition(Canceled) {
+	limiter, err := throttle.New(r.Context, r)
+	if err != nil {
+		return
+	}
+
+	ordering := order.New(r.Context, r, limiter)
+	vms, err := ordering.Sort(r.Plan.Status.Migration.VMs)
+	if err != nil {
+		return
+	}
+
+	for _, vmStatus := range vms {
+		if vmStatus.HasAnyCondition(Canceled, Paused) {
 			continue
 		}
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
+			var admitted bool
+			admitted, err = limiter.Admit(vmStatus.Ref)
+			if err != nil {
+				return
+			}
+			if !admitted {
+				continue
+			}
+			var yield bool
+			yield, err = ordering.Yield(vmStatus)
+			if err != nil {
+				return
+			}
+			if yield {
+				continue
+			}
 			vm = vmStatus
 			hasNext = true
 			return
@@ -70,10 +105,54 @@ func (r *Scheduler) calcInFlight(planList *api.PlanList) int {
 		}
 
 		for _, vmStatus := range p.Status.Migration.VMs {
-			if vmStatus.Running() {
+			if throttle.InFlight(vmStatus) {
 				inFlight++
 			}
 		}
 	}
 	return inFlight
 }
+
+// Find the source storage of each disk of the VM.
+func (r *Scheduler) DiskStorage(vmRef ref.Ref) (storage []ref.Ref, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, volume := range vm.Volumes {
+		for _, volumeType := range vm.VolumeTypes {
+			if volumeType.Name == volume.VolumeType {
+				storage = append(storage, ref.Ref{ID: volumeType.ID})
+				break
+			}
+		}
+	}
+	// Image based VMs are transferred from glance.
+	if vm.ImageID != "" {
+		storage = append(storage, ref.Ref{Name: api.GlanceSource})
+	}
+
+	return
+}
+
+// Total size (bytes) of the disks of the VM.
+func (r *Scheduler) DiskSize(vmRef ref.Ref) (size int64, err error) {
+	vm := &model.Workload{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, volume := range vm.Volumes {
+		// The volume size is in GiB.
+		size += int64(volume.Size) << 30
+	}
+	// Image based VMs are transferred from glance.
+	if vm.ImageID != "" {
+		size += vm.Image.VirtualSize
+	}
+
+	return
+}
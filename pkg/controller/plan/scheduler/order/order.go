@@ -0,0 +1,190 @@
+package order
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/schedule"
+	"github.com/kubev2v/forklift/pkg/controller/plan/wave"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Conditions.
+const (
+	Canceled = "Canceled"
+	Paused   = "Paused"
+)
+
+// Provider specific lookup of the
+// size of the disks of a VM.
+type Sizer interface {
+	// Total size (bytes) of the disks of the VM.
+	DiskSize(vmRef ref.Ref) (size int64, err error)
+}
+
+// Provider specific determination of whether
+// a plan can start a pending VM now.
+type Admitter interface {
+	// The plan can start the VM now.
+	Startable(p *api.Plan, vm *plan.VMStatus) (startable bool, err error)
+}
+
+// Order.
+// Orders the VMs of the plan by the plan ordering policy
+// and determines whether a VM must yield to a pending VM
+// with a higher priority in another plan sharing the
+// source provider.
+type Order struct {
+	*plancontext.Context
+	// Provider specific disk sizer.
+	Sizer Sizer
+	// Provider specific admitter.
+	Admitter Admitter
+	// Plans listed on the first yield.
+	plans []api.Plan
+}
+
+// Build the order.
+func New(ctx *plancontext.Context, sizer Sizer, admitter Admitter) *Order {
+	return &Order{
+		Context:  ctx,
+		Sizer:    sizer,
+		Admitter: admitter,
+	}
+}
+
+// Sort the VMs by the plan ordering policy.
+// The sort is stable so VMs that are equal keep the
+// order of the VM list. The VMs are not modified.
+func (r *Order) Sort(vms []*plan.VMStatus) (sorted []*plan.VMStatus, err error) {
+	sorted = make([]*plan.VMStatus, len(vms))
+	copy(sorted, vms)
+	switch r.Plan.Spec.Ordering {
+	case plan.OrderSource:
+	case plan.OrderSmallestFirst, plan.OrderLargestFirst:
+		size := make(map[*plan.VMStatus]int64)
+		for _, vm := range sorted {
+			if vm.MarkedStarted() || vm.MarkedCompleted() {
+				continue
+			}
+			size[vm], err = r.Sizer.DiskSize(vm.Ref)
+			if err != nil {
+				err = liberr.Wrap(err, "vm", vm.String())
+				return
+			}
+		}
+		largest := r.Plan.Spec.Ordering == plan.OrderLargestFirst
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if largest {
+				return size[sorted[i]] > size[sorted[j]]
+			}
+			return size[sorted[i]] < size[sorted[j]]
+		})
+	default:
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return Priority(r.Plan, sorted[i]) > Priority(r.Plan, sorted[j])
+		})
+	}
+
+	return
+}
+
+// Determine whether the VM must yield to a pending VM with a
+// higher priority in another executing plan sharing the source
+// provider. Only VMs that their plan can start now are considered:
+// the VMs of plans outside their schedule, in waves that are not
+// running or held back by the limits of their plan do not hold
+// back the other plans.
+func (r *Order) Yield(vm *plan.VMStatus) (yield bool, err error) {
+	priority := Priority(r.Plan, vm)
+	if r.plans == nil {
+		planList := &api.PlanList{}
+		err = r.List(context.TODO(), planList)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		r.plans = planList.Items
+	}
+	now := time.Now()
+	for i := range r.plans {
+		p := &r.plans[i]
+		if p.Name == r.Plan.Name && p.Namespace == r.Plan.Namespace {
+			continue
+		}
+		if p.Spec.Provider.Source != r.Plan.Spec.Provider.Source {
+			continue
+		}
+		if p.Spec.Archived {
+			continue
+		}
+		snapshot := p.Status.Migration.ActiveSnapshot()
+		if !snapshot.HasCondition("Executing") {
+			continue
+		}
+		if !r.open(p, now) {
+			continue
+		}
+		waves, wErr := wave.New(p)
+		if wErr != nil {
+			continue
+		}
+		for _, other := range p.Status.Migration.VMs {
+			if other.MarkedStarted() || other.MarkedCompleted() {
+				continue
+			}
+			if other.HasAnyCondition(Canceled, Paused) {
+				continue
+			}
+			if waves.Enabled() && !waves.Schedulable(other) {
+				continue
+			}
+			if Priority(p, other) <= priority {
+				continue
+			}
+			var startable bool
+			startable, err = r.Admitter.Startable(p, other)
+			if err != nil {
+				return
+			}
+			if startable {
+				r.Log.V(1).Info(
+					"VM yields to a higher priority VM.",
+					"vm",
+					vm.String(),
+					"plan",
+					p.Namespace+"/"+p.Name,
+					"other",
+					other.String())
+				yield = true
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// The schedule of the plan is open.
+func (r *Order) open(p *api.Plan, now time.Time) bool {
+	calendar, err := schedule.New(p.Spec.Schedule)
+	if err != nil {
+		return false
+	}
+	return calendar.Open(now)
+}
+
+// Priority of the VM.
+// The priority listed on the plan is used so that it may be
+// changed while the migration is running.
+func Priority(p *api.Plan, vm *plan.VMStatus) int {
+	if listed, found := p.Spec.FindVM(vm.Ref); found {
+		return listed.Priority
+	}
+	return vm.Priority
+}
@@ -0,0 +1,145 @@
+package order
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Disk sizer backed by a map of VM ID to size.
+type sizer map[string]int64
+
+func (r sizer) DiskSize(vmRef ref.Ref) (size int64, err error) {
+	size = r[vmRef.ID]
+	return
+}
+
+// Admitter backed by a set of the IDs
+// of the VMs that cannot be started.
+type blocked map[string]bool
+
+func (r blocked) Startable(p *api.Plan, vm *plan.VMStatus) (startable bool, err error) {
+	startable = !r[vm.ID]
+	return
+}
+
+func TestSort(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	p := &api.Plan{}
+	p.Spec.VMs = []plan.VM{
+		{Ref: ref.Ref{ID: "vm-1"}},
+		{Ref: ref.Ref{ID: "vm-2"}, Priority: 10},
+		{Ref: ref.Ref{ID: "vm-3"}},
+		{Ref: ref.Ref{ID: "vm-4"}, Priority: 5},
+	}
+	vms := []*plan.VMStatus{}
+	for _, vm := range p.Spec.VMs {
+		vms = append(vms, &plan.VMStatus{VM: vm})
+	}
+	ids := func(sorted []*plan.VMStatus) (ids []string) {
+		for _, vm := range sorted {
+			ids = append(ids, vm.ID)
+		}
+		return
+	}
+	order := New(
+		&plancontext.Context{Plan: p},
+		sizer{
+			"vm-1": 30,
+			"vm-2": 10,
+			"vm-3": 40,
+			"vm-4": 20,
+		},
+		blocked{})
+
+	// Priority by default.
+	sorted, err := order.Sort(vms)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(ids(sorted)).To(gomega.Equal([]string{"vm-2", "vm-4", "vm-1", "vm-3"}))
+	// The listed priority supersedes the status.
+	p.Spec.VMs[0].Priority = 7
+	sorted, _ = order.Sort(vms)
+	g.Expect(ids(sorted)).To(gomega.Equal([]string{"vm-2", "vm-1", "vm-4", "vm-3"}))
+	p.Spec.Ordering = plan.OrderSource
+	sorted, _ = order.Sort(vms)
+	g.Expect(ids(sorted)).To(gomega.Equal([]string{"vm-1", "vm-2", "vm-3", "vm-4"}))
+	p.Spec.Ordering = plan.OrderSmallestFirst
+	sorted, _ = order.Sort(vms)
+	g.Expect(ids(sorted)).To(gomega.Equal([]string{"vm-2", "vm-4", "vm-1", "vm-3"}))
+	p.Spec.Ordering = plan.OrderLargestFirst
+	sorted, _ = order.Sort(vms)
+	g.Expect(ids(sorted)).To(gomega.Equal([]string{"vm-3", "vm-1", "vm-4", "vm-2"}))
+	// The VMs are not modified.
+	g.Expect(ids(vms)).To(gomega.Equal([]string{"vm-1", "vm-2", "vm-3", "vm-4"}))
+}
+
+func TestYield(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	source := core.ObjectReference{Namespace: "test", Name: "vsphere"}
+	executing := plan.Snapshot{}
+	executing.SetCondition(libcnd.Condition{Type: "Executing", Status: libcnd.True})
+	newPlan := func(name string, priority int) *api.Plan {
+		p := &api.Plan{ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: name}}
+		p.Spec.Provider.Source = source
+		vm := plan.VM{Ref: ref.Ref{ID: name + "-vm"}, Priority: priority}
+		p.Spec.VMs = []plan.VM{vm}
+		p.Status.Migration.VMs = []*plan.VMStatus{{VM: vm}}
+		p.Status.Migration.History = []plan.Snapshot{executing}
+		return p
+	}
+	this := newPlan("this", 5)
+	higher := newPlan("higher", 10)
+	other := newPlan("other", 10)
+	other.Spec.Provider.Source = core.ObjectReference{Namespace: "test", Name: "ovirt"}
+
+	held := blocked{}
+	scheme := runtime.NewScheme()
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	newOrder := func(objects ...runtime.Object) *Order {
+		return New(
+			&plancontext.Context{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build(),
+				Plan:   this,
+				Log:    logging.WithName("test"),
+			},
+			sizer{},
+			held)
+	}
+	vm := this.Status.Migration.VMs[0]
+
+	// A higher priority VM of another provider is ignored.
+	yield, err := newOrder(this, other).Yield(vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(yield).To(gomega.BeFalse())
+	// A higher priority VM sharing the provider is started first.
+	yield, err = newOrder(this, higher).Yield(vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(yield).To(gomega.BeTrue())
+	// Unless its plan cannot start it.
+	held["higher-vm"] = true
+	yield, err = newOrder(this, higher).Yield(vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(yield).To(gomega.BeFalse())
+	delete(held, "higher-vm")
+	// Or it is paused.
+	higher.Status.Migration.VMs[0].SetCondition(libcnd.Condition{Type: Paused, Status: libcnd.True})
+	yield, _ = newOrder(this, higher).Yield(vm)
+	g.Expect(yield).To(gomega.BeFalse())
+	// Or already started.
+	higher.Status.Migration.VMs[0].DeleteCondition(Paused)
+	higher.Status.Migration.VMs[0].MarkStarted()
+	yield, _ = newOrder(this, higher).Yield(vm)
+	g.Expect(yield).To(gomega.BeFalse())
+}
@@ -6,7 +6,11 @@ import (
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/order"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/throttle"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 )
 
@@ -16,7 +20,11 @@ import (
 // slots.
 var mutex sync.Mutex
 
-const Canceled = "Canceled"
+// Conditions.
+const (
+	Canceled = "Canceled"
+	Paused   = "Paused"
+)
 
 // Scheduler for migrations from OVA.
 type Scheduler struct {
@@ -51,7 +59,7 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 		}
 
 		for _, vmStatus := range p.Status.Migration.VMs {
-			if vmStatus.Running() {
+			if throttle.InFlight(vmStatus) {
 				inFlight++
 			}
 		}
@@ -61,11 +69,38 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 		return
 	}
 
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
-		if vmStatus.HasCondition(Canceled) {
+	limiter, err := throttle.New(r.Context, r)
+	if err != nil {
+		return
+	}
+
+	ordering := order.New(r.Context, r, limiter)
+	vms, err := ordering.Sort(r.Plan.Status.Migration.VMs)
+	if err != nil {
+		return
+	}
+
+	for _, vmStatus := range vms {
+		if vmStatus.HasAnyCondition(Canceled, Paused) {
 			continue
 		}
 		if !vmStatus.MarkedStarted() && !vmStatus.MarkedCompleted() {
+			var admitted bool
+			admitted, err = limiter.Admit(vmStatus.Ref)
+			if err != nil {
+				return
+			}
+			if !admitted {
+				continue
+			}
+			var yield bool
+			yield, err = ordering.Yield(vmStatus)
+			if err != nil {
+				return
+			}
+			if yield {
+				continue
+			}
 			vm = vmStatus
 			hasNext = true
 			return
@@ -74,3 +109,39 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 
 	return
 }
+
+// Find the source storage of each disk of the VM.
+func (r *Scheduler) DiskStorage(vmRef ref.Ref) (storage []ref.Ref, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		storage = append(storage, ref.Ref{ID: disk.ID})
+	}
+
+	return
+}
+
+// Total size (bytes) of the disks of the VM.
+func (r *Scheduler) DiskSize(vmRef ref.Ref) (size int64, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		var bytes int64
+		bytes, err = disk.Bytes()
+		if err != nil {
+			err = liberr.Wrap(err, "vm", vmRef.String())
+			return
+		}
+		size += bytes
+	}
+
+	return
+}
@@ -0,0 +1,463 @@
+package throttle
+
+import (
+	"context"
+	"errors"
+	"path"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Phases in which the disks have already been
+// transferred and no longer count against the limits.
+const (
+	CopyingPaused            = "CopyingPaused"
+	CreateGuestConversionPod = "CreateGuestConversionPod"
+	ConvertGuest             = "ConvertGuest"
+	CreateVM                 = "CreateVM"
+	PostHook                 = "PostHook"
+	Completed                = "Completed"
+)
+
+// Conditions.
+const (
+	Canceled = "Canceled"
+	Paused   = "Paused"
+)
+
+// Provider specific lookup of the
+// storage used by the disks of a VM.
+type StorageFinder interface {
+	// Find the source storage of each disk of the VM.
+	// The storage ref must have the ID or Name set.
+	DiskStorage(vmRef ref.Ref) (storage []ref.Ref, err error)
+}
+
+// Disk usage of a VM.
+type Usage struct {
+	// Number of disks per source storage.
+	Source map[string]int
+	// Number of disks per destination storage class.
+	Destination map[string]int
+}
+
+// Resolved storage pair.
+type pair struct {
+	// Resolved source storage.
+	source ref.Ref
+	// Destination storage class.
+	destination string
+	// Limits.
+	limits *api.StorageLimits
+}
+
+// Throttle.
+// Caps the number of concurrent disk transfers per source
+// storage and per destination storage class, based on the limits
+// configured on the plan storage map pairs. The disks of running
+// VMs are counted across all plans sharing the source provider.
+type Throttle struct {
+	*plancontext.Context
+	// Provider specific storage finder.
+	Finder StorageFinder
+	// Limits per source storage key.
+	sourceLimit map[string]int
+	// Limits per destination storage class.
+	destinationLimit map[string]int
+	// Disks in flight per source storage key.
+	sourceInFlight map[string]int
+	// Disks in flight per destination storage class.
+	destinationInFlight map[string]int
+	// Resolved pairs by storage map (namespace/name).
+	pairs map[string][]pair
+	// The in-flight disks have been counted.
+	counted bool
+}
+
+// Build the throttle.
+// The in-flight disks are only counted when
+// limits are configured on the storage map.
+func New(ctx *plancontext.Context, finder StorageFinder) (throttle *Throttle, err error) {
+	throttle = &Throttle{
+		Context:             ctx,
+		Finder:              finder,
+		sourceInFlight:      make(map[string]int),
+		destinationInFlight: make(map[string]int),
+		pairs:               make(map[string][]pair),
+	}
+	err = throttle.buildLimits()
+	if err != nil {
+		return
+	}
+	if !throttle.Enabled() {
+		return
+	}
+	err = throttle.buildInFlight()
+	if err != nil {
+		return
+	}
+
+	throttle.Log.V(1).Info(
+		"Throttle built.",
+		"source",
+		throttle.sourceInFlight,
+		"destination",
+		throttle.destinationInFlight)
+
+	return
+}
+
+// Limits are configured.
+func (r *Throttle) Enabled() bool {
+	return len(r.sourceLimit) > 0 || len(r.destinationLimit) > 0
+}
+
+// Determine whether the migration of the VM can be started
+// without exceeding the limits. A VM with more disks than a
+// limit is admitted when no other disks are in flight
+// on the same storage.
+func (r *Throttle) Admit(vmRef ref.Ref) (admitted bool, err error) {
+	if !r.Enabled() {
+		admitted = true
+		return
+	}
+	admitted, err = r.admit(vmRef, r.Map.Storage, r.sourceLimit, r.destinationLimit)
+	return
+}
+
+// Determine whether the pending VM of another plan sharing
+// the source provider can be started without exceeding the
+// limits configured on its own storage map. The destination
+// storage class limits are only applied to plans sharing the
+// destination provider since the disks are only counted for
+// this destination. A VM not found in the inventory cannot
+// be started.
+func (r *Throttle) Startable(p *api.Plan, vm *plan.VMStatus) (startable bool, err error) {
+	mp, err := r.storageMap(p)
+	if err != nil {
+		return
+	}
+	sourceLimit, destinationLimit, err := r.limits(mp)
+	if err != nil {
+		return
+	}
+	if p.Spec.Provider.Destination != r.Plan.Spec.Provider.Destination {
+		destinationLimit = make(map[string]int)
+	}
+	if len(sourceLimit) == 0 && len(destinationLimit) == 0 {
+		startable = true
+		return
+	}
+	if !r.counted {
+		err = r.buildInFlight()
+		if err != nil {
+			return
+		}
+	}
+	startable, err = r.admit(vm.Ref, mp, sourceLimit, destinationLimit)
+	if errors.As(err, &web.NotFoundError{}) || errors.As(err, &web.RefNotUniqueError{}) {
+		err = nil
+	}
+
+	return
+}
+
+// Determine whether the VM fits within the limits.
+func (r *Throttle) admit(
+	vmRef ref.Ref,
+	mp *api.StorageMap,
+	sourceLimit map[string]int,
+	destinationLimit map[string]int) (admitted bool, err error) {
+	usage, err := r.usage(vmRef, mp)
+	if err != nil {
+		return
+	}
+	for key, disks := range usage.Source {
+		if !r.fits(sourceLimit[key], r.sourceInFlight[key], disks) {
+			r.Log.V(1).Info(
+				"Source storage limit reached.",
+				"vm",
+				vmRef.String(),
+				"storage",
+				key)
+			return
+		}
+	}
+	for sc, disks := range usage.Destination {
+		if !r.fits(destinationLimit[sc], r.destinationInFlight[sc], disks) {
+			r.Log.V(1).Info(
+				"Destination storage class limit reached.",
+				"vm",
+				vmRef.String(),
+				"storageClass",
+				sc)
+			return
+		}
+	}
+	admitted = true
+	return
+}
+
+// Disks fit within the limit.
+func (r *Throttle) fits(limit, inFlight, disks int) bool {
+	if limit <= 0 {
+		return true
+	}
+	return inFlight+disks <= limit || inFlight == 0
+}
+
+// Build the limits from the plan storage map.
+func (r *Throttle) buildLimits() (err error) {
+	r.sourceLimit, r.destinationLimit, err = r.limits(r.Map.Storage)
+	return
+}
+
+// Limits configured on the storage map pairs.
+// When several pairs share a destination storage
+// class, the lowest limit is used.
+func (r *Throttle) limits(mp *api.StorageMap) (sourceLimit, destinationLimit map[string]int, err error) {
+	sourceLimit = make(map[string]int)
+	destinationLimit = make(map[string]int)
+	pairs, err := r.resolve(mp)
+	if err != nil {
+		return
+	}
+	for _, p := range pairs {
+		if p.limits == nil {
+			continue
+		}
+		if n := p.limits.MaxSourceInFlight; n > 0 {
+			sourceLimit[key(p.source)] = n
+		}
+		if n := p.limits.MaxDestinationInFlight; n > 0 {
+			if limit, found := destinationLimit[p.destination]; !found || n < limit {
+				destinationLimit[p.destination] = n
+			}
+		}
+	}
+
+	return
+}
+
+// Build the number of disks in flight for each storage
+// across all plans that share the source provider.
+func (r *Throttle) buildInFlight() (err error) {
+	// Since we modify the plan VMStatuses in memory,
+	// we need to use the plan from the context rather
+	// than from the list of plans that are retrieved below.
+	err = r.add(r.Plan, r.Map.Storage)
+	if err != nil {
+		return
+	}
+	planList := &api.PlanList{}
+	err = r.List(context.TODO(), planList)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for i := range planList.Items {
+		p := &planList.Items[i]
+		if p.Name == r.Plan.Name && p.Namespace == r.Plan.Namespace {
+			continue
+		}
+		if p.Spec.Provider.Source != r.Plan.Spec.Provider.Source {
+			continue
+		}
+		if p.Spec.Archived {
+			continue
+		}
+		snapshot := p.Status.Migration.ActiveSnapshot()
+		if !snapshot.HasCondition("Executing") {
+			continue
+		}
+		var mp *api.StorageMap
+		mp, err = r.storageMap(p)
+		if err != nil {
+			return
+		}
+		err = r.add(p, mp)
+		if err != nil {
+			return
+		}
+	}
+	r.counted = true
+
+	return
+}
+
+// Get the storage map of another plan.
+// Returns nil when not found.
+func (r *Throttle) storageMap(p *api.Plan) (mp *api.StorageMap, err error) {
+	mp = &api.StorageMap{}
+	err = r.Get(
+		context.TODO(),
+		k8sclient.ObjectKey{
+			Namespace: p.Spec.Map.Storage.Namespace,
+			Name:      p.Spec.Map.Storage.Name,
+		},
+		mp)
+	if err != nil {
+		mp = nil
+		if k8sclient.IgnoreNotFound(err) == nil {
+			err = nil
+		} else {
+			err = liberr.Wrap(err)
+		}
+	}
+
+	return
+}
+
+// Add the disks of the running VMs in the plan.
+// Storage classes are only counted for plans
+// sharing the destination provider.
+func (r *Throttle) add(p *api.Plan, mp *api.StorageMap) (err error) {
+	sameDestination := p.Spec.Provider.Destination == r.Plan.Spec.Provider.Destination
+	for _, vmStatus := range p.Status.Migration.VMs {
+		if !transferring(vmStatus) {
+			continue
+		}
+		var usage Usage
+		usage, err = r.usage(vmStatus.Ref, mp)
+		if err != nil {
+			if errors.As(err, &web.NotFoundError{}) {
+				err = nil
+				continue
+			}
+			if errors.As(err, &web.RefNotUniqueError{}) {
+				err = nil
+				continue
+			}
+			return
+		}
+		for key, disks := range usage.Source {
+			r.sourceInFlight[key] += disks
+		}
+		if !sameDestination {
+			continue
+		}
+		for sc, disks := range usage.Destination {
+			r.destinationInFlight[sc] += disks
+		}
+	}
+
+	return
+}
+
+// The VM occupies scheduler capacity.
+// A paused VM is counted while in a transfer phase since
+// the running DataVolume and populator transfers cannot
+// be suspended and the VM does not leave the phase.
+func InFlight(vmStatus *plan.VMStatus) bool {
+	if !vmStatus.Running() {
+		return false
+	}
+	if vmStatus.HasCondition(Paused) {
+		return transferring(vmStatus)
+	}
+	return true
+}
+
+// The VM disks may be being transferred.
+// Paused VMs are included.
+func transferring(vmStatus *plan.VMStatus) bool {
+	if !vmStatus.Running() || vmStatus.HasCondition(Canceled) {
+		return false
+	}
+	switch vmStatus.Phase {
+	case CopyingPaused, CreateGuestConversionPod, ConvertGuest, CreateVM, PostHook, Completed:
+		return false
+	default:
+		return true
+	}
+}
+
+// Determine the disk usage of the VM using the storage map.
+// Disks on unmapped storage are ignored.
+func (r *Throttle) usage(vmRef ref.Ref, mp *api.StorageMap) (usage Usage, err error) {
+	usage = Usage{
+		Source:      make(map[string]int),
+		Destination: make(map[string]int),
+	}
+	storage, err := r.Finder.DiskStorage(vmRef)
+	if err != nil {
+		return
+	}
+	pairs, err := r.resolve(mp)
+	if err != nil {
+		return
+	}
+	for _, disk := range storage {
+		for _, p := range pairs {
+			if matches(disk, p.source) {
+				usage.Source[key(p.source)]++
+				usage.Destination[p.destination]++
+				break
+			}
+		}
+	}
+
+	return
+}
+
+// Resolve the source storage of the storage map pairs.
+// Pairs that cannot be resolved are ignored.
+func (r *Throttle) resolve(mp *api.StorageMap) (pairs []pair, err error) {
+	if mp == nil {
+		return
+	}
+	name := path.Join(mp.Namespace, mp.Name)
+	pairs, found := r.pairs[name]
+	if found {
+		return
+	}
+	for _, p := range mp.Spec.Map {
+		source := p.Source
+		_, err = r.Source.Inventory.Storage(&source)
+		if err != nil {
+			if errors.As(err, &web.NotFoundError{}) {
+				err = nil
+				continue
+			}
+			if errors.As(err, &web.RefNotUniqueError{}) {
+				err = nil
+				continue
+			}
+			err = liberr.Wrap(err, "storage", source.String())
+			return
+		}
+		pairs = append(
+			pairs,
+			pair{
+				source:      source,
+				destination: p.Destination.StorageClass,
+				limits:      p.Limits,
+			})
+	}
+	r.pairs[name] = pairs
+
+	return
+}
+
+// Storage key.
+func key(storage ref.Ref) string {
+	if storage.ID != "" {
+		return storage.ID
+	}
+	return storage.Name
+}
+
+// The storage refs match.
+// Compared by ID when both are set, otherwise by name.
+func matches(a, b ref.Ref) bool {
+	if a.ID != "" && b.ID != "" {
+		return a.ID == b.ID
+	}
+	return a.Name != "" && a.Name == b.Name
+}
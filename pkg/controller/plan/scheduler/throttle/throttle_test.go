@@ -0,0 +1,215 @@
+package throttle
+
+import (
+	"testing"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// Storage finder backed by a map of VM ID to disk storage.
+type finder map[string][]ref.Ref
+
+func (r finder) DiskStorage(vmRef ref.Ref) (storage []ref.Ref, err error) {
+	storage = r[vmRef.ID]
+	return
+}
+
+func TestThrottle(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	ctx := &plancontext.Context{
+		Plan: &api.Plan{},
+		Log:  logging.WithName("test"),
+	}
+	ctx.Map.Storage = &api.StorageMap{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "storage"},
+	}
+	throttle := &Throttle{
+		Context: ctx,
+		Finder: finder{
+			"vm-1": {{ID: "ds-1"}, {ID: "ds-1"}},
+			"vm-2": {{ID: "ds-2"}},
+			"vm-3": {{ID: "ds-1"}, {ID: "ds-1"}, {ID: "ds-1"}, {ID: "ds-1"}},
+			"vm-4": {{ID: "ds-3"}},
+		},
+		sourceLimit:         make(map[string]int),
+		destinationLimit:    make(map[string]int),
+		sourceInFlight:      make(map[string]int),
+		destinationInFlight: make(map[string]int),
+		pairs: map[string][]pair{
+			"test/storage": {
+				{
+					source:      ref.Ref{ID: "ds-1", Name: "ds-1"},
+					destination: "fast",
+					limits:      &api.StorageLimits{MaxSourceInFlight: 3},
+				},
+				{
+					source:      ref.Ref{ID: "ds-2", Name: "ds-2"},
+					destination: "slow",
+					limits:      &api.StorageLimits{MaxDestinationInFlight: 2},
+				},
+				{
+					source:      ref.Ref{ID: "ds-3", Name: "ds-3"},
+					destination: "slow",
+					limits:      &api.StorageLimits{MaxDestinationInFlight: 4},
+				},
+			},
+		},
+	}
+	g.Expect(throttle.buildLimits()).To(gomega.Succeed())
+	g.Expect(throttle.Enabled()).To(gomega.BeTrue())
+	g.Expect(throttle.sourceLimit).To(gomega.Equal(map[string]int{"ds-1": 3}))
+	// The lowest limit is used for a shared storage class.
+	g.Expect(throttle.destinationLimit).To(gomega.Equal(map[string]int{"slow": 2}))
+
+	// Nothing in flight.
+	for _, id := range []string{"vm-1", "vm-2", "vm-3", "vm-4"} {
+		admitted, err := throttle.Admit(ref.Ref{ID: id})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(admitted).To(gomega.BeTrue())
+	}
+
+	// One disk in flight on ds-1 and the slow storage class.
+	throttle.sourceInFlight["ds-1"] = 1
+	throttle.destinationInFlight["fast"] = 1
+	throttle.destinationInFlight["slow"] = 1
+	admitted, err := throttle.Admit(ref.Ref{ID: "vm-1"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(admitted).To(gomega.BeTrue())
+	// A VM with more disks than the limit waits for
+	// the storage to be idle.
+	admitted, err = throttle.Admit(ref.Ref{ID: "vm-3"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(admitted).To(gomega.BeFalse())
+	admitted, err = throttle.Admit(ref.Ref{ID: "vm-2"})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(admitted).To(gomega.BeTrue())
+
+	// Limits reached.
+	throttle.sourceInFlight["ds-1"] = 3
+	throttle.destinationInFlight["slow"] = 2
+	for _, id := range []string{"vm-1", "vm-2", "vm-4"} {
+		admitted, err = throttle.Admit(ref.Ref{ID: id})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		g.Expect(admitted).To(gomega.BeFalse())
+	}
+}
+
+func TestStartable(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	destination := core.ObjectReference{Namespace: "test", Name: "host"}
+	mp := &api.StorageMap{
+		ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "other"},
+	}
+	scheme := runtime.NewScheme()
+	_ = api.SchemeBuilder.AddToScheme(scheme)
+	ctx := &plancontext.Context{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(mp).Build(),
+		Plan:   &api.Plan{},
+		Log:    logging.WithName("test"),
+	}
+	ctx.Plan.Spec.Provider.Destination = destination
+	throttle := &Throttle{
+		Context: ctx,
+		Finder: finder{
+			"vm-1": {{ID: "ds-1"}, {ID: "ds-1"}},
+			"vm-2": {{ID: "ds-2"}},
+		},
+		sourceInFlight:      map[string]int{"ds-1": 2},
+		destinationInFlight: map[string]int{"slow": 2},
+		pairs: map[string][]pair{
+			"test/other": {
+				{
+					source:      ref.Ref{ID: "ds-1"},
+					destination: "fast",
+					limits:      &api.StorageLimits{MaxSourceInFlight: 3},
+				},
+				{
+					source:      ref.Ref{ID: "ds-2"},
+					destination: "slow",
+					limits:      &api.StorageLimits{MaxDestinationInFlight: 2},
+				},
+			},
+		},
+		counted: true,
+	}
+	p := &api.Plan{}
+	p.Spec.Map.Storage = core.ObjectReference{Namespace: "test", Name: "other"}
+	p.Spec.Provider.Destination = destination
+	vm1 := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-1"}}}
+	vm2 := &plan.VMStatus{VM: plan.VM{Ref: ref.Ref{ID: "vm-2"}}}
+
+	// The limits of the storage map of the other plan are reached.
+	startable, err := throttle.Startable(p, vm1)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(startable).To(gomega.BeFalse())
+	startable, err = throttle.Startable(p, vm2)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(startable).To(gomega.BeFalse())
+	// The destination limits are not applied
+	// to another destination provider.
+	p.Spec.Provider.Destination = core.ObjectReference{Namespace: "test", Name: "remote"}
+	startable, _ = throttle.Startable(p, vm2)
+	g.Expect(startable).To(gomega.BeTrue())
+	// Without a storage map.
+	p.Spec.Map.Storage.Name = "missing"
+	startable, err = throttle.Startable(p, vm1)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(startable).To(gomega.BeTrue())
+}
+
+func TestMatches(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	g.Expect(matches(ref.Ref{ID: "1"}, ref.Ref{ID: "1", Name: "a"})).To(gomega.BeTrue())
+	g.Expect(matches(ref.Ref{ID: "1"}, ref.Ref{ID: "2", Name: "a"})).To(gomega.BeFalse())
+	g.Expect(matches(ref.Ref{Name: "a"}, ref.Ref{ID: "2", Name: "a"})).To(gomega.BeTrue())
+	g.Expect(matches(ref.Ref{}, ref.Ref{ID: "2"})).To(gomega.BeFalse())
+}
+
+func TestTransferring(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := &plan.VMStatus{Phase: "CopyDisks"}
+	g.Expect(transferring(vm)).To(gomega.BeFalse())
+	vm.MarkStarted()
+	g.Expect(transferring(vm)).To(gomega.BeTrue())
+	// Transfers of paused VMs keep running.
+	vm.SetCondition(libcnd.Condition{Type: Paused, Status: libcnd.True})
+	g.Expect(transferring(vm)).To(gomega.BeTrue())
+	vm.DeleteCondition(Paused)
+	vm.SetCondition(libcnd.Condition{Type: Canceled, Status: libcnd.True})
+	g.Expect(transferring(vm)).To(gomega.BeFalse())
+	vm.DeleteCondition(Canceled)
+	vm.Phase = CopyingPaused
+	g.Expect(transferring(vm)).To(gomega.BeFalse())
+}
+
+func TestInFlight(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vm := &plan.VMStatus{Phase: "CopyDisks"}
+	g.Expect(InFlight(vm)).To(gomega.BeFalse())
+	vm.MarkStarted()
+	g.Expect(InFlight(vm)).To(gomega.BeTrue())
+	vm.Phase = CreateVM
+	g.Expect(InFlight(vm)).To(gomega.BeTrue())
+	// Paused VMs are counted while transferring.
+	vm.SetCondition(libcnd.Condition{Type: Paused, Status: libcnd.True})
+	g.Expect(InFlight(vm)).To(gomega.BeFalse())
+	vm.Phase = "CopyDisks"
+	g.Expect(InFlight(vm)).To(gomega.BeTrue())
+	vm.Phase = CopyingPaused
+	g.Expect(InFlight(vm)).To(gomega.BeFalse())
+}
@@ -3,11 +3,15 @@ package vsphere
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/order"
+	"github.com/kubev2v/forklift/pkg/controller/plan/scheduler/throttle"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
@@ -22,6 +26,7 @@ const (
 	PostHook                 = "PostHook"
 	Completed                = "Completed"
 	Canceled                 = "Canceled"
+	Paused                   = "Paused"
 )
 
 // Steps.
@@ -48,6 +53,10 @@ type Scheduler struct {
 	// Mapping of hosts by ID to lists of VMs
 	// that are waiting to be migrated.
 	pending map[string][]*pendingVM
+	// Plan ordering.
+	order *order.Order
+	// Storage limits.
+	limiter *throttle.Throttle
 }
 
 // Convenience struct to package a
@@ -56,6 +65,8 @@ type Scheduler struct {
 type pendingVM struct {
 	status *plan.VMStatus
 	cost   int
+	// Position in the plan ordering.
+	rank int
 }
 
 // Return the next VM to migrate.
@@ -66,11 +77,26 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 	if err != nil {
 		return
 	}
-	for _, vms := range r.schedulable() {
-		if len(vms) > 0 {
-			vm = vms[0].status
-			hasNext = true
+	for _, pending := range r.ranked(r.schedulable()) {
+		var admitted bool
+		admitted, err = r.limiter.Admit(pending.status.Ref)
+		if err != nil {
+			return
+		}
+		if !admitted {
+			continue
+		}
+		var yield bool
+		yield, err = r.order.Yield(pending.status)
+		if err != nil {
+			return
 		}
+		if yield {
+			continue
+		}
+		vm = pending.status
+		hasNext = true
+		break
 	}
 
 	if hasNext {
@@ -88,6 +114,11 @@ func (r *Scheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
 // the same provider, and determine which
 // VMs are still waiting to be started.
 func (r *Scheduler) buildSchedule() (err error) {
+	r.limiter, err = throttle.New(r.Context, r)
+	if err != nil {
+		return
+	}
+	r.order = order.New(r.Context, r, r)
 	err = r.buildInFlight()
 	if err != nil {
 		return
@@ -117,7 +148,7 @@ func (r *Scheduler) buildInFlight() (err error) {
 	// we need to use the plan from the context rather
 	// than from the list of plans that are retrieved below.
 	for _, vmStatus := range r.Plan.Status.Migration.VMs {
-		if vmStatus.HasCondition(Canceled) {
+		if vmStatus.HasCondition(Canceled) || !throttle.InFlight(vmStatus) {
 			continue
 		}
 		vm := &model.VM{}
@@ -125,9 +156,7 @@ func (r *Scheduler) buildInFlight() (err error) {
 		if err != nil {
 			return
 		}
-		if vmStatus.Running() {
-			r.inFlight[vm.Host] += r.cost(vm, vmStatus)
-		}
+		r.inFlight[vm.Host] += r.cost(vm, vmStatus)
 	}
 
 	planList := &api.PlanList{}
@@ -158,7 +187,7 @@ func (r *Scheduler) buildInFlight() (err error) {
 		}
 
 		for _, vmStatus := range p.Status.Migration.VMs {
-			if !vmStatus.Running() {
+			if !throttle.InFlight(vmStatus) {
 				continue
 			}
 			vm := &model.VM{}
@@ -180,11 +209,16 @@ func (r *Scheduler) buildInFlight() (err error) {
 }
 
 // Build the map of pending VMs belonging to each host.
+// The VMs are ranked by the plan ordering.
 func (r *Scheduler) buildPending() (err error) {
 	r.pending = make(map[string][]*pendingVM)
 
-	for _, vmStatus := range r.Plan.Status.Migration.VMs {
-		if vmStatus.HasCondition(Canceled) {
+	vms, err := r.order.Sort(r.Plan.Status.Migration.VMs)
+	if err != nil {
+		return
+	}
+	for rank, vmStatus := range vms {
+		if vmStatus.HasAnyCondition(Canceled, Paused) {
 			continue
 		}
 		vm := &model.VM{}
@@ -197,6 +231,7 @@ func (r *Scheduler) buildPending() (err error) {
 			pending := &pendingVM{
 				status: vmStatus,
 				cost:   r.cost(vm, vmStatus),
+				rank:   rank,
 			}
 			r.pending[vm.Host] = append(r.pending[vm.Host], pending)
 		}
@@ -250,16 +285,8 @@ func (r *Scheduler) finishedDisks(vmStatus *plan.VMStatus) int {
 func (r *Scheduler) schedulable() (schedulable map[string][]*pendingVM) {
 	schedulable = make(map[string][]*pendingVM)
 	for host, vms := range r.pending {
-		if r.inFlight[host] >= r.MaxInFlight {
-			continue
-		}
 		for i := range vms {
-			if vms[i].cost+r.inFlight[host] <= r.MaxInFlight {
-				schedulable[host] = append(schedulable[host], vms[i])
-			}
-			// In case there is VM with more disks than the MaxInFlight MTV will migrate it, if there are no other VMs
-			// being migrated at that time.
-			if vms[i].cost > r.MaxInFlight && r.inFlight[host] == 0 {
+			if r.fits(host, vms[i].cost) {
 				schedulable[host] = append(schedulable[host], vms[i])
 			}
 		}
@@ -267,3 +294,76 @@ func (r *Scheduler) schedulable() (schedulable map[string][]*pendingVM) {
 
 	return
 }
+
+// The cost fits the available capacity of the host.
+func (r *Scheduler) fits(host string, cost int) bool {
+	if r.inFlight[host] >= r.MaxInFlight {
+		return false
+	}
+	// In case there is VM with more disks than the MaxInFlight MTV will migrate it, if there are no other VMs
+	// being migrated at that time.
+	return cost+r.inFlight[host] <= r.MaxInFlight || r.inFlight[host] == 0
+}
+
+// Determine whether another plan can start the pending VM now.
+// The host of the VM must have the capacity and the VM must be
+// within the storage limits of the plan. A VM not found in the
+// inventory cannot be started.
+func (r *Scheduler) Startable(p *api.Plan, vmStatus *plan.VMStatus) (startable bool, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmStatus.Ref)
+	if err != nil {
+		if errors.As(err, &web.NotFoundError{}) || errors.As(err, &web.RefNotUniqueError{}) {
+			err = nil
+		}
+		return
+	}
+	if !r.fits(vm.Host, r.cost(vm, vmStatus)) {
+		return
+	}
+	startable, err = r.limiter.Startable(p, vmStatus)
+	return
+}
+
+// Flatten the schedulable VMs of all hosts
+// in the order of the plan.
+func (r *Scheduler) ranked(schedulable map[string][]*pendingVM) (ranked []*pendingVM) {
+	for _, vms := range schedulable {
+		ranked = append(ranked, vms...)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		return ranked[i].rank < ranked[j].rank
+	})
+
+	return
+}
+
+// Find the source storage of each disk of the VM.
+func (r *Scheduler) DiskStorage(vmRef ref.Ref) (storage []ref.Ref, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		storage = append(storage, ref.Ref{ID: disk.Datastore.ID})
+	}
+
+	return
+}
+
+// Total size (bytes) of the disks of the VM.
+func (r *Scheduler) DiskSize(vmRef ref.Ref) (size int64, err error) {
+	vm := &model.VM{}
+	err = r.Source.Inventory.Find(vm, vmRef)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vmRef.String())
+		return
+	}
+	for _, disk := range vm.Disks {
+		size += disk.Capacity
+	}
+
+	return
+}
@@ -0,0 +1,40 @@
+package scheduler
+
+import (
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/wave"
+)
+
+// Scheduler that only starts the VMs of running
+// waves. Delegates to a provider scheduler built for
+// the plan restricted to the VMs that may be started.
+type WaveScheduler struct {
+	*plancontext.Context
+	// Provider scheduler factory.
+	New func(ctx *plancontext.Context) (Scheduler, error)
+	// Plan waves.
+	Waves *wave.Waves
+}
+
+// Return the next VM that can be migrated.
+func (r *WaveScheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
+	vms := []*plan.VMStatus{}
+	for _, vmStatus := range r.Plan.Status.Migration.VMs {
+		if vmStatus.MarkedStarted() || r.Waves.Schedulable(vmStatus) {
+			vms = append(vms, vmStatus)
+		}
+	}
+	// The VM statuses are shared with the plan
+	// so the selected VM is updated in place.
+	restricted := *r.Plan
+	restricted.Status.Migration.VMs = vms
+	ctx := *r.Context
+	ctx.Plan = &restricted
+	scheduler, err := r.New(&ctx)
+	if err != nil {
+		return
+	}
+	vm, hasNext, err = scheduler.Next()
+	return
+}
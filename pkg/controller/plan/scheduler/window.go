@@ -0,0 +1,30 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/schedule"
+)
+
+// Scheduler that does not start VMs outside
+// of the plan migration windows or within
+// a blackout. Delegates to the provider scheduler.
+type WindowScheduler struct {
+	*plancontext.Context
+	// Provider scheduler.
+	Scheduler Scheduler
+	// Plan calendar.
+	Calendar *schedule.Calendar
+}
+
+// Return the next VM that can be migrated.
+func (r *WindowScheduler) Next() (vm *plan.VMStatus, hasNext bool, err error) {
+	if !r.Calendar.Open(time.Now()) {
+		r.Log.V(1).Info("Outside of the migration window.")
+		return
+	}
+	vm, hasNext, err = r.Scheduler.Next()
+	return
+}
@@ -0,0 +1,100 @@
+package plan
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Resolve the VMs selected by the VM selector.
+// The selected VMs are reported on the status and added to the
+// (in-memory) list of VMs so they are validated and migrated the
+// same as the listed VMs.
+func (r *Reconciler) selectVMs(plan *api.Plan) (err error) {
+	if plan.Spec.VMSelector == nil {
+		plan.Status.Selector = nil
+		return
+	}
+	if !r.validateVMSelector(plan) {
+		return
+	}
+	if resolveNeeded(plan) {
+		err = r.resolveSelector(plan)
+		if err != nil {
+			return
+		}
+	}
+	plan.AddSelectedVMs()
+	return
+}
+
+// Resolve the selected VMs using the inventory.
+func (r *Reconciler) resolveSelector(plan *api.Plan) (err error) {
+	inventory, err := web.NewClient(plan.Referenced.Provider.Source)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	list := []vsphere.VM{}
+	err = inventory.List(&list, selectorParams(plan.Spec.VMSelector)...)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	status := &planapi.SelectorStatus{
+		Generation: plan.Generation,
+	}
+	for _, vm := range list {
+		status.VMs = append(
+			status.VMs,
+			refapi.Ref{
+				ID:   vm.ID,
+				Name: vm.Name,
+			})
+	}
+	plan.Status.Selector = status
+	r.Log.V(1).Info(
+		"VM selector resolved.",
+		"selected",
+		len(status.VMs))
+	return
+}
+
+// Determine whether the selected VMs need to be resolved.
+// The selected VMs are frozen once a migration has started.
+func resolveNeeded(plan *api.Plan) bool {
+	status := plan.Status.Selector
+	if status == nil {
+		return true
+	}
+	if len(plan.Status.Migration.History) > 0 {
+		return false
+	}
+	switch plan.Spec.VMSelector.Mode {
+	case planapi.SelectorFreeze:
+		return status.Generation != plan.Generation
+	default:
+		return true
+	}
+}
+
+// Build the inventory query params of the selector.
+func selectorParams(selector *planapi.VMSelector) (params []web.Param) {
+	add := func(key, value string) {
+		if value != "" {
+			params = append(params, web.Param{Key: key, Value: value})
+		}
+	}
+	add(vsphere.PathParam, selector.Path)
+	add(vsphere.NameRegexParam, selector.Name)
+	add(vsphere.PowerStateParam, selector.PowerState)
+	add(vsphere.GuestOSParam, selector.GuestOS)
+	add(vsphere.DatastoreParam, selector.Datastore)
+	for _, tag := range selector.Tags {
+		add(vsphere.TagParam, tag)
+	}
+	return
+}
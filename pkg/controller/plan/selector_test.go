@@ -0,0 +1,66 @@
+package plan
+
+import (
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	ginkgo "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = ginkgo.Describe("VM selector", func() {
+	ginkgo.It("should add the selected VMs not listed", func() {
+		plan := &api.Plan{}
+		plan.Spec.VMs = []planapi.VM{
+			{Ref: refapi.Ref{ID: "vm-1"}, TargetName: "first"},
+			{Ref: refapi.Ref{Name: "/DC0/vm/web-2"}},
+		}
+		plan.Status.Selector = &planapi.SelectorStatus{
+			VMs: []refapi.Ref{
+				{ID: "vm-1", Name: "web-1"},
+				{ID: "vm-2", Name: "web-2"},
+				{ID: "vm-3", Name: "web-3"},
+			},
+		}
+		plan.AddSelectedVMs()
+		Expect(plan.Spec.VMs).To(Equal([]planapi.VM{
+			{Ref: refapi.Ref{ID: "vm-1"}, TargetName: "first"},
+			{Ref: refapi.Ref{Name: "/DC0/vm/web-2"}},
+			{Ref: refapi.Ref{ID: "vm-3", Name: "web-3"}},
+		}))
+	})
+
+	ginkgo.It("should resolve the VMs until the migration is started", func() {
+		plan := &api.Plan{}
+		plan.Generation = 2
+		plan.Spec.VMSelector = &planapi.VMSelector{}
+		Expect(resolveNeeded(plan)).To(BeTrue())
+		plan.Status.Selector = &planapi.SelectorStatus{Generation: 2}
+		Expect(resolveNeeded(plan)).To(BeTrue())
+		// Frozen.
+		plan.Spec.VMSelector.Mode = planapi.SelectorFreeze
+		Expect(resolveNeeded(plan)).To(BeFalse())
+		plan.Generation = 3
+		Expect(resolveNeeded(plan)).To(BeTrue())
+		// Started.
+		plan.Spec.VMSelector.Mode = planapi.SelectorSync
+		plan.Status.Migration.History = []planapi.Snapshot{{}}
+		Expect(resolveNeeded(plan)).To(BeFalse())
+	})
+
+	ginkgo.It("should build the inventory query", func() {
+		params := selectorParams(&planapi.VMSelector{
+			Path:       "/DC0/vm/prod",
+			PowerState: "poweredOn",
+			Tags:       []string{"env:prod", "app:web"},
+		})
+		Expect(params).To(Equal([]web.Param{
+			{Key: vsphere.PathParam, Value: "/DC0/vm/prod"},
+			{Key: vsphere.PowerStateParam, Value: "poweredOn"},
+			{Key: vsphere.TagParam, Value: "env:prod"},
+			{Key: vsphere.TagParam, Value: "app:web"},
+		}))
+	})
+})
@@ -0,0 +1,197 @@
+package plan
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/controller/plan/health"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	cnv "kubevirt.io/api/core/v1"
+)
+
+// VM condition types.
+const (
+	TargetValidationFailed = "TargetValidationFailed"
+)
+
+// Validate the migrated VM.
+// The checks are repeated until they pass or the timeout expires.
+// Checks that cannot run are skipped. Failed checks are reported
+// according to the failure policy: as a warning, by failing the VM
+// migration, or by failing the VM migration and rolling it back.
+// Errors preventing the checks from running are reported the same.
+func (r *Migration) validateTarget(vm *plan.VMStatus, step *plan.Step) (err error) {
+	spec := r.Plan.Spec.TargetValidation
+	if spec == nil {
+		r.NextPhase(vm)
+		return
+	}
+	step.MarkStarted()
+	step.Phase = api.StepRunning
+	checker := &health.Checker{Spec: spec}
+	if r.kubevirt.determineRunStrategy(vm) != cnv.RunStrategyAlways {
+		for _, task := range step.Tasks {
+			r.checkDone(task, health.Result{Skipped: true, Detail: "The target VM is not powered on."})
+		}
+		r.targetValidated(vm, step)
+		return
+	}
+	expired := time.Since(step.Started.Time) > spec.TimeoutDuration()
+	checker.VMI, err = r.kubevirt.GetVMI(vm)
+	if err == nil && spec.ExpectedIPs {
+		checker.SourceIPs, err = r.sourceIPs(vm)
+	}
+	if err != nil {
+		err = r.checksNotRun(vm, step, err, expired)
+		return
+	}
+	if !r.Plan.Provider.Destination.IsHost() {
+		checker.SkipProbes = "The VMs on a remote destination cluster cannot be probed."
+	}
+	pending := 0
+	for _, task := range step.Tasks {
+		if task.MarkedCompleted() {
+			continue
+		}
+		task.MarkStarted()
+		task.Phase = api.StepRunning
+		result := checker.Check(task.Name)
+		if result.Passed || result.Skipped || expired {
+			r.checkDone(task, result)
+			continue
+		}
+		if task.Annotations == nil {
+			task.Annotations = make(map[string]string)
+		}
+		task.Annotations[health.AnnDetail] = result.Detail
+		pending++
+	}
+	if pending == 0 {
+		r.targetValidated(vm, step)
+	}
+
+	return
+}
+
+// The checks could not run.
+// Retried until the timeout expires. Then, the VM migration fails
+// under the Fail and Rollback policies. Otherwise, the pending checks
+// are skipped and the error is reported as a warning.
+func (r *Migration) checksNotRun(vm *plan.VMStatus, step *plan.Step, cause error, expired bool) (err error) {
+	r.Log.Info(
+		"The validation of the migrated VM could not run.",
+		"vm",
+		vm.String(),
+		"reason",
+		cause.Error())
+	if !expired {
+		return
+	}
+	switch r.Plan.Spec.TargetValidation.Policy() {
+	case plan.TargetValidationFail, plan.TargetValidationRollback:
+		err = cause
+		return
+	}
+	detail := fmt.Sprintf("The check could not run: %s", cause.Error())
+	for _, task := range step.Tasks {
+		if !task.MarkedCompleted() {
+			r.checkDone(task, health.Result{Skipped: true, Detail: detail})
+		}
+	}
+	vm.SetCondition(
+		libcnd.Condition{
+			Type:     TargetValidationFailed,
+			Status:   True,
+			Category: api.CategoryWarn,
+			Message:  "The validation of the migrated VM could not run.",
+			Items:    []string{cause.Error()},
+			Durable:  true,
+		})
+	r.targetValidated(vm, step)
+
+	return
+}
+
+// Record the result of a check.
+func (r *Migration) checkDone(task *plan.Task, result health.Result) {
+	if task.Annotations == nil {
+		task.Annotations = make(map[string]string)
+	}
+	switch {
+	case result.Passed:
+		task.Reason = health.Passed
+		delete(task.Annotations, health.AnnDetail)
+	case result.Skipped:
+		task.Reason = health.Skipped
+		task.Annotations[health.AnnDetail] = result.Detail
+	default:
+		task.Reason = health.Failed
+		task.Annotations[health.AnnDetail] = result.Detail
+	}
+	task.Progress.Completed = task.Progress.Total
+	task.Phase = api.StepCompleted
+	task.MarkCompleted()
+}
+
+// All checks are done.
+// Report the failed checks according to the failure policy.
+func (r *Migration) targetValidated(vm *plan.VMStatus, step *plan.Step) {
+	failed := []string{}
+	for _, task := range step.Tasks {
+		if task.Reason == health.Failed {
+			failed = append(
+				failed,
+				fmt.Sprintf("%s: %s", task.Name, task.Annotations[health.AnnDetail]))
+		}
+	}
+	step.Progress.Completed = step.Progress.Total
+	if len(failed) > 0 {
+		switch r.Plan.Spec.TargetValidation.Policy() {
+		case plan.TargetValidationFail, plan.TargetValidationRollback:
+			step.AddError(failed...)
+			step.MarkCompleted()
+			step.Phase = api.StepCompleted
+			return
+		default:
+			vm.SetCondition(
+				libcnd.Condition{
+					Type:     TargetValidationFailed,
+					Status:   True,
+					Category: api.CategoryWarn,
+					Message:  "The validation of the migrated VM failed.",
+					Items:    failed,
+					Durable:  true,
+				})
+		}
+	}
+	r.NextPhase(vm)
+}
+
+// IPs reported by the source guest.
+// Only known for vSphere. Link-local addresses are ignored.
+func (r *Migration) sourceIPs(vm *plan.VMStatus) (ips []string, err error) {
+	if r.Source.Provider.Type() != api.VSphere {
+		return
+	}
+	model := &vsphere.VM{}
+	err = r.Source.Inventory.Find(model, vm.Ref)
+	if err != nil {
+		err = liberr.Wrap(err, "vm", vm.String())
+		return
+	}
+	ips = []string{}
+	for _, network := range model.GuestNetworks {
+		ip := net.ParseIP(network.IP)
+		if ip == nil || ip.IsLinkLocalUnicast() || ip.IsLoopback() {
+			continue
+		}
+		ips = append(ips, network.IP)
+	}
+
+	return
+}
@@ -68,6 +68,21 @@ func GetDeviceNumber(deviceString string) int {
 	return 0
 }
 
+// Shift the device, e.g. /dev/sdc shifted by -1 is /dev/sdb.
+// The device is returned unchanged when it cannot be parsed.
+func ShiftDevice(deviceString string, n int) string {
+	deviceNumber := GetDeviceNumber(deviceString)
+	if deviceNumber == 0 || deviceNumber+n < 1 || deviceNumber+n > 26 {
+		return deviceString
+	}
+	for i := len(diskPrefix); i < len(deviceString); i++ {
+		if unicode.IsLetter(rune(deviceString[i])) {
+			return deviceString[:i] + string(rune('a'+deviceNumber+n-1)) + deviceString[i+1:]
+		}
+	}
+	return deviceString
+}
+
 type HostsFunc func() (map[string]*api.Host, error)
 
 // ChangeVmName changes VM name to match DNS1123 RFC convention.
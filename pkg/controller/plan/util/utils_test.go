@@ -29,6 +29,16 @@ var _ = Describe("Plan/utils", func() {
 		Entry("test", "test", 0),
 	)
 
+	DescribeTable("shift dev", func(dev string, n int, shifted string) {
+		Expect(ShiftDevice(dev, n)).Should(Equal(shifted))
+	},
+		Entry("sdc", "/dev/sdc", -1, "/dev/sdb"),
+		Entry("sdc2", "/dev/sdc2", -2, "/dev/sda2"),
+		Entry("sda", "/dev/sda", -1, "/dev/sda"),
+		Entry("unchanged", "/dev/sdb", 0, "/dev/sdb"),
+		Entry("test", "test", -1, "test"),
+	)
+
 	Context("VM Name Handler", func() {
 		It("should handle all cases in name adjustments", func() {
 			originalVmName := "----------------Vm!@#$%^&*()_+-Name/.is,';[]-CorREct-<>123----------------------"
@@ -8,15 +8,21 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"regexp"
 	"strconv"
 	"strings"
 
 	k8snet "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	"github.com/kubev2v/forklift/pkg/controller/plan/adapter"
+	"github.com/kubev2v/forklift/pkg/controller/plan/capacity"
 	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/plan/schedule"
+	"github.com/kubev2v/forklift/pkg/controller/plan/wave"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+	vsmodel "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
@@ -79,6 +85,7 @@ const (
 	Paused                          = "Paused"
 	Archived                        = "Archived"
 	InvalidDiskSizes                = "InvalidDiskSizes"
+	DiskSelectionNotValid           = "DiskSelectionNotValid"
 	MacConflicts                    = "MacConflicts"
 	MissingPvcForOnlyConversion     = "MissingPvcForOnlyConversion"
 	LuksAndClevisIncompatibility    = "LuksAndClevisIncompatibility"
@@ -92,6 +99,17 @@ const (
 	VMPowerStateUnsupported         = "VMPowerStateUnsupported"
 	VMMigrationTypeUnsupported      = "VMMigrationTypeUnsupported"
 	GuestToolsIssue                 = "GuestToolsIssue"
+	ScheduleNotValid                = "ScheduleNotValid"
+	WavesNotValid                   = "WavesNotValid"
+	InsufficientCapacity            = "InsufficientCapacity"
+	CapacityNotAssessed             = "CapacityNotAssessed"
+	ReplicationNotValid             = "ReplicationNotValid"
+	HardwareNotValid                = "HardwareNotValid"
+	TargetMetadataNotValid          = "TargetMetadataNotValid"
+	DrsRuleNotInPlan                = "DrsRuleNotInPlan"
+	VMSelectorNotValid              = "VMSelectorNotValid"
+	VMModified                      = "Modified"
+	DryRun                          = "DryRun"
 )
 
 // Categories
@@ -124,6 +142,9 @@ const (
 	InMaintenanceMode           = "InMaintenanceMode"
 	MissingGuestInfo            = "MissingGuestInformation"
 	MissingChangedBlockTracking = "MissingChangedBlockTracking"
+	OutsideWindow               = "OutsideWindow"
+	Blackout                    = "Blackout"
+	WaveBlocked                 = "WaveBlocked"
 )
 
 // Statuses
@@ -157,6 +178,10 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	if err = r.selectVMs(plan); err != nil {
+		return err
+	}
+
 	if err = r.validateTargetNamespace(plan); err != nil {
 		return err
 	}
@@ -191,6 +216,8 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	r.validateModified(plan)
+
 	if err = r.validateTransferNetwork(plan); err != nil {
 		return err
 	}
@@ -228,9 +255,221 @@ func (r *Reconciler) validate(plan *api.Plan) error {
 		return err
 	}
 
+	r.validateSchedule(plan)
+	r.validateWaves(plan)
+	r.validateReplication(plan)
+	r.validateHardware(plan)
+	r.validateTargetMetadata(plan)
+
+	r.validateCapacity(ctx)
+
 	return nil
 }
 
+// Validate the destination cluster can absorb the plan.
+// The CPU, memory and storage (including the overhead) required
+// by the VMs are compared with the ResourceQuotas in the target
+// namespace and the allocatable resources of the nodes matching
+// the target node selector and affinity.
+// Not assessed while the migration is running. The assessment is
+// advisory, errors are reported as a warning and do not block the plan.
+func (r *Reconciler) validateCapacity(ctx *plancontext.Context) {
+	plan := ctx.Plan
+	if plan.Status.HasBlockerCondition() || plan.Status.Migration.Running() {
+		return
+	}
+	shortfalls, err := r.capacityShortfalls(ctx)
+	if err != nil {
+		r.Log.Error(err, "capacity assessment failed.", "plan", plan.Name, "namespace", plan.Namespace)
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     CapacityNotAssessed,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryWarn,
+			Message:  "The capacity of the destination cluster could not be assessed.",
+			Items:    []string{liberr.Unwrap(err).Error()},
+		})
+		return
+	}
+	if len(shortfalls) > 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     InsufficientCapacity,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryWarn,
+			Message:  "The destination cluster may not have enough capacity for the plan.",
+			Items:    shortfalls,
+		})
+	}
+}
+
+// Compare the resources required by the plan
+// with the capacity of the destination cluster.
+func (r *Reconciler) capacityShortfalls(ctx *plancontext.Context) (shortfalls []string, err error) {
+	planner, err := capacity.New(ctx)
+	if err != nil {
+		return
+	}
+	assessment, err := planner.AssessPlan(ctx.Destination.Client)
+	if err != nil {
+		return
+	}
+	shortfalls = assessment.Shortfalls
+
+	return
+}
+
+// Validate the migration waves.
+func (r *Reconciler) validateWaves(plan *api.Plan) {
+	_, err := wave.New(plan)
+	if err != nil {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     WavesNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryCritical,
+			Message:  "The migration waves are not valid.",
+			Items:    []string{liberr.Unwrap(err).Error()},
+		})
+	}
+}
+
+// Validate the replication mode.
+// Replication is only supported by warm migrations from vSphere
+// since the snapshot of each precopy is removed on the next precopy.
+func (r *Reconciler) validateReplication(plan *api.Plan) {
+	if plan.Spec.Replication == nil {
+		return
+	}
+	var reason string
+	source := plan.Referenced.Provider.Source
+	switch {
+	case !plan.IsWarm():
+		reason = "Replication requires a warm migration."
+	case source != nil && source.Type() != api.VSphere:
+		reason = fmt.Sprintf("Replication from a %s provider is not supported.", source.Type())
+	default:
+		return
+	}
+	plan.Status.SetCondition(libcnd.Condition{
+		Type:     ReplicationNotValid,
+		Status:   True,
+		Reason:   NotSupported,
+		Category: api.CategoryCritical,
+		Message:  "The replication mode is not valid.",
+		Items:    []string{reason},
+	})
+}
+
+// Validate the hardware overrides.
+// The CPU and memory of a VM with an instance type are defined
+// by the instance type.
+func (r *Reconciler) validateHardware(plan *api.Plan) {
+	var items []string
+	for i := range plan.Spec.VMs {
+		vm := &plan.Spec.VMs[i]
+		hardware := plan.Spec.Hardware.With(vm.Hardware)
+		if hardware == nil {
+			continue
+		}
+		if vm.InstanceType != "" && (hardware.CPU != nil || hardware.Memory != nil) {
+			items = append(items, fmt.Sprintf("%s: the CPU and memory cannot be overridden with an instance type.", vm.String()))
+		}
+		firmware := hardware.Firmware
+		if firmware != nil && firmware.Type == planapi.FirmwareBIOS && firmware.SecureBoot != nil && *firmware.SecureBoot {
+			items = append(items, fmt.Sprintf("%s: secure boot requires the EFI firmware.", vm.String()))
+		}
+	}
+	if len(items) > 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     HardwareNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryCritical,
+			Message:  "The hardware overrides are not valid.",
+			Items:    items,
+		})
+	}
+}
+
+// Validate the target metadata mappings.
+// Tags and custom attributes are only collected from vSphere.
+func (r *Reconciler) validateTargetMetadata(plan *api.Plan) {
+	if len(plan.Spec.TargetMetadata) == 0 {
+		return
+	}
+	var items []string
+	source := plan.Referenced.Provider.Source
+	if source != nil && source.Type() != api.VSphere {
+		items = append(items, fmt.Sprintf("Metadata from a %s provider is not supported.", source.Type()))
+	}
+	for _, mapping := range plan.Spec.TargetMetadata {
+		key := metadataKey(mapping)
+		if errs := k8svalidation.IsQualifiedName(key); len(errs) > 0 {
+			items = append(items, fmt.Sprintf("%s: %s", key, strings.Join(errs, " ")))
+		}
+	}
+	if len(items) > 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     TargetMetadataNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryCritical,
+			Message:  "The target metadata mappings are not valid.",
+			Items:    items,
+		})
+	}
+}
+
+// Validate the VM selector.
+// Only VMs of vSphere providers can be selected.
+func (r *Reconciler) validateVMSelector(plan *api.Plan) (valid bool) {
+	var items []string
+	source := plan.Referenced.Provider.Source
+	if source != nil && source.Type() != api.VSphere {
+		items = append(items, fmt.Sprintf("Selecting the VMs of a %s provider is not supported.", source.Type()))
+	}
+	if path := plan.Spec.VMSelector.Path; path != "" && !strings.HasPrefix(path, "/") {
+		items = append(items, fmt.Sprintf("path: %s: expected an absolute inventory path", path))
+	}
+	if _, err := regexp.Compile(plan.Spec.VMSelector.Name); err != nil {
+		items = append(items, fmt.Sprintf("name: %s", err.Error()))
+	}
+	for _, tag := range plan.Spec.VMSelector.Tags {
+		if !strings.Contains(tag, ":") {
+			items = append(items, fmt.Sprintf("tag: %s: expected category:name", tag))
+		}
+	}
+	if len(items) > 0 {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     VMSelectorNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryCritical,
+			Message:  "The VM selector is not valid.",
+			Items:    items,
+		})
+		return
+	}
+	valid = true
+	return
+}
+
+// Validate the migration schedule (windows and blackouts).
+func (r *Reconciler) validateSchedule(plan *api.Plan) {
+	_, err := schedule.New(plan.Spec.Schedule)
+	if err != nil {
+		plan.Status.SetCondition(libcnd.Condition{
+			Type:     ScheduleNotValid,
+			Status:   True,
+			Reason:   NotValid,
+			Category: api.CategoryCritical,
+			Message:  "The migration schedule is not valid.",
+			Items:    []string{liberr.Unwrap(err).Error()},
+		})
+	}
+}
+
 func (r *Reconciler) validateVolumeNameTemplate(plan *api.Plan) error {
 	if err := r.IsValidVolumeNameTemplate(plan.Spec.VolumeNameTemplate); err != nil {
 		invalidPVCNameTemplate := libcnd.Condition{
@@ -739,6 +978,14 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "VM has disks with invalid sizes.",
 		Items:    []string{},
 	}
+	diskSelectionNotValid := libcnd.Condition{
+		Type:     DiskSelectionNotValid,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryCritical,
+		Message:  "VM disk selection is not valid: the root disk cannot be excluded and at least one disk must be migrated.",
+		Items:    []string{},
+	}
 	macConflicts := libcnd.Condition{
 		Type:     MacConflicts,
 		Status:   True,
@@ -762,6 +1009,14 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		Message:  "LUKS keys and Clevis cannot be configured together; Clevis will be used.",
 		Items:    []string{},
 	}
+	drsRuleNotInPlan := libcnd.Condition{
+		Type:     DrsRuleNotInPlan,
+		Status:   True,
+		Reason:   NotValid,
+		Category: api.CategoryWarn,
+		Message:  "VM is a member of DRS affinity rules that include VMs not in the plan. The rules are only enforced between the VMs migrated by the plan.",
+		Items:    []string{},
+	}
 
 	var sharedDisksConditions []libcnd.Condition
 	setOf := map[string]bool{}
@@ -844,6 +1099,9 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 				}
 			}
 		}
+		if vm, ok := v.(*vsphere.VM); ok {
+			drsRuleNotInPlan.Items = append(drsRuleNotInPlan.Items, drsRulesNotInPlan(plan, ref, vm)...)
+		}
 		if plan.Spec.Type == api.MigrationOnlyConversion {
 			if vm, ok := v.(*vsphere.VM); ok {
 				pvcs, err := r.getVmPVCs(plan, vm)
@@ -946,6 +1204,13 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 		if len(invalidSizes) > 0 {
 			invalidDiskSizes.Items = append(invalidDiskSizes.Items, ref.String())
 		}
+		ok, err = validator.DiskSelection(*ref)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			diskSelectionNotValid.Items = append(diskSelectionNotValid.Items, ref.String())
+		}
 
 		conflicts, err := validator.MacConflicts(*ref)
 		if err != nil {
@@ -1160,6 +1425,9 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if len(invalidDiskSizes.Items) > 0 {
 		plan.Status.SetCondition(invalidDiskSizes)
 	}
+	if len(diskSelectionNotValid.Items) > 0 {
+		plan.Status.SetCondition(diskSelectionNotValid)
+	}
 	if len(macConflicts.Items) > 0 {
 		plan.Status.SetCondition(macConflicts)
 	}
@@ -1169,9 +1437,42 @@ func (r *Reconciler) validateVM(plan *api.Plan) error {
 	if len(luksAndClevisIncompatibility.Items) > 0 {
 		plan.Status.SetCondition(luksAndClevisIncompatibility)
 	}
+	if len(drsRuleNotInPlan.Items) > 0 {
+		plan.Status.SetCondition(drsRuleNotInPlan)
+	}
 	return nil
 }
 
+// Find the enabled DRS affinity and anti-affinity rules of the
+// VM with members not in the plan. Returns an item for each rule
+// listing the missing members.
+func drsRulesNotInPlan(plan *api.Plan, ref *refapi.Ref, vm *vsphere.VM) (items []string) {
+	for _, rule := range vm.DrsRules {
+		if rule.Kind != vsmodel.DrsAffinity && rule.Kind != vsmodel.DrsAntiAffinity {
+			continue
+		}
+		if !rule.Enabled {
+			continue
+		}
+		missing := []string{}
+		for _, member := range rule.VMs {
+			if _, found := plan.Spec.FindVM(refapi.Ref{ID: member.ID}); !found {
+				missing = append(missing, member.ID)
+			}
+		}
+		if len(missing) > 0 {
+			items = append(
+				items,
+				fmt.Sprintf(
+					"%s rule:%s missing:%s",
+					ref.String(),
+					rule.Name,
+					strings.Join(missing, ",")))
+		}
+	}
+	return
+}
+
 // Return PersistentVolumeClaims associated with a VM.
 func (r *Reconciler) getVmPVCs(plan *api.Plan, vm *vsphere.VM) (pvcs []*core.PersistentVolumeClaim, err error) {
 	// Add VM uuid
@@ -1408,6 +1709,22 @@ func (r *Reconciler) validateVddkImage(plan *api.Plan) (err error) {
 			Message:  "VDDK image not set on the provider, this is required for the raw copy mode migration",
 		})
 	}
+	if vddkImage == "" && plan.SelectsDisks() {
+		useV2v, v2vErr := plan.ShouldUseV2vForTransfer()
+		if v2vErr != nil {
+			err = v2vErr
+			return
+		}
+		if useV2v {
+			plan.Status.SetCondition(libcnd.Condition{
+				Type:     VDDKInitImageUnavailable,
+				Status:   True,
+				Reason:   NotSet,
+				Category: api.CategoryCritical,
+				Message:  "VDDK image not set on the provider, this is required to select the disks transferred by virt-v2v",
+			})
+		}
+	}
 
 	return
 }
@@ -5,9 +5,12 @@ import (
 
 	k8snet "github.com/k8snetworkplumbingwg/network-attachment-definition-client/pkg/apis/k8s.cni.cncf.io/v1"
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
 	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	"github.com/kubev2v/forklift/pkg/controller/base"
+	vsmodel "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/vsphere"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
 	ginkgo "github.com/onsi/ginkgo/v2"
@@ -348,6 +351,208 @@ var _ = ginkgo.Describe("Plan Validations", func() {
 		})
 	})
 
+	ginkgo.Describe("validateReplication", func() {
+		ginkgo.It("should pass for a warm plan from vSphere", func() {
+			source := createProvider(sourceName, sourceNamespace, "https://source", api.VSphere, &core.ObjectReference{})
+			destination := createProvider(destName, destNamespace, "", api.OpenShift, &core.ObjectReference{})
+			plan := createPlan(testPlanName, testNamespace, source, destination)
+			plan.Spec.Type = api.MigrationWarm
+			plan.Spec.Replication = &planapi.Replication{}
+
+			reconciler = createFakeReconciler(plan, source, destination)
+			reconciler.validateReplication(plan)
+
+			gomega.Expect(plan.Status.HasCondition(ReplicationNotValid)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should fail for a cold plan", func() {
+			source := createProvider(sourceName, sourceNamespace, "https://source", api.VSphere, &core.ObjectReference{})
+			destination := createProvider(destName, destNamespace, "", api.OpenShift, &core.ObjectReference{})
+			plan := createPlan(testPlanName, testNamespace, source, destination)
+			plan.Spec.Replication = &planapi.Replication{}
+
+			reconciler = createFakeReconciler(plan, source, destination)
+			reconciler.validateReplication(plan)
+
+			gomega.Expect(plan.Status.HasCondition(ReplicationNotValid)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should fail for a warm plan from oVirt", func() {
+			source := createProvider(sourceName, sourceNamespace, "https://source", api.OVirt, &core.ObjectReference{})
+			destination := createProvider(destName, destNamespace, "", api.OpenShift, &core.ObjectReference{})
+			plan := createPlan(testPlanName, testNamespace, source, destination)
+			plan.Spec.Type = api.MigrationWarm
+			plan.Spec.Replication = &planapi.Replication{}
+
+			reconciler = createFakeReconciler(plan, source, destination)
+			reconciler.validateReplication(plan)
+
+			gomega.Expect(plan.Status.HasCondition(ReplicationNotValid)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("validateTargetMetadata", func() {
+		ginkgo.It("should pass for valid keys from vSphere", func() {
+			source := createProvider(sourceName, sourceNamespace, "https://source", api.VSphere, &core.ObjectReference{})
+			destination := createProvider(destName, destNamespace, "", api.OpenShift, &core.ObjectReference{})
+			plan := createPlan(testPlanName, testNamespace, source, destination)
+			plan.Spec.TargetMetadata = []planapi.MetadataMapping{
+				{Source: planapi.MetadataTag, Name: "env", Target: planapi.MetadataLabel, Key: "example.com/env"},
+			}
+
+			reconciler = createFakeReconciler(plan, source, destination)
+			reconciler.validateTargetMetadata(plan)
+
+			gomega.Expect(plan.Status.HasCondition(TargetMetadataNotValid)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should fail for an invalid key", func() {
+			source := createProvider(sourceName, sourceNamespace, "https://source", api.VSphere, &core.ObjectReference{})
+			destination := createProvider(destName, destNamespace, "", api.OpenShift, &core.ObjectReference{})
+			plan := createPlan(testPlanName, testNamespace, source, destination)
+			plan.Spec.TargetMetadata = []planapi.MetadataMapping{
+				{Source: planapi.MetadataAttribute, Name: "Cost Center", Target: planapi.MetadataAnnotation},
+			}
+
+			reconciler = createFakeReconciler(plan, source, destination)
+			reconciler.validateTargetMetadata(plan)
+
+			gomega.Expect(plan.Status.HasCondition(TargetMetadataNotValid)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should fail for an oVirt source", func() {
+			source := createProvider(sourceName, sourceNamespace, "https://source", api.OVirt, &core.ObjectReference{})
+			destination := createProvider(destName, destNamespace, "", api.OpenShift, &core.ObjectReference{})
+			plan := createPlan(testPlanName, testNamespace, source, destination)
+			plan.Spec.TargetMetadata = []planapi.MetadataMapping{
+				{Source: planapi.MetadataTag, Name: "env", Target: planapi.MetadataLabel},
+			}
+
+			reconciler = createFakeReconciler(plan, source, destination)
+			reconciler.validateTargetMetadata(plan)
+
+			gomega.Expect(plan.Status.HasCondition(TargetMetadataNotValid)).To(gomega.BeTrue())
+		})
+	})
+
+	ginkgo.Describe("validateVMSelector", func() {
+		ginkgo.It("should pass for a vSphere source", func() {
+			source := createProvider(sourceName, sourceNamespace, "https://source", api.VSphere, &core.ObjectReference{})
+			destination := createProvider(destName, destNamespace, "", api.OpenShift, &core.ObjectReference{})
+			plan := createPlan(testPlanName, testNamespace, source, destination)
+			plan.Spec.VMSelector = &planapi.VMSelector{Name: "^web-", Tags: []string{"env:prod"}}
+
+			reconciler = createFakeReconciler(plan, source, destination)
+
+			gomega.Expect(reconciler.validateVMSelector(plan)).To(gomega.BeTrue())
+			gomega.Expect(plan.Status.HasCondition(VMSelectorNotValid)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("should fail for an invalid name pattern, path or tag", func() {
+			source := createProvider(sourceName, sourceNamespace, "https://source", api.VSphere, &core.ObjectReference{})
+			destination := createProvider(destName, destNamespace, "", api.OpenShift, &core.ObjectReference{})
+			plan := createPlan(testPlanName, testNamespace, source, destination)
+			plan.Spec.VMSelector = &planapi.VMSelector{Name: "web-(", Path: "DC0/vm", Tags: []string{"prod"}}
+
+			reconciler = createFakeReconciler(plan, source, destination)
+
+			gomega.Expect(reconciler.validateVMSelector(plan)).To(gomega.BeFalse())
+			gomega.Expect(plan.Status.FindCondition(VMSelectorNotValid).Items).To(gomega.HaveLen(3))
+		})
+
+		ginkgo.It("should fail for an oVirt source", func() {
+			source := createProvider(sourceName, sourceNamespace, "https://source", api.OVirt, &core.ObjectReference{})
+			destination := createProvider(destName, destNamespace, "", api.OpenShift, &core.ObjectReference{})
+			plan := createPlan(testPlanName, testNamespace, source, destination)
+			plan.Spec.VMSelector = &planapi.VMSelector{}
+
+			reconciler = createFakeReconciler(plan, source, destination)
+
+			gomega.Expect(reconciler.validateVMSelector(plan)).To(gomega.BeFalse())
+		})
+	})
+
+	ginkgo.Describe("validateVddkImage", func() {
+		selectingPlan := func() *api.Plan {
+			source := createProvider(sourceName, sourceNamespace, "https://source", api.VSphere, &core.ObjectReference{})
+			destination := createProvider(destName, destNamespace, "", api.OpenShift, &core.ObjectReference{})
+			plan := createPlan(testPlanName, testNamespace, source, destination)
+			plan.Spec.MigrateSharedDisks = true
+			plan.Spec.VMs = []planapi.VM{
+				{
+					Ref: ref.Ref{ID: "vm-1"},
+					Disks: &planapi.DiskSelector{
+						Exclude: []planapi.DiskMatch{{Label: "Hard disk 2"}},
+					},
+				},
+			}
+			return plan
+		}
+
+		ginkgo.It("should require the VDDK to select the disks transferred by virt-v2v", func() {
+			plan := selectingPlan()
+
+			reconciler = createFakeReconciler(plan, plan.Referenced.Provider.Source, plan.Referenced.Provider.Destination)
+			err := reconciler.validateVddkImage(plan)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(plan.Status.HasCondition(VDDKInitImageUnavailable)).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should not require the VDDK when the disks are transferred by CDI", func() {
+			plan := selectingPlan()
+			plan.Spec.Type = api.MigrationOnlyConversion
+
+			reconciler = createFakeReconciler(plan, plan.Referenced.Provider.Source, plan.Referenced.Provider.Destination)
+			err := reconciler.validateVddkImage(plan)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(plan.Status.HasCondition(VDDKInitImageUnavailable)).To(gomega.BeFalse())
+		})
+	})
+
+	ginkgo.Describe("drsRulesNotInPlan", func() {
+		ginkgo.It("should report the members not in the plan", func() {
+			plan := &api.Plan{}
+			plan.Spec.VMs = []planapi.VM{
+				{Ref: ref.Ref{ID: "vm-1"}},
+				{Ref: ref.Ref{ID: "vm-2"}},
+			}
+			vm := &vsphere.VM{}
+			vm.ID = "vm-1"
+			vm.DrsRules = []vsmodel.DrsRule{
+				{
+					Name:    "together",
+					Kind:    vsmodel.DrsAffinity,
+					Enabled: true,
+					VMs:     []vsmodel.Ref{{ID: "vm-1"}, {ID: "vm-2"}},
+				},
+				{
+					Name:    "apart",
+					Kind:    vsmodel.DrsAntiAffinity,
+					Enabled: true,
+					VMs:     []vsmodel.Ref{{ID: "vm-1"}, {ID: "vm-3"}, {ID: "vm-4"}},
+				},
+				{
+					Name: "disabled",
+					Kind: vsmodel.DrsAffinity,
+					VMs:  []vsmodel.Ref{{ID: "vm-1"}, {ID: "vm-5"}},
+				},
+				{
+					Name:    "host",
+					Kind:    vsmodel.DrsVmHostAffinity,
+					Enabled: true,
+					VMs:     []vsmodel.Ref{{ID: "vm-1"}, {ID: "vm-6"}},
+				},
+			}
+
+			items := drsRulesNotInPlan(plan, &plan.Spec.VMs[0].Ref, vm)
+
+			gomega.Expect(items).To(gomega.HaveLen(1))
+			gomega.Expect(items[0]).To(gomega.ContainSubstring("rule:apart missing:vm-3,vm-4"))
+		})
+	})
+
 	ginkgo.Describe("validateConversionTempStorage", func() {
 		ginkgo.It("should pass when both fields are set", func() {
 			secret := createSecret(sourceSecretName, sourceNamespace, false)
@@ -0,0 +1,284 @@
+package wave
+
+import (
+	"fmt"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+)
+
+// Default time to wait for the migrated
+// VMs to become healthy.
+const DefaultHealthTimeout = 30 * time.Minute
+
+// Determine whether a migrated VM is healthy
+// on the destination.
+type HealthFunc func(vm *planapi.VMStatus) (healthy bool, err error)
+
+// Migration waves built from the plan.
+// Determines which VMs may be started based on the
+// wave dependencies and reflects the wave status.
+type Waves struct {
+	// Plan.
+	plan *api.Plan
+	// Waves in dependency (topological) order.
+	ordered []*planapi.Wave
+	// Waves by name.
+	byName map[string]*planapi.Wave
+}
+
+// Build the waves for the plan.
+// Returns an error when a wave name is missing or duplicated,
+// a dependency or a VM wave is not defined or the
+// dependencies contain a cycle.
+func New(plan *api.Plan) (waves *Waves, err error) {
+	waves = &Waves{
+		plan:   plan,
+		byName: make(map[string]*planapi.Wave),
+	}
+	for i := range plan.Spec.Waves {
+		wave := &plan.Spec.Waves[i]
+		if wave.Name == "" {
+			err = liberr.New("wave name required.")
+			return
+		}
+		if _, found := waves.byName[wave.Name]; found {
+			err = liberr.New("wave name not unique.", "wave", wave.Name)
+			return
+		}
+		waves.byName[wave.Name] = wave
+	}
+	for _, wave := range waves.byName {
+		for _, name := range wave.DependsOn {
+			if _, found := waves.byName[name]; !found {
+				err = liberr.New("wave dependency not found.", "wave", wave.Name, "dependsOn", name)
+				return
+			}
+		}
+	}
+	for _, vm := range plan.Spec.VMs {
+		if vm.Wave == "" {
+			continue
+		}
+		if _, found := waves.byName[vm.Wave]; !found {
+			err = liberr.New("VM wave not found.", "vm", vm.String(), "wave", vm.Wave)
+			return
+		}
+	}
+	err = waves.sort()
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Waves are defined.
+func (r *Waves) Enabled() bool {
+	return len(r.ordered) > 0
+}
+
+// Sort the waves in dependency order.
+// Waves are visited in the order they are listed on the plan.
+func (r *Waves) sort() (err error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int)
+	var visit func(wave *planapi.Wave) error
+	visit = func(wave *planapi.Wave) error {
+		switch state[wave.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return liberr.New("wave dependencies contain a cycle.", "wave", wave.Name)
+		}
+		state[wave.Name] = visiting
+		for _, name := range wave.DependsOn {
+			if err := visit(r.byName[name]); err != nil {
+				return err
+			}
+		}
+		state[wave.Name] = visited
+		r.ordered = append(r.ordered, wave)
+		return nil
+	}
+	for i := range r.plan.Spec.Waves {
+		err = visit(&r.plan.Spec.Waves[i])
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Reflect the status of the waves on the plan.
+// The health of the VMs is only checked for waves
+// with all VMs completed that wait until healthy.
+func (r *Waves) Update(healthy HealthFunc) (err error) {
+	if !r.Enabled() {
+		r.plan.Status.Migration.Waves = nil
+		return
+	}
+	statusList := []planapi.WaveStatus{}
+	for _, wave := range r.ordered {
+		status := planapi.WaveStatus{Name: wave.Name}
+		if current, found := r.plan.Status.Migration.FindWave(wave.Name); found {
+			status.Timed = current.Timed
+		}
+		err = r.update(wave, &status, statusList, healthy)
+		if err != nil {
+			return
+		}
+		statusList = append(statusList, status)
+	}
+	r.plan.Status.Migration.Waves = statusList
+
+	return
+}
+
+// Update the status of a wave.
+func (r *Waves) update(
+	wave *planapi.Wave,
+	status *planapi.WaveStatus,
+	upstream []planapi.WaveStatus,
+	healthy HealthFunc) (err error) {
+	var lastCompleted time.Time
+	vms := r.vms(wave.Name)
+	for _, vm := range vms {
+		status.VMs++
+		switch {
+		case vm.HasCondition(api.ConditionCanceled):
+			status.Canceled++
+		case vm.HasCondition(api.ConditionFailed):
+			status.Failed++
+		case vm.HasCondition(api.ConditionSucceeded):
+			status.Succeeded++
+		case vm.Running():
+			status.Running++
+		}
+		if vm.MarkedCompleted() && vm.Completed.After(lastCompleted) {
+			lastCompleted = vm.Completed.Time
+		}
+	}
+	for _, name := range wave.DependsOn {
+		for _, dependency := range upstream {
+			if dependency.Name != name {
+				continue
+			}
+			switch dependency.Phase {
+			case planapi.WaveFailed, planapi.WaveBlocked:
+				status.Phase = planapi.WaveBlocked
+				status.Message = fmt.Sprintf("Wave '%s' did not succeed.", name)
+				status.MarkCompleted()
+				return
+			case planapi.WaveSucceeded:
+			default:
+				status.Phase = planapi.WavePending
+				status.Message = fmt.Sprintf("Waiting for wave '%s'.", name)
+				return
+			}
+		}
+	}
+	status.MarkStarted()
+	for _, vm := range vms {
+		if !vm.MarkedCompleted() {
+			status.Phase = planapi.WaveRunning
+			return
+		}
+	}
+	if status.Failed > 0 {
+		status.Phase = planapi.WaveFailed
+		status.Message = fmt.Sprintf("%d VM(s) failed.", status.Failed)
+		status.MarkCompleted()
+		return
+	}
+	if wave.WaitUntilHealthy && status.Succeeded > 0 {
+		for _, vm := range vms {
+			if !vm.HasCondition(api.ConditionSucceeded) {
+				continue
+			}
+			var ok bool
+			ok, err = healthy(vm)
+			if err != nil {
+				return
+			}
+			if ok {
+				continue
+			}
+			timeout := DefaultHealthTimeout
+			if wave.HealthTimeout != nil {
+				timeout = wave.HealthTimeout.Duration
+			}
+			if time.Since(lastCompleted) > timeout {
+				status.Phase = planapi.WaveFailed
+				status.Message = fmt.Sprintf("VM '%s' did not become healthy.", vm.String())
+				status.MarkCompleted()
+				return
+			}
+			status.Phase = planapi.WaveVerifying
+			status.Message = fmt.Sprintf("Waiting for VM '%s' to become healthy.", vm.String())
+			return
+		}
+	}
+	status.Phase = planapi.WaveSucceeded
+	status.MarkCompleted()
+
+	return
+}
+
+// The VM may be started.
+// VMs not assigned to a wave may always be started.
+// Otherwise the wave must be running and below its
+// concurrency limit.
+func (r *Waves) Schedulable(vm *planapi.VMStatus) bool {
+	if vm.Wave == "" {
+		return true
+	}
+	wave, found := r.byName[vm.Wave]
+	if !found {
+		return false
+	}
+	status, found := r.plan.Status.Migration.FindWave(vm.Wave)
+	if !found || status.Phase != planapi.WaveRunning {
+		return false
+	}
+	if wave.MaxInFlight > 0 {
+		running := 0
+		for _, other := range r.vms(vm.Wave) {
+			if other.Running() {
+				running++
+			}
+		}
+		if running >= wave.MaxInFlight {
+			return false
+		}
+	}
+
+	return true
+}
+
+// The VM belongs to a blocked wave.
+func (r *Waves) Blocked(vm *planapi.VMStatus) (blocked bool, status *planapi.WaveStatus) {
+	if vm.Wave == "" {
+		return
+	}
+	status, found := r.plan.Status.Migration.FindWave(vm.Wave)
+	blocked = found && status.Phase == planapi.WaveBlocked
+	return
+}
+
+// The VMs assigned to the wave.
+func (r *Waves) vms(name string) (vms []*planapi.VMStatus) {
+	for _, vm := range r.plan.Status.Migration.VMs {
+		if vm.Wave == name {
+			vms = append(vms, vm)
+		}
+	}
+
+	return
+}
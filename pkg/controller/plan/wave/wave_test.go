@@ -0,0 +1,187 @@
+package wave
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	planapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/plan"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
+	"github.com/onsi/gomega"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func vmStatus(id, wave string) *planapi.VMStatus {
+	return &planapi.VMStatus{
+		VM: planapi.VM{
+			Ref:  ref.Ref{ID: id},
+			Wave: wave,
+		},
+	}
+}
+
+func succeeded(vm *planapi.VMStatus) {
+	vm.MarkCompleted()
+	vm.SetCondition(libcnd.Condition{Type: api.ConditionSucceeded, Status: libcnd.True})
+}
+
+func failed(vm *planapi.VMStatus) {
+	vm.MarkCompleted()
+	vm.SetCondition(libcnd.Condition{Type: api.ConditionFailed, Status: libcnd.True})
+}
+
+func testPlan(vms ...*planapi.VMStatus) *api.Plan {
+	plan := &api.Plan{}
+	plan.Spec.Waves = []planapi.Wave{
+		{Name: "frontend", DependsOn: []string{"app"}},
+		{Name: "db", MaxInFlight: 1},
+		{Name: "app", DependsOn: []string{"db"}, WaitUntilHealthy: true},
+	}
+	for _, vm := range vms {
+		plan.Spec.VMs = append(plan.Spec.VMs, vm.VM)
+	}
+	plan.Status.Migration.VMs = vms
+	return plan
+}
+
+func phases(plan *api.Plan) map[string]string {
+	phases := map[string]string{}
+	for _, status := range plan.Status.Migration.Waves {
+		phases[status.Name] = status.Phase
+	}
+	return phases
+}
+
+func TestWavesOrder(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	waves, err := New(testPlan())
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(waves.Enabled()).To(gomega.BeTrue())
+	names := []string{}
+	for _, wave := range waves.ordered {
+		names = append(names, wave.Name)
+	}
+	g.Expect(names).To(gomega.Equal([]string{"db", "app", "frontend"}))
+
+	waves, err = New(&api.Plan{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(waves.Enabled()).To(gomega.BeFalse())
+}
+
+func TestWavesNotValid(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	plans := []*api.Plan{
+		{Spec: api.PlanSpec{Waves: []planapi.Wave{{}}}},
+		{Spec: api.PlanSpec{Waves: []planapi.Wave{{Name: "a"}, {Name: "a"}}}},
+		{Spec: api.PlanSpec{Waves: []planapi.Wave{{Name: "a", DependsOn: []string{"b"}}}}},
+		{Spec: api.PlanSpec{Waves: []planapi.Wave{
+			{Name: "a", DependsOn: []string{"b"}},
+			{Name: "b", DependsOn: []string{"a"}},
+		}}},
+		testPlan(vmStatus("vm-1", "missing")),
+	}
+	for _, plan := range plans {
+		_, err := New(plan)
+		g.Expect(err).To(gomega.HaveOccurred())
+	}
+}
+
+func TestWavesUpdate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	db1 := vmStatus("db-1", "db")
+	db2 := vmStatus("db-2", "db")
+	app := vmStatus("app-1", "app")
+	web := vmStatus("web-1", "frontend")
+	other := vmStatus("other-1", "")
+	plan := testPlan(db1, db2, app, web, other)
+	waves, err := New(plan)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	healthy := false
+	health := func(vm *planapi.VMStatus) (bool, error) {
+		return healthy, nil
+	}
+
+	g.Expect(waves.Update(health)).To(gomega.Succeed())
+	g.Expect(phases(plan)).To(gomega.Equal(map[string]string{
+		"db":       planapi.WaveRunning,
+		"app":      planapi.WavePending,
+		"frontend": planapi.WavePending,
+	}))
+	g.Expect(waves.Schedulable(db1)).To(gomega.BeTrue())
+	g.Expect(waves.Schedulable(app)).To(gomega.BeFalse())
+	g.Expect(waves.Schedulable(other)).To(gomega.BeTrue())
+
+	// Per wave concurrency.
+	db1.MarkStarted()
+	g.Expect(waves.Schedulable(db2)).To(gomega.BeFalse())
+
+	succeeded(db1)
+	succeeded(db2)
+	g.Expect(waves.Update(health)).To(gomega.Succeed())
+	g.Expect(phases(plan)["db"]).To(gomega.Equal(planapi.WaveSucceeded))
+	g.Expect(phases(plan)["app"]).To(gomega.Equal(planapi.WaveRunning))
+	status, found := plan.Status.Migration.FindWave("db")
+	g.Expect(found).To(gomega.BeTrue())
+	g.Expect(status.VMs).To(gomega.Equal(2))
+	g.Expect(status.Succeeded).To(gomega.Equal(2))
+
+	// Waiting until healthy.
+	succeeded(app)
+	g.Expect(waves.Update(health)).To(gomega.Succeed())
+	g.Expect(phases(plan)["app"]).To(gomega.Equal(planapi.WaveVerifying))
+	g.Expect(phases(plan)["frontend"]).To(gomega.Equal(planapi.WavePending))
+	healthy = true
+	g.Expect(waves.Update(health)).To(gomega.Succeed())
+	g.Expect(phases(plan)["app"]).To(gomega.Equal(planapi.WaveSucceeded))
+	g.Expect(phases(plan)["frontend"]).To(gomega.Equal(planapi.WaveRunning))
+	g.Expect(waves.Schedulable(web)).To(gomega.BeTrue())
+}
+
+func TestWavesBlocked(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	db := vmStatus("db-1", "db")
+	app := vmStatus("app-1", "app")
+	web := vmStatus("web-1", "frontend")
+	plan := testPlan(db, app, web)
+	waves, err := New(plan)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	failed(db)
+	g.Expect(waves.Update(nil)).To(gomega.Succeed())
+	g.Expect(phases(plan)).To(gomega.Equal(map[string]string{
+		"db":       planapi.WaveFailed,
+		"app":      planapi.WaveBlocked,
+		"frontend": planapi.WaveBlocked,
+	}))
+	blocked, status := waves.Blocked(web)
+	g.Expect(blocked).To(gomega.BeTrue())
+	g.Expect(status.Name).To(gomega.Equal("frontend"))
+	g.Expect(waves.Schedulable(web)).To(gomega.BeFalse())
+}
+
+func TestWavesHealthTimeout(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	db := vmStatus("db-1", "db")
+	app := vmStatus("app-1", "app")
+	plan := testPlan(db, app)
+	plan.Spec.Waves[2].HealthTimeout = &meta.Duration{Duration: time.Minute}
+	waves, err := New(plan)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+
+	succeeded(db)
+	succeeded(app)
+	completed := meta.NewTime(time.Now().Add(-2 * time.Minute))
+	app.Completed = &completed
+	g.Expect(waves.Update(func(*planapi.VMStatus) (bool, error) {
+		return false, nil
+	})).To(gomega.Succeed())
+	g.Expect(phases(plan)["app"]).To(gomega.Equal(planapi.WaveFailed))
+	g.Expect(phases(plan)["frontend"]).To(gomega.Equal(planapi.WaveBlocked))
+}
@@ -0,0 +1,242 @@
+package container
+
+import (
+	"reflect"
+	"time"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	ovirt "github.com/kubev2v/forklift/pkg/controller/provider/model/ovirt"
+	vsphere "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	"github.com/kubev2v/forklift/pkg/lib/ref"
+	"github.com/kubev2v/forklift/pkg/settings"
+)
+
+// Application settings.
+var Settings = &settings.Settings
+
+// The retained changes are pruned at most once per interval.
+const PruneInterval = time.Minute
+
+// Bookkeeping and derived (validation) fields
+// not reported as changed.
+var ignoredFields = map[string]bool{
+	"Revision":          true,
+	"RevisionValidated": true,
+	"PolicyVersion":     true,
+	"Concerns":          true,
+}
+
+// Build the change history recorder for the provider.
+// Returns nil when the provider does not retain changes.
+func NewHistory(provider *api.Provider, collector libcontainer.Collector) (h *History) {
+	var model libmodel.Model
+	switch provider.Type() {
+	case api.VSphere:
+		model = &vsphere.VM{}
+	case api.OVirt:
+		model = &ovirt.VM{}
+	default:
+		return
+	}
+	h = &History{
+		Collector: collector,
+		Model:     model,
+		Age:       time.Duration(Settings.Inventory.History.Age) * time.Hour,
+		Limit:     Settings.Inventory.History.Limit,
+	}
+	return
+}
+
+// Change history recorder.
+// Records the changes to the watched models with field-level
+// diffs. The changes made before the collector has parity (the
+// initial load) are not recorded. The retained changes are
+// pruned by age and count.
+type History struct {
+	libmodel.StockEventHandler
+	// Collector.
+	Collector libcontainer.Collector
+	// The (kind of) model recorded.
+	Model libmodel.Model
+	// Max age of the retained changes.
+	Age time.Duration
+	// Max number of retained changes.
+	Limit int
+	// Serial number of the last change.
+	serial int64
+	// Last pruned.
+	pruned time.Time
+	// Logger.
+	log logging.LevelLogger
+}
+
+// Start recording.
+// The watch is ended when the DB is closed.
+func (r *History) Start() (err error) {
+	r.log = logging.WithName("history").WithValues(
+		"provider",
+		r.Collector.Name())
+	db := r.Collector.DB()
+	last := []base.Change{}
+	err = db.List(
+		&last,
+		libmodel.ListOptions{
+			SortBy: []string{"-ID"},
+			Page:   &libmodel.Page{Limit: 1},
+		})
+	if err != nil {
+		return
+	}
+	if len(last) > 0 {
+		r.serial = last[0].ID
+	}
+	_, err = db.Watch(r.Model, r)
+	if err != nil {
+		return
+	}
+
+	r.log.V(3).Info("history started.")
+
+	return
+}
+
+// A model has been created.
+func (r *History) Created(event libmodel.Event) {
+	r.record(event.Model, base.ChangeCreated, nil)
+}
+
+// A model has been updated.
+func (r *History) Updated(event libmodel.Event) {
+	fields := Diff(event.Model, event.Updated)
+	if len(fields) == 0 {
+		return
+	}
+	r.record(event.Updated, base.ChangeUpdated, fields)
+}
+
+// A model has been deleted.
+func (r *History) Deleted(event libmodel.Event) {
+	r.record(event.Model, base.ChangeDeleted, nil)
+}
+
+// An error has occurred delivering an event.
+func (r *History) Error(err error) {
+	r.log.Error(err, "history event failed.")
+}
+
+// Record the change.
+func (r *History) record(m libmodel.Model, action string, fields []base.FieldChange) {
+	if !r.Collector.HasParity() {
+		return
+	}
+	r.serial++
+	change := &base.Change{
+		ID:      r.serial,
+		Kind:    ref.ToKind(m),
+		Subject: m.Pk(),
+		Action:  action,
+		Time:    time.Now().UnixMilli(),
+		Fields:  fields,
+	}
+	err := r.Collector.DB().Insert(change)
+	if err != nil {
+		r.log.Error(err, "change not recorded.")
+		return
+	}
+	if time.Since(r.pruned) > PruneInterval {
+		err = r.prune()
+		if err != nil {
+			r.log.Error(err, "history not pruned.")
+		}
+	}
+}
+
+// Delete the changes older than the max age
+// and exceeding the max count.
+func (r *History) prune() (err error) {
+	r.pruned = time.Now()
+	deleted := 0
+	predicate := libmodel.Or(
+		libmodel.Lt("Time", time.Now().Add(-r.Age).UnixMilli()),
+		libmodel.Lt("ID", r.serial-int64(r.Limit)+1))
+	err = r.Collector.DB().With(
+		func(tx *libmodel.Tx) (err error) {
+			itr, err := tx.Find(&base.Change{}, libmodel.ListOptions{Predicate: predicate})
+			if err != nil {
+				return
+			}
+			for {
+				object, hasNext := itr.Next()
+				if !hasNext {
+					break
+				}
+				err = tx.Delete(object.(*base.Change))
+				if err != nil {
+					return
+				}
+				deleted++
+			}
+			return
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	r.log.V(3).Info(
+		"history pruned.",
+		"deleted",
+		deleted)
+
+	return
+}
+
+// Diff the fields of the former and updated model.
+// Fields of embedded structs are diffed as fields
+// of the model.
+func Diff(former, updated interface{}) (fields []base.FieldChange) {
+	fv := reflect.Indirect(reflect.ValueOf(former))
+	uv := reflect.Indirect(reflect.ValueOf(updated))
+	if fv.Kind() != reflect.Struct || fv.Type() != uv.Type() {
+		return
+	}
+	for i := 0; i < fv.NumField(); i++ {
+		ft := fv.Type().Field(i)
+		if !ft.IsExported() || ignoredFields[ft.Name] {
+			continue
+		}
+		if ft.Anonymous {
+			fields = append(fields, Diff(fv.Field(i).Interface(), uv.Field(i).Interface())...)
+			continue
+		}
+		if equal(fv.Field(i), uv.Field(i)) {
+			continue
+		}
+		fields = append(
+			fields,
+			base.FieldChange{
+				Name: ft.Name,
+				Old:  fv.Field(i).Interface(),
+				New:  uv.Field(i).Interface(),
+			})
+	}
+
+	return
+}
+
+// Compare field values.
+// Empty and nil slices (and maps) are equal.
+func equal(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Slice, reflect.Map:
+		if a.Len() == 0 && b.Len() == 0 {
+			return true
+		}
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
@@ -0,0 +1,111 @@
+package container
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/onsi/gomega"
+)
+
+type fakeCollector struct {
+	libcontainer.Collector
+	db     libmodel.DB
+	parity bool
+}
+
+func (r *fakeCollector) Name() string {
+	return "test"
+}
+
+func (r *fakeCollector) DB() libmodel.DB {
+	return r.db
+}
+
+func (r *fakeCollector) HasParity() bool {
+	return r.parity
+}
+
+func TestDiff(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	former := &model.VM{
+		Base:     model.Base{ID: "vm-1", Name: "web", Revision: 1},
+		Host:     "host-1",
+		CpuCount: 2,
+		Disks:    []model.Disk{{File: "a.vmdk"}},
+	}
+	updated := &model.VM{
+		Base:     model.Base{ID: "vm-1", Name: "web-1", Revision: 2},
+		Host:     "host-2",
+		CpuCount: 2,
+		Disks:    []model.Disk{{File: "a.vmdk"}},
+		NICs:     []model.NIC{},
+		Concerns: []model.Concern{{Id: "a"}},
+	}
+	g.Expect(Diff(former, updated)).To(gomega.Equal([]base.FieldChange{
+		{Name: "Name", Old: "web", New: "web-1"},
+		{Name: "Host", Old: "host-1", New: "host-2"},
+	}))
+	updated.Disks = append(updated.Disks, model.Disk{File: "b.vmdk"})
+	fields := Diff(former, updated)
+	g.Expect(fields).To(gomega.HaveLen(3))
+	g.Expect(fields[2].Name).To(gomega.Equal("Disks"))
+}
+
+func TestHistory(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	db := libmodel.New("/tmp/test-history.db", model.All()...)
+	g.Expect(db.Open(true)).To(gomega.Succeed())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	collector := &fakeCollector{db: db, parity: true}
+	history := &History{
+		Collector: collector,
+		Model:     &model.VM{},
+		Age:       time.Hour,
+		Limit:     2,
+	}
+	g.Expect(history.Start()).To(gomega.Succeed())
+	changes := func() []base.Change {
+		list := []base.Change{}
+		g.Expect(db.List(&list, libmodel.ListOptions{SortBy: []string{"ID"}, Detail: libmodel.MaxDetail})).To(gomega.Succeed())
+		return list
+	}
+	// Created.
+	vm := &model.VM{Base: model.Base{ID: "vm-1"}, Host: "host-1"}
+	g.Expect(db.Insert(vm)).To(gomega.Succeed())
+	// Updated.
+	vm.Host = "host-2"
+	g.Expect(db.Update(vm)).To(gomega.Succeed())
+	g.Eventually(changes).Should(gomega.HaveLen(2))
+	g.Expect(changes()[0].Action).To(gomega.Equal(base.ChangeCreated))
+	change := changes()[1]
+	g.Expect(change.Kind).To(gomega.Equal("VM"))
+	g.Expect(change.Subject).To(gomega.Equal("vm-1"))
+	g.Expect(change.Action).To(gomega.Equal(base.ChangeUpdated))
+	g.Expect(change.Fields).To(gomega.Equal([]base.FieldChange{
+		{Name: "Host", Old: "host-1", New: "host-2"},
+	}))
+	// Pruned by count.
+	vm.CpuCount = 4
+	g.Expect(db.Update(vm)).To(gomega.Succeed())
+	g.Expect(db.Delete(vm)).To(gomega.Succeed())
+	g.Eventually(changes).Should(gomega.HaveLen(4))
+	g.Expect(history.prune()).To(gomega.Succeed())
+	list := changes()
+	g.Expect(list).To(gomega.HaveLen(2))
+	g.Expect(list[0].ID).To(gomega.Equal(int64(3)))
+	g.Expect(list[1].Action).To(gomega.Equal(base.ChangeDeleted))
+	// Resumed from the last change.
+	resumed := &History{Collector: collector, Model: &model.VM{}}
+	g.Expect(resumed.Start()).To(gomega.Succeed())
+	g.Expect(resumed.serial).To(gomega.Equal(int64(4)))
+	// Not recorded before parity (initial load).
+	collector.parity = false
+	resumed.Created(libmodel.Event{Model: vm})
+	g.Expect(resumed.serial).To(gomega.Equal(int64(4)))
+}
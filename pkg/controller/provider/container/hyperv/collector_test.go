@@ -0,0 +1,191 @@
+//nolint:errcheck
+package hyperv
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	inventory "github.com/kubev2v/forklift/cmd/ova-provider-server/hyperv"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/hyperv"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const vmConfig = `<?xml version="1.0" encoding="UTF-8"?>
+<configuration>
+  <properties>
+    <global_id type="string">4A2C9B2E-1F0D-4F7C-8E3A-5B6C7D8E9F00</global_id>
+    <name type="string">db-01</name>
+    <subtype type="integer">0</subtype>
+  </properties>
+  <settings>
+    <memory><bank><size type="integer">2048</size></bank></memory>
+    <processors><count type="integer">2</count></processors>
+  </settings>
+  <_83f8638b-8dca-4152-9eda-2ca8b33039b4_0_>
+    <controller0>
+      <drive0>
+        <pathname type="string">D:\VMs\db-01\Virtual Hard Disks\db-01.vhd</pathname>
+        <type type="string">VHD</type>
+      </drive0>
+    </controller0>
+  </_83f8638b-8dca-4152-9eda-2ca8b33039b4_0_>
+  <_a1b2c3d4-0000-0000-0000-000000000000_0_>
+    <address type="string">00155D112233</address>
+    <ChannelInstanceGuid type="string">{11111111-0000-0000-0000-000000000000}</ChannelInstanceGuid>
+    <Connection><AltSwitchName type="string">Internal</AltSwitchName></Connection>
+  </_a1b2c3d4-0000-0000-0000-000000000000_0_>
+</configuration>
+`
+
+// Serve the inventory for the export directory the way
+// the provider server does.
+func inventoryServer(root string) *httptest.Server {
+	mux := http.NewServeMux()
+	list := func(build func(vms []inventory.VM) interface{}) http.HandlerFunc {
+		return func(w http.ResponseWriter, _ *http.Request) {
+			vms, err := inventory.Scan(root)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			b, _ := json.Marshal(build(vms))
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(b)
+		}
+	}
+	mux.HandleFunc("/test_connection", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`"Test connection successful"`))
+	})
+	mux.HandleFunc("/vms", list(func(vms []inventory.VM) interface{} { return vms }))
+	mux.HandleFunc("/networks", list(func(vms []inventory.VM) interface{} { return inventory.Networks(vms) }))
+	mux.HandleFunc("/disks", list(func(vms []inventory.VM) interface{} { return inventory.Disks(vms) }))
+	return httptest.NewServer(mux)
+}
+
+// Write an exported VM.
+func writeExport(root string) {
+	export := filepath.Join(root, "db-01")
+	config := filepath.Join(export, inventory.VirtualMachinesDir, "4A2C9B2E-1F0D-4F7C-8E3A-5B6C7D8E9F00.xml")
+	disk := filepath.Join(export, inventory.VirtualHardDisksDir, "db-01.vhd")
+	Expect(os.MkdirAll(filepath.Dir(config), 0755)).To(Succeed())
+	Expect(os.MkdirAll(filepath.Dir(disk), 0755)).To(Succeed())
+	Expect(os.WriteFile(config, []byte(vmConfig), 0644)).To(Succeed())
+	old := time.Now().Add(-time.Hour)
+	Expect(os.Chtimes(config, old, old)).To(Succeed())
+	footer := make([]byte, 512)
+	copy(footer, "conectix")
+	binary.BigEndian.PutUint64(footer[48:], 8<<30)
+	Expect(os.WriteFile(disk, footer, 0644)).To(Succeed())
+}
+
+var _ = ginkgo.Describe("hyperv collector", func() {
+	var (
+		root      string
+		server    *httptest.Server
+		db        libmodel.DB
+		collector *Collector
+		ctx       *Context
+	)
+
+	ginkgo.BeforeEach(func() {
+		var err error
+		root, err = os.MkdirTemp("", "hyperv")
+		Expect(err).ToNot(HaveOccurred())
+		writeExport(root)
+		server = inventoryServer(root)
+		db = libmodel.New(
+			filepath.Join(root, "inventory.db"),
+			&ocp.Provider{},
+			&model.VM{},
+			&model.Network{},
+			&model.Disk{},
+			&model.Storage{})
+		Expect(db.Open(true)).To(Succeed())
+		provider := &api.Provider{
+			ObjectMeta: meta.ObjectMeta{Namespace: "test", Name: "hyperv"},
+			Spec: api.ProviderSpec{
+				Type: ptr.To(api.HyperV),
+				URL:  "nfs.example.com:/exports",
+			},
+		}
+		collector = New(db, provider, &core.Secret{})
+		collector.client.serviceURL = server.URL
+		Expect(collector.client.Connect(provider)).To(Succeed())
+		ctx = &Context{
+			ctx:    context.Background(),
+			client: collector.client,
+			db:     db,
+			log:    collector.log,
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+		_ = db.Close(true)
+		_ = os.RemoveAll(root)
+	})
+
+	ginkgo.It("should load the exported VMs", func() {
+		Expect(collector.load(ctx)).To(Succeed())
+		vm := &model.VM{Base: model.Base{ID: "4a2c9b2e-1f0d-4f7c-8e3a-5b6c7d8e9f00"}}
+		Expect(db.Get(vm)).To(Succeed())
+		Expect(vm.Name).To(Equal("db-01"))
+		Expect(vm.Generation).To(Equal(1))
+		Expect(vm.Firmware).To(Equal(inventory.BIOS))
+		Expect(vm.CpuCount).To(Equal(int32(2)))
+		Expect(vm.MemoryMB).To(Equal(int32(2048)))
+		Expect(vm.Disks).To(HaveLen(1))
+		Expect(vm.Disks[0].Bus).To(Equal(inventory.IDE))
+		Expect(vm.Disks[0].Format).To(Equal(inventory.VHD))
+		Expect(vm.Disks[0].Capacity).To(Equal(int64(8 << 30)))
+		Expect(vm.NICs).To(HaveLen(1))
+		Expect(vm.NICs[0].MAC).To(Equal("00:15:5d:11:22:33"))
+		networks := []model.Network{}
+		Expect(db.List(&networks, libmodel.ListOptions{})).To(Succeed())
+		Expect(networks).To(HaveLen(1))
+		Expect(networks[0].Name).To(Equal("Internal"))
+		storage := []model.Storage{}
+		Expect(db.List(&storage, libmodel.ListOptions{})).To(Succeed())
+		Expect(storage).To(HaveLen(1))
+		Expect(storage[0].ID).To(Equal(vm.Disks[0].ID))
+	})
+
+	ginkgo.It("should refresh without needless revisions", func() {
+		Expect(collector.load(ctx)).To(Succeed())
+		vm := &model.VM{Base: model.Base{ID: "4a2c9b2e-1f0d-4f7c-8e3a-5b6c7d8e9f00"}}
+		Expect(db.Get(vm)).To(Succeed())
+		revision := vm.Revision
+		Expect(collector.refresh(ctx)).To(Succeed())
+		Expect(collector.refresh(ctx)).To(Succeed())
+		Expect(db.Get(vm)).To(Succeed())
+		Expect(vm.Revision).To(Equal(revision))
+	})
+
+	ginkgo.It("should delete VMs removed from the share", func() {
+		Expect(collector.load(ctx)).To(Succeed())
+		Expect(os.RemoveAll(filepath.Join(root, "db-01"))).To(Succeed())
+		Expect(collector.refresh(ctx)).To(Succeed())
+		vms := []model.VM{}
+		Expect(db.List(&vms, libmodel.ListOptions{})).To(Succeed())
+		Expect(vms).To(BeEmpty())
+		disks := []model.Disk{}
+		Expect(db.List(&disks, libmodel.ListOptions{})).To(Succeed())
+		Expect(disks).To(BeEmpty())
+		storage := []model.Storage{}
+		Expect(db.List(&storage, libmodel.ListOptions{})).To(Succeed())
+		Expect(storage).To(BeEmpty())
+	})
+})
@@ -0,0 +1,411 @@
+package hyperv
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/hyperv"
+	fb "github.com/kubev2v/forklift/pkg/lib/filebacked"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Inventory service collections.
+const (
+	VMsCollection      = "vms"
+	NetworksCollection = "networks"
+	DisksCollection    = "disks"
+)
+
+// All adapters.
+var adapterList []Adapter
+
+func init() {
+	adapterList = []Adapter{
+		&NetworkAdapter{},
+		&DiskAdapter{},
+		&VMAdapter{},
+		&StorageAdapter{},
+	}
+}
+
+// Updates the DB based on
+// changes described by an Event.
+type Updater func(tx *libmodel.Tx) error
+
+// Adapter context.
+type Context struct {
+	// Context.
+	ctx context.Context
+	// Hyper-V inventory client.
+	client *Client
+	// Log.
+	log logging.LevelLogger
+	// DB client.
+	db libmodel.DB
+}
+
+// The adapter request is canceled.
+func (r *Context) canceled() (done bool) {
+	select {
+	case <-r.ctx.Done():
+		done = true
+	default:
+	}
+
+	return
+}
+
+// Model adapter.
+// Provides integration between the REST resource
+// model and the inventory model.
+type Adapter interface {
+	// List REST collections.
+	List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error)
+	// Get object updates
+	GetUpdates(ctx *Context) (updater []Updater, err error)
+	// Clean unexisting objects within the database
+	DeleteUnexisting(ctx *Context) (deletions []Updater, err error)
+}
+
+// Base adapter.
+type BaseAdapter struct {
+}
+
+// Build an updater that inserts the model when not found
+// and updates it only when changed by apply() to avoid
+// needless revisions (and validation).
+func (r *BaseAdapter) upsert(m libmodel.Model, apply func(m libmodel.Model)) Updater {
+	return func(tx *libmodel.Tx) (err error) {
+		err = tx.Get(m)
+		if err != nil {
+			if errors.Is(err, libmodel.NotFound) {
+				apply(m)
+				err = tx.Insert(m)
+			}
+			return
+		}
+		before := reflect.ValueOf(m).Elem().Interface()
+		apply(m)
+		if reflect.DeepEqual(before, reflect.ValueOf(m).Elem().Interface()) {
+			return
+		}
+		err = tx.Update(m)
+		return
+	}
+}
+
+// Build updaters that delete the models not reported
+// by the inventory service.
+func (r *BaseAdapter) deletions(stored []string, reported map[string]bool, build func(id string) libmodel.Model) (deletions []Updater) {
+	for _, id := range stored {
+		if reported[id] {
+			continue
+		}
+		m := build(id)
+		deletions = append(
+			deletions,
+			func(tx *libmodel.Tx) (err error) {
+				err = tx.Delete(m)
+				if errors.Is(err, libmodel.NotFound) {
+					err = nil
+				}
+				return
+			})
+	}
+	return
+}
+
+// Network adapter.
+type NetworkAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *NetworkAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	networkList := []Network{}
+	err = ctx.client.list(NetworksCollection, &networkList)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, object := range networkList {
+		m := &model.Network{}
+		object.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+
+	return
+}
+
+// Get updates since last sync.
+func (r *NetworkAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	networkList := []Network{}
+	err = ctx.client.list(NetworksCollection, &networkList)
+	if err != nil {
+		return
+	}
+	for i := range networkList {
+		network := &networkList[i]
+		updates = append(
+			updates,
+			r.upsert(
+				&model.Network{Base: model.Base{ID: network.ID}},
+				func(m libmodel.Model) {
+					network.ApplyTo(m.(*model.Network))
+				}))
+	}
+	return
+}
+
+// Delete networks no longer reported.
+func (r *NetworkAdapter) DeleteUnexisting(ctx *Context) (deletions []Updater, err error) {
+	stored := []model.Network{}
+	err = ctx.db.List(&stored, libmodel.FilterOptions{})
+	if err != nil {
+		return
+	}
+	networkList := []Network{}
+	err = ctx.client.list(NetworksCollection, &networkList)
+	if err != nil {
+		return
+	}
+	reported := map[string]bool{}
+	for _, network := range networkList {
+		reported[network.ID] = true
+	}
+	ids := []string{}
+	for _, m := range stored {
+		ids = append(ids, m.ID)
+	}
+	deletions = r.deletions(
+		ids,
+		reported,
+		func(id string) libmodel.Model {
+			return &model.Network{Base: model.Base{ID: id}}
+		})
+	return
+}
+
+// VM adapter.
+type VMAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *VMAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	vmList := []VM{}
+	err = ctx.client.list(VMsCollection, &vmList)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, object := range vmList {
+		m := &model.VM{}
+		object.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+	return
+}
+
+// Get updates since last sync.
+func (r *VMAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	vmList := []VM{}
+	err = ctx.client.list(VMsCollection, &vmList)
+	if err != nil {
+		return
+	}
+	for i := range vmList {
+		vm := &vmList[i]
+		updates = append(
+			updates,
+			r.upsert(
+				&model.VM{Base: model.Base{ID: vm.UUID}},
+				func(m libmodel.Model) {
+					vm.ApplyTo(m.(*model.VM))
+				}))
+	}
+	return
+}
+
+// Delete VMs no longer reported.
+func (r *VMAdapter) DeleteUnexisting(ctx *Context) (deletions []Updater, err error) {
+	stored := []model.VM{}
+	err = ctx.db.List(&stored, libmodel.FilterOptions{})
+	if err != nil {
+		return
+	}
+	vmList := []VM{}
+	err = ctx.client.list(VMsCollection, &vmList)
+	if err != nil {
+		return
+	}
+	reported := map[string]bool{}
+	for _, vm := range vmList {
+		reported[vm.UUID] = true
+	}
+	ids := []string{}
+	for _, m := range stored {
+		ids = append(ids, m.ID)
+	}
+	deletions = r.deletions(
+		ids,
+		reported,
+		func(id string) libmodel.Model {
+			return &model.VM{Base: model.Base{ID: id}}
+		})
+	return
+}
+
+// Disk adapter.
+type DiskAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *DiskAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	diskList := []Disk{}
+	err = ctx.client.list(DisksCollection, &diskList)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, object := range diskList {
+		m := &model.Disk{}
+		object.ApplyTo(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+
+	return
+}
+
+// Get updates since last sync.
+func (r *DiskAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	diskList := []Disk{}
+	err = ctx.client.list(DisksCollection, &diskList)
+	if err != nil {
+		return
+	}
+	for i := range diskList {
+		disk := &diskList[i]
+		updates = append(
+			updates,
+			r.upsert(
+				&model.Disk{Base: model.Base{ID: disk.ID}},
+				func(m libmodel.Model) {
+					disk.ApplyTo(m.(*model.Disk))
+				}))
+	}
+	return
+}
+
+// Delete disks no longer reported.
+func (r *DiskAdapter) DeleteUnexisting(ctx *Context) (deletions []Updater, err error) {
+	stored := []model.Disk{}
+	err = ctx.db.List(&stored, libmodel.FilterOptions{})
+	if err != nil {
+		return
+	}
+	diskList := []Disk{}
+	err = ctx.client.list(DisksCollection, &diskList)
+	if err != nil {
+		return
+	}
+	reported := map[string]bool{}
+	for _, disk := range diskList {
+		reported[disk.ID] = true
+	}
+	ids := []string{}
+	for _, m := range stored {
+		ids = append(ids, m.ID)
+	}
+	deletions = r.deletions(
+		ids,
+		reported,
+		func(id string) libmodel.Model {
+			return &model.Disk{Base: model.Base{ID: id}}
+		})
+	return
+}
+
+// Storage adapter.
+// Each disk is reported as a storage that may be mapped.
+type StorageAdapter struct {
+	BaseAdapter
+}
+
+// List the collection.
+func (r *StorageAdapter) List(ctx *Context, provider *api.Provider) (itr fb.Iterator, err error) {
+	diskList := []Disk{}
+	err = ctx.client.list(DisksCollection, &diskList)
+	if err != nil {
+		return
+	}
+	list := fb.NewList()
+	for _, object := range diskList {
+		m := &model.Storage{}
+		object.ApplyToStorage(m)
+		list.Append(m)
+	}
+
+	itr = list.Iter()
+
+	return
+}
+
+// Get updates since last sync.
+func (r *StorageAdapter) GetUpdates(ctx *Context) (updates []Updater, err error) {
+	diskList := []Disk{}
+	err = ctx.client.list(DisksCollection, &diskList)
+	if err != nil {
+		return
+	}
+	for i := range diskList {
+		disk := &diskList[i]
+		updates = append(
+			updates,
+			r.upsert(
+				&model.Storage{Base: model.Base{ID: disk.ID}},
+				func(m libmodel.Model) {
+					disk.ApplyToStorage(m.(*model.Storage))
+				}))
+	}
+	return
+}
+
+// Delete storage no longer reported.
+func (r *StorageAdapter) DeleteUnexisting(ctx *Context) (deletions []Updater, err error) {
+	stored := []model.Storage{}
+	err = ctx.db.List(&stored, libmodel.FilterOptions{})
+	if err != nil {
+		return
+	}
+	diskList := []Disk{}
+	err = ctx.client.list(DisksCollection, &diskList)
+	if err != nil {
+		return
+	}
+	reported := map[string]bool{}
+	for _, disk := range diskList {
+		reported[disk.ID] = true
+	}
+	ids := []string{}
+	for _, m := range stored {
+		ids = append(ids, m.ID)
+	}
+	deletions = r.deletions(
+		ids,
+		reported,
+		func(id string) libmodel.Model {
+			return &model.Storage{Base: model.Base{ID: id}}
+		})
+	return
+}
@@ -0,0 +1,126 @@
+package hyperv
+
+import (
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/hyperv"
+)
+
+// VM.
+type VM struct {
+	UUID          string `json:"UUID"`
+	Name          string `json:"Name"`
+	ExportPath    string `json:"ExportPath"`
+	ConfigPath    string `json:"ConfigPath"`
+	Generation    int    `json:"Generation"`
+	Firmware      string `json:"Firmware"`
+	SecureBoot    bool   `json:"SecureBoot"`
+	CpuCount      int32  `json:"CpuCount"`
+	MemoryMB      int32  `json:"MemoryMB"`
+	DynamicMemory bool   `json:"DynamicMemory"`
+	StorageUsed   int64  `json:"StorageUsed"`
+	NICs          []struct {
+		Name    string `json:"Name"`
+		MAC     string `json:"MAC"`
+		Network string `json:"Network"`
+	} `json:"NICs"`
+	Disks    []Disk    `json:"Disks"`
+	Networks []Network `json:"Networks"`
+}
+
+// Apply to (update) the model.
+func (r *VM) ApplyTo(m *model.VM) {
+	m.ID = r.UUID
+	m.Name = r.Name
+	m.UUID = r.UUID
+	m.ExportPath = r.ExportPath
+	m.ConfigPath = r.ConfigPath
+	m.Generation = r.Generation
+	m.Firmware = r.Firmware
+	m.SecureBoot = r.SecureBoot
+	m.CpuCount = r.CpuCount
+	m.MemoryMB = r.MemoryMB
+	m.DynamicMemory = r.DynamicMemory
+	m.StorageUsed = r.StorageUsed
+	r.addNICs(m)
+	r.addDisks(m)
+	r.addNetworks(m)
+}
+
+func (r *VM) addNICs(m *model.VM) {
+	m.NICs = []model.NIC{}
+	for _, n := range r.NICs {
+		m.NICs = append(
+			m.NICs,
+			model.NIC{
+				Name:    n.Name,
+				MAC:     n.MAC,
+				Network: n.Network,
+			})
+	}
+}
+
+func (r *VM) addDisks(m *model.VM) {
+	m.Disks = []model.Disk{}
+	for _, disk := range r.Disks {
+		md := model.Disk{}
+		disk.ApplyTo(&md)
+		m.Disks = append(m.Disks, md)
+	}
+}
+
+func (r *VM) addNetworks(m *model.VM) {
+	m.Networks = []model.Network{}
+	for _, network := range r.Networks {
+		mn := model.Network{}
+		network.ApplyTo(&mn)
+		m.Networks = append(m.Networks, mn)
+	}
+}
+
+// Network (virtual switch).
+type Network struct {
+	ID          string `json:"ID"`
+	Name        string `json:"Name"`
+	Description string `json:"Description"`
+}
+
+// Apply to (update) the model.
+func (r *Network) ApplyTo(m *model.Network) {
+	m.ID = r.ID
+	m.Name = r.Name
+	m.Description = r.Description
+}
+
+// Disk.
+type Disk struct {
+	ID            string `json:"ID"`
+	Name          string `json:"Name"`
+	FilePath      string `json:"FilePath"`
+	SourcePath    string `json:"SourcePath"`
+	Format        string `json:"Format"`
+	Bus           string `json:"Bus"`
+	Controller    int    `json:"Controller"`
+	Unit          int    `json:"Unit"`
+	Capacity      int64  `json:"Capacity"`
+	PopulatedSize int64  `json:"PopulatedSize"`
+}
+
+// Apply to (update) the model.
+func (r *Disk) ApplyTo(m *model.Disk) {
+	m.ID = r.ID
+	m.Name = r.Name
+	m.FilePath = r.FilePath
+	m.SourcePath = r.SourcePath
+	m.Format = r.Format
+	m.Bus = r.Bus
+	m.Controller = r.Controller
+	m.Unit = r.Unit
+	m.Capacity = r.Capacity
+	m.PopulatedSize = r.PopulatedSize
+}
+
+// Apply to (update) the storage model.
+// Each disk is a storage that may be mapped.
+func (r *Disk) ApplyToStorage(m *model.Storage) {
+	m.ID = r.ID
+	m.Name = r.Name
+}
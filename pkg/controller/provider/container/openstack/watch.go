@@ -259,8 +259,9 @@ func (r *VMEventHandler) canceled() bool {
 // Analyze the VM.
 func (r *VMEventHandler) validate(VM *model.VM) (err error) {
 	task := &policy.Task{
-		Path:    ValidationEndpoint,
-		Context: r.context,
+		Path:     ValidationEndpoint,
+		Provider: r.Provider.Type().String(),
+		Context:  r.context,
 		// Workload: r.workload,
 		Result:   r.taskResult,
 		Revision: VM.Revision,
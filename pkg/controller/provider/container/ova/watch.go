@@ -241,6 +241,7 @@ func (r *VMEventHandler) canceled() bool {
 func (r *VMEventHandler) validate(VM *model.VM) (err error) {
 	task := &policy.Task{
 		Path:     ValidationEndpoint,
+		Provider: r.Provider.Type().String(),
 		Context:  r.context,
 		Workload: r.workload,
 		Result:   r.taskResult,
@@ -2,6 +2,7 @@ package ovirt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	liburl "net/url"
@@ -29,6 +30,8 @@ const (
 
 	// Default timeout for the HTTP client
 	DefaultClientTimeout = 30 * time.Minute
+	// Checkpoint ID.
+	CheckpointID = "ovirt"
 )
 
 // Phases
@@ -56,6 +59,11 @@ type Collector struct {
 	cancel func()
 	// Last event ID.
 	lastEvent int
+	// Last event ID saved in the checkpoint.
+	savedEvent int
+	// The persisted inventory may contain
+	// resources that no longer exist.
+	stale bool
 	// Phase
 	phase string
 	// List of watches.
@@ -204,17 +212,33 @@ func (r *Collector) run(ctx *Context) (err error) {
 		r.phase)
 	switch r.phase {
 	case Started:
+		var resumed bool
+		resumed, err = r.resume()
+		if err != nil {
+			break
+		}
+		if resumed {
+			r.phase = Loaded
+			r.parity = true
+			break
+		}
 		err = r.noteLastEvent()
 		if err == nil {
 			r.phase = Load
 		}
 	case Load:
 		err = r.load(ctx)
+		if err == nil {
+			err = r.checkpoint()
+		}
 		if err == nil {
 			r.phase = Loaded
 		}
 	case Loaded:
 		err = r.refresh(ctx)
+		if err == nil {
+			err = r.checkpoint()
+		}
 		if err == nil {
 			r.phase = Parity
 		}
@@ -227,6 +251,9 @@ func (r *Collector) run(ctx *Context) (err error) {
 		}
 	case Refresh:
 		err = r.refresh(ctx)
+		if err == nil {
+			err = r.checkpoint()
+		}
 		if err == nil {
 			r.parity = true
 			time.Sleep(RefreshInterval)
@@ -256,6 +283,93 @@ func (r *Collector) Shutdown() {
 	}
 }
 
+// Resume from the checkpoint persisted with the inventory.
+// The persisted inventory is served while the events since the
+// checkpoint are applied. The checkpoint is not valid when the
+// provider URL has changed or the event has been purged by the
+// engine, in which case the inventory is fully reloaded.
+func (r *Collector) resume() (resumed bool, err error) {
+	checkpoint := &model.Checkpoint{ID: CheckpointID}
+	err = r.db.Get(checkpoint)
+	if err != nil {
+		if errors.Is(err, model.NotFound) {
+			err = nil
+		}
+		return
+	}
+	r.stale = true
+	lastEvent, pErr := strconv.Atoi(checkpoint.Token)
+	if pErr != nil || checkpoint.URL != r.provider.Spec.URL {
+		r.log.Info("Checkpoint not valid.")
+		err = r.invalidate(checkpoint)
+		return
+	}
+	err = r.connect()
+	if err != nil {
+		return
+	}
+	url, err := liburl.Parse(r.provider.Spec.URL)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = r.client.get(
+		libpath.Join(url.Path, "events", checkpoint.Token),
+		&Event{})
+	if err != nil {
+		var notFound *NotFound
+		if errors.As(err, &notFound) {
+			r.log.Info(
+				"Checkpoint event purged.",
+				"id",
+				lastEvent)
+			err = r.invalidate(checkpoint)
+		}
+		return
+	}
+	r.lastEvent = lastEvent
+	r.savedEvent = lastEvent
+	r.stale = false
+	resumed = true
+
+	r.log.Info(
+		"Resumed from checkpoint.",
+		"id",
+		lastEvent)
+
+	return
+}
+
+// Invalidate the checkpoint while the inventory is reloaded.
+// The checkpoint is kept so the persisted inventory is still
+// known to be stale when the reload is interrupted.
+func (r *Collector) invalidate(checkpoint *model.Checkpoint) (err error) {
+	if checkpoint.Token == "" {
+		return
+	}
+	checkpoint.Token = ""
+	err = r.db.Update(checkpoint)
+	return
+}
+
+// Save the checkpoint.
+func (r *Collector) checkpoint() (err error) {
+	if r.lastEvent == r.savedEvent {
+		return
+	}
+	err = r.db.Insert(
+		&model.Checkpoint{
+			ID:    CheckpointID,
+			URL:   r.provider.Spec.URL,
+			Token: strconv.Itoa(r.lastEvent),
+		})
+	if err != nil {
+		return
+	}
+	r.savedEvent = r.lastEvent
+	return
+}
+
 // Fetch and note that last event.
 func (r *Collector) noteLastEvent() (err error) {
 	err = r.connect()
@@ -292,11 +406,18 @@ func (r *Collector) load(ctx *Context) (err error) {
 		return
 	}
 	mark := time.Now()
+	sweep := &libmodel.Sweep{}
 	for _, adapter := range adapterList {
 		if ctx.canceled() {
 			return
 		}
-		err = r.create(ctx, adapter)
+		err = r.create(ctx, adapter, sweep)
+		if err != nil {
+			return
+		}
+	}
+	if r.stale {
+		err = r.sweep(sweep)
 		if err != nil {
 			return
 		}
@@ -311,7 +432,7 @@ func (r *Collector) load(ctx *Context) (err error) {
 }
 
 // List and create resources using the adapter.
-func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
+func (r *Collector) create(ctx *Context, adapter Adapter, sweep *libmodel.Sweep) (err error) {
 	itr, aErr := adapter.List(ctx)
 	if aErr != nil {
 		err = aErr
@@ -337,11 +458,51 @@ func (r *Collector) create(ctx *Context, adapter Adapter) (err error) {
 		if err != nil {
 			return
 		}
+		sweep.Seen(m)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Delete the persisted resources that were not
+// found while the inventory was reloaded.
+func (r *Collector) sweep(sweep *libmodel.Sweep) (err error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	deleted, err := sweep.Delete(
+		tx,
+		&model.DataCenter{},
+		&model.Cluster{},
+		&model.ServerCpu{},
+		&model.NICProfile{},
+		&model.DiskProfile{},
+		&model.Network{},
+		&model.StorageDomain{},
+		&model.Disk{},
+		&model.Host{},
+		&model.VM{})
+	if err != nil {
+		return
 	}
 	err = tx.Commit()
 	if err != nil {
 		return
 	}
+	r.stale = false
+
+	r.log.Info(
+		"Stale resources deleted.",
+		"count",
+		deleted)
 
 	return
 }
@@ -260,6 +260,7 @@ func (r *VMEventHandler) canceled() bool {
 func (r *VMEventHandler) validate(vm *model.VM) (err error) {
 	task := &policy.Task{
 		Path:     ValidationEndpoint,
+		Provider: r.Provider.Type().String(),
 		Context:  r.context,
 		Workload: r.workload,
 		Result:   r.taskResult,
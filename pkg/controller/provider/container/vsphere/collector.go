@@ -2,6 +2,7 @@ package vsphere
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	liburl "net/url"
@@ -37,6 +38,8 @@ const (
 	MaxObjectUpdates = 10000
 	// Connection timeout for provider operations.
 	ConnectionTimeout = 30 * time.Second
+	// Checkpoint ID.
+	CheckpointID = "vsphere"
 )
 
 // Types
@@ -75,6 +78,7 @@ const (
 	fDrsEnabled    = "configuration.drsConfig.enabled"
 	fDrsVmBehavior = "configuration.drsConfig.defaultVmBehavior"
 	fDrsVmCfg      = "configuration.drsVmConfig"
+	fConfigEx      = "configurationEx"
 	// Host
 	fVm                   = "vm"
 	fOverallStatus        = "overallStatus"
@@ -132,6 +136,8 @@ const (
 	fNestedHVEnabled          = "config.nestedHVEnabled"
 	fChangeTracking           = "config.changeTrackingEnabled"
 	fGuestName                = "summary.config.guestFullName"
+	fCustomValue              = "customValue"
+	fAvailableField           = "availableField"
 	fGuestNameFromVmwareTools = "guest.guestFullName"
 	fGuestID                  = "summary.guest.guestId"
 	fTpmPresent               = "summary.config.tpmPresent"
@@ -288,6 +294,9 @@ type Collector struct {
 	cancel func()
 	// has parity.
 	parity bool
+	// Objects seen while the persisted
+	// inventory is resynchronized.
+	sweep *libmodel.Sweep
 }
 
 // New collector.
@@ -399,6 +408,7 @@ func (r *Collector) Start() error {
 	}
 
 	go start()
+	go r.refreshTags(ctx)
 
 	return nil
 }
@@ -413,9 +423,17 @@ func (r *Collector) Shutdown() {
 
 // Get object updates.
 //  1. connect.
-//  2. apply updates.
+//  2. create the property collector.
+//  3. apply updates.
 //
 // Blocks waiting on updates until canceled.
+// The property collector and its update versions only exist for
+// the lifetime of the vCenter session, so the updates cannot be
+// resumed after a restart. Instead, the persisted inventory is
+// resynchronized in place: the initial updates are applied over
+// the persisted objects and the objects not reported are deleted.
+// When the inventory was persisted for the same vCenter, it is
+// served while the collector resynchronizes.
 func (r *Collector) getUpdates(ctx context.Context) error {
 	_, err := r.connect(ctx)
 	if err != nil {
@@ -432,6 +450,24 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	checkpoint, err := r.checkpoint()
+	if err != nil {
+		return err
+	}
+	if checkpoint != nil {
+		r.sweep = &libmodel.Sweep{}
+		if checkpoint.URL == r.url {
+			r.parity = true
+			r.log.Info("Serving the persisted inventory.")
+		}
+	} else {
+		// Without parity until the initial updates are applied
+		// so an interrupted load is swept on the next start.
+		err = r.db.Insert(&model.Checkpoint{ID: CheckpointID})
+		if err != nil {
+			return err
+		}
+	}
 	pc := property.DefaultCollector(r.client.Client)
 	pc, err = pc.Create(ctx)
 	if err != nil {
@@ -454,10 +490,12 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 		This:    pc.Reference(),
 		Options: filter.Options,
 	}
+	synced := false
 	var tx *libmodel.Tx
 	watchList := []*libmodel.Watch{}
 	defer func() {
 		r.parity = false
+		r.sweep = nil
 		for _, w := range watchList {
 			w.End()
 		}
@@ -512,7 +550,20 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 				"tx commit failed.")
 		}
 		if updateSet.Truncated == nil || !*updateSet.Truncated {
-			if !r.parity {
+			if !synced {
+				synced = true
+				err = r.deleteStale()
+				if err != nil {
+					return err
+				}
+				err = r.db.Insert(
+					&model.Checkpoint{
+						ID:  CheckpointID,
+						URL: r.url,
+					})
+				if err != nil {
+					return err
+				}
 				r.parity = true
 				r.log.Info(
 					"Initial parity.",
@@ -526,6 +577,62 @@ func (r *Collector) getUpdates(ctx context.Context) error {
 	return nil
 }
 
+// Get the checkpoint persisted with the inventory.
+// The checkpoint records the vCenter with which the persisted
+// inventory last had parity. There is no resume token.
+// Returns nil when the inventory has not been persisted.
+func (r *Collector) checkpoint() (checkpoint *model.Checkpoint, err error) {
+	checkpoint = &model.Checkpoint{ID: CheckpointID}
+	err = r.db.Get(checkpoint)
+	if err != nil {
+		checkpoint = nil
+		if errors.Is(err, model.NotFound) {
+			err = nil
+		}
+	}
+
+	return
+}
+
+// Delete the persisted objects that were not
+// found while the inventory was resynchronized.
+func (r *Collector) deleteStale() (err error) {
+	if r.sweep == nil {
+		return
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	deleted, err := r.sweep.Delete(
+		tx,
+		&model.Folder{},
+		&model.Datacenter{},
+		&model.Cluster{},
+		&model.Network{},
+		&model.Datastore{},
+		&model.Host{},
+		&model.VM{})
+	if err != nil {
+		return
+	}
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+	r.sweep = nil
+
+	r.log.Info(
+		"Stale objects deleted.",
+		"count",
+		deleted)
+
+	return
+}
+
 // Add model watches.
 func (r *Collector) watch() (list []*libmodel.Watch) {
 	// Cluster
@@ -723,6 +830,7 @@ func (r *Collector) propertySpec() []types.PropertySpec {
 				fDrsEnabled,
 				fDrsVmBehavior,
 				fDrsVmCfg,
+				fConfigEx,
 				fHost,
 				fNetwork,
 				fDatastore,
@@ -852,6 +960,8 @@ func (r *Collector) vmPathSet() []string {
 		fToolsStatus,
 		fToolsRunningStatus,
 		fToolsVersionStatus,
+		fCustomValue,
+		fAvailableField,
 	}
 
 	apiVer := strings.Split(r.client.ServiceContent.About.ApiVersion, ".")
@@ -1003,10 +1113,19 @@ func (r Collector) applyEnter(tx *libmodel.Tx, u types.ObjectUpdate) error {
 	}
 	adapter.Apply(u)
 	m := adapter.Model()
+	if r.sweep != nil {
+		err := r.keepTags(tx, m)
+		if err != nil {
+			return liberr.Wrap(err)
+		}
+	}
 	err := tx.Insert(m)
 	if err != nil {
 		return liberr.Wrap(err)
 	}
+	if r.sweep != nil {
+		r.sweep.Seen(m)
+	}
 
 	return nil
 }
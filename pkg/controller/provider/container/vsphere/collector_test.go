@@ -3,6 +3,10 @@ package vsphere
 import (
 	liburl "net/url"
 
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+
 	. "github.com/onsi/ginkgo"
 	"github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
@@ -35,3 +39,31 @@ var _ = Describe("vSphere collector", func() {
 		table.Entry("collect TPM from vSphere > 6.7", "7.0", ContainElements(fTpmPresent)),
 	)
 })
+
+var _ = Describe("vSphere collector checkpoint", func() {
+	It("should find the persisted checkpoint", func() {
+		db := libmodel.New("/tmp/test-vsphere-checkpoint.db", model.All()...)
+		Expect(db.Open(true)).To(Succeed())
+		defer func() {
+			_ = db.Close(true)
+		}()
+		collector := Collector{
+			url: "https://fake.com/sdk",
+			db:  db,
+			log: logging.WithName("test"),
+		}
+		checkpoint, err := collector.checkpoint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(checkpoint).To(BeNil())
+
+		Expect(db.Insert(&model.Checkpoint{
+			ID:  CheckpointID,
+			URL: collector.url,
+		})).To(Succeed())
+		checkpoint, err = collector.checkpoint()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(checkpoint).ToNot(BeNil())
+		Expect(checkpoint.URL).To(Equal(collector.url))
+		Expect(checkpoint.Token).To(BeEmpty())
+	})
+})
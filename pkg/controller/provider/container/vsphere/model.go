@@ -225,11 +225,77 @@ func (v *ClusterAdapter) Apply(u types.ObjectUpdate) {
 				if b, cast := p.Val.(types.DrsBehavior); cast {
 					v.model.DrsBehavior = string(b)
 				}
+			case fConfigEx:
+				switch config := p.Val.(type) {
+				case types.ClusterConfigInfoEx:
+					v.updateDrsRules(&config)
+				case *types.ClusterConfigInfoEx:
+					v.updateDrsRules(config)
+				}
 			}
 		}
 	}
 }
 
+// Update the DRS rules and groups.
+func (v *ClusterAdapter) updateDrsRules(config *types.ClusterConfigInfoEx) {
+	rules := []model.DrsRule{}
+	for _, info := range config.Rule {
+		base := info.GetClusterRuleInfo()
+		rule := model.DrsRule{
+			Key:       base.Key,
+			Name:      base.Name,
+			Enabled:   base.Enabled != nil && *base.Enabled,
+			Mandatory: base.Mandatory != nil && *base.Mandatory,
+		}
+		switch r := info.(type) {
+		case *types.ClusterAffinityRuleSpec:
+			rule.Kind = model.DrsAffinity
+			rule.VMs = v.refs(r.Vm)
+		case *types.ClusterAntiAffinityRuleSpec:
+			rule.Kind = model.DrsAntiAffinity
+			rule.VMs = v.refs(r.Vm)
+		case *types.ClusterVmHostRuleInfo:
+			rule.VmGroup = r.VmGroupName
+			if r.AffineHostGroupName != "" {
+				rule.Kind = model.DrsVmHostAffinity
+				rule.HostGroup = r.AffineHostGroupName
+			} else {
+				rule.Kind = model.DrsVmHostAntiAffinity
+				rule.HostGroup = r.AntiAffineHostGroupName
+			}
+		default:
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	groups := []model.DrsGroup{}
+	for _, info := range config.Group {
+		group := model.DrsGroup{
+			Name: info.GetClusterGroupInfo().Name,
+		}
+		switch g := info.(type) {
+		case *types.ClusterVmGroup:
+			group.VMs = v.refs(g.Vm)
+		case *types.ClusterHostGroup:
+			group.Hosts = v.refs(g.Host)
+		default:
+			continue
+		}
+		groups = append(groups, group)
+	}
+	v.model.DrsRules = rules
+	v.model.DrsGroups = groups
+}
+
+// Build a list of refs.
+func (v *ClusterAdapter) refs(list []types.ManagedObjectReference) (refs []model.Ref) {
+	for _, ref := range list {
+		refs = append(refs, v.Ref(ref))
+	}
+	return
+}
+
 // Host model adapter.
 type HostAdapter struct {
 	Base
@@ -835,6 +901,12 @@ func (v *VmAdapter) Apply(u types.ObjectUpdate) {
 				if b, cast := p.Val.(bool); cast {
 					v.model.NestedHVEnabled = b
 				}
+			case fAvailableField:
+				fields, _ := p.Val.(types.ArrayOfCustomFieldDef)
+				v.defineAttributes(fields.CustomFieldDef)
+			case fCustomValue:
+				values, _ := p.Val.(types.ArrayOfCustomFieldValue)
+				v.setAttributes(values.CustomFieldValue)
 			case fGuestDisk:
 				if disks, cast := p.Val.(types.ArrayOfGuestDiskInfo); cast {
 					var diskMountPoints []model.DiskMountPoint
@@ -1043,6 +1115,66 @@ func extractWindowsDriveLetter(diskPath string) string {
 	return ""
 }
 
+// Define the custom attributes by the custom fields available
+// on the VM. The collected values are kept. Values of fields not
+// (yet) defined are kept without a name.
+func (v *VmAdapter) defineAttributes(fields []types.CustomFieldDef) {
+	defined := make(map[int32]bool)
+	values := make(map[int32]string)
+	for _, attribute := range v.model.CustomAttributes {
+		values[attribute.Key] = attribute.Value
+	}
+	attributes := []model.Attribute{}
+	for _, field := range fields {
+		defined[field.Key] = true
+		attributes = append(
+			attributes,
+			model.Attribute{
+				Key:   field.Key,
+				Name:  field.Name,
+				Value: values[field.Key],
+			})
+	}
+	for _, attribute := range v.model.CustomAttributes {
+		if !defined[attribute.Key] && attribute.Value != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+	v.model.CustomAttributes = attributes
+}
+
+// Set the values of the custom attributes.
+func (v *VmAdapter) setAttributes(values []types.BaseCustomFieldValue) {
+	set := make(map[int32]string)
+	for _, value := range values {
+		if s, cast := value.(*types.CustomFieldStringValue); cast {
+			set[s.Key] = s.Value
+		}
+	}
+	attributes := []model.Attribute{}
+	for _, attribute := range v.model.CustomAttributes {
+		value, found := set[attribute.Key]
+		if attribute.Name == "" && !found {
+			continue
+		}
+		attribute.Value = value
+		delete(set, attribute.Key)
+		attributes = append(attributes, attribute)
+	}
+	for _, value := range values {
+		key := value.GetCustomFieldValue().Key
+		if s, found := set[key]; found && s != "" {
+			attributes = append(
+				attributes,
+				model.Attribute{
+					Key:   key,
+					Value: s,
+				})
+		}
+	}
+	v.model.CustomAttributes = attributes
+}
+
 // Update virtual disk devices.
 func (v *VmAdapter) updateControllers(devArray *types.ArrayOfVirtualDevice) {
 	controllers := []model.Controller{}
@@ -1145,6 +1277,12 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 				winDriveLetter = extractWindowsDriveLetter(guestDiskInfo.DiskPath)
 			}
 
+			// Device label, e.g. "Hard disk 1".
+			label := ""
+			if description := disk.GetVirtualDevice().DeviceInfo; description != nil {
+				label = description.GetDescription().Label
+			}
+
 			switch backing := disk.Backing.(type) {
 			case *types.VirtualDiskFlatVer1BackingInfo:
 				md := model.Disk{
@@ -1156,6 +1294,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 					Mode:           backing.DiskMode,
 					Bus:            bus,
 					WinDriveLetter: winDriveLetter,
+					Label:          label,
 				}
 				if backing.Datastore != nil {
 					datastoreId, _ := sanitize(backing.Datastore.Value)
@@ -1180,6 +1319,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 					Bus:            bus,
 					Serial:         backing.Uuid,
 					WinDriveLetter: winDriveLetter,
+					Label:          label,
 				}
 				if backing.Parent != nil {
 					md.ParentFile = backing.Parent.FileName
@@ -1205,6 +1345,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 					Bus:            bus,
 					Serial:         backing.Uuid,
 					WinDriveLetter: winDriveLetter,
+					Label:          label,
 				}
 				if backing.Datastore != nil {
 					datastoreId, _ := sanitize(backing.Datastore.Value)
@@ -1225,6 +1366,7 @@ func (v *VmAdapter) updateDisks(devArray *types.ArrayOfVirtualDevice) {
 					RDM:            true,
 					Bus:            bus,
 					WinDriveLetter: winDriveLetter,
+					Label:          label,
 				}
 				disks = append(disks, md)
 			}
@@ -1,9 +1,11 @@
 package vsphere
 
 import (
+	"reflect"
 	"testing"
 
 	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 // Test getDiskGuestInfo method
@@ -241,3 +243,92 @@ func TestVmAdapter_getDiskGuestInfo(t *testing.T) {
 		})
 	}
 }
+
+// Test updateDrsRules method
+func TestClusterAdapter_updateDrsRules(t *testing.T) {
+	enabled := true
+	vm := func(id string) types.ManagedObjectReference {
+		return types.ManagedObjectReference{Type: VirtualMachine, Value: id}
+	}
+	adapter := &ClusterAdapter{}
+	adapter.updateDrsRules(&types.ClusterConfigInfoEx{
+		Rule: []types.BaseClusterRuleInfo{
+			&types.ClusterAffinityRuleSpec{
+				ClusterRuleInfo: types.ClusterRuleInfo{Key: 1, Name: "together", Enabled: &enabled},
+				Vm:              []types.ManagedObjectReference{vm("vm-1"), vm("vm-2")},
+			},
+			&types.ClusterAntiAffinityRuleSpec{
+				ClusterRuleInfo: types.ClusterRuleInfo{Key: 2, Name: "apart", Enabled: &enabled, Mandatory: &enabled},
+				Vm:              []types.ManagedObjectReference{vm("vm-2"), vm("vm-3")},
+			},
+			&types.ClusterVmHostRuleInfo{
+				ClusterRuleInfo:         types.ClusterRuleInfo{Key: 3, Name: "off-host"},
+				VmGroupName:             "group",
+				AntiAffineHostGroupName: "hosts",
+			},
+			&types.ClusterDependencyRuleInfo{
+				ClusterRuleInfo: types.ClusterRuleInfo{Key: 4, Name: "dependency"},
+			},
+		},
+		Group: []types.BaseClusterGroupInfo{
+			&types.ClusterVmGroup{
+				ClusterGroupInfo: types.ClusterGroupInfo{Name: "group"},
+				Vm:               []types.ManagedObjectReference{vm("vm-1")},
+			},
+			&types.ClusterHostGroup{
+				ClusterGroupInfo: types.ClusterGroupInfo{Name: "hosts"},
+				Host:             []types.ManagedObjectReference{{Type: Host, Value: "host-1"}},
+			},
+		},
+	})
+	cluster := adapter.model
+	if len(cluster.DrsRules) != 3 {
+		t.Fatalf("expected 3 rules, got %d", len(cluster.DrsRules))
+	}
+	expected := []model.DrsRule{
+		{
+			Key:     1,
+			Name:    "together",
+			Kind:    model.DrsAffinity,
+			Enabled: true,
+			VMs:     []model.Ref{{Kind: model.VmKind, ID: "vm-1"}, {Kind: model.VmKind, ID: "vm-2"}},
+		},
+		{
+			Key:       2,
+			Name:      "apart",
+			Kind:      model.DrsAntiAffinity,
+			Enabled:   true,
+			Mandatory: true,
+			VMs:       []model.Ref{{Kind: model.VmKind, ID: "vm-2"}, {Kind: model.VmKind, ID: "vm-3"}},
+		},
+		{
+			Key:       3,
+			Name:      "off-host",
+			Kind:      model.DrsVmHostAntiAffinity,
+			VmGroup:   "group",
+			HostGroup: "hosts",
+		},
+	}
+	if !reflect.DeepEqual(cluster.DrsRules, expected) {
+		t.Errorf("unexpected rules: %+v", cluster.DrsRules)
+	}
+	if len(cluster.DrsGroups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(cluster.DrsGroups))
+	}
+	// The rules of the VMs.
+	names := func(rules []model.DrsRule) (names []string) {
+		for _, rule := range rules {
+			names = append(names, rule.Name)
+		}
+		return
+	}
+	if got := names(cluster.RulesOf("vm-1")); !reflect.DeepEqual(got, []string{"together", "off-host"}) {
+		t.Errorf("unexpected rules of vm-1: %v", got)
+	}
+	if got := names(cluster.RulesOf("vm-3")); !reflect.DeepEqual(got, []string{"apart"}) {
+		t.Errorf("unexpected rules of vm-3: %v", got)
+	}
+	if got := cluster.RulesOf("vm-4"); len(got) != 0 {
+		t.Errorf("unexpected rules of vm-4: %v", got)
+	}
+}
@@ -0,0 +1,196 @@
+package vsphere
+
+import (
+	"context"
+	"errors"
+	liburl "net/url"
+	"reflect"
+	"time"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// Tag settings.
+const (
+	// Tag refresh interval.
+	TagRefreshInterval = time.Minute * 5
+	// Max objects in each tag association query.
+	MaxTagObjects = 1000
+)
+
+// Refresh the tags attached to the VMs.
+// The tags are managed by the vCenter tagging service (vAPI)
+// rather than the property collector so they are polled once
+// the collector has parity. ESXi does not provide tagging.
+func (r *Collector) refreshTags(ctx context.Context) {
+	delay := RetryDelay
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if !r.parity {
+			delay = RetryDelay
+			continue
+		}
+		err := r.getTags(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.log.Error(
+				err,
+				"refresh tags failed.",
+				"retry",
+				TagRefreshInterval)
+		}
+		delay = TagRefreshInterval
+	}
+}
+
+// Get the tags attached to the VMs and
+// update the VMs with changed tags.
+func (r *Collector) getTags(ctx context.Context) (err error) {
+	client, err := r.buildClient(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = client.Logout(context.Background())
+		client.CloseIdleConnections()
+	}()
+	if !client.IsVC() {
+		return
+	}
+	rc := rest.NewClient(client.Client)
+	err = rc.Login(ctx, liburl.UserPassword(r.user(), r.password()))
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	defer func() {
+		_ = rc.Logout(context.Background())
+	}()
+	manager := tags.NewManager(rc)
+	categories, err := manager.GetCategories(ctx)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	categoryNames := make(map[string]string)
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+	}
+	vms := []model.VM{}
+	err = r.db.List(&vms, libmodel.FilterOptions{Detail: model.MaxDetail})
+	if err != nil {
+		return
+	}
+	attached := make(map[string][]model.Tag)
+	for start := 0; start < len(vms); start += MaxTagObjects {
+		end := min(start+MaxTagObjects, len(vms))
+		refs := []mo.Reference{}
+		for _, vm := range vms[start:end] {
+			refs = append(
+				refs,
+				types.ManagedObjectReference{
+					Type:  VirtualMachine,
+					Value: vm.ID,
+				})
+		}
+		var found []tags.AttachedTags
+		found, err = manager.GetAttachedTagsOnObjects(ctx, refs)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		for _, object := range found {
+			id := object.ObjectID.Reference().Value
+			attached[id] = vmTags(object.Tags, categoryNames)
+		}
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return
+	}
+	defer func() {
+		_ = tx.End()
+	}()
+	updated := 0
+	for i := range vms {
+		found := attached[vms[i].ID]
+		if reflect.DeepEqual(vms[i].Tags, found) {
+			continue
+		}
+		// Updated within the transaction so the changes
+		// applied by the collector meanwhile are kept.
+		vm := &model.VM{Base: model.Base{ID: vms[i].ID}}
+		err = tx.Get(vm)
+		if err != nil {
+			if errors.Is(err, model.NotFound) {
+				err = nil
+				continue
+			}
+			return
+		}
+		vm.Tags = found
+		err = tx.Update(vm)
+		if err != nil {
+			return
+		}
+		updated++
+	}
+	err = tx.Commit()
+	if err != nil {
+		return
+	}
+
+	r.log.V(3).Info(
+		"Tags refreshed.",
+		"updated",
+		updated)
+
+	return
+}
+
+// Keep the tags of a persisted VM entered again while
+// the inventory is resynchronized. The tags are not
+// reported by the property collector.
+func (r *Collector) keepTags(tx *libmodel.Tx, m libmodel.Model) (err error) {
+	vm, cast := m.(*model.VM)
+	if !cast {
+		return
+	}
+	persisted := &model.VM{Base: model.Base{ID: vm.ID}}
+	err = tx.Get(persisted)
+	if err != nil {
+		if errors.Is(err, model.NotFound) {
+			err = nil
+		}
+		return
+	}
+	vm.Tags = persisted.Tags
+	return
+}
+
+// Build the VM tags.
+// Returns nil when no tags are attached.
+func vmTags(attached []tags.Tag, categoryNames map[string]string) (list []model.Tag) {
+	for _, tag := range attached {
+		list = append(
+			list,
+			model.Tag{
+				ID:       tag.ID,
+				Category: categoryNames[tag.CategoryID],
+				Name:     tag.Name,
+			})
+	}
+	return
+}
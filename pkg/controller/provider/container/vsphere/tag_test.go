@@ -0,0 +1,135 @@
+package vsphere
+
+import (
+	"context"
+
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25/types"
+	core "k8s.io/api/core/v1"
+
+	_ "github.com/vmware/govmomi/vapi/simulator"
+)
+
+var _ = Describe("vSphere collector metadata", func() {
+	It("should collect the tags and custom attributes of the VMs", func() {
+		db := libmodel.New("/tmp/test-vsphere-tags.db", model.All()...)
+		Expect(db.Open(true)).To(Succeed())
+		defer func() {
+			_ = db.Close(true)
+		}()
+		vcsim := simulator.VPX()
+		Expect(vcsim.Create()).To(Succeed())
+		defer vcsim.Remove()
+		vcsim.Service.RegisterEndpoints = true
+		server := vcsim.Service.NewServer()
+		defer server.Close()
+		password, _ := server.URL.User.Password()
+		url := *server.URL
+		url.User = nil
+		collector := New(
+			db,
+			&api.Provider{Spec: api.ProviderSpec{URL: url.String()}},
+			&core.Secret{
+				Data: map[string][]byte{
+					"user":               []byte(server.URL.User.Username()),
+					"password":           []byte(password),
+					"insecureSkipVerify": []byte("true"),
+				},
+			})
+		collector.log = logging.WithName("test")
+
+		ctx := context.Background()
+		client, err := collector.buildClient(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		defer func() {
+			_ = client.Logout(ctx)
+		}()
+		vm, err := find.NewFinder(client.Client).VirtualMachine(ctx, "DC0_H0_VM0")
+		Expect(err).ToNot(HaveOccurred())
+		other, err := find.NewFinder(client.Client).VirtualMachine(ctx, "DC0_H0_VM1")
+		Expect(err).ToNot(HaveOccurred())
+		for _, ref := range []types.ManagedObjectReference{vm.Reference(), other.Reference()} {
+			Expect(db.Insert(&model.VM{Base: model.Base{ID: ref.Value}})).To(Succeed())
+		}
+
+		// Tags.
+		rc := rest.NewClient(client.Client)
+		Expect(rc.Login(ctx, server.URL.User)).To(Succeed())
+		manager := tags.NewManager(rc)
+		category, err := manager.CreateCategory(ctx, &tags.Category{Name: "env"})
+		Expect(err).ToNot(HaveOccurred())
+		tag, err := manager.CreateTag(ctx, &tags.Tag{CategoryID: category, Name: "prod"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manager.AttachTag(ctx, tag, vm.Reference())).To(Succeed())
+		Expect(collector.getTags(ctx)).To(Succeed())
+		tagged := &model.VM{Base: model.Base{ID: vm.Reference().Value}}
+		Expect(db.Get(tagged)).To(Succeed())
+		Expect(tagged.Tags).To(Equal([]model.Tag{{ID: tag, Category: "env", Name: "prod"}}))
+		Expect(tagged.HasTag("env", "prod")).To(BeTrue())
+		untagged := &model.VM{Base: model.Base{ID: other.Reference().Value}}
+		Expect(db.Get(untagged)).To(Succeed())
+		Expect(untagged.Tags).To(BeEmpty())
+		// Detached.
+		Expect(manager.DetachTag(ctx, tag, vm.Reference())).To(Succeed())
+		Expect(collector.getTags(ctx)).To(Succeed())
+		Expect(db.Get(tagged)).To(Succeed())
+		Expect(tagged.Tags).To(BeEmpty())
+
+		// Custom attributes.
+		fields := object.NewCustomFieldsManager(client.Client)
+		owner, err := fields.Add(ctx, "owner", VirtualMachine, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = fields.Add(ctx, "team", VirtualMachine, nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fields.Set(ctx, vm.Reference(), owner.Key, "alice")).To(Succeed())
+		content := []types.ObjectContent{}
+		err = client.PropertyCollector().Retrieve(
+			ctx,
+			[]types.ManagedObjectReference{vm.Reference()},
+			[]string{fAvailableField, fCustomValue},
+			&content)
+		Expect(err).ToNot(HaveOccurred())
+		update := types.ObjectUpdate{Obj: vm.Reference(), Kind: Enter}
+		for _, p := range content[0].PropSet {
+			update.ChangeSet = append(
+				update.ChangeSet,
+				types.PropertyChange{Name: p.Name, Op: Assign, Val: p.Val})
+		}
+		adapter := &VmAdapter{model: model.VM{Base: model.Base{ID: vm.Reference().Value}}}
+		adapter.Apply(update)
+		value, found := adapter.model.Attribute("owner")
+		Expect(found).To(BeTrue())
+		Expect(value).To(Equal("alice"))
+		_, found = adapter.model.Attribute("team")
+		Expect(found).To(BeFalse())
+		Expect(adapter.model.CustomAttributes).To(HaveLen(2))
+	})
+
+	It("should keep the values of fields not yet defined", func() {
+		adapter := &VmAdapter{}
+		adapter.setAttributes([]types.BaseCustomFieldValue{
+			&types.CustomFieldStringValue{CustomFieldValue: types.CustomFieldValue{Key: 1}, Value: "alice"},
+		})
+		Expect(adapter.model.CustomAttributes).To(Equal([]model.Attribute{{Key: 1, Value: "alice"}}))
+		adapter.defineAttributes([]types.CustomFieldDef{{Key: 1, Name: "owner"}, {Key: 2, Name: "team"}})
+		Expect(adapter.model.CustomAttributes).To(Equal([]model.Attribute{
+			{Key: 1, Name: "owner", Value: "alice"},
+			{Key: 2, Name: "team"},
+		}))
+		adapter.setAttributes(nil)
+		Expect(adapter.model.CustomAttributes).To(Equal([]model.Attribute{
+			{Key: 1, Name: "owner"},
+			{Key: 2, Name: "team"},
+		}))
+	})
+})
@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -33,7 +34,9 @@ import (
 	"github.com/kubev2v/forklift/pkg/controller/provider/container"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	planweb "github.com/kubev2v/forklift/pkg/controller/provider/web/plan"
 	"github.com/kubev2v/forklift/pkg/controller/validation/policy"
+	"github.com/kubev2v/forklift/pkg/controller/validation/rules"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libfb "github.com/kubev2v/forklift/pkg/lib/filebacked"
@@ -76,7 +79,8 @@ var Settings = &settings.Settings
 func Add(mgr manager.Manager) error {
 	libfb.WorkingDir = Settings.WorkingDir
 	container := libcontainer.New()
-	web := libweb.New(container, web.All(container)...)
+	handlers := append(web.All(container), planweb.Handlers(container)...)
+	web := libweb.New(container, handlers...)
 	web.Port = Settings.Inventory.Port
 	if Settings.Inventory.TLS.Key != "" {
 		web.TLS.Enabled = true
@@ -97,6 +101,32 @@ func Add(mgr manager.Manager) error {
 
 	web.Start()
 
+	if Settings.PolicyAgent.RulesEnabled() {
+		watchClient, err := client.NewWithWatch(
+			mgr.GetConfig(),
+			client.Options{
+				Scheme: mgr.GetScheme(),
+				Mapper: mgr.GetRESTMapper(),
+			})
+		if err != nil {
+			log.Trace(err)
+			return err
+		}
+		engine, err := rules.New(
+			watchClient,
+			Settings.Inventory.Namespace,
+			Settings.PolicyAgent.Rules.ConfigMap)
+		if err != nil {
+			log.Trace(err)
+			return err
+		}
+		err = mgr.Add(manager.RunnableFunc(engine.Run))
+		if err != nil {
+			log.Trace(err)
+			return err
+		}
+		policy.Agent.Rules = engine
+	}
 	policy.Agent.Start()
 
 	cnt, err := controller.New(
@@ -215,7 +245,7 @@ func (r Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (r
 		}
 	}()
 
-	if provider.Type() == api.Ova && provider.DeletionTimestamp == nil {
+	if provider.UsesApplianceServer() && provider.DeletionTimestamp == nil {
 		if !provider.HasReconciled() {
 			// the provider has changed, so delete the old
 			// so we can redeploy.
@@ -354,12 +384,12 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 		r.Log.V(2).Info(
 			"Shutdown found collector.")
 	}
-	db := r.getDB(provider)
+	db, reused := r.getDB(provider)
 	secret, err := r.getSecret(provider)
 	if err != nil {
 		return
 	}
-	err = db.Open(true)
+	err = db.Open(!reused)
 	if err != nil {
 		return
 	}
@@ -373,11 +403,22 @@ func (r *Reconciler) updateContainer(provider *api.Provider) (err error) {
 	r.Log.V(2).Info(
 		"Data collector added/started.")
 
+	history := container.NewHistory(provider, collector)
+	if history != nil {
+		err = history.Start()
+		if err != nil {
+			return
+		}
+	}
+
 	return
 }
 
 // Build DB for provider.
-func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
+// When the inventory is persistent, the DB is reused when
+// it has been built with the same schema so the collector
+// may resume from its checkpoint.
+func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB, reused bool) {
 	dir := Settings.Inventory.WorkingDir
 	dir = filepath.Join(
 		dir,
@@ -388,10 +429,46 @@ func (r *Reconciler) getDB(provider *api.Provider) (db libmodel.DB) {
 	path := filepath.Join(dir, file)
 	models := model.Models(provider)
 	db = libmodel.New(path, models...)
+	if Settings.Inventory.Persistent {
+		reused = r.reusable(dir, path, models)
+	}
 	r.Log.Info(
 		"Opening DB.",
 		"path",
-		path)
+		path,
+		"reused",
+		reused)
+	return
+}
+
+// Determine whether the persisted DB may be reused.
+// The DBs of former providers with the same name are deleted.
+// The schema digest is stored next to the DB and the DB is
+// not reused when the schema has changed.
+func (r *Reconciler) reusable(dir, path string, models []interface{}) (reused bool) {
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		found := filepath.Join(dir, entry.Name())
+		if strings.HasPrefix(found, path) {
+			continue
+		}
+		_ = os.Remove(found)
+	}
+	dm, err := libmodel.NewModel(models)
+	if err != nil {
+		return
+	}
+	digest, err := dm.Digest()
+	if err != nil {
+		return
+	}
+	stored, err := os.ReadFile(path + ".schema")
+	_, statErr := os.Stat(path)
+	reused = err == nil && statErr == nil && string(stored) == digest
+	if !reused {
+		_ = os.WriteFile(path+".schema", []byte(digest), 0644)
+	}
+
 	return
 }
 
@@ -2,6 +2,7 @@ package base
 
 import (
 	"fmt"
+	"strconv"
 
 	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
 )
@@ -50,3 +51,62 @@ type Concern struct {
 	Category   string `json:"category"`
 	Assessment string `json:"assessment"`
 }
+
+// Collector checkpoint.
+// Persisted with the inventory so that the
+// collector may resume after a restart.
+type Checkpoint struct {
+	// Collector name.
+	ID string `sql:"pk"`
+	// Provider URL.
+	// The token is only valid for the same URL.
+	URL string `sql:""`
+	// Provider specific resume token.
+	// Empty when the collector cannot resume.
+	Token string `sql:""`
+}
+
+// Get the PK.
+func (m *Checkpoint) Pk() string {
+	return m.ID
+}
+
+// Change actions.
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated"
+	ChangeDeleted = "deleted"
+)
+
+// Model change.
+// Retained (bounded) with the inventory so the
+// history of changes to a model may be reported.
+type Change struct {
+	// Serial number.
+	ID int64 `sql:"pk"`
+	// Kind of the changed model.
+	Kind string `sql:"d0,index(subject)"`
+	// ID of the changed model.
+	Subject string `sql:"d0,index(subject)"`
+	// Action (created|updated|deleted).
+	Action string `sql:"d0"`
+	// Time (unix milliseconds).
+	Time int64 `sql:"d0,index(time)"`
+	// Changed fields.
+	Fields []FieldChange `sql:""`
+}
+
+// Get the PK.
+func (m *Change) Pk() string {
+	return strconv.FormatInt(m.ID, 10)
+}
+
+// Changed field.
+type FieldChange struct {
+	// Field name.
+	Name string `json:"name"`
+	// Former value.
+	Old interface{} `json:"old,omitempty"`
+	// Updated value.
+	New interface{} `json:"new,omitempty"`
+}
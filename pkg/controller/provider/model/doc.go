@@ -2,6 +2,7 @@ package model
 
 import (
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/hyperv"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/ova"
@@ -32,6 +33,10 @@ func Models(provider *api.Provider) (all []interface{}) {
 		all = append(
 			all,
 			ova.All()...)
+	case api.HyperV:
+		all = append(
+			all,
+			hyperv.All()...)
 	}
 
 	return
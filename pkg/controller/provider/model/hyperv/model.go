@@ -0,0 +1,112 @@
+package hyperv
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Errors
+var NotFound = libmodel.NotFound
+
+type InvalidRefError = base.InvalidRefError
+
+const (
+	MaxDetail = base.MaxDetail
+)
+
+// Types
+type ListOptions = base.ListOptions
+type Concern = base.Concern
+type Ref = base.Ref
+
+// Model.
+type Model interface {
+	base.Model
+	GetName() string
+}
+
+// Base Hyper-V model.
+type Base struct {
+	// Managed object ID.
+	ID string `sql:"pk"`
+	// Variant
+	Variant string `sql:"d0,index(variant)"`
+	// Name
+	Name string `sql:"d0,index(name)"`
+	// Revision
+	Revision int64 `sql:"incremented,d0,index(revision)"`
+}
+
+func (m *Base) Pk() string {
+	return m.ID
+}
+
+// String representation.
+func (m *Base) String() string {
+	return m.ID
+}
+
+// Get labels.
+func (m *Base) Labels() libmodel.Labels {
+	return nil
+}
+
+// Name.
+func (m *Base) GetName() string {
+	return m.Name
+}
+
+// Determine if current revision has been validated.
+func (m *VM) Validated() bool {
+	return m.RevisionValidated == m.Revision
+}
+
+// Virtual switch.
+type Network struct {
+	Base
+	Description string `sql:"" json:"description"`
+}
+
+type VM struct {
+	Base
+	UUID              string    `sql:""`
+	ExportPath        string    `sql:""`
+	ConfigPath        string    `sql:""`
+	RevisionValidated int64     `sql:"d0,index(revisionValidated)"`
+	PolicyVersion     int       `sql:"d0,index(policyVersion)"`
+	Generation        int       `sql:""`
+	Firmware          string    `sql:""`
+	SecureBoot        bool      `sql:""`
+	CpuCount          int32     `sql:""`
+	MemoryMB          int32     `sql:""`
+	DynamicMemory     bool      `sql:""`
+	StorageUsed       int64     `sql:""`
+	NICs              []NIC     `sql:""`
+	Disks             []Disk    `sql:""`
+	Networks          []Network `sql:""`
+	Concerns          []Concern `sql:""`
+}
+
+// Virtual Disk.
+type Disk struct {
+	Base
+	FilePath      string `sql:"" json:"filePath"`
+	SourcePath    string `sql:"" json:"sourcePath"`
+	Format        string `sql:"" json:"format"`
+	Bus           string `sql:"" json:"bus"`
+	Controller    int    `sql:"" json:"controller"`
+	Unit          int    `sql:"" json:"unit"`
+	Capacity      int64  `sql:"" json:"capacity"`
+	PopulatedSize int64  `sql:"" json:"populatedSize"`
+}
+
+// Virtual network adapter.
+type NIC struct {
+	Name    string `sql:"" json:"name"`
+	MAC     string `sql:"" json:"mac"`
+	Network string `sql:"" json:"network"`
+}
+
+type Storage struct {
+	Base
+}
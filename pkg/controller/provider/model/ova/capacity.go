@@ -0,0 +1,50 @@
+package ova
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Capacity (bytes) of the disk.
+func (r *Disk) Bytes() (int64, error) {
+	return ResourceCapacity(r.Capacity, r.CapacityAllocationUnits)
+}
+
+// Convert a capacity expressed in OVF allocation
+// units (e.g. "byte * 2^30") to bytes.
+func ResourceCapacity(capacity int64, units string) (int64, error) {
+	if strings.ToLower(units) == "megabytes" {
+		return capacity * (1 << 20), nil
+	}
+	items := strings.Split(units, "*")
+	for i := range items {
+		item := strings.TrimSpace(items[i])
+		if i == 0 && len(item) > 0 && item != "byte" {
+			return 0, fmt.Errorf("units '%s' are invalid, only 'byte' is supported", units)
+		}
+		if i == 0 {
+			continue
+		}
+		num, err := strconv.Atoi(item)
+		if err == nil {
+			capacity = capacity * int64(num)
+			continue
+		}
+		nums := strings.Split(item, "^")
+		if len(nums) != 2 {
+			return 0, fmt.Errorf("units '%s' are invalid, item is invalid: %s", units, item)
+		}
+		base, err := strconv.Atoi(nums[0])
+		if err != nil {
+			return 0, fmt.Errorf("units '%s' are invalid, base component is invalid: %s", units, item)
+		}
+		pow, err := strconv.Atoi(nums[1])
+		if err != nil {
+			return 0, fmt.Errorf("units '%s' are invalid, pow component is invalid: %s", units, item)
+		}
+		capacity = capacity * int64(math.Pow(float64(base), float64(pow)))
+	}
+	return capacity, nil
+}
@@ -18,6 +18,8 @@ const (
 type Model = base.Model
 type ListOptions = base.ListOptions
 type Concern = base.Concern
+type Checkpoint = base.Checkpoint
+type Change = base.Change
 type Ref = base.Ref
 
 // Base oVirt model.
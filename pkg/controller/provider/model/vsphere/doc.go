@@ -8,6 +8,8 @@ import (
 func All() []interface{} {
 	return []interface{}{
 		&ocp.Provider{},
+		&Checkpoint{},
+		&Change{},
 		&About{},
 		&Folder{},
 		&Datacenter{},
@@ -40,6 +40,8 @@ const (
 // Types
 type ListOptions = base.ListOptions
 type Concern = base.Concern
+type Checkpoint = base.Checkpoint
+type Change = base.Change
 type Ref = base.Ref
 
 // Model.
@@ -125,15 +127,86 @@ type Datacenter struct {
 
 type Cluster struct {
 	Base
-	Folder      string `sql:"d0,index(folder)"`
-	Hosts       []Ref  `sql:""`
-	Networks    []Ref  `sql:""`
-	Datastores  []Ref  `sql:""`
-	DasEnabled  bool   `sql:""`
-	DasVms      []Ref  `sql:""`
-	DrsEnabled  bool   `sql:""`
-	DrsBehavior string `sql:""`
-	DrsVms      []Ref  `sql:""`
+	Folder      string     `sql:"d0,index(folder)"`
+	Hosts       []Ref      `sql:""`
+	Networks    []Ref      `sql:""`
+	Datastores  []Ref      `sql:""`
+	DasEnabled  bool       `sql:""`
+	DasVms      []Ref      `sql:""`
+	DrsEnabled  bool       `sql:""`
+	DrsBehavior string     `sql:""`
+	DrsVms      []Ref      `sql:""`
+	DrsRules    []DrsRule  `sql:""`
+	DrsGroups   []DrsGroup `sql:""`
+}
+
+// Find the group by name.
+func (m *Cluster) DrsGroup(name string) (group *DrsGroup, found bool) {
+	for i := range m.DrsGroups {
+		if m.DrsGroups[i].Name == name {
+			group = &m.DrsGroups[i]
+			found = true
+			return
+		}
+	}
+	return
+}
+
+// The rules of the VM.
+// The members of the VM-Host rules are the VMs of the VM group.
+func (m *Cluster) RulesOf(vmID string) (rules []DrsRule) {
+	for _, rule := range m.DrsRules {
+		switch rule.Kind {
+		case DrsVmHostAffinity, DrsVmHostAntiAffinity:
+			group, found := m.DrsGroup(rule.VmGroup)
+			if !found {
+				continue
+			}
+			rule.VMs = group.VMs
+		}
+		for _, ref := range rule.VMs {
+			if ref.ID == vmID {
+				rules = append(rules, rule)
+				break
+			}
+		}
+	}
+	return
+}
+
+// DRS rule kinds.
+const (
+	// VMs kept together.
+	DrsAffinity = "Affinity"
+	// VMs kept apart.
+	DrsAntiAffinity = "AntiAffinity"
+	// VMs of the VM group kept on the hosts of the host group.
+	DrsVmHostAffinity = "VmHostAffinity"
+	// VMs of the VM group kept off the hosts of the host group.
+	DrsVmHostAntiAffinity = "VmHostAntiAffinity"
+)
+
+// DRS rule.
+type DrsRule struct {
+	Key     int32  `json:"key"`
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Enabled bool   `json:"enabled"`
+	// Required rather than preferred.
+	Mandatory bool `json:"mandatory"`
+	// Member VMs (VM-VM rules).
+	VMs []Ref `json:"vms,omitempty"`
+	// VM group (VM-Host rules).
+	VmGroup string `json:"vmGroup,omitempty"`
+	// Host group (VM-Host rules).
+	HostGroup string `json:"hostGroup,omitempty"`
+}
+
+// DRS group of VMs or hosts.
+type DrsGroup struct {
+	Name  string `json:"name"`
+	VMs   []Ref  `json:"vms,omitempty"`
+	Hosts []Ref  `json:"hosts,omitempty"`
 }
 
 type Host struct {
@@ -333,6 +406,8 @@ type VM struct {
 	ToolsVersionStatus       string           `sql:""`
 	DiskEnableUuid           bool             `sql:""`
 	NestedHVEnabled          bool             `sql:""`
+	Tags                     []Tag            `sql:""`
+	CustomAttributes         []Attribute      `sql:""`
 }
 
 // Determine if current revision has been validated.
@@ -340,6 +415,39 @@ func (m *VM) Validated() bool {
 	return m.RevisionValidated == m.Revision
 }
 
+// Names of the tags in the category.
+func (m *VM) TagNames(category string) (names []string) {
+	for _, tag := range m.Tags {
+		if tag.Category == category {
+			names = append(names, tag.Name)
+		}
+	}
+	return
+}
+
+// Determine if the tag is attached.
+func (m *VM) HasTag(category, name string) bool {
+	for _, tag := range m.Tags {
+		if tag.Category == category && tag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Find the value of a custom attribute by name.
+// Attributes without a value are not found.
+func (m *VM) Attribute(name string) (value string, found bool) {
+	for _, attribute := range m.CustomAttributes {
+		if attribute.Name == name && attribute.Value != "" {
+			value = attribute.Value
+			found = true
+			return
+		}
+	}
+	return
+}
+
 // Virtual Controller.
 type Controller struct {
 	Key   int32   `json:"key"`
@@ -363,6 +471,23 @@ type Disk struct {
 	WinDriveLetter        string `json:"winDriveLetter,omitempty"`
 	ChangeTrackingEnabled bool   `json:"changeTrackingEnabled"`
 	ParentFile            string `json:"parent"`
+	Label                 string `json:"label,omitempty"`
+}
+
+// Tag attached to the VM.
+type Tag struct {
+	ID       string `json:"id"`
+	Category string `json:"category"`
+	Name     string `json:"name"`
+}
+
+// Custom attribute of the VM.
+// Attributes defined for VMs without a value
+// are listed with an empty value.
+type Attribute struct {
+	Key   int32  `json:"key"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 // Virtual Device.
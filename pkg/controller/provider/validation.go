@@ -6,11 +6,11 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strings"
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/controller/base"
+	"github.com/kubev2v/forklift/pkg/controller/ova"
 	"github.com/kubev2v/forklift/pkg/controller/provider/container"
 	vsphere "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
 	libcnd "github.com/kubev2v/forklift/pkg/lib/condition"
@@ -157,8 +157,8 @@ func (r *Reconciler) validateURL(provider *api.Provider) error {
 				Message:  "The `url` is not valid.",
 			})
 	}
-	if provider.Type() == api.Ova {
-		if !isValidNFSPath(provider.Spec.URL) {
+	if provider.UsesApplianceServer() {
+		if !isValidSharePath(provider.Spec.URL) {
 			provider.Status.Phase = ValidationFailed
 			provider.Status.SetCondition(
 				libcnd.Condition{
@@ -166,7 +166,7 @@ func (r *Reconciler) validateURL(provider *api.Provider) error {
 					Status:   True,
 					Reason:   Malformed,
 					Category: Critical,
-					Message:  "The NFS path is malformed",
+					Message:  "The NFS/SMB path is malformed",
 				})
 		}
 		return nil
@@ -313,10 +313,13 @@ func (r *Reconciler) validateSecret(provider *api.Provider) (secret *core.Secret
 		} else {
 			keyList = append(keyList, "cacert")
 		}
-	case api.Ova:
+	case api.Ova, api.HyperV:
 		keyList = []string{
 			"url",
 		}
+		if (ova.Share{URL: provider.Spec.URL}).IsSMB() {
+			keyList = append(keyList, "username", "password")
+		}
 	}
 	for _, key := range keyList {
 		if _, found := secret.Data[key]; !found {
@@ -419,10 +422,8 @@ func (r *Reconciler) inventoryCreated(provider *api.Provider) error {
 	return nil
 }
 
-func isValidNFSPath(nfsPath string) bool {
-	nfsRegex := `^[^:]+:\/[^:].*$`
-	re := regexp.MustCompile(nfsRegex)
-	return re.MatchString(nfsPath)
+func isValidSharePath(path string) bool {
+	return ova.Share{URL: path}.Valid()
 }
 
 // hostInfo holds information about a host for SSH testing
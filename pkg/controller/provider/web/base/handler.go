@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
 	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
 	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -61,6 +62,8 @@ type Handler struct {
 	Collector libcontainer.Collector
 	// Resources detail level.
 	Detail int
+	// Collection query.
+	Query Query
 }
 
 // Prepare to handle the request.
@@ -85,6 +88,10 @@ func (h *Handler) Prepare(ctx *gin.Context) (int, error) {
 	if status != http.StatusOK {
 		return status, err
 	}
+	status, err = h.setQuery(ctx)
+	if status != http.StatusOK {
+		return status, err
+	}
 
 	return http.StatusOK, nil
 }
@@ -142,6 +149,31 @@ func (h *Handler) setDetail(ctx *gin.Context) (status int) {
 	return
 }
 
+// Set the collection query.
+// The filter and sort are not supported by the OpenShift
+// provider (not stored in the inventory DB). The projection
+// is not applied to watch requests.
+func (h *Handler) setQuery(ctx *gin.Context) (status int, err error) {
+	status = http.StatusOK
+	err = h.Query.With(ctx)
+	if err != nil {
+		status = http.StatusBadRequest
+		return
+	}
+	if h.Provider.Type() == api.OpenShift {
+		if h.Query.Predicate != nil || len(h.Query.Sort) > 0 {
+			err = liberr.New("filter and sort not supported by the provider.")
+			status = http.StatusBadRequest
+			return
+		}
+	}
+	if !h.WatchRequest {
+		h.Query.Project(ctx)
+	}
+
+	return
+}
+
 func (h *Handler) Token(ctx *gin.Context) string {
 	return DefaultAuth.Token(ctx)
 }
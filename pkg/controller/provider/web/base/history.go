@@ -0,0 +1,148 @@
+package base
+
+import (
+	"net/http"
+	liburl "net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Routes.
+const (
+	HistoryCollection = "history"
+)
+
+// Params.
+const (
+	// Report the changes made after (RFC3339) time.
+	SinceParam = "since"
+)
+
+// History handler.
+// Reports the retained changes to a model.
+type HistoryHandler struct {
+	Handler
+	// Kind of the model.
+	Kind string
+	// Route of the model.
+	Root string
+	// Route param (ID) of the model.
+	Param string
+}
+
+// Add routes to the `gin` router.
+func (h *HistoryHandler) AddRoutes(e *gin.Engine) {
+	e.GET(h.Root+"/"+HistoryCollection, h.Get)
+}
+
+// Get the change history of the model.
+func (h HistoryHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		SetForkliftError(ctx, err)
+		return
+	}
+	content := History{
+		Kind:    h.Kind,
+		ID:      ctx.Param(h.Param),
+		Changes: []Change{},
+	}
+	predicates := []libmodel.Predicate{
+		libmodel.Eq("Kind", content.Kind),
+		libmodel.Eq("Subject", content.ID),
+	}
+	pSince := ctx.Query(SinceParam)
+	if len(pSince) > 0 {
+		since, pErr := time.Parse(time.RFC3339, pSince)
+		if pErr != nil {
+			ctx.Status(http.StatusBadRequest)
+			SetForkliftError(ctx, pErr)
+			return
+		}
+		content.Since = &since
+		predicates = append(predicates, libmodel.Gt("Time", since.UnixMilli()))
+	}
+	list := []model.Change{}
+	err = h.Collector.DB().List(
+		&list,
+		libmodel.ListOptions{
+			Predicate: libmodel.And(predicates...),
+			SortBy:    []string{"ID"},
+			Detail:    model.MaxDetail,
+		})
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	for _, m := range list {
+		content.Changes = append(
+			content.Changes,
+			Change{
+				ID:     m.ID,
+				Action: m.Action,
+				Time:   time.UnixMilli(m.Time).UTC(),
+				Fields: m.Fields,
+			})
+	}
+
+	ctx.JSON(http.StatusOK, content)
+}
+
+// Change history (REST resource) of a model.
+type History struct {
+	// Kind of the model.
+	Kind string `json:"kind"`
+	// ID of the model.
+	ID string `json:"id"`
+	// Changes reported (made after) since.
+	Since *time.Time `json:"since,omitempty"`
+	// Changes (oldest first).
+	Changes []Change `json:"changes"`
+}
+
+// Build the path of the history of the model
+// using the model (self) link.
+func (r *History) Link(path string) string {
+	path += "/" + HistoryCollection
+	if r.Since != nil {
+		q := liburl.Values{}
+		q.Set(SinceParam, r.Since.UTC().Format(time.RFC3339))
+		path += "?" + q.Encode()
+	}
+	return path
+}
+
+// Fields changed.
+// Names of the fields changed (once each) in order.
+func (r *History) Fields() (names []string) {
+	found := map[string]bool{}
+	for _, change := range r.Changes {
+		for _, f := range change.Fields {
+			if !found[f.Name] {
+				found[f.Name] = true
+				names = append(names, f.Name)
+			}
+		}
+	}
+	return
+}
+
+// Model change (REST) resource.
+type Change struct {
+	// Serial number.
+	ID int64 `json:"id"`
+	// Action (created|updated|deleted).
+	Action string `json:"action"`
+	// Time.
+	Time time.Time `json:"time"`
+	// Changed fields.
+	Fields []model.FieldChange `json:"fields,omitempty"`
+}
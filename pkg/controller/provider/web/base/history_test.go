@@ -0,0 +1,31 @@
+package base
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	"github.com/onsi/gomega"
+)
+
+func TestHistory(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	history := History{}
+	g.Expect(history.Link("/providers/vsphere/p1/vms/vm-1")).To(
+		gomega.Equal("/providers/vsphere/p1/vms/vm-1/history"))
+	since := time.Date(2026, 10, 1, 12, 30, 0, 0, time.UTC)
+	history.Since = &since
+	g.Expect(history.Link("/providers/vsphere/p1/vms/vm-1")).To(
+		gomega.Equal("/providers/vsphere/p1/vms/vm-1/history?since=2026-10-01T12%3A30%3A00Z"))
+	history.Changes = []Change{
+		{
+			Action: model.ChangeUpdated,
+			Fields: []model.FieldChange{{Name: "Host"}, {Name: "Disks"}},
+		},
+		{
+			Action: model.ChangeUpdated,
+			Fields: []model.FieldChange{{Name: "Disks"}, {Name: "NICs"}},
+		},
+	}
+	g.Expect(history.Fields()).To(gomega.Equal([]string{"Host", "Disks", "NICs"}))
+}
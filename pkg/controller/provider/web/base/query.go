@@ -0,0 +1,418 @@
+package base
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Query params.
+const (
+	// Filter expression.
+	FilterParam = "filter"
+	// Sort keys.
+	SortParam = "sort"
+	// Projected fields.
+	FieldsParam = "fields"
+)
+
+// Label field prefix.
+const LabelPrefix = "label."
+
+// Query syntax error.
+var QuerySyntaxErr = errors.New("query syntax not valid")
+
+// Collection query.
+// Built using the query params:
+//
+//	filter=<expression>
+//	  comparison: <field> (=|!=|>|<) <value>
+//	  label: label.<name>=<value>
+//	  compound: <expression> (and|or) <expression>
+//	  grouped: (<expression>)
+//	  values may be quoted using (') or (").
+//	sort=<field>[,-<field>]
+//	  prefixed by (-) for descending order.
+//	fields=<field>[,<field>.<field>]
+//	  the (JSON) fields included in the reply.
+//
+// Example: ?filter=powerState=poweredOn and (cpuCount>2 or
+// label.app=web)&sort=-cpuCount,name&fields=id,name,cpuCount
+//
+// The filter and sort are applied to the collections stored
+// in the inventory DB. Field names match the model fields
+// (case-insensitive).
+type Query struct {
+	// Filter predicate.
+	Predicate libmodel.Predicate
+	// Sort keys.
+	Sort []string
+	// Projected fields.
+	Fields []string
+}
+
+// Build the query using the request params.
+func (r *Query) With(ctx *gin.Context) (err error) {
+	*r = Query{}
+	q := ctx.Request.URL.Query()
+	filter := q.Get(FilterParam)
+	if len(filter) > 0 {
+		r.Predicate, err = ParseFilter(filter)
+		if err != nil {
+			return
+		}
+	}
+	r.Sort = r.split(q.Get(SortParam))
+	for _, key := range r.Sort {
+		if strings.TrimPrefix(key, "-") == "" {
+			err = fmt.Errorf("%w: sort key '%s'", QuerySyntaxErr, key)
+			return
+		}
+	}
+	r.Fields = r.split(q.Get(FieldsParam))
+	return
+}
+
+// Report the error of a collection query.
+// The filter and sort fields not found in the model are
+// reported as a bad request. Other errors are reported
+// as an internal server error.
+func SetQueryError(ctx *gin.Context, err error) {
+	if errors.Is(err, libmodel.PredicateRefErr) || errors.Is(err, libmodel.SortRefErr) {
+		ctx.Status(http.StatusBadRequest)
+		SetForkliftError(ctx, err)
+		return
+	}
+	ctx.Status(http.StatusInternalServerError)
+}
+
+// Filter (AND) the predicate.
+func (r *Query) Filter(p libmodel.Predicate) libmodel.Predicate {
+	switch {
+	case r.Predicate == nil:
+		return p
+	case p == nil:
+		return r.Predicate
+	default:
+		return libmodel.And(p, r.Predicate)
+	}
+}
+
+// Install the projection of the (JSON) reply.
+func (r *Query) Project(ctx *gin.Context) {
+	if len(r.Fields) == 0 {
+		return
+	}
+	ctx.Writer = &projection{
+		ResponseWriter: ctx.Writer,
+		fields:         r.Fields,
+	}
+}
+
+// Split comma separated list.
+func (r *Query) split(s string) (list []string) {
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if len(item) > 0 {
+			list = append(list, item)
+		}
+	}
+	return
+}
+
+// Parse the filter expression into a predicate.
+func ParseFilter(expr string) (p libmodel.Predicate, err error) {
+	parser := filterParser{}
+	err = parser.tokenize(expr)
+	if err != nil {
+		return
+	}
+	p, err = parser.or()
+	if err != nil {
+		return
+	}
+	if !parser.done() {
+		err = parser.unexpected()
+	}
+	return
+}
+
+// Filter token.
+type token struct {
+	// Text.
+	text string
+	// Quoted (literal) value.
+	quoted bool
+}
+
+// Filter expression parser.
+type filterParser struct {
+	tokens []token
+	next   int
+}
+
+// Split the expression into tokens.
+func (r *filterParser) tokenize(expr string) (err error) {
+	operators := "=!<>()"
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(expr[i+1:], c)
+			if end == -1 {
+				err = fmt.Errorf("%w: unterminated quote at: %d", QuerySyntaxErr, i)
+				return
+			}
+			r.tokens = append(r.tokens, token{text: expr[i+1 : i+1+end], quoted: true})
+			i += end + 2
+		case strings.HasPrefix(expr[i:], "!="):
+			r.tokens = append(r.tokens, token{text: "!="})
+			i += 2
+		case strings.IndexByte(operators, c) != -1:
+			r.tokens = append(r.tokens, token{text: string(c)})
+			i++
+		default:
+			end := i
+			for end < len(expr) && !strings.ContainsRune(" \t'\""+operators, rune(expr[end])) {
+				end++
+			}
+			r.tokens = append(r.tokens, token{text: expr[i:end]})
+			i = end
+		}
+	}
+	return
+}
+
+// Parse: <and> (or <and>)*
+func (r *filterParser) or() (p libmodel.Predicate, err error) {
+	p, err = r.and()
+	if err != nil {
+		return
+	}
+	list := []libmodel.Predicate{p}
+	for r.keyword("or") {
+		p, err = r.and()
+		if err != nil {
+			return
+		}
+		list = append(list, p)
+	}
+	if len(list) > 1 {
+		p = libmodel.Or(list...)
+	}
+	return
+}
+
+// Parse: <term> (and <term>)*
+func (r *filterParser) and() (p libmodel.Predicate, err error) {
+	p, err = r.term()
+	if err != nil {
+		return
+	}
+	list := []libmodel.Predicate{p}
+	for r.keyword("and") {
+		p, err = r.term()
+		if err != nil {
+			return
+		}
+		list = append(list, p)
+	}
+	if len(list) > 1 {
+		p = libmodel.And(list...)
+	}
+	return
+}
+
+// Parse: (<or>) | <field> <operator> <value>
+func (r *filterParser) term() (p libmodel.Predicate, err error) {
+	if r.operator("(") {
+		p, err = r.or()
+		if err != nil {
+			return
+		}
+		if !r.operator(")") {
+			err = r.unexpected()
+		}
+		return
+	}
+	field, found := r.value()
+	if !found {
+		err = r.unexpected()
+		return
+	}
+	if r.done() {
+		err = r.unexpected()
+		return
+	}
+	op := r.tokens[r.next]
+	if op.quoted {
+		err = r.unexpected()
+		return
+	}
+	r.next++
+	value, found := r.value()
+	if !found {
+		err = r.unexpected()
+		return
+	}
+	if strings.HasPrefix(field, LabelPrefix) {
+		if op.text != "=" {
+			err = fmt.Errorf("%w: label supports (=) only", QuerySyntaxErr)
+			return
+		}
+		p = libmodel.Match(libmodel.Labels{
+			strings.TrimPrefix(field, LabelPrefix): value,
+		})
+		return
+	}
+	switch op.text {
+	case "=":
+		p = libmodel.Eq(field, value)
+	case "!=":
+		p = libmodel.Neq(field, value)
+	case ">":
+		p = libmodel.Gt(field, value)
+	case "<":
+		p = libmodel.Lt(field, value)
+	default:
+		r.next--
+		err = r.unexpected()
+	}
+	return
+}
+
+// Consume a field name or value.
+func (r *filterParser) value() (value string, found bool) {
+	if r.done() {
+		return
+	}
+	t := r.tokens[r.next]
+	if !t.quoted && (strings.Contains("=!=<>()", t.text) || r.isKeyword(t)) {
+		return
+	}
+	r.next++
+	value = t.text
+	found = true
+	return
+}
+
+// Consume the keyword (case-insensitive).
+func (r *filterParser) keyword(word string) (found bool) {
+	if r.done() {
+		return
+	}
+	t := r.tokens[r.next]
+	found = !t.quoted && strings.EqualFold(t.text, word)
+	if found {
+		r.next++
+	}
+	return
+}
+
+// Consume the operator.
+func (r *filterParser) operator(op string) (found bool) {
+	if r.done() {
+		return
+	}
+	t := r.tokens[r.next]
+	found = !t.quoted && t.text == op
+	if found {
+		r.next++
+	}
+	return
+}
+
+// Determine whether the token is a keyword.
+func (r *filterParser) isKeyword(t token) bool {
+	return strings.EqualFold(t.text, "and") || strings.EqualFold(t.text, "or")
+}
+
+// All tokens consumed.
+func (r *filterParser) done() bool {
+	return r.next >= len(r.tokens)
+}
+
+// Unexpected token error.
+func (r *filterParser) unexpected() error {
+	if r.done() {
+		return fmt.Errorf("%w: unexpected end", QuerySyntaxErr)
+	}
+	return fmt.Errorf("%w: unexpected '%s'", QuerySyntaxErr, r.tokens[r.next].text)
+}
+
+// Response writer projecting the (JSON) content
+// on the requested fields.
+type projection struct {
+	gin.ResponseWriter
+	// Projected fields.
+	fields []string
+}
+
+// Write the projected content.
+// Content that is not JSON is written as-is.
+func (w *projection) Write(b []byte) (n int, err error) {
+	var content interface{}
+	if w.Status() != http.StatusOK || json.Unmarshal(b, &content) != nil {
+		return w.ResponseWriter.Write(b)
+	}
+	paths := [][]string{}
+	for _, field := range w.fields {
+		paths = append(paths, strings.Split(field, "."))
+	}
+	projected, err := json.Marshal(Project(content, paths))
+	if err != nil {
+		return
+	}
+	_, err = w.ResponseWriter.Write(projected)
+	if err != nil {
+		return
+	}
+	n = len(b)
+	return
+}
+
+// Write the projected string.
+func (w *projection) WriteString(s string) (n int, err error) {
+	return w.Write([]byte(s))
+}
+
+// Project the (decoded JSON) content on the field paths.
+// Lists are projected item by item.
+func Project(content interface{}, paths [][]string) interface{} {
+	switch object := content.(type) {
+	case []interface{}:
+		for i := range object {
+			object[i] = Project(object[i], paths)
+		}
+		return object
+	case map[string]interface{}:
+		projected := map[string]interface{}{}
+		nested := map[string][][]string{}
+		for _, path := range paths {
+			value, found := object[path[0]]
+			if !found {
+				continue
+			}
+			if len(path) == 1 {
+				projected[path[0]] = value
+			} else {
+				nested[path[0]] = append(nested[path[0]], path[1:])
+			}
+		}
+		for key, paths := range nested {
+			if _, whole := projected[key]; !whole {
+				projected[key] = Project(object[key], paths)
+			}
+		}
+		return projected
+	default:
+		return content
+	}
+}
@@ -0,0 +1,124 @@
+package base
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	"github.com/onsi/gomega"
+)
+
+func TestParseFilter(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	// Comparison.
+	p, err := ParseFilter("powerState = poweredOn")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(p).To(gomega.Equal(libmodel.Eq("powerState", "poweredOn")))
+	p, err = ParseFilter("name!='my vm'")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(p).To(gomega.Equal(libmodel.Neq("name", "my vm")))
+	// Compound (AND binds tighter than OR).
+	p, err = ParseFilter("cpuCount>2 and cpuCount<8 OR label.app=web")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(p).To(gomega.Equal(
+		libmodel.Or(
+			libmodel.And(
+				libmodel.Gt("cpuCount", "2"),
+				libmodel.Lt("cpuCount", "8")),
+			libmodel.Match(libmodel.Labels{"app": "web"}))))
+	// Grouped.
+	p, err = ParseFilter(`(name="and" or name=b) and isTemplate=false`)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(p).To(gomega.Equal(
+		libmodel.And(
+			libmodel.Or(
+				libmodel.Eq("name", "and"),
+				libmodel.Eq("name", "b")),
+			libmodel.Eq("isTemplate", "false"))))
+	// Not valid.
+	for _, expr := range []string{
+		"name",
+		"name=",
+		"=a",
+		"name=a and",
+		"(name=a",
+		"name=a)",
+		"name=a b",
+		"name='a",
+		"name ! a",
+		"label.app>web",
+	} {
+		_, err = ParseFilter(expr)
+		g.Expect(errors.Is(err, QuerySyntaxErr)).To(gomega.BeTrue(), expr)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ctx := &gin.Context{
+		Request: &http.Request{
+			URL: &url.URL{
+				RawQuery: url.Values{
+					FilterParam: {"name=a"},
+					SortParam:   {"-cpuCount, name"},
+					FieldsParam: {"id,name"},
+				}.Encode(),
+			},
+		},
+	}
+	query := Query{}
+	g.Expect(query.With(ctx)).To(gomega.Succeed())
+	g.Expect(query.Sort).To(gomega.Equal([]string{"-cpuCount", "name"}))
+	g.Expect(query.Fields).To(gomega.Equal([]string{"id", "name"}))
+	g.Expect(query.Filter(nil)).To(gomega.Equal(libmodel.Eq("name", "a")))
+	g.Expect(query.Filter(libmodel.Eq("id", "1"))).To(gomega.Equal(
+		libmodel.And(
+			libmodel.Eq("id", "1"),
+			libmodel.Eq("name", "a"))))
+	// Not valid sort key.
+	ctx.Request.URL.RawQuery = url.Values{SortParam: {"name,-"}}.Encode()
+	g.Expect(errors.Is(query.With(ctx), QuerySyntaxErr)).To(gomega.BeTrue())
+}
+
+func TestSetQueryError(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	status := func(err error) int {
+		recorder := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(recorder)
+		SetQueryError(ctx, err)
+		ctx.Writer.WriteHeaderNow()
+		return recorder.Code
+	}
+	g.Expect(status(liberr.Wrap(libmodel.PredicateRefErr))).To(gomega.Equal(http.StatusBadRequest))
+	g.Expect(status(liberr.Wrap(libmodel.SortRefErr, "field", "x"))).To(gomega.Equal(http.StatusBadRequest))
+	g.Expect(status(errors.New("db closed"))).To(gomega.Equal(http.StatusInternalServerError))
+}
+
+func TestProjection(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(recorder)
+	query := Query{Fields: []string{"id", "parent.kind", "disks.file"}}
+	query.Project(ctx)
+	ctx.JSON(
+		http.StatusOK,
+		[]interface{}{
+			map[string]interface{}{
+				"id":     "vm-1",
+				"name":   "web",
+				"parent": map[string]interface{}{"kind": "Folder", "id": "group-v1"},
+				"disks": []interface{}{
+					map[string]interface{}{"file": "a.vmdk", "capacity": 1},
+				},
+			},
+		})
+	g.Expect(recorder.Body.String()).To(gomega.MatchJSON(
+		`[{"id":"vm-1","parent":{"kind":"Folder"},"disks":[{"file":"a.vmdk"}]}]`))
+}
@@ -7,6 +7,7 @@ import (
 
 	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/hyperv"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
@@ -54,6 +55,7 @@ type Client = base.Client
 type Finder = base.Finder
 type Param = base.Param
 type Watch = base.Watch
+type History = base.History
 
 // Build an appropriate client.
 func NewClient(provider *api.Provider) (client Client, err error) {
@@ -98,6 +100,14 @@ func NewClient(provider *api.Provider) (client Client, err error) {
 				Resolver: &ova.Resolver{Provider: provider},
 			},
 		}
+	case api.HyperV:
+		client = &ProviderClient{
+			provider: provider,
+			finder:   &hyperv.Finder{},
+			restClient: base.RestClient{
+				Resolver: &hyperv.Resolver{Provider: provider},
+			},
+		}
 	default:
 		err = liberr.Wrap(
 			ProviderNotSupportedError{
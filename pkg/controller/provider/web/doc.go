@@ -2,6 +2,7 @@ package web
 
 import (
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/hyperv"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/openstack"
 	"github.com/kubev2v/forklift/pkg/controller/provider/web/ova"
@@ -20,6 +21,20 @@ func All(container *container.Container) (all []libweb.RequestHandler) {
 				Container: container,
 			},
 		},
+		&ReportHandler{
+			UserHandler: UserHandler{
+				Handler: base.Handler{
+					Container: container,
+				},
+			},
+		},
+		&MapHandler{
+			UserHandler: UserHandler{
+				Handler: base.Handler{
+					Container: container,
+				},
+			},
+		},
 	}
 	all = append(
 		all,
@@ -36,5 +51,8 @@ func All(container *container.Container) (all []libweb.RequestHandler) {
 	all = append(
 		all,
 		ova.Handlers(container)...)
+	all = append(
+		all,
+		hyperv.Handlers(container)...)
 	return
 }
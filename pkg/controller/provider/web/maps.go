@@ -0,0 +1,575 @@
+package web
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/provider"
+	"github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
+	"github.com/kubev2v/forklift/pkg/controller/map/generate"
+	modelOpenstack "github.com/kubev2v/forklift/pkg/controller/provider/model/openstack"
+	modelOvirt "github.com/kubev2v/forklift/pkg/controller/provider/model/ovirt"
+	modelVsphere "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/ocp"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libcontainer "github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	GenerateMapsRoot = "/maps/generate"
+)
+
+// Map generation request.
+type MapRequest struct {
+	// Plan. Provides the providers and the VMs when not
+	// specified. The maps are created owned by the plan.
+	Plan *core.ObjectReference `json:"plan,omitempty"`
+	// Providers.
+	Provider provider.Pair `json:"provider"`
+	// VMs. All networks and storage of the source
+	// provider are mapped when not specified.
+	VMs []ref.Ref `json:"vms,omitempty"`
+	// Rules.
+	Rules generate.Rules `json:"rules"`
+	// Create the maps. Requires the plan.
+	Create bool `json:"create,omitempty"`
+}
+
+// Map generation reply.
+type MapReply struct {
+	// Proposed (or created) network map.
+	NetworkMap *api.NetworkMap `json:"networkMap"`
+	// Proposed (or created) storage map.
+	StorageMap *api.StorageMap `json:"storageMap"`
+	// Sources not matched by the rules.
+	Unmapped generate.Unmapped `json:"unmapped"`
+}
+
+// Map generation handler.
+// Proposes the network and storage maps for the VMs of the
+// source provider using the rules in the request. The maps
+// are created (owned by the plan) using the token provided
+// with the request so the RBAC of the user is enforced.
+type MapHandler struct {
+	UserHandler
+}
+
+// Add routes to the `gin` router.
+func (h *MapHandler) AddRoutes(e *gin.Engine) {
+	e.POST(GenerateMapsRoot, h.Generate)
+}
+
+// Generate the maps.
+func (h MapHandler) Generate(ctx *gin.Context) {
+	request := &MapRequest{}
+	err := ctx.BindJSON(request)
+	if err != nil {
+		return
+	}
+	err = request.Rules.Validate()
+	if err != nil {
+		h.badRequest(ctx, err)
+		return
+	}
+	var cl client.Client
+	var plan *api.Plan
+	if request.Plan != nil {
+		var status int
+		cl, status = h.BuildClient(ctx)
+		if status != http.StatusOK {
+			return
+		}
+		plan = &api.Plan{}
+		err = cl.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: request.Plan.Namespace,
+				Name:      request.Plan.Name,
+			},
+			plan)
+		if err != nil {
+			h.Fail(ctx, err)
+			return
+		}
+		if request.Provider.Source.Name == "" {
+			request.Provider = plan.Spec.Provider
+		}
+		if len(request.VMs) == 0 {
+			for _, vm := range plan.Spec.VMs {
+				request.VMs = append(request.VMs, vm.Ref)
+			}
+		}
+	} else if request.Create {
+		h.badRequest(ctx, liberr.New("plan required to create the maps"))
+		return
+	}
+	source, status, err := h.collector(ctx, request.Provider.Source)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	destination, status, err := h.collector(ctx, request.Provider.Destination)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	if destination.Owner().(*api.Provider).Type() != api.OpenShift {
+		h.badRequest(ctx, liberr.New("destination provider must be OpenShift"))
+		return
+	}
+	networks, storage, err := h.sources(source, request.VMs)
+	if err != nil {
+		if errors.Is(err, errSourceNotSupported) || errors.Is(err, errVMNotFound) {
+			h.badRequest(ctx, err)
+		} else {
+			log.Trace(err, "url", ctx.Request.URL)
+			ctx.Status(http.StatusInternalServerError)
+		}
+		return
+	}
+	generator := &generate.Generator{Rules: request.Rules}
+	generator.Destination, err = h.destination(ctx, destination)
+	if err != nil {
+		log.Trace(err, "url", ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	reply := &MapReply{
+		NetworkMap: &api.NetworkMap{
+			Spec: api.NetworkMapSpec{Provider: request.Provider},
+		},
+		StorageMap: &api.StorageMap{
+			Spec: api.StorageMapSpec{Provider: request.Provider},
+		},
+	}
+	reply.NetworkMap.Spec.Map, reply.Unmapped.Networks = generator.Networks(networks)
+	reply.StorageMap.Spec.Map, reply.Unmapped.Storage = generator.Storage(storage)
+	if plan != nil {
+		for _, object := range []client.Object{reply.NetworkMap, reply.StorageMap} {
+			object.SetNamespace(plan.Namespace)
+			object.SetGenerateName(plan.Name + "-")
+			object.SetOwnerReferences(
+				[]meta.OwnerReference{
+					{
+						APIVersion: api.SchemeGroupVersion.String(),
+						Kind:       "Plan",
+						Name:       plan.Name,
+						UID:        plan.UID,
+					},
+				})
+			if !request.Create {
+				continue
+			}
+			err = cl.Create(context.TODO(), object)
+			if err != nil {
+				h.Fail(ctx, err)
+				return
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, reply)
+}
+
+// Find the collector of the provider.
+// The user must be permitted to read the inventory of the provider.
+func (h MapHandler) collector(ctx *gin.Context, reference core.ObjectReference) (collector libcontainer.Collector, status int, err error) {
+	for _, c := range h.Container.List() {
+		p, cast := c.Owner().(*api.Provider)
+		if !cast || p.Namespace != reference.Namespace || p.Name != reference.Name {
+			continue
+		}
+		collector = c
+		break
+	}
+	if collector == nil {
+		ctx.Header(base.ReasonHeader, base.UnknownProvider)
+		status = http.StatusNotFound
+		return
+	}
+	status = h.EnsureParity(collector, time.Second*10)
+	if status != http.StatusOK {
+		return
+	}
+	if base.Settings.AuthRequired {
+		status, err = base.DefaultAuth.Permit(ctx, collector.Owner().(*api.Provider))
+	}
+
+	return
+}
+
+// Errors.
+var (
+	errSourceNotSupported = errors.New("source provider type not supported")
+	errVMNotFound         = errors.New("VM not found")
+)
+
+// Source networks and storage.
+// Those used by the VMs or all when no VMs are specified.
+func (h MapHandler) sources(collector libcontainer.Collector, vms []ref.Ref) (networks []generate.Network, storage []generate.Storage, err error) {
+	db := collector.DB()
+	switch collector.Owner().(*api.Provider).Type() {
+	case api.VSphere:
+		networks, storage, err = h.vSphere(db, vms)
+	case api.OVirt:
+		networks, storage, err = h.oVirt(db, vms)
+	case api.OpenStack:
+		networks, storage, err = h.openStack(db, vms)
+	default:
+		err = liberr.Wrap(errSourceNotSupported)
+	}
+
+	return
+}
+
+// Predicate matching the VMs by ID or name.
+func (h MapHandler) vmPredicate(vms []ref.Ref) libmodel.Predicate {
+	predicates := []libmodel.Predicate{}
+	for _, vm := range vms {
+		if vm.ID != "" {
+			predicates = append(predicates, libmodel.Eq("ID", vm.ID))
+		} else {
+			path := strings.Split(vm.Name, "/")
+			predicates = append(predicates, libmodel.Eq("Name", path[len(path)-1]))
+		}
+	}
+	return libmodel.Or(predicates...)
+}
+
+// vSphere networks and datastores.
+func (h MapHandler) vSphere(db libmodel.DB, vms []ref.Ref) (networks []generate.Network, storage []generate.Storage, err error) {
+	networkList := []modelVsphere.Network{}
+	datastoreList := []modelVsphere.Datastore{}
+	if len(vms) == 0 {
+		err = db.List(&networkList, modelVsphere.ListOptions{Detail: modelVsphere.MaxDetail})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = db.List(&datastoreList, modelVsphere.ListOptions{Detail: modelVsphere.MaxDetail})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	} else {
+		vmList := []modelVsphere.VM{}
+		err = db.List(
+			&vmList,
+			modelVsphere.ListOptions{
+				Predicate: h.vmPredicate(vms),
+				Detail:    modelVsphere.MaxDetail,
+			})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if len(vmList) < len(vms) {
+			err = liberr.Wrap(errVMNotFound)
+			return
+		}
+		networkIds := map[string]bool{}
+		datastoreIds := map[string]bool{}
+		for _, vm := range vmList {
+			for _, network := range vm.Networks {
+				networkIds[network.ID] = true
+			}
+			for _, disk := range vm.Disks {
+				datastoreIds[disk.Datastore.ID] = true
+			}
+		}
+		for id := range networkIds {
+			m := modelVsphere.Network{Base: modelVsphere.Base{ID: id}}
+			err = db.Get(&m)
+			if err != nil {
+				err = liberr.Wrap(err, "network", id)
+				return
+			}
+			networkList = append(networkList, m)
+		}
+		for id := range datastoreIds {
+			m := modelVsphere.Datastore{Base: modelVsphere.Base{ID: id}}
+			err = db.Get(&m)
+			if err != nil {
+				err = liberr.Wrap(err, "datastore", id)
+				return
+			}
+			datastoreList = append(datastoreList, m)
+		}
+	}
+	for _, m := range networkList {
+		networks = append(
+			networks,
+			generate.Network{
+				Ref:  ref.Ref{ID: m.ID, Name: m.Name},
+				VLAN: m.VlanId,
+			})
+	}
+	for _, m := range datastoreList {
+		storage = append(
+			storage,
+			generate.Storage{
+				Ref:            ref.Ref{ID: m.ID, Name: m.Name},
+				Type:           m.Type,
+				BackingDevices: m.BackingDevicesNames,
+			})
+	}
+
+	return
+}
+
+// oVirt networks and storage domains.
+func (h MapHandler) oVirt(db libmodel.DB, vms []ref.Ref) (networks []generate.Network, storage []generate.Storage, err error) {
+	networkList := []modelOvirt.Network{}
+	domainList := []modelOvirt.StorageDomain{}
+	if len(vms) == 0 {
+		err = db.List(&networkList, modelOvirt.ListOptions{Detail: modelOvirt.MaxDetail})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = db.List(&domainList, modelOvirt.ListOptions{Detail: modelOvirt.MaxDetail})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	} else {
+		vmList := []modelOvirt.VM{}
+		err = db.List(
+			&vmList,
+			modelOvirt.ListOptions{
+				Predicate: h.vmPredicate(vms),
+				Detail:    modelOvirt.MaxDetail,
+			})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if len(vmList) < len(vms) {
+			err = liberr.Wrap(errVMNotFound)
+			return
+		}
+		networkIds := map[string]bool{}
+		domainIds := map[string]bool{}
+		for _, vm := range vmList {
+			for _, nic := range vm.NICs {
+				if nic.Profile == "" {
+					continue
+				}
+				profile := modelOvirt.NICProfile{Base: modelOvirt.Base{ID: nic.Profile}}
+				err = db.Get(&profile)
+				if err != nil {
+					err = liberr.Wrap(err, "profile", nic.Profile)
+					return
+				}
+				networkIds[profile.Network] = true
+			}
+			for _, da := range vm.DiskAttachments {
+				disk := modelOvirt.Disk{Base: modelOvirt.Base{ID: da.Disk}}
+				err = db.Get(&disk)
+				if err != nil {
+					err = liberr.Wrap(err, "disk", da.Disk)
+					return
+				}
+				if disk.StorageDomain != "" {
+					domainIds[disk.StorageDomain] = true
+				}
+			}
+		}
+		for id := range networkIds {
+			m := modelOvirt.Network{Base: modelOvirt.Base{ID: id}}
+			err = db.Get(&m)
+			if err != nil {
+				err = liberr.Wrap(err, "network", id)
+				return
+			}
+			networkList = append(networkList, m)
+		}
+		for id := range domainIds {
+			m := modelOvirt.StorageDomain{Base: modelOvirt.Base{ID: id}}
+			err = db.Get(&m)
+			if err != nil {
+				err = liberr.Wrap(err, "storageDomain", id)
+				return
+			}
+			domainList = append(domainList, m)
+		}
+	}
+	for _, m := range networkList {
+		networks = append(
+			networks,
+			generate.Network{
+				Ref:  ref.Ref{ID: m.ID, Name: m.Name},
+				VLAN: m.VLan,
+			})
+	}
+	for _, m := range domainList {
+		storage = append(
+			storage,
+			generate.Storage{
+				Ref:  ref.Ref{ID: m.ID, Name: m.Name},
+				Type: m.Storage.Type,
+			})
+	}
+
+	return
+}
+
+// OpenStack networks and volume types.
+// Image based VMs require the glance storage.
+func (h MapHandler) openStack(db libmodel.DB, vms []ref.Ref) (networks []generate.Network, storage []generate.Storage, err error) {
+	networkList := []modelOpenstack.Network{}
+	typeList := []modelOpenstack.VolumeType{}
+	glance := false
+	if len(vms) == 0 {
+		err = db.List(&networkList, modelOpenstack.ListOptions{Detail: modelOpenstack.MaxDetail})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		err = db.List(&typeList, modelOpenstack.ListOptions{Detail: modelOpenstack.MaxDetail})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+	} else {
+		vmList := []modelOpenstack.VM{}
+		err = db.List(
+			&vmList,
+			modelOpenstack.ListOptions{
+				Predicate: h.vmPredicate(vms),
+				Detail:    modelOpenstack.MaxDetail,
+			})
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		if len(vmList) < len(vms) {
+			err = liberr.Wrap(errVMNotFound)
+			return
+		}
+		networkNames := map[string]bool{}
+		typeNames := map[string]bool{}
+		for _, vm := range vmList {
+			for name := range vm.Addresses {
+				networkNames[name] = true
+			}
+			for _, attached := range vm.AttachedVolumes {
+				volume := modelOpenstack.Volume{Base: modelOpenstack.Base{ID: attached.ID}}
+				err = db.Get(&volume)
+				if err != nil {
+					err = liberr.Wrap(err, "volume", attached.ID)
+					return
+				}
+				typeNames[volume.VolumeType] = true
+			}
+			if vm.ImageID != "" {
+				glance = true
+			}
+		}
+		for name := range networkNames {
+			list := []modelOpenstack.Network{}
+			err = db.List(
+				&list,
+				modelOpenstack.ListOptions{
+					Predicate: libmodel.Eq("Name", name),
+					Detail:    modelOpenstack.MaxDetail,
+				})
+			if err != nil {
+				err = liberr.Wrap(err, "network", name)
+				return
+			}
+			networkList = append(networkList, list...)
+		}
+		for name := range typeNames {
+			list := []modelOpenstack.VolumeType{}
+			err = db.List(
+				&list,
+				modelOpenstack.ListOptions{
+					Predicate: libmodel.Eq("Name", name),
+					Detail:    modelOpenstack.MaxDetail,
+				})
+			if err != nil {
+				err = liberr.Wrap(err, "volumeType", name)
+				return
+			}
+			typeList = append(typeList, list...)
+		}
+	}
+	for _, m := range networkList {
+		networks = append(
+			networks,
+			generate.Network{
+				Ref: ref.Ref{ID: m.ID, Name: m.Name},
+			})
+	}
+	for _, m := range typeList {
+		storage = append(
+			storage,
+			generate.Storage{
+				Ref: ref.Ref{ID: m.ID, Name: m.Name},
+			})
+	}
+	if glance {
+		storage = append(
+			storage,
+			generate.Storage{
+				Ref: ref.Ref{Name: api.GlanceSource},
+			})
+	}
+
+	return
+}
+
+// Destination network attachment definitions and storage classes.
+func (h MapHandler) destination(ctx *gin.Context, collector libcontainer.Collector) (destination generate.Destination, err error) {
+	handler := ocp.Handler{
+		Handler: base.Handler{
+			Container: h.Container,
+			Provider:  collector.Owner().(*api.Provider),
+			Collector: collector,
+		},
+	}
+	nads, err := handler.NetworkAttachmentDefinitions(ctx, handler.Provider)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, m := range nads {
+		destination.NADs = append(
+			destination.NADs,
+			generate.NAD{
+				Namespace:   m.Namespace,
+				Name:        m.Name,
+				Annotations: m.Object.Annotations,
+			})
+	}
+	classes, err := handler.StorageClasses(ctx)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, m := range classes {
+		destination.StorageClasses = append(destination.StorageClasses, m.Name)
+	}
+
+	return
+}
+
+// Report a request that is not valid.
+func (h MapHandler) badRequest(ctx *gin.Context, err error) {
+	ctx.Status(http.StatusBadRequest)
+	base.SetForkliftError(ctx, err)
+}
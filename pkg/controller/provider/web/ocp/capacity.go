@@ -0,0 +1,282 @@
+package ocp
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	core "k8s.io/api/core/v1"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	ocpclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	CapacityRoot = ProviderRoot + "/capacity"
+)
+
+// Query parameters.
+const (
+	NodeSelectorParam = "nodeSelector"
+)
+
+// ResourceQuota storage class suffix.
+const StorageClassQuotaSuffix = ".storageclass.storage.k8s.io/requests.storage"
+
+// Capacity handler.
+// Reports the capacity available in a namespace on the cluster.
+type CapacityHandler struct {
+	Handler
+}
+
+// Add routes to the `gin` router.
+func (h *CapacityHandler) AddRoutes(e *gin.Engine) {
+	e.GET(CapacityRoot, h.Get)
+}
+
+// List not supported.
+func (h CapacityHandler) List(ctx *gin.Context) {
+	ctx.Status(http.StatusMethodNotAllowed)
+}
+
+// Get the capacity.
+// The `namespace` parameter is required. The optional `nodeSelector`
+// parameter (k1=v1,k2=v2) restricts the nodes that are considered.
+// The node affinity is not considered. Without a node selector, the
+// nodes are reported as cluster-wide. The capacity assessment of a
+// plan, including its affinity, is reported by the plan capacity.
+func (h CapacityHandler) Get(ctx *gin.Context) {
+	status, err := h.Prepare(ctx)
+	if status != http.StatusOK {
+		ctx.Status(status)
+		base.SetForkliftError(ctx, err)
+		return
+	}
+	q := ctx.Request.URL.Query()
+	namespace := q.Get(NsParam)
+	if namespace == "" {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	nodeSelector, err := labels.ConvertSelectorToLabelsMap(q.Get(NodeSelectorParam))
+	if err != nil {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	client, err := h.UserClient(ctx)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+	capacity, err := BuildCapacity(client, namespace, nodeSelector, nil)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, capacity)
+}
+
+// Capacity available in a namespace.
+type Capacity struct {
+	// Namespace.
+	Namespace string `json:"namespace"`
+	// Schedulable nodes. Not set when the
+	// nodes cannot be listed.
+	Nodes *NodeCapacity `json:"nodes,omitempty"`
+	// Remaining ResourceQuota.
+	Quota QuotaCapacity `json:"quota"`
+}
+
+// Allocatable resources of the schedulable nodes.
+type NodeCapacity struct {
+	// The nodes are not restricted by a node
+	// selector or a required node affinity.
+	ClusterWide bool `json:"clusterWide"`
+	// Number of nodes.
+	Count int `json:"count"`
+	// Total allocatable CPU.
+	CPU resource.Quantity `json:"cpu"`
+	// Total allocatable memory.
+	Memory resource.Quantity `json:"memory"`
+	// Allocatable CPU of the largest node.
+	LargestCPU resource.Quantity `json:"largestCpu"`
+	// Allocatable memory of the largest node.
+	LargestMemory resource.Quantity `json:"largestMemory"`
+}
+
+// Remaining ResourceQuota in the namespace.
+// Resources without a quota are not set.
+type QuotaCapacity struct {
+	// Requested CPU.
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+	// Requested memory.
+	Memory *resource.Quantity `json:"memory,omitempty"`
+	// Requested storage.
+	Storage *resource.Quantity `json:"storage,omitempty"`
+	// Requested storage by storage class.
+	StorageClasses map[string]resource.Quantity `json:"storageClasses,omitempty"`
+}
+
+// Build the capacity available in the namespace.
+// Only nodes that are ready, schedulable and match the
+// node selector and the required node affinity are counted.
+func BuildCapacity(
+	client ocpclient.Client,
+	namespace string,
+	nodeSelector map[string]string,
+	affinity *core.Affinity) (capacity *Capacity, err error) {
+	//
+	capacity = &Capacity{Namespace: namespace}
+	err = capacity.buildQuota(client)
+	if err != nil {
+		return
+	}
+	err = capacity.buildNodes(client, nodeSelector, affinity)
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Build the remaining quota.
+// The lowest remaining amount is used when
+// several quotas constrain a resource.
+func (r *Capacity) buildQuota(client ocpclient.Client) (err error) {
+	list := &core.ResourceQuotaList{}
+	err = client.List(context.TODO(), list, ocpclient.InNamespace(r.Namespace))
+	if err != nil {
+		err = liberr.Wrap(err, "namespace", r.Namespace)
+		return
+	}
+	lower := func(current *resource.Quantity, q resource.Quantity) *resource.Quantity {
+		if current == nil || q.Cmp(*current) < 0 {
+			return &q
+		}
+		return current
+	}
+	for _, quota := range list.Items {
+		for name, hard := range quota.Status.Hard {
+			remaining := hard.DeepCopy()
+			if used, found := quota.Status.Used[name]; found {
+				remaining.Sub(used)
+			}
+			if remaining.Sign() < 0 {
+				remaining = resource.Quantity{}
+			}
+			switch name {
+			case core.ResourceCPU, core.ResourceRequestsCPU:
+				r.Quota.CPU = lower(r.Quota.CPU, remaining)
+			case core.ResourceMemory, core.ResourceRequestsMemory:
+				r.Quota.Memory = lower(r.Quota.Memory, remaining)
+			case core.ResourceRequestsStorage:
+				r.Quota.Storage = lower(r.Quota.Storage, remaining)
+			default:
+				sc, found := strings.CutSuffix(string(name), StorageClassQuotaSuffix)
+				if !found {
+					continue
+				}
+				if r.Quota.StorageClasses == nil {
+					r.Quota.StorageClasses = make(map[string]resource.Quantity)
+				}
+				current, found := r.Quota.StorageClasses[sc]
+				if found {
+					r.Quota.StorageClasses[sc] = *lower(&current, remaining)
+				} else {
+					r.Quota.StorageClasses[sc] = remaining
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// Build the allocatable resources of the nodes.
+// The nodes are not reported when the client is
+// not permitted to list them.
+func (r *Capacity) buildNodes(
+	client ocpclient.Client,
+	nodeSelector map[string]string,
+	affinity *core.Affinity) (err error) {
+	//
+	list := &core.NodeList{}
+	err = client.List(context.TODO(), list)
+	if err != nil {
+		if k8serr.IsForbidden(err) {
+			err = nil
+			return
+		}
+		err = liberr.Wrap(err)
+		return
+	}
+	required := nodeaffinity.GetRequiredNodeAffinity(
+		&core.Pod{
+			Spec: core.PodSpec{
+				NodeSelector: nodeSelector,
+				Affinity:     affinity,
+			},
+		})
+	nodes := &NodeCapacity{
+		ClusterWide: len(nodeSelector) == 0 &&
+			(affinity == nil ||
+				affinity.NodeAffinity == nil ||
+				affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil),
+	}
+	for i := range list.Items {
+		node := &list.Items[i]
+		if !schedulable(node) {
+			continue
+		}
+		matched, mErr := required.Match(node)
+		if mErr != nil {
+			err = liberr.Wrap(mErr, "node", node.Name)
+			return
+		}
+		if !matched {
+			continue
+		}
+		nodes.Count++
+		cpu := node.Status.Allocatable[core.ResourceCPU]
+		memory := node.Status.Allocatable[core.ResourceMemory]
+		nodes.CPU.Add(cpu)
+		nodes.Memory.Add(memory)
+		if cpu.Cmp(nodes.LargestCPU) > 0 {
+			nodes.LargestCPU = cpu.DeepCopy()
+		}
+		if memory.Cmp(nodes.LargestMemory) > 0 {
+			nodes.LargestMemory = memory.DeepCopy()
+		}
+	}
+	r.Nodes = nodes
+
+	return
+}
+
+// The node is ready and schedulable.
+func schedulable(node *core.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, cnd := range node.Status.Conditions {
+		if cnd.Type == core.NodeReady {
+			return cnd.Status == core.ConditionTrue
+		}
+	}
+	return false
+}
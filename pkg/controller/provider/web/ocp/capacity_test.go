@@ -0,0 +1,135 @@
+package ocp
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func node(name string, ready bool, cpu, memory string, labels map[string]string) *core.Node {
+	status := core.ConditionFalse
+	if ready {
+		status = core.ConditionTrue
+	}
+	return &core.Node{
+		ObjectMeta: meta.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Status: core.NodeStatus{
+			Conditions: []core.NodeCondition{
+				{Type: core.NodeReady, Status: status},
+			},
+			Allocatable: core.ResourceList{
+				core.ResourceCPU:    resource.MustParse(cpu),
+				core.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func quota(name string, hard, used core.ResourceList) *core.ResourceQuota {
+	return &core.ResourceQuota{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "target",
+			Name:      name,
+		},
+		Status: core.ResourceQuotaStatus{
+			Hard: hard,
+			Used: used,
+		},
+	}
+}
+
+var _ = Describe("Capacity", func() {
+	var (
+		builder *fake.ClientBuilder
+	)
+
+	BeforeEach(func() {
+		builder = fake.NewClientBuilder().WithObjects(
+			node("worker-1", true, "16", "64Gi", map[string]string{"zone": "a"}),
+			node("worker-2", true, "32", "128Gi", map[string]string{"zone": "b"}),
+			node("worker-3", false, "64", "256Gi", map[string]string{"zone": "a"}),
+			quota(
+				"compute",
+				core.ResourceList{
+					core.ResourceRequestsCPU:    resource.MustParse("20"),
+					core.ResourceRequestsMemory: resource.MustParse("64Gi"),
+				},
+				core.ResourceList{
+					core.ResourceRequestsCPU:    resource.MustParse("4"),
+					core.ResourceRequestsMemory: resource.MustParse("80Gi"),
+				}),
+			quota(
+				"storage",
+				core.ResourceList{
+					core.ResourceRequestsStorage:     resource.MustParse("1Ti"),
+					"fast" + StorageClassQuotaSuffix: resource.MustParse("100Gi"),
+					"slow" + StorageClassQuotaSuffix: resource.MustParse("500Gi"),
+				},
+				core.ResourceList{
+					"fast" + StorageClassQuotaSuffix: resource.MustParse("40Gi"),
+				}),
+			quota(
+				"cpu",
+				core.ResourceList{
+					core.ResourceCPU: resource.MustParse("10"),
+				},
+				nil))
+	})
+
+	It("Builds the remaining quota and allocatable resources", func() {
+		capacity, err := BuildCapacity(builder.Build(), "target", nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		quota := capacity.Quota
+		Expect(quota.CPU.String()).To(Equal("10"))
+		Expect(quota.Memory.IsZero()).To(BeTrue())
+		Expect(quota.Storage.String()).To(Equal("1Ti"))
+		fast := quota.StorageClasses["fast"]
+		Expect(fast.String()).To(Equal("60Gi"))
+		slow := quota.StorageClasses["slow"]
+		Expect(slow.String()).To(Equal("500Gi"))
+		nodes := capacity.Nodes
+		Expect(nodes.ClusterWide).To(BeTrue())
+		Expect(nodes.Count).To(Equal(2))
+		Expect(nodes.CPU.String()).To(Equal("48"))
+		Expect(nodes.Memory.String()).To(Equal("192Gi"))
+		Expect(nodes.LargestCPU.String()).To(Equal("32"))
+		Expect(nodes.LargestMemory.String()).To(Equal("128Gi"))
+	})
+
+	It("Only counts the nodes matching the selector and affinity", func() {
+		capacity, err := BuildCapacity(builder.Build(), "target", map[string]string{"zone": "a"}, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(capacity.Nodes.ClusterWide).To(BeFalse())
+		Expect(capacity.Nodes.Count).To(Equal(1))
+		Expect(capacity.Nodes.CPU.String()).To(Equal("16"))
+
+		affinity := &core.Affinity{
+			NodeAffinity: &core.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &core.NodeSelector{
+					NodeSelectorTerms: []core.NodeSelectorTerm{
+						{
+							MatchExpressions: []core.NodeSelectorRequirement{
+								{
+									Key:      "zone",
+									Operator: core.NodeSelectorOpNotIn,
+									Values:   []string{"a"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		capacity, err = BuildCapacity(builder.Build(), "target", nil, affinity)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(capacity.Nodes.ClusterWide).To(BeFalse())
+		Expect(capacity.Nodes.Count).To(Equal(1))
+		Expect(capacity.Nodes.CPU.String()).To(Equal("32"))
+	})
+})
@@ -70,5 +70,10 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&CapacityHandler{
+			Handler: Handler{
+				base.Handler{Container: container},
+			},
+		},
 	}
 }
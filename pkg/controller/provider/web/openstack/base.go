@@ -46,7 +46,8 @@ func (h Handler) ListOptions(ctx *gin.Context) libmodel.ListOptions {
 		detail = model.MaxDetail
 	}
 	return libmodel.ListOptions{
-		Predicate: h.Predicate(ctx),
+		Predicate: h.Query.Filter(h.Predicate(ctx)),
+		SortBy:    h.Query.Sort,
 		Detail:    detail,
 		Page:      &h.Page,
 	}
@@ -54,7 +54,7 @@ func (h FlavorHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.SetQueryError(ctx, err)
 		}
 	}()
 	db := h.Collector.DB()
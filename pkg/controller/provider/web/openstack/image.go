@@ -108,7 +108,7 @@ func (h ImageHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.SetQueryError(ctx, err)
 		return
 	}
 	content := []interface{}{}
@@ -53,7 +53,7 @@ func (h ProjectHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.SetQueryError(ctx, err)
 		}
 	}()
 	db := h.Collector.DB()
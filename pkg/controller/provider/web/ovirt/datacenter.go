@@ -54,7 +54,7 @@ func (h DataCenterHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.SetQueryError(ctx, err)
 		return
 	}
 	pb := PathBuilder{DB: db}
@@ -54,7 +54,7 @@ func (h DiskHandler) List(ctx *gin.Context) {
 			err,
 			"url",
 			ctx.Request.URL)
-		ctx.Status(http.StatusInternalServerError)
+		base.SetQueryError(ctx, err)
 		return
 	}
 	content := []interface{}{}
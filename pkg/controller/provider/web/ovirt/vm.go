@@ -20,6 +20,9 @@ const (
 	VMRoot       = VMsRoot + "/:" + VMParam
 )
 
+// Kind of the VM model.
+const VMKind = "VM"
+
 type CpuPinningPolicy string
 
 // CPU Pinning Policies
@@ -64,7 +67,7 @@ func (h VMHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.SetQueryError(ctx, err)
 		}
 	}()
 	db := h.Collector.DB()
@@ -0,0 +1,172 @@
+package plan
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/plan/capacity"
+	plancontext "github.com/kubev2v/forklift/pkg/controller/plan/context"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	ocp "github.com/kubev2v/forklift/pkg/lib/client/openshift"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	libref "github.com/kubev2v/forklift/pkg/lib/ref"
+	core "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	CapacityRoot = "/plans/:namespace/:name/capacity"
+)
+
+// Plan capacity handler.
+// Assesses the capacity of the destination cluster for the plan
+// the same as the plan validation. The plan, the referenced
+// resources and the destination cluster are read using the token
+// provided with the request so the RBAC of the user is enforced.
+type CapacityHandler struct {
+	web.UserHandler
+}
+
+// Add routes to the `gin` router.
+func (h *CapacityHandler) AddRoutes(e *gin.Engine) {
+	e.GET(CapacityRoot, h.Get)
+}
+
+// Get the capacity assessment of the plan.
+// Reports the resources required by the VMs (including the
+// storage overhead), the limits of the destination cluster
+// (including the target node selector and affinity) and the
+// shortfalls reported by the InsufficientCapacity condition.
+// Returns 409 (conflict) when the storage map is not set.
+func (h CapacityHandler) Get(ctx *gin.Context) {
+	cl, status := h.BuildClient(ctx)
+	if status != http.StatusOK {
+		return
+	}
+	plan := &api.Plan{}
+	err := cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(base.NameParam),
+		},
+		plan)
+	if err != nil {
+		h.Fail(ctx, err)
+		return
+	}
+	if plan.Spec.Type != api.MigrationOnlyConversion && !libref.RefSet(&plan.Spec.Map.Storage) {
+		ctx.Status(http.StatusConflict)
+		return
+	}
+	planContext, err := h.context(cl, plan)
+	if err != nil {
+		h.Fail(ctx, err)
+		return
+	}
+	destination, err := h.destination(cl, planContext.Destination.Provider)
+	if err != nil {
+		h.Fail(ctx, err)
+		return
+	}
+	planner, err := capacity.New(planContext)
+	if err != nil {
+		h.Fail(ctx, err)
+		return
+	}
+	assessment, err := planner.AssessPlan(destination)
+	if err != nil {
+		h.Fail(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, assessment)
+}
+
+// Build the plan context used by the planner.
+// Only the providers, the storage map and the source inventory
+// are resolved. The VMs selected by the VM selector are added.
+func (h CapacityHandler) context(cl client.Client, plan *api.Plan) (ctx *plancontext.Context, err error) {
+	get := func(ref core.ObjectReference, object client.Object) error {
+		return cl.Get(
+			context.TODO(),
+			client.ObjectKey{
+				Namespace: ref.Namespace,
+				Name:      ref.Name,
+			},
+			object)
+	}
+	source := &api.Provider{}
+	err = get(plan.Spec.Provider.Source, source)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	destination := &api.Provider{}
+	err = get(plan.Spec.Provider.Destination, destination)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	plan.Referenced.Provider.Source = source
+	plan.Referenced.Provider.Destination = destination
+	plan.AddSelectedVMs()
+	ctx = &plancontext.Context{
+		Client:    cl,
+		Plan:      plan,
+		Migration: &api.Migration{},
+		Log:       log,
+	}
+	if libref.RefSet(&plan.Spec.Map.Storage) {
+		mp := &api.StorageMap{}
+		err = get(plan.Spec.Map.Storage, mp)
+		if err != nil {
+			err = liberr.Wrap(err)
+			return
+		}
+		ctx.Map.Storage = mp
+	}
+	ctx.Source.Provider = source
+	ctx.Source.Inventory, err = web.NewClient(source)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	ctx.Destination.Provider = destination
+
+	return
+}
+
+// Build the client of the destination cluster.
+// The user client is used for the host cluster, otherwise
+// the client is built using the provider secret.
+func (h CapacityHandler) destination(cl client.Client, provider *api.Provider) (destination client.Client, err error) {
+	if provider.IsHost() {
+		destination = cl
+		return
+	}
+	ref := provider.Spec.Secret
+	secret := &core.Secret{}
+	err = cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ref.Namespace,
+			Name:      ref.Name,
+		},
+		secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	destination, err = ocp.Client(provider, secret)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
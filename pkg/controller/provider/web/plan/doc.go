@@ -0,0 +1,27 @@
+package plan
+
+import (
+	"github.com/kubev2v/forklift/pkg/controller/provider/web"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"github.com/kubev2v/forklift/pkg/lib/inventory/container"
+	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+)
+
+// Package logger.
+var log = logging.WithName("web|plan")
+
+// Build all handlers.
+// The plan handlers are not part of the `web` package
+// since the plan context depends on it.
+func Handlers(container *container.Container) []libweb.RequestHandler {
+	return []libweb.RequestHandler{
+		&CapacityHandler{
+			UserHandler: web.UserHandler{
+				Handler: base.Handler{
+					Container: container,
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,128 @@
+package web
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	api "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1"
+	"github.com/kubev2v/forklift/pkg/controller/plan/report"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Routes.
+const (
+	PlanReportRoot      = "/plans/:namespace/:name/report"
+	MigrationReportRoot = "/migrations/:namespace/:name/report"
+)
+
+// Query params.
+const (
+	FormatParam = "format"
+)
+
+// Migration report handler.
+// The plan and migrations are read from the cluster using the
+// token provided with the request so the RBAC of the user is
+// enforced. Archived plans are reported.
+type ReportHandler struct {
+	UserHandler
+}
+
+// Add routes to the `gin` router.
+func (h *ReportHandler) AddRoutes(e *gin.Engine) {
+	e.GET(PlanReportRoot, h.Plan)
+	e.GET(MigrationReportRoot, h.Migration)
+}
+
+// Report for a plan.
+// All migrations of the plan are reported.
+func (h ReportHandler) Plan(ctx *gin.Context) {
+	cl, status := h.BuildClient(ctx)
+	if status != http.StatusOK {
+		return
+	}
+	plan := &api.Plan{}
+	err := cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(base.NameParam),
+		},
+		plan)
+	if err != nil {
+		h.Fail(ctx, err)
+		return
+	}
+	list := &api.MigrationList{}
+	err = cl.List(
+		context.TODO(),
+		list,
+		client.InNamespace(plan.Namespace))
+	if err != nil {
+		h.Fail(ctx, err)
+		return
+	}
+	h.render(ctx, report.Build(plan, list.Items))
+}
+
+// Report for a migration.
+func (h ReportHandler) Migration(ctx *gin.Context) {
+	cl, status := h.BuildClient(ctx)
+	if status != http.StatusOK {
+		return
+	}
+	migration := &api.Migration{}
+	err := cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: ctx.Param(base.NsParam),
+			Name:      ctx.Param(base.NameParam),
+		},
+		migration)
+	if err != nil {
+		h.Fail(ctx, err)
+		return
+	}
+	plan := &api.Plan{}
+	err = cl.Get(
+		context.TODO(),
+		client.ObjectKey{
+			Namespace: migration.Spec.Plan.Namespace,
+			Name:      migration.Spec.Plan.Name,
+		},
+		plan)
+	if err != nil {
+		h.Fail(ctx, err)
+		return
+	}
+	h.render(ctx, report.Build(plan, []api.Migration{*migration}))
+}
+
+// Render the report in the requested format.
+func (h ReportHandler) render(ctx *gin.Context, r *report.Report) {
+	format := ctx.Query(FormatParam)
+	if format == "" {
+		format = report.JSON
+	}
+	contentType, found := report.ContentType[format]
+	if !found {
+		ctx.Status(http.StatusBadRequest)
+		return
+	}
+	ctx.Header("Content-Type", contentType)
+	if format != report.JSON {
+		ctx.Header(
+			"Content-Disposition",
+			"attachment; filename=\""+r.Plan.Name+"-report."+format+"\"")
+	}
+	ctx.Status(http.StatusOK)
+	err := r.Render(format, ctx.Writer)
+	if err != nil {
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+	}
+}
@@ -0,0 +1,108 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/kubev2v/forklift/pkg/apis"
+	"github.com/kubev2v/forklift/pkg/controller/provider/web/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	k8serr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// Handler using the cluster API on behalf of the user.
+// The client is built using the token provided with the
+// request so the RBAC of the user is enforced.
+type UserHandler struct {
+	base.Handler
+	// Build the client used for the request.
+	// Defaults to the user client.
+	Client func(ctx *gin.Context) (client.Client, error)
+}
+
+// Build the client for the request.
+func (h UserHandler) BuildClient(ctx *gin.Context) (cl client.Client, status int) {
+	status = http.StatusOK
+	build := h.Client
+	if build == nil {
+		build = h.userClient
+	}
+	cl, err := build(ctx)
+	if err != nil {
+		if errors.Is(err, errNoToken) {
+			status = http.StatusUnauthorized
+		} else {
+			log.Trace(
+				err,
+				"url",
+				ctx.Request.URL)
+			status = http.StatusInternalServerError
+		}
+		ctx.Status(status)
+	}
+
+	return
+}
+
+// Report a failed API request.
+func (h UserHandler) Fail(ctx *gin.Context, err error) {
+	switch {
+	case k8serr.IsNotFound(err):
+		ctx.Status(http.StatusNotFound)
+	case k8serr.IsForbidden(err):
+		ctx.Status(http.StatusForbidden)
+	case k8serr.IsUnauthorized(err):
+		ctx.Status(http.StatusUnauthorized)
+	default:
+		log.Trace(
+			err,
+			"url",
+			ctx.Request.URL)
+		ctx.Status(http.StatusInternalServerError)
+	}
+}
+
+// No authentication token.
+var errNoToken = errors.New("no authentication token found")
+
+// Build a client using the token provided with the request.
+// The service account is used when authentication is not required.
+func (h UserHandler) userClient(ctx *gin.Context) (cl client.Client, err error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	if base.Settings.AuthRequired {
+		token := h.Token(ctx)
+		if token == "" {
+			err = errNoToken
+			return
+		}
+		cfg.BearerTokenFile = ""
+		cfg.BearerToken = token
+	}
+	cl, err = client.New(
+		cfg,
+		client.Options{
+			Scheme: userScheme,
+		})
+	if err != nil {
+		err = liberr.Wrap(err)
+	}
+
+	return
+}
+
+// Scheme used by the user client.
+var userScheme = func() *runtime.Scheme {
+	s := runtime.NewScheme()
+	_ = scheme.AddToScheme(s)
+	_ = apis.AddToScheme(s)
+	return s
+}()
@@ -21,7 +21,7 @@ type Resolver struct {
 // Build the URL path.
 func (r *Resolver) Path(resource interface{}, id string) (path string, err error) {
 	provider := r.Provider
-	switch resource.(type) {
+	switch res := resource.(type) {
 	case *Provider:
 		r := Provider{}
 		r.UID = id
@@ -62,6 +62,11 @@ func (r *Resolver) Path(resource interface{}, id string) (path string, err error
 		r.ID = id
 		r.Link(provider)
 		path = r.SelfLink
+	case *base.History:
+		r := VM{}
+		r.ID = id
+		r.Link(provider)
+		path = res.Link(r.SelfLink)
 	case *Workload:
 		r := Workload{}
 		r.ID = id
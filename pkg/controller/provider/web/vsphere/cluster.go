@@ -53,7 +53,7 @@ func (h ClusterHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.SetQueryError(ctx, err)
 		}
 	}()
 	db := h.Collector.DB()
@@ -183,15 +183,17 @@ func (h *ClusterHandler) filter(ctx *gin.Context, list *[]model.Cluster) (err er
 // REST Resource.
 type Cluster struct {
 	Resource
-	Folder      string      `json:"folder"`
-	Networks    []model.Ref `json:"networks"`
-	Datastores  []model.Ref `json:"datastores"`
-	Hosts       []model.Ref `json:"hosts"`
-	DasEnabled  bool        `json:"dasEnabled"`
-	DasVms      []model.Ref `json:"dasVms"`
-	DrsEnabled  bool        `json:"drsEnabled"`
-	DrsBehavior string      `json:"drsBehavior"`
-	DrsVms      []model.Ref `json:"drsVms"`
+	Folder      string           `json:"folder"`
+	Networks    []model.Ref      `json:"networks"`
+	Datastores  []model.Ref      `json:"datastores"`
+	Hosts       []model.Ref      `json:"hosts"`
+	DasEnabled  bool             `json:"dasEnabled"`
+	DasVms      []model.Ref      `json:"dasVms"`
+	DrsEnabled  bool             `json:"drsEnabled"`
+	DrsBehavior string           `json:"drsBehavior"`
+	DrsVms      []model.Ref      `json:"drsVms"`
+	DrsRules    []model.DrsRule  `json:"drsRules"`
+	DrsGroups   []model.DrsGroup `json:"drsGroups"`
 }
 
 // Build the resource using the model.
@@ -206,6 +208,8 @@ func (r *Cluster) With(m *model.Cluster) {
 	r.Hosts = m.Hosts
 	r.DasVms = m.DasVms
 	r.DrsVms = m.DasVms
+	r.DrsRules = m.DrsRules
+	r.DrsGroups = m.DrsGroups
 }
 
 // Build self link (URI).
@@ -61,6 +61,12 @@ func Handlers(container *container.Container) []libweb.RequestHandler {
 				base.Handler{Container: container},
 			},
 		},
+		&base.HistoryHandler{
+			Handler: base.Handler{Container: container},
+			Kind:    VMKind,
+			Root:    VMRoot,
+			Param:   VMParam,
+		},
 		&WorkloadHandler{
 			Handler: Handler{
 				base.Handler{Container: container},
@@ -0,0 +1,176 @@
+package vsphere
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+)
+
+// Selector fields.
+const (
+	// Inventory path of a folder, cluster or host.
+	PathParam = "path"
+	// Regular expression matched against the VM name.
+	NameRegexParam = "nameRegex"
+	// Power state of the VM.
+	PowerStateParam = "powerState"
+	// Guest OS identifier or full name.
+	GuestOSParam = "guestOS"
+)
+
+// VM selector.
+// Built using the selector queries and the `datastore` query (the
+// ID or name of a datastore backing a disk of the VM). The path,
+// power state and guest OS are evaluated by the list predicate.
+// The name pattern and the datastore are matched on the listed VMs.
+type VMSelector struct {
+	// Database.
+	DB libmodel.DB
+	// Inventory path of a folder, cluster or host.
+	Path string
+	// Name pattern.
+	Name *regexp.Regexp
+	// Power state.
+	PowerState string
+	// Guest OS.
+	GuestOS string
+	// Datastore ID or name.
+	Datastore string
+	// IDs of the datastore.
+	datastores map[string]bool
+}
+
+// Build the selector using the query.
+// Returns an error when the selector is not valid.
+func (r *VMSelector) With(q url.Values) (err error) {
+	path := q.Get(PathParam)
+	if path != "" && !strings.HasPrefix(path, "/") {
+		err = fmt.Errorf("path: %s: expected an absolute inventory path", path)
+		return
+	}
+	r.Path = strings.TrimRight(path, "/")
+	r.PowerState = q.Get(PowerStateParam)
+	r.GuestOS = q.Get(GuestOSParam)
+	r.Datastore = q.Get(DatastoreParam)
+	pattern := q.Get(NameRegexParam)
+	if pattern != "" {
+		r.Name, err = regexp.Compile(pattern)
+	}
+	return
+}
+
+// Build the list predicate.
+// The path of a folder selects the VMs within the folder and
+// its sub-folders. The path of a cluster or host selects the VMs
+// running on the hosts. Returns matched=false when no VM can
+// match the selector. The error reports that the inventory could
+// not be read, matched is not set in that case.
+func (r *VMSelector) Predicate() (p libmodel.Predicate, matched bool, err error) {
+	predicates := []libmodel.Predicate{}
+	if r.PowerState != "" {
+		predicates = append(predicates, libmodel.Eq("powerState", r.PowerState))
+	}
+	if r.GuestOS != "" {
+		predicates = append(
+			predicates,
+			libmodel.Or(
+				libmodel.Eq("guestId", r.GuestOS),
+				libmodel.Eq("guestName", r.GuestOS)))
+	}
+	if r.Path != "" {
+		var contained []libmodel.Predicate
+		contained, err = r.contained()
+		if err != nil || len(contained) == 0 {
+			return
+		}
+		predicates = append(predicates, libmodel.Or(contained...))
+	}
+	if r.Datastore != "" {
+		err = r.findDatastores()
+		if err != nil || len(r.datastores) == 0 {
+			return
+		}
+	}
+	switch len(predicates) {
+	case 0:
+	case 1:
+		p = predicates[0]
+	default:
+		p = libmodel.And(predicates...)
+	}
+	matched = true
+	return
+}
+
+// Determine whether the VM matches the criteria
+// not evaluated by the list predicate.
+func (r *VMSelector) Match(m *model.VM) bool {
+	if r.Name != nil && !r.Name.MatchString(m.Name) {
+		return false
+	}
+	if r.Datastore != "" {
+		for _, disk := range m.Disks {
+			if r.datastores[disk.Datastore.ID] {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Build the predicates matching the VMs contained
+// by the folders, clusters and hosts on the path.
+func (r *VMSelector) contained() (predicates []libmodel.Predicate, err error) {
+	pb := PathBuilder{DB: r.DB}
+	folders := []model.Folder{}
+	err = r.DB.List(&folders, libmodel.ListOptions{})
+	if err != nil {
+		return
+	}
+	for i := range folders {
+		if r.onPath(pb.Path(&folders[i])) {
+			predicates = append(predicates, libmodel.Eq("folder", folders[i].ID))
+		}
+	}
+	hosts := []model.Host{}
+	err = r.DB.List(&hosts, libmodel.ListOptions{})
+	if err != nil {
+		return
+	}
+	for i := range hosts {
+		if r.onPath(pb.Path(&hosts[i])) {
+			predicates = append(predicates, libmodel.Eq("host", hosts[i].ID))
+		}
+	}
+	return
+}
+
+// Determine whether the path is (or is within) the selector path.
+func (r *VMSelector) onPath(path string) bool {
+	return path == r.Path || strings.HasPrefix(path, r.Path+"/")
+}
+
+// Find the IDs of the datastore by ID or name.
+func (r *VMSelector) findDatastores() (err error) {
+	r.datastores = map[string]bool{}
+	list := []model.Datastore{}
+	err = r.DB.List(
+		&list,
+		libmodel.ListOptions{
+			Predicate: libmodel.Or(
+				libmodel.Eq("id", r.Datastore),
+				libmodel.Eq("name", r.Datastore)),
+		})
+	if err != nil {
+		return
+	}
+	for _, m := range list {
+		r.datastores[m.ID] = true
+	}
+	return
+}
@@ -0,0 +1,111 @@
+package vsphere
+
+import (
+	"net/url"
+	"testing"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	. "github.com/onsi/gomega"
+)
+
+func TestVMSelector(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	db := libmodel.New("/tmp/test-vsphere-selector.db", model.All()...)
+	g.Expect(db.Open(true)).To(Succeed())
+	defer func() {
+		_ = db.Close(true)
+	}()
+	base := func(id, name string, parent model.Ref) model.Base {
+		return model.Base{ID: id, Name: name, Parent: parent}
+	}
+	ref := func(kind, id string) model.Ref {
+		return model.Ref{Kind: kind, ID: id}
+	}
+	root := ref(model.FolderKind, "group-d1")
+	dc := ref(model.DatacenterKind, "datacenter-1")
+	vmFolder := ref(model.FolderKind, "group-v1")
+	prodFolder := ref(model.FolderKind, "group-v2")
+	hostFolder := ref(model.FolderKind, "group-h1")
+	cluster := ref(model.ClusterKind, "domain-c1")
+	objects := []libmodel.Model{
+		&model.Folder{Base: base(root.ID, "Datacenters", model.Ref{})},
+		&model.Datacenter{Base: base(dc.ID, "DC0", root)},
+		&model.Folder{Base: base(vmFolder.ID, "vm", dc)},
+		&model.Folder{Base: base(prodFolder.ID, "prod", vmFolder)},
+		&model.Folder{Base: base(hostFolder.ID, "host", dc)},
+		&model.Cluster{Base: base(cluster.ID, "cluster0", hostFolder)},
+		&model.Host{Base: base("host-1", "H0", cluster), Cluster: cluster.ID},
+		&model.Host{Base: base("host-2", "H1", hostFolder)},
+		&model.Datastore{Base: base("datastore-1", "ds0", model.Ref{})},
+		&model.VM{
+			Base:       base("vm-1", "web-1", prodFolder),
+			Folder:     prodFolder.ID,
+			Host:       "host-1",
+			PowerState: "poweredOn",
+			GuestID:    "rhel8_64Guest",
+			Disks:      []model.Disk{{Datastore: ref(model.DsKind, "datastore-1")}},
+		},
+		&model.VM{
+			Base:       base("vm-2", "db-1", vmFolder),
+			Folder:     vmFolder.ID,
+			Host:       "host-2",
+			PowerState: "poweredOff",
+			GuestID:    "windows9Guest",
+		},
+	}
+	for _, m := range objects {
+		g.Expect(db.Insert(m)).To(Succeed())
+	}
+	selected := func(q url.Values) (ids []string) {
+		selector := VMSelector{DB: db}
+		g.Expect(selector.With(q)).To(Succeed())
+		p, matched, err := selector.Predicate()
+		g.Expect(err).ToNot(HaveOccurred())
+		if !matched {
+			return
+		}
+		list := []model.VM{}
+		g.Expect(db.List(&list, libmodel.ListOptions{Predicate: p, Detail: model.MaxDetail})).To(Succeed())
+		for i := range list {
+			if selector.Match(&list[i]) {
+				ids = append(ids, list[i].ID)
+			}
+		}
+		return
+	}
+
+	g.Expect(selected(url.Values{})).To(ConsistOf("vm-1", "vm-2"))
+	// Path.
+	g.Expect(selected(url.Values{PathParam: {"/DC0/vm"}})).To(ConsistOf("vm-1", "vm-2"))
+	g.Expect(selected(url.Values{PathParam: {"/DC0/vm/prod/"}})).To(ConsistOf("vm-1"))
+	g.Expect(selected(url.Values{PathParam: {"/DC0/host/cluster0"}})).To(ConsistOf("vm-1"))
+	g.Expect(selected(url.Values{PathParam: {"/DC0/host/H1"}})).To(ConsistOf("vm-2"))
+	g.Expect(selected(url.Values{PathParam: {"/DC0/vm/pro"}})).To(BeEmpty())
+	// Power state and guest OS.
+	g.Expect(selected(url.Values{PowerStateParam: {"poweredOff"}})).To(ConsistOf("vm-2"))
+	g.Expect(selected(url.Values{GuestOSParam: {"rhel8_64Guest"}})).To(ConsistOf("vm-1"))
+	g.Expect(selected(url.Values{
+		PathParam:       {"/DC0/vm"},
+		PowerStateParam: {"poweredOn"},
+	})).To(ConsistOf("vm-1"))
+	g.Expect(selected(url.Values{
+		PathParam:       {"/DC0/host"},
+		PowerStateParam: {"poweredOff"},
+	})).To(ConsistOf("vm-2"))
+	g.Expect(selected(url.Values{
+		GuestOSParam:    {"windows9Guest"},
+		PowerStateParam: {"poweredOn"},
+	})).To(BeEmpty())
+	// Name and datastore.
+	g.Expect(selected(url.Values{NameRegexParam: {"^db-[0-9]+$"}})).To(ConsistOf("vm-2"))
+	g.Expect(selected(url.Values{DatastoreParam: {"ds0"}})).To(ConsistOf("vm-1"))
+	g.Expect(selected(url.Values{DatastoreParam: {"datastore-1"}})).To(ConsistOf("vm-1"))
+	g.Expect(selected(url.Values{DatastoreParam: {"ds1"}})).To(BeEmpty())
+	// Invalid name pattern.
+	selector := VMSelector{DB: db}
+	g.Expect(selector.With(url.Values{NameRegexParam: {"("}})).ToNot(Succeed())
+	// Relative path.
+	g.Expect(selector.With(url.Values{PathParam: {"DC0/vm"}})).ToNot(Succeed())
+}
@@ -20,6 +20,18 @@ const (
 	VMRoot       = VMsRoot + "/:" + VMParam
 )
 
+// Kind of the VM model.
+const VMKind = "VM"
+
+// Fields.
+const (
+	// Tag attached to the VM: `tag=category:name`.
+	TagParam = "tag"
+	// Custom attribute of the VM: `attribute=name=value`
+	// or `attribute=name` for any value.
+	AttributeParam = "attribute"
+)
+
 // Virtual Machine handler.
 type VMHandler struct {
 	Handler
@@ -53,24 +65,59 @@ func (h VMHandler) List(ctx *gin.Context) {
 				err,
 				"url",
 				ctx.Request.URL)
-			ctx.Status(http.StatusInternalServerError)
+			base.SetQueryError(ctx, err)
 		}
 	}()
 
 	// We need model.MaxDetail for retrieving IsTemplate field from the database
 	h.Detail = model.MaxDetail
 	db := h.Collector.DB()
-	list := []model.VM{}
-	err = db.List(&list, h.ListOptions(ctx))
+	selector := VMSelector{DB: db}
+	err = selector.With(ctx.Request.URL.Query())
+	if err != nil {
+		ctx.Status(http.StatusBadRequest)
+		base.SetForkliftError(ctx, err)
+		err = nil
+		return
+	}
+	// Inventory errors are reported as 500 by the deferred handler.
+	predicate, matched, err := selector.Predicate()
 	if err != nil {
 		return
 	}
 	content := []interface{}{}
+	if !matched {
+		ctx.JSON(http.StatusOK, content)
+		return
+	}
+	options := h.ListOptions(ctx)
+	if predicate != nil {
+		if options.Predicate != nil {
+			predicate = libmodel.And(options.Predicate, predicate)
+		}
+		options.Predicate = predicate
+	}
+	q := ctx.Request.URL.Query()
+	tags := q[TagParam]
+	attributes := q[AttributeParam]
+	metadata := len(tags) > 0 || len(attributes) > 0
+	if metadata {
+		options.Page = nil
+	}
+	list := []model.VM{}
+	err = db.List(&list, options)
+	if err != nil {
+		return
+	}
+	if metadata {
+		list = metadataFilter(list, tags, attributes, &h.Page)
+	}
 	err = h.filter(ctx, &list)
 	if err != nil {
 		return
 	}
 	pb := PathBuilder{DB: db}
+	rb := RuleBuilder{DB: db}
 	for _, m := range list {
 		if m.IsTemplate {
 			log.Info(
@@ -79,10 +126,14 @@ func (h VMHandler) List(ctx *gin.Context) {
 				"isTemplate", m.IsTemplate)
 			continue
 		}
+		if !selector.Match(&m) {
+			continue
+		}
 		r := &VM{}
 		r.With(&m)
 		r.Link(h.Provider)
 		r.Path = pb.Path(&m)
+		r.DrsRules = rb.Rules(&m)
 		content = append(content, r.Content(h.Detail))
 	}
 
@@ -117,10 +168,12 @@ func (h VMHandler) Get(ctx *gin.Context) {
 		return
 	}
 	pb := PathBuilder{DB: db}
+	rb := RuleBuilder{DB: db}
 	r := &VM{}
 	r.With(m)
 	r.Link(h.Provider)
 	r.Path = pb.Path(m)
+	r.DrsRules = rb.Rules(m)
 	content := r.Content(model.MaxDetail)
 
 	ctx.JSON(http.StatusOK, content)
@@ -135,11 +188,13 @@ func (h *VMHandler) watch(ctx *gin.Context) {
 		&model.VM{},
 		func(in libmodel.Model) (r interface{}) {
 			pb := PathBuilder{DB: db}
+			rb := RuleBuilder{DB: db}
 			m := in.(*model.VM)
 			vm := &VM{}
 			vm.With(m)
 			vm.Link(h.Provider)
 			vm.Path = pb.Path(m)
+			vm.DrsRules = rb.Rules(m)
 			r = vm
 			return
 		})
@@ -181,6 +236,72 @@ func (h *VMHandler) filter(ctx *gin.Context, list *[]model.VM) (err error) {
 	return
 }
 
+// Filter the (unpaged) VMs by the `tag` and `attribute` queries
+// then page the result. All of the tags and attributes listed must
+// match. The tags and attributes are matched in memory so the VMs
+// cannot be paged by the database.
+func metadataFilter(list []model.VM, tags []string, attributes []string, page *libmodel.Page) (kept []model.VM) {
+	kept = []model.VM{}
+	for _, m := range list {
+		if MetadataMatch(&m, tags, attributes) {
+			kept = append(kept, m)
+		}
+	}
+	page.Slice(&kept)
+
+	return
+}
+
+// Determine if the VM has all of the tags (category:name)
+// and custom attributes (name=value or name).
+func MetadataMatch(m *model.VM, tags []string, attributes []string) bool {
+	for _, tag := range tags {
+		category, name, _ := strings.Cut(tag, ":")
+		if !m.HasTag(category, name) {
+			return false
+		}
+	}
+	for _, attribute := range attributes {
+		name, wanted, hasValue := strings.Cut(attribute, "=")
+		value, found := m.Attribute(name)
+		if !found || hasValue && value != wanted {
+			return false
+		}
+	}
+	return true
+}
+
+// DRS rules builder.
+type RuleBuilder struct {
+	// Database.
+	DB libmodel.DB
+	// Cached clusters by host ID.
+	cache map[string]*model.Cluster
+}
+
+// The DRS rules of the cluster including the VM.
+func (r *RuleBuilder) Rules(m *model.VM) (rules []model.DrsRule) {
+	if r.cache == nil {
+		r.cache = map[string]*model.Cluster{}
+	}
+	cluster, cached := r.cache[m.Host]
+	if !cached {
+		host := &model.Host{Base: model.Base{ID: m.Host}}
+		err := r.DB.Get(host)
+		if err != nil {
+			return
+		}
+		cluster = &model.Cluster{Base: model.Base{ID: host.Cluster}}
+		err = r.DB.Get(cluster)
+		if err != nil {
+			return
+		}
+		r.cache[m.Host] = cluster
+	}
+	rules = cluster.RulesOf(m.ID)
+	return
+}
+
 // VM detail=0
 type VM0 = Resource
 
@@ -253,9 +374,12 @@ type VM struct {
 	ToolsRunningStatus       string                 `json:"toolsRunningStatus"`
 	// Note: vSphere reports version as "toolsVersionStatus2"; we keep the Go field
 	// name ToolsVersionStatus for continuity while serializing as toolsVersionStatus2.
-	ToolsVersionStatus string `json:"toolsVersionStatus2"`
-	DiskEnableUuid     bool   `json:"diskEnableUuid"`
-	NestedHVEnabled    bool   `json:"nestedHVEnabled"`
+	ToolsVersionStatus string            `json:"toolsVersionStatus2"`
+	DiskEnableUuid     bool              `json:"diskEnableUuid"`
+	NestedHVEnabled    bool              `json:"nestedHVEnabled"`
+	Tags               []model.Tag       `json:"tags"`
+	CustomAttributes   []model.Attribute `json:"customAttributes"`
+	DrsRules           []model.DrsRule   `json:"drsRules"`
 }
 
 // Build the resource using the model.
@@ -295,6 +419,8 @@ func (r *VM) With(m *model.VM) {
 	r.ToolsVersionStatus = m.ToolsVersionStatus
 	r.DiskEnableUuid = m.DiskEnableUuid
 	r.NestedHVEnabled = m.NestedHVEnabled
+	r.Tags = m.Tags
+	r.CustomAttributes = m.CustomAttributes
 }
 
 // Build self link (URI).
@@ -316,6 +442,18 @@ func (r *VM) Content(detail int) interface{} {
 	return r
 }
 
+// Names of the tags in the category.
+func (r *VM) TagNames(category string) []string {
+	m := &model.VM{Tags: r.Tags}
+	return m.TagNames(category)
+}
+
+// Find the value of a custom attribute by name.
+func (r *VM) Attribute(name string) (string, bool) {
+	m := &model.VM{CustomAttributes: r.CustomAttributes}
+	return m.Attribute(name)
+}
+
 func (r *VM) HasDisk(disk model.Disk) bool {
 	for _, d := range r.Disks {
 		if d.File == disk.File {
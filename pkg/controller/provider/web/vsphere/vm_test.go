@@ -0,0 +1,65 @@
+package vsphere
+
+import (
+	"testing"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	libmodel "github.com/kubev2v/forklift/pkg/lib/inventory/model"
+	. "github.com/onsi/gomega"
+)
+
+func TestMetadataMatch(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	vm := &model.VM{
+		Tags: []model.Tag{
+			{Category: "env", Name: "prod"},
+			{Category: "app", Name: "web"},
+		},
+		CustomAttributes: []model.Attribute{
+			{Key: 1, Name: "owner", Value: "alice"},
+			{Key: 2, Name: "team"},
+		},
+	}
+	g.Expect(MetadataMatch(vm, nil, nil)).To(BeTrue())
+	g.Expect(MetadataMatch(vm, []string{"env:prod"}, nil)).To(BeTrue())
+	g.Expect(MetadataMatch(vm, []string{"env:prod", "app:web"}, nil)).To(BeTrue())
+	g.Expect(MetadataMatch(vm, []string{"env:prod", "app:db"}, nil)).To(BeFalse())
+	g.Expect(MetadataMatch(vm, []string{"env"}, nil)).To(BeFalse())
+	g.Expect(MetadataMatch(vm, nil, []string{"owner=alice"})).To(BeTrue())
+	g.Expect(MetadataMatch(vm, nil, []string{"owner"})).To(BeTrue())
+	g.Expect(MetadataMatch(vm, nil, []string{"owner=bob"})).To(BeFalse())
+	// Attributes without a value do not match.
+	g.Expect(MetadataMatch(vm, nil, []string{"team"})).To(BeFalse())
+	g.Expect(MetadataMatch(vm, []string{"env:prod"}, []string{"owner=alice"})).To(BeTrue())
+}
+
+func TestMetadataFilter(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	vm := func(id string, tags ...model.Tag) model.VM {
+		m := model.VM{Tags: tags}
+		m.ID = id
+		return m
+	}
+	prod := model.Tag{Category: "env", Name: "prod"}
+	list := []model.VM{
+		vm("vm-1"),
+		vm("vm-2"),
+		vm("vm-3", prod),
+		vm("vm-4", prod),
+	}
+	ids := func(list []model.VM) (ids []string) {
+		for _, m := range list {
+			ids = append(ids, m.ID)
+		}
+		return
+	}
+	// Paged after the match.
+	page := &libmodel.Page{Limit: 1}
+	g.Expect(ids(metadataFilter(list, []string{"env:prod"}, nil, page))).To(Equal([]string{"vm-3"}))
+	page.Offset = 1
+	g.Expect(ids(metadataFilter(list, []string{"env:prod"}, nil, page))).To(Equal([]string{"vm-4"}))
+	page.Offset = 2
+	g.Expect(metadataFilter(list, []string{"env:prod"}, nil, page)).To(BeEmpty())
+}
@@ -13,6 +13,7 @@ import (
 
 	refapi "github.com/kubev2v/forklift/pkg/apis/forklift/v1beta1/ref"
 	model "github.com/kubev2v/forklift/pkg/controller/provider/model/vsphere"
+	"github.com/kubev2v/forklift/pkg/controller/validation/rules"
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
 	libweb "github.com/kubev2v/forklift/pkg/lib/inventory/web"
 	"github.com/kubev2v/forklift/pkg/lib/logging"
@@ -40,13 +41,17 @@ func (r *ValidationError) Error() string {
 }
 
 // Client.
+// Validation is delegated to the built-in rules
+// engine when set, otherwise to the policy agent.
 type Client struct {
 	LibClient
+	// Built-in rules engine.
+	Rules *rules.Engine
 }
 
 // Enabled.
 func (r *Client) Enabled() bool {
-	return Settings.PolicyAgent.Enabled()
+	return r.Rules != nil || Settings.PolicyAgent.Enabled()
 }
 
 // Policy version.
@@ -54,6 +59,10 @@ func (r *Client) Version(path string) (version int, err error) {
 	if !r.Enabled() {
 		return
 	}
+	if r.Rules != nil {
+		version = r.Rules.Version()
+		return
+	}
 	out := &struct {
 		Result struct {
 			Version int `json:"rules_version"`
@@ -81,7 +90,7 @@ func (r *Client) Validate(
 	path string,
 	workload interface{}) (version int, concerns []model.Concern, err error) {
 	//
-	if !r.Enabled() {
+	if !Settings.PolicyAgent.Enabled() {
 		return
 	}
 	in := &struct {
@@ -114,6 +123,17 @@ func (r *Client) Validate(
 	return
 }
 
+// Validate the VM using the built-in rules
+// engine when set, otherwise the policy agent.
+func (r *Client) validate(task *Task, workload interface{}) (version int, concerns []model.Concern, err error) {
+	if r.Rules != nil {
+		version, concerns, err = r.Rules.Validate(task.Provider, workload)
+		return
+	}
+	version, concerns, err = r.Validate(task.Path, workload)
+	return
+}
+
 // Get request.
 func (r *Client) get(path string, out interface{}) (err error) {
 	parsedURL, err := liburl.Parse(Settings.PolicyAgent.URL)
@@ -214,6 +234,8 @@ func (c *Client) buildTransport() (err error) {
 type Task struct {
 	// Path (endpoint).
 	Path string
+	// Provider type.
+	Provider string
 	// VM reference.
 	Ref refapi.Ref
 	// Revision number of the VM being validated.
@@ -298,7 +320,7 @@ type Worker struct {
 
 // Main worker run.
 // Process input queue. Validation delegated to the
-// policy agent.
+// rules engine or the policy agent.
 func (r *Worker) run() {
 	go func() {
 		log.V(1).Info(
@@ -317,7 +339,7 @@ func (r *Worker) run() {
 			task.started = time.Now()
 			workload, err := task.Workload(task.Ref.ID)
 			if err == nil {
-				task.Version, task.Concerns, task.Error = r.client.Validate(task.Path, workload)
+				task.Version, task.Concerns, task.Error = r.client.validate(task, workload)
 				task.completed = time.Now()
 			} else {
 				task.Error = err
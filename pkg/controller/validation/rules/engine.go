@@ -0,0 +1,529 @@
+package rules
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"path"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/google/cel-go/ext"
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	liberr "github.com/kubev2v/forklift/pkg/lib/error"
+	"github.com/kubev2v/forklift/pkg/lib/logging"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	k8sclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+var log = logging.WithName("validation|rules")
+
+// Built-in rules.
+// Equivalent to the policy agent (Rego) rules.
+//
+//go:embed builtin/*.yaml
+var builtin embed.FS
+
+// Concern categories.
+const (
+	Critical    = "Critical"
+	Warning     = "Warning"
+	Information = "Information"
+)
+
+// CEL variables.
+const (
+	// The workload (VM) being validated.
+	VarVM = "vm"
+	// The provider type.
+	VarProvider = "provider"
+	// The item (of the rule items) being validated.
+	VarItem = "item"
+)
+
+// Validation rule.
+// The expression is evaluated against the workload using the
+// same document the policy agent receives as `input`. A concern
+// is reported when the expression evaluates to true. When items
+// are listed, the expression is evaluated for each item and a
+// concern is reported for each matched item.
+type Rule struct {
+	// Concern ID.
+	ID string `json:"id"`
+	// Provider types the rule applies to.
+	// Applies to all providers when empty.
+	Providers []string `json:"providers,omitempty"`
+	// Concern category (Critical|Warning|Information).
+	Category string `json:"category"`
+	// Concern label.
+	Label string `json:"label,omitempty"`
+	// Concern label CEL (string) expression.
+	// Supersedes the label when set.
+	LabelExpression string `json:"labelExpression,omitempty"`
+	// Concern assessment.
+	Assessment string `json:"assessment,omitempty"`
+	// Concern assessment CEL (string) expression.
+	// Supersedes the assessment when set.
+	AssessmentExpression string `json:"assessmentExpression,omitempty"`
+	// Items CEL (list) expression.
+	Items string `json:"items,omitempty"`
+	// CEL expression.
+	Expression string `json:"expression"`
+}
+
+// Applies to the provider type.
+func (r *Rule) AppliesTo(provider string) bool {
+	return len(r.Providers) == 0 || slices.Contains(r.Providers, provider)
+}
+
+// The concern reported by the rule.
+func (r *Rule) Concern() model.Concern {
+	return model.Concern{
+		Id:         r.ID,
+		Category:   r.Category,
+		Label:      r.Label,
+		Assessment: r.Assessment,
+	}
+}
+
+// Compiled rule.
+type compiled struct {
+	Rule
+	program    cel.Program
+	items      cel.Program
+	label      cel.Program
+	assessment cel.Program
+}
+
+// Built-in validation rules engine.
+// The built-in rules are extended by the rules loaded from a
+// ConfigMap. Each key contains a YAML list of rules. A rule with
+// the ID of a built-in rule replaces the built-in rule. The rules
+// version is derived from the rules content so that VMs are
+// validated again when the rules change. The ConfigMap is watched
+// and the rules are reloaded when it changes.
+type Engine struct {
+	// Client used to watch the ConfigMap.
+	Client k8sclient.WithWatch
+	// ConfigMap namespace.
+	Namespace string
+	// ConfigMap name.
+	Name string
+	// CEL environment.
+	env *cel.Env
+	// Compiled built-in rules.
+	builtin []compiled
+	// Built-in rules content.
+	builtinContent []byte
+	// Compiled rules.
+	rules []compiled
+	// Rules version.
+	version int
+	// Protect the rules.
+	mutex sync.RWMutex
+}
+
+// Build the engine.
+// The built-in rules are loaded.
+func New(client k8sclient.WithWatch, namespace, name string) (engine *Engine, err error) {
+	engine = &Engine{
+		Client:    client,
+		Namespace: namespace,
+		Name:      name,
+	}
+	engine.env, err = cel.NewEnv(
+		cel.Variable(VarVM, cel.DynType),
+		cel.Variable(VarProvider, cel.StringType),
+		cel.Variable(VarItem, cel.DynType),
+		cel.OptionalTypes(),
+		ext.Bindings(),
+		ext.Math(),
+		ext.Strings())
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	err = engine.loadBuiltin()
+	if err != nil {
+		return
+	}
+	err = engine.Load(&core.ConfigMap{})
+	if err != nil {
+		return
+	}
+
+	return
+}
+
+// Rules version.
+func (r *Engine) Version() (version int) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	version = r.version
+	return
+}
+
+// Run the ConfigMap watch.
+// The rules are loaded when the ConfigMap is created or
+// updated. Only the built-in rules are evaluated when the
+// ConfigMap does not exist.
+func (r *Engine) Run(ctx context.Context) (err error) {
+	selector := fields.OneTermEqualSelector("metadata.name", r.Name)
+	_, informer := cache.NewInformerWithOptions(
+		cache.InformerOptions{
+			ListerWatcher: &cache.ListWatch{
+				ListFunc: func(options meta.ListOptions) (runtime.Object, error) {
+					list := &core.ConfigMapList{}
+					err := r.Client.List(
+						ctx,
+						list,
+						&k8sclient.ListOptions{
+							Namespace:     r.Namespace,
+							FieldSelector: selector,
+							Raw:           &options,
+						})
+					return list, err
+				},
+				WatchFunc: func(options meta.ListOptions) (watch.Interface, error) {
+					return r.Client.Watch(
+						ctx,
+						&core.ConfigMapList{},
+						&k8sclient.ListOptions{
+							Namespace:     r.Namespace,
+							FieldSelector: selector,
+							Raw:           &options,
+						})
+				},
+			},
+			ObjectType: &core.ConfigMap{},
+			Handler: cache.ResourceEventHandlerFuncs{
+				AddFunc: func(object interface{}) {
+					r.changed(object)
+				},
+				UpdateFunc: func(_, object interface{}) {
+					r.changed(object)
+				},
+				DeleteFunc: func(object interface{}) {
+					if cm, cast := object.(*core.ConfigMap); cast && cm.Name == r.Name {
+						_ = r.Load(&core.ConfigMap{})
+					}
+				},
+			},
+		})
+	log.Info(
+		"Watching rules.",
+		"namespace",
+		r.Namespace,
+		"name",
+		r.Name)
+	informer.Run(ctx.Done())
+	return
+}
+
+// The ConfigMap has been created or updated.
+func (r *Engine) changed(object interface{}) {
+	cm, cast := object.(*core.ConfigMap)
+	if !cast || cm.Name != r.Name {
+		return
+	}
+	_ = r.Load(cm)
+}
+
+// Load the rules from the ConfigMap.
+// Rules that cannot be parsed or compiled are
+// logged and skipped.
+func (r *Engine) Load(cm *core.ConfigMap) (err error) {
+	keys := []string{}
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	hash := fnv.New32a()
+	_, _ = hash.Write(r.builtinContent)
+	replaced := map[string]compiled{}
+	added := []compiled{}
+	for _, key := range keys {
+		content := cm.Data[key]
+		_, _ = hash.Write([]byte(key))
+		_, _ = hash.Write([]byte(content))
+		for _, rule := range r.parse(key, []byte(content)) {
+			if slices.ContainsFunc(r.builtin, func(b compiled) bool { return b.ID == rule.ID }) {
+				replaced[rule.ID] = rule
+			} else {
+				added = append(added, rule)
+			}
+		}
+	}
+	loaded := []compiled{}
+	for _, rule := range r.builtin {
+		if replacement, found := replaced[rule.ID]; found {
+			rule = replacement
+		}
+		loaded = append(loaded, rule)
+	}
+	loaded = append(loaded, added...)
+	version := int(hash.Sum32()&math.MaxInt32) | 1
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.rules = loaded
+	r.version = version
+
+	log.Info(
+		"Rules loaded.",
+		"count",
+		len(loaded),
+		"replaced",
+		len(replaced),
+		"version",
+		version)
+
+	return
+}
+
+// Load the built-in rules.
+func (r *Engine) loadBuiltin() (err error) {
+	entries, err := builtin.ReadDir("builtin")
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	for _, entry := range entries {
+		name := path.Join("builtin", entry.Name())
+		content, rErr := builtin.ReadFile(name)
+		if rErr != nil {
+			err = liberr.Wrap(rErr)
+			return
+		}
+		r.builtinContent = append(r.builtinContent, content...)
+		r.builtin = append(r.builtin, r.parse(name, content)...)
+	}
+
+	return
+}
+
+// Parse and compile the rules.
+// Rules that cannot be parsed or compiled are
+// logged and skipped.
+func (r *Engine) parse(key string, content []byte) (rules []compiled) {
+	ruleList := []Rule{}
+	err := yaml.Unmarshal(content, &ruleList)
+	if err != nil {
+		log.Error(err, "Rules not valid.", "key", key)
+		return
+	}
+	for _, rule := range ruleList {
+		compiled, err := r.compile(&rule)
+		if err != nil {
+			log.Error(err, "Rule not valid.", "key", key, "rule", rule.ID)
+			continue
+		}
+		rules = append(rules, compiled)
+	}
+
+	return
+}
+
+// Compile the rule.
+func (r *Engine) compile(rule *Rule) (compiled compiled, err error) {
+	if rule.ID == "" {
+		err = liberr.New("rule id required.")
+		return
+	}
+	switch rule.Category {
+	case Critical, Warning, Information:
+	default:
+		err = liberr.New("rule category not valid.", "category", rule.Category)
+		return
+	}
+	compiled.Rule = *rule
+	compiled.program, err = r.program(rule.Expression, cel.BoolType)
+	if err != nil {
+		return
+	}
+	if rule.Items != "" {
+		compiled.items, err = r.program(rule.Items, cel.ListType(cel.DynType))
+		if err != nil {
+			return
+		}
+	}
+	if rule.LabelExpression != "" {
+		compiled.label, err = r.program(rule.LabelExpression, cel.StringType)
+		if err != nil {
+			return
+		}
+	}
+	if rule.AssessmentExpression != "" {
+		compiled.assessment, err = r.program(rule.AssessmentExpression, cel.StringType)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// Compile the expression.
+// The expression must evaluate to the specified type.
+func (r *Engine) program(expression string, outType *cel.Type) (program cel.Program, err error) {
+	ast, issues := r.env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		err = liberr.Wrap(issues.Err())
+		return
+	}
+	if !ast.OutputType().IsAssignableType(outType) && ast.OutputType() != cel.DynType {
+		err = liberr.New(
+			"rule expression type not valid.",
+			"expected",
+			outType.String(),
+			"type",
+			ast.OutputType().String())
+		return
+	}
+	program, err = r.env.Program(ast)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+
+	return
+}
+
+// Validate the workload.
+// The workload is converted to the document the policy agent
+// receives as `input`. Expressions that cannot be evaluated
+// against the workload, for example because a field is not set,
+// are logged and do not report a concern. Like the policy agent,
+// the same concern is reported once.
+func (r *Engine) Validate(
+	provider string,
+	workload interface{}) (version int, concerns []model.Concern, err error) {
+	//
+	b, err := json.Marshal(workload)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	var vm interface{}
+	err = json.Unmarshal(b, &vm)
+	if err != nil {
+		err = liberr.Wrap(err)
+		return
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	concerns = []model.Concern{}
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if !rule.AppliesTo(provider) {
+			continue
+		}
+		for _, concern := range rule.evaluate(provider, vm) {
+			if !slices.Contains(concerns, concern) {
+				concerns = append(concerns, concern)
+			}
+		}
+	}
+	version = r.version
+
+	return
+}
+
+// Evaluate the rule.
+// Returns the reported concerns.
+func (r *compiled) evaluate(provider string, vm interface{}) (concerns []model.Concern) {
+	vars := map[string]interface{}{
+		VarVM:       vm,
+		VarProvider: provider,
+		VarItem:     nil,
+	}
+	if r.items == nil {
+		if concern, matched := r.match(vars); matched {
+			concerns = append(concerns, concern)
+		}
+		return
+	}
+	out, _, err := r.items.Eval(vars)
+	if err != nil {
+		r.skipped(err)
+		return
+	}
+	items, cast := out.(traits.Lister)
+	if !cast {
+		return
+	}
+	for it := items.Iterator(); it.HasNext() == types.True; {
+		vars[VarItem] = it.Next()
+		if concern, matched := r.match(vars); matched {
+			concerns = append(concerns, concern)
+		}
+	}
+
+	return
+}
+
+// Match the rule expression.
+// Returns the concern when matched.
+func (r *compiled) match(vars map[string]interface{}) (concern model.Concern, matched bool) {
+	out, _, err := r.program.Eval(vars)
+	if err != nil {
+		r.skipped(err)
+		return
+	}
+	if matched, _ = out.Value().(bool); !matched {
+		return
+	}
+	concern = r.Concern()
+	if r.label != nil {
+		concern.Label, err = r.format(r.label, vars)
+		if err != nil {
+			r.skipped(err)
+			matched = false
+			return
+		}
+	}
+	if r.assessment != nil {
+		concern.Assessment, err = r.format(r.assessment, vars)
+		if err != nil {
+			r.skipped(err)
+			matched = false
+			return
+		}
+	}
+
+	return
+}
+
+// Evaluate a string expression.
+func (r *compiled) format(program cel.Program, vars map[string]interface{}) (s string, err error) {
+	out, _, err := program.Eval(vars)
+	if err != nil {
+		return
+	}
+	s, cast := out.Value().(string)
+	if !cast {
+		err = liberr.New("rule expression must be string.")
+	}
+	return
+}
+
+// The rule was not evaluated.
+func (r *compiled) skipped(err error) {
+	log.V(3).Info(
+		"Rule not evaluated.",
+		"rule",
+		r.ID,
+		"reason",
+		err.Error())
+}
@@ -0,0 +1,212 @@
+package rules
+
+import (
+	"context"
+	"testing"
+
+	model "github.com/kubev2v/forklift/pkg/controller/provider/model/base"
+	"github.com/onsi/gomega"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const testRules = `
+- id: lab.memory.dynamic.enabled
+  providers: [lab]
+  category: Warning
+  label: Dynamic memory detected
+  assessment: Dynamic memory is not supported.
+  expression: has(vm.dynamicMemory) && vm.dynamicMemory
+- id: disk.capacity.invalid
+  category: Critical
+  label: Disk has an invalid capacity
+  assessment: The disk capacity is not valid.
+  expression: vm.disks.exists(d, d.capacity <= 0)
+- id: name.invalid
+  category: Warning
+  label: Invalid VM Name
+  assessment: The VM name is not valid.
+  expression: >-
+    !vm.name.matches('^(([A-Za-z0-9][-A-Za-z0-9.]*)?[A-Za-z0-9])?$') || size(vm.name) > 63
+`
+
+type disk struct {
+	Capacity int64 `json:"capacity"`
+}
+
+type workload struct {
+	Name          string `json:"name"`
+	DynamicMemory bool   `json:"dynamicMemory"`
+	Disks         []disk `json:"disks"`
+}
+
+func ids(concerns []model.Concern) (ids []string) {
+	ids = []string{}
+	for _, concern := range concerns {
+		ids = append(ids, concern.Id)
+	}
+	return
+}
+
+func TestEngine(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	engine, err := New(nil, "test", "rules")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = engine.Load(&core.ConfigMap{Data: map[string]string{"rules.yaml": testRules}})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(len(engine.rules)).To(gomega.Equal(len(engine.builtin) + 3))
+
+	vm := &workload{
+		Name:          "my_vm",
+		DynamicMemory: true,
+		Disks:         []disk{{Capacity: 10}, {Capacity: 0}},
+	}
+	version, concerns, err := engine.Validate("lab", vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(version).ToNot(gomega.BeZero())
+	g.Expect(ids(concerns)).To(gomega.Equal([]string{
+		"lab.memory.dynamic.enabled",
+		"disk.capacity.invalid",
+		"name.invalid",
+	}))
+
+	// Provider specific rules.
+	_, concerns, err = engine.Validate("other", vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(ids(concerns)).To(gomega.Equal([]string{
+		"disk.capacity.invalid",
+		"name.invalid",
+	}))
+
+	// No concerns.
+	vm = &workload{Name: "my-vm", Disks: []disk{{Capacity: 10}}}
+	_, concerns, err = engine.Validate("lab", vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(concerns).To(gomega.BeEmpty())
+}
+
+func TestEngineNotValid(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	engine, err := New(nil, "test", "rules")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	err = engine.Load(&core.ConfigMap{Data: map[string]string{
+		"rules.yaml": testRules,
+		"invalid.yaml": `
+- id: no.category
+  label: Category missing
+  expression: "true"
+- id: not.boolean
+  category: Warning
+  expression: size(vm.name)
+- id: not.compiled
+  category: Warning
+  expression: vm.name ==
+- category: Warning
+  expression: "true"
+`,
+		"garbage.yaml": "{",
+	}})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(len(engine.rules)).To(gomega.Equal(len(engine.builtin) + 3))
+
+	// Missing fields.
+	_, concerns, err := engine.Validate("lab", &struct{}{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(concerns).To(gomega.BeEmpty())
+}
+
+func TestEngineBuiltin(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	engine, err := New(nil, "test", "rules")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(engine.builtin).ToNot(gomega.BeEmpty())
+	g.Expect(engine.version).ToNot(gomega.BeZero())
+
+	vm := map[string]interface{}{
+		"name":          "test",
+		"dynamicMemory": true,
+		"disks": []interface{}{
+			map[string]interface{}{"filePath": "/a.vhdx", "capacity": 0},
+			map[string]interface{}{"filePath": "/b.vhdx", "capacity": -1},
+			map[string]interface{}{"filePath": "/c.vhdx", "capacity": 10},
+		},
+	}
+	_, concerns, err := engine.Validate("hyperv", vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(ids(concerns)).To(gomega.Equal([]string{
+		"hyperv.disk.capacity.invalid",
+		"hyperv.disk.capacity.invalid",
+		"hyperv.memory.dynamic.enabled",
+	}))
+	g.Expect(concerns[0].Label).To(gomega.Equal("Disk '/a.vhdx' has an invalid capacity of 0 bytes"))
+	g.Expect(concerns[1].Label).To(gomega.Equal("Disk '/b.vhdx' has an invalid capacity of -1 bytes"))
+
+	// Replaced.
+	err = engine.Load(&core.ConfigMap{Data: map[string]string{"rules.yaml": `
+- id: hyperv.memory.dynamic.enabled
+  providers: [hyperv]
+  category: Information
+  label: Dynamic memory
+  assessment: Dynamic memory.
+  expression: "false"
+`}})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(len(engine.rules)).To(gomega.Equal(len(engine.builtin)))
+	_, concerns, err = engine.Validate("hyperv", vm)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(ids(concerns)).To(gomega.Equal([]string{
+		"hyperv.disk.capacity.invalid",
+		"hyperv.disk.capacity.invalid",
+	}))
+}
+
+func TestEngineReload(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	cm := &core.ConfigMap{
+		ObjectMeta: meta.ObjectMeta{
+			Namespace: "test",
+			Name:      "rules",
+		},
+		Data: map[string]string{"rules.yaml": testRules},
+	}
+	client := fake.NewClientBuilder().
+		WithObjects(cm).
+		WithIndex(&core.ConfigMap{}, "metadata.name", func(object client.Object) []string {
+			return []string{object.GetName()}
+		}).
+		Build()
+	engine, err := New(client, "test", "rules")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	builtin := engine.Version()
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go func() {
+		_ = engine.Run(ctx)
+	}()
+	g.Eventually(engine.Version).ShouldNot(gomega.Equal(builtin))
+	version := engine.Version()
+	g.Expect(len(engine.rules)).To(gomega.Equal(len(engine.builtin) + 3))
+
+	// Changed.
+	cm.Data["more.yaml"] = `
+- id: vm.windows
+  category: Information
+  label: Windows
+  assessment: Windows guest.
+  expression: vm.name.lowerAscii().startsWith('win')
+`
+	g.Expect(client.Update(t.Context(), cm)).To(gomega.Succeed())
+	g.Eventually(engine.Version).ShouldNot(gomega.Equal(version))
+	g.Expect(len(engine.rules)).To(gomega.Equal(len(engine.builtin) + 4))
+
+	// Deleted.
+	g.Expect(client.Delete(t.Context(), cm)).To(gomega.Succeed())
+	g.Eventually(engine.Version).Should(gomega.Equal(builtin))
+	g.Expect(len(engine.rules)).To(gomega.Equal(len(engine.builtin)))
+}
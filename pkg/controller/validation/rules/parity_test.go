@@ -0,0 +1,86 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"sigs.k8s.io/yaml"
+)
+
+// Policy agent (Rego) rules.
+const policies = "../../../../validation/policies/io/konveyor/forklift"
+
+// Policy agent package by provider type.
+var packages = map[string]string{
+	"vsphere":   "vmware",
+	"ovirt":     "ovirt",
+	"openstack": "openstack",
+	"ova":       "ova",
+	"hyperv":    "hyperv",
+}
+
+// Policy agent test case.
+type policyCase struct {
+	Provider string                 `json:"provider"`
+	Name     string                 `json:"name"`
+	Concerns int                    `json:"concerns"`
+	Input    map[string]interface{} `json:"input"`
+}
+
+// The concern IDs reported by the policy agent.
+func policyIDs(t *testing.T, pkg string) (ids []string) {
+	pattern := regexp.MustCompile(`"id":\s*"([^"]+)"`)
+	paths, err := filepath.Glob(filepath.Join(policies, pkg, "*.rego"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.rego") {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, match := range pattern.FindAllStringSubmatch(string(content), -1) {
+			ids = append(ids, match[1])
+		}
+	}
+	sort.Strings(ids)
+	return
+}
+
+func TestBuiltinParity(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	engine, err := New(nil, "test", "rules")
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	for provider, pkg := range packages {
+		ids := []string{}
+		for _, rule := range engine.builtin {
+			if rule.AppliesTo(provider) {
+				ids = append(ids, rule.ID)
+			}
+		}
+		sort.Strings(ids)
+		g.Expect(ids).To(gomega.Equal(policyIDs(t, pkg)), provider)
+	}
+
+	content, err := os.ReadFile(filepath.Join("testdata", "policies.yaml"))
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	cases := []policyCase{}
+	g.Expect(yaml.Unmarshal(content, &cases)).To(gomega.Succeed())
+	g.Expect(cases).ToNot(gomega.BeEmpty())
+	for _, c := range cases {
+		_, concerns, err := engine.Validate(c.Provider, c.Input)
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+		if len(concerns) != c.Concerns {
+			t.Errorf("%s: expected %d concerns, got: %v", c.Name, c.Concerns, ids(concerns))
+		}
+	}
+}
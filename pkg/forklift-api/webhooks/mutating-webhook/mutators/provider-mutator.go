@@ -82,7 +82,7 @@ func (mutator *ProviderMutator) setSdkEndpointIfNeeded() bool {
 
 func (mutator *ProviderMutator) setFinalizers() bool {
 	var changed bool
-	if mutator.provider.Type() == api.Ova {
+	if mutator.provider.UsesApplianceServer() {
 		changed = k8sutil.AddFinalizer(&(mutator.provider), api.OvaProviderFinalizer)
 	}
 	return changed
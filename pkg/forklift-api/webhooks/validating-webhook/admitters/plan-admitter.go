@@ -103,7 +103,7 @@ func (admitter *PlanAdmitter) validateLUKS() error {
 	}
 
 	providerType := admitter.sourceProvider.Type()
-	if providerType != api.VSphere && providerType != api.Ova {
+	if providerType != api.VSphere && providerType != api.Ova && providerType != api.HyperV {
 		err := liberr.New(fmt.Sprintf("migration of encrypted disks from source provider of type %s is not supported", providerType))
 		log.Error(err, "Provider type (non-VSphere & non-OVA) does not support LUKS")
 		return err
@@ -88,7 +88,7 @@ func (admitter *SecretAdmitter) validateProviderSecret() *admissionv1.AdmissionR
 	if createdForProviderType, ok := admitter.secret.GetLabels()["createdForProviderType"]; ok {
 		providerType := api.ProviderType(createdForProviderType)
 
-		if admitter.ar.Request.Operation == admissionv1.Update && providerType == api.Ova {
+		if admitter.ar.Request.Operation == admissionv1.Update && (providerType == api.Ova || providerType == api.HyperV) {
 			// there's no need to proceed to provider connection test since the URL
 			// does not change and credentials are not specified
 			return admitter.validateUpdateOfOVAProviderSecret()
@@ -1,7 +1,10 @@
 package model
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"reflect"
+	"sort"
 	"strings"
 
 	liberr "github.com/kubev2v/forklift/pkg/lib/error"
@@ -269,6 +272,22 @@ func (r *DataModel) DDL() (list []string, err error) {
 	return
 }
 
+// Digest of the DDL.
+// Changes when the schema of the models changes.
+func (r *DataModel) Digest() (digest string, err error) {
+	ddl, err := r.DDL()
+	if err != nil {
+		return
+	}
+	sort.Strings(ddl)
+	h := sha256.New()
+	for _, statement := range ddl {
+		_, _ = h.Write([]byte(statement))
+	}
+	digest = hex.EncodeToString(h.Sum(nil))
+	return
+}
+
 // Find by kind.
 func (r *DataModel) Find(kind string) (md *Definition, found bool) {
 	key := strings.ToLower(kind)
@@ -849,6 +849,24 @@ func TestList(t *testing.T) {
 	g.Expect(len(list)).To(gomega.Equal(2))
 	g.Expect(list[0].ID).To(gomega.Equal(4))
 	g.Expect(list[1].ID).To(gomega.Equal(8))
+	// Sort by name.
+	list = []TestObject{}
+	err = DB.List(
+		&list,
+		ListOptions{
+			SortBy: []string{"-id"},
+			Page:   &Page{Limit: 2},
+		})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(len(list)).To(gomega.Equal(2))
+	g.Expect(list[0].ID).To(gomega.Equal(9))
+	g.Expect(list[1].ID).To(gomega.Equal(8))
+	err = DB.List(
+		&list,
+		ListOptions{
+			SortBy: []string{"unknown"},
+		})
+	g.Expect(errors.Is(err, SortRefErr)).To(gomega.BeTrue())
 	// Test count all.
 	count, err := DB.Count(&TestObject{}, nil)
 	g.Expect(err).ToNot(gomega.HaveOccurred())
@@ -1541,3 +1559,48 @@ func fieldNames(fields []*Field) (names []string) {
 
 	return
 }
+
+func TestSweep(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	DB := New("/tmp/test-sweep.db", &PlainObject{})
+	err := DB.Open(true)
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	defer func() {
+		_ = DB.Close(true)
+	}()
+	for i := 0; i < 5; i++ {
+		err = DB.Insert(&PlainObject{ID: i})
+		g.Expect(err).ToNot(gomega.HaveOccurred())
+	}
+	sweep := Sweep{}
+	sweep.Seen(&PlainObject{ID: 1})
+	sweep.Seen(&PlainObject{ID: 3})
+	tx, err := DB.Begin()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	deleted, err := sweep.Delete(tx, &PlainObject{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(tx.Commit()).To(gomega.Succeed())
+	g.Expect(deleted).To(gomega.Equal(3))
+	list := []PlainObject{}
+	err = DB.List(&list, ListOptions{})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	g.Expect(len(list)).To(gomega.Equal(2))
+	g.Expect(list[0].ID).To(gomega.Equal(1))
+	g.Expect(list[1].ID).To(gomega.Equal(3))
+}
+
+func TestDigest(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	dmA, err := NewModel([]interface{}{&PlainObject{}, &TestObject{}})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	dmB, err := NewModel([]interface{}{&TestObject{}, &PlainObject{}})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	dmC, err := NewModel([]interface{}{&PlainObject{}})
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	digestA, err := dmA.Digest()
+	g.Expect(err).ToNot(gomega.HaveOccurred())
+	digestB, _ := dmB.Digest()
+	digestC, _ := dmC.Digest()
+	g.Expect(digestA).To(gomega.Equal(digestB))
+	g.Expect(digestA).ToNot(gomega.Equal(digestC))
+}
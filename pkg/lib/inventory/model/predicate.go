@@ -315,7 +315,7 @@ func (p *AndPredicate) Expr() string {
 		predicates = append(predicates, p.Expr())
 	}
 
-	expr := strings.Join(predicates, " AND ")
+	expr := "(" + strings.Join(predicates, " AND ") + ")"
 
 	return expr
 }
@@ -344,7 +344,7 @@ func (p *OrPredicate) Expr() string {
 		predicates = append(predicates, p.Expr())
 	}
 
-	expr := strings.Join(predicates, " OR ")
+	expr := "(" + strings.Join(predicates, " OR ") + ")"
 
 	return expr
 }
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"reflect"
+)
+
+// Sweep.
+// Tracks the models seen while the DB is resynchronized
+// in place so the models no longer found may be deleted.
+type Sweep struct {
+	// Primary keys seen by kind.
+	seen map[string]map[string]bool
+}
+
+// Note the model has been seen.
+func (r *Sweep) Seen(m Model) {
+	if r.seen == nil {
+		r.seen = make(map[string]map[string]bool)
+	}
+	kind := r.kind(m)
+	if r.seen[kind] == nil {
+		r.seen[kind] = make(map[string]bool)
+	}
+	r.seen[kind][m.Pk()] = true
+}
+
+// Delete the models of the specified kinds
+// that have not been seen.
+func (r *Sweep) Delete(tx *Tx, models ...Model) (deleted int, err error) {
+	for _, m := range models {
+		seen := r.seen[r.kind(m)]
+		itr, fErr := tx.Find(m, ListOptions{})
+		if fErr != nil {
+			err = fErr
+			return
+		}
+		for {
+			object, hasNext := itr.Next()
+			if !hasNext {
+				break
+			}
+			found := object.(Model)
+			if seen[found.Pk()] {
+				continue
+			}
+			err = tx.Delete(found)
+			if err != nil {
+				return
+			}
+			deleted++
+		}
+	}
+
+	return
+}
+
+// Kind of model.
+func (r *Sweep) kind(m Model) string {
+	return reflect.TypeOf(m).Elem().Name()
+}
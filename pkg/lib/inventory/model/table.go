@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -109,9 +110,9 @@ WHERE
 {{ if .Predicate -}}
 {{ .Predicate.Expr }}
 {{ end -}}
-{{ if .Sort -}}
+{{ if .OrderBy -}}
 ORDER BY
-{{ range $i,$n := .Sort -}}
+{{ range $i,$n := .OrderBy -}}
 {{ if $i }},{{ end }}{{ $n }}
 {{ end -}}
 {{ end -}}
@@ -145,6 +146,8 @@ var (
 	PredicateValueErr = errors.New("predicate value not valid")
 	// Invalid detail level.
 	DetailErr = errors.New("detail level must be <= MaxDetail")
+	// Sort references unknown field.
+	SortRefErr = errors.New("sort referenced unknown field")
 )
 
 // Represents a table in the DB.
@@ -969,12 +972,21 @@ func (t TmplData) Sort() []int {
 	return t.Options.Sort
 }
 
+// Order by (SQL) terms.
+func (t TmplData) OrderBy() []string {
+	return t.Options.orderBy
+}
+
 // FilterOptions options.
 type FilterOptions struct {
 	// Pagination.
 	Page *Page
 	// Sort by field position.
 	Sort []int
+	// Sort by field name.
+	// Prefixed by `-` for descending order.
+	// Applied before the Sort (positions).
+	SortBy []string
 	// Field detail level.
 	// Defaults:
 	//   0 = primary and natural fields.
@@ -988,6 +1000,8 @@ type FilterOptions struct {
 	fields []*Field
 	// Params.
 	params []interface{}
+	// Order by (SQL) terms.
+	orderBy []string
 }
 
 // Validate options.
@@ -996,11 +1010,50 @@ func (l *FilterOptions) Build(md *Definition) (err error) {
 	l.fields = md.Fields
 	if l.Predicate != nil {
 		err = l.Predicate.Build(l)
+		if err != nil {
+			return
+		}
+	}
+	err = l.buildOrderBy()
+
+	return
+}
+
+// Build the order by terms.
+func (l *FilterOptions) buildOrderBy() (err error) {
+	l.orderBy = []string{}
+	for _, key := range l.SortBy {
+		name := strings.TrimPrefix(key, "-")
+		f, found := l.field(name)
+		if !found {
+			err = liberr.Wrap(SortRefErr, "field", name)
+			return
+		}
+		term := f.Name
+		if name != key {
+			term += " DESC"
+		}
+		l.orderBy = append(l.orderBy, term)
+	}
+	for _, n := range l.Sort {
+		l.orderBy = append(l.orderBy, strconv.Itoa(n))
 	}
 
 	return
 }
 
+// Find field by name (case-insensitive).
+func (l *FilterOptions) field(name string) (*Field, bool) {
+	name = strings.ToLower(name)
+	for _, f := range l.fields {
+		if name == strings.ToLower(f.Name) {
+			return f, true
+		}
+	}
+
+	return nil, false
+}
+
 // Get an appropriate parameter name.
 // Builds a parameter and adds it to the options.param list.
 func (l *FilterOptions) Param(name string, value interface{}) (p string) {
@@ -100,6 +100,33 @@ var (
 		},
 	)
 
+	// 'plan' - [Id]
+	// 'vm' - [Id]
+	replicationRPOGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_replication_rpo_seconds",
+		Help: "Time since the recovery point of replicated VMs in seconds",
+	},
+		[]string{"plan", "vm"},
+	)
+
+	// 'plan' - [Id]
+	// 'vm' - [Id]
+	replicationFailuresGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_replication_consecutive_failures",
+		Help: "Consecutive failed precopies of replicated VMs",
+	},
+		[]string{"plan", "vm"},
+	)
+
+	// 'plan' - [Id]
+	// 'vm' - [Id]
+	replicationDegradedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "mtv_replication_degraded",
+		Help: "Replicated VMs with consecutive failed precopies over the threshold",
+	},
+		[]string{"plan", "vm"},
+	)
+
 	// 'provider' - [oVirt, VSphere, Openstack, OVA, Openshift]
 	// 'mode' - [Cold, Warm]
 	// 'target' - [Local, Remote]
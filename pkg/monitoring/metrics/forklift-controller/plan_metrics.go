@@ -30,7 +30,13 @@ func RecordPlanMetrics(c client.Client) {
 			// Initialize or reset the counter map at the beginning of each iteration
 			plansCounterMap := make(map[string]float64)
 
+			replicationRPOGauge.Reset()
+			replicationFailuresGauge.Reset()
+			replicationDegradedGauge.Reset()
+
 			for _, m := range plans.Items {
+				recordReplicationMetrics(&m)
+
 				sourceProvider := api.Provider{}
 				err = c.Get(context.TODO(), client.ObjectKey{Namespace: m.Spec.Provider.Source.Namespace, Name: m.Spec.Provider.Source.Name}, &sourceProvider)
 				if err != nil {
@@ -100,3 +106,25 @@ func RecordPlanMetrics(c client.Client) {
 		}
 	}()
 }
+
+// Record the RPO and failures of the VMs replicated by the plan.
+func recordReplicationMetrics(m *api.Plan) {
+	if !m.IsReplicating() {
+		return
+	}
+	for _, vm := range m.Status.Migration.VMs {
+		if vm.Warm == nil || vm.MarkedCompleted() {
+			continue
+		}
+		labels := prometheus.Labels{"plan": string(m.UID), "vm": vm.ID}
+		if vm.Warm.RecoveryPoint != nil {
+			replicationRPOGauge.With(labels).Set(time.Since(vm.Warm.RecoveryPoint.Time).Seconds())
+		}
+		replicationFailuresGauge.With(labels).Set(float64(vm.Warm.ConsecutiveFailures))
+		degraded := 0.0
+		if vm.Warm.ConsecutiveFailures >= m.Spec.Replication.Threshold() {
+			degraded = 1
+		}
+		replicationDegradedGauge.With(labels).Set(degraded)
+	}
+}
@@ -21,6 +21,9 @@ const (
 const (
 	AllowedOrigins = "CORS_ALLOWED_ORIGINS"
 	WorkingDir     = "WORKING_DIR"
+	Persistent     = "INVENTORY_PERSISTENT"
+	HistoryAge     = "INVENTORY_HISTORY_RETENTION"
+	HistoryLimit   = "INVENTORY_HISTORY_LIMIT"
 	AuthRequired   = "AUTH_REQUIRED"
 	Host           = "API_HOST"
 	Namespace      = "POD_NAMESPACE"
@@ -43,6 +46,16 @@ type Inventory struct {
 	CORS CORS
 	// DB working directory.
 	WorkingDir string
+	// The DB is persisted in the working directory
+	// and reused when the controller is restarted.
+	Persistent bool
+	// Change history retention.
+	History struct {
+		// Max age (hours) of the retained changes.
+		Age int
+		// Max number of changes retained per provider.
+		Limit int
+	}
 	// Authorization required.
 	AuthRequired bool
 	// Host.
@@ -65,7 +78,7 @@ type Inventory struct {
 }
 
 // Load settings.
-func (r *Inventory) Load() error {
+func (r *Inventory) Load() (err error) {
 	r.CORS = CORS{
 		AllowedOrigins: []string{},
 	}
@@ -84,6 +97,17 @@ func (r *Inventory) Load() error {
 	} else {
 		r.WorkingDir = os.TempDir()
 	}
+	// Persistent
+	r.Persistent = getEnvBool(Persistent, false)
+	// History
+	r.History.Age, err = getPositiveEnvLimit(HistoryAge, 72)
+	if err != nil {
+		return err
+	}
+	r.History.Limit, err = getPositiveEnvLimit(HistoryLimit, 10000)
+	if err != nil {
+		return err
+	}
 	// Auth
 	r.AuthRequired = getEnvBool(AuthRequired, true)
 	// Host
@@ -0,0 +1,78 @@
+package settings
+
+import (
+	"os"
+	"strings"
+)
+
+// Environment variables.
+const (
+	NotifierSink       = "NOTIFIER_SINK_URL"
+	NotifierCA         = "NOTIFIER_CA"
+	NotifierEvents     = "NOTIFIER_EVENTS"
+	NotifierNamespaces = "NOTIFIER_NAMESPACES"
+	NotifierQueueLimit = "NOTIFIER_QUEUE_LIMIT"
+)
+
+// Notifier settings.
+// Migration lifecycle events are posted as CloudEvents
+// to the sink when enabled.
+type Notifier struct {
+	// Sink URL.
+	Sink string
+	// CA path used to verify the sink.
+	CA string
+	// Filters.
+	Filter struct {
+		// Event types. All when empty.
+		// Either the full CloudEvent type or the short
+		// name, e.g. `vm.failed`.
+		Events []string
+		// Plan namespaces. All when empty.
+		Namespaces []string
+	}
+	// Limits.
+	Limit struct {
+		// Number of queued events.
+		Queue int
+	}
+}
+
+// Load settings.
+func (r *Notifier) Load() (err error) {
+	if s, found := os.LookupEnv(NotifierSink); found {
+		r.Sink = s
+	}
+	if s, found := os.LookupEnv(NotifierCA); found {
+		r.CA = s
+	}
+	r.Filter.Events = getEnvList(NotifierEvents)
+	r.Filter.Namespaces = getEnvList(NotifierNamespaces)
+	r.Limit.Queue, err = getPositiveEnvLimit(NotifierQueueLimit, 100)
+	if err != nil {
+		return err
+	}
+
+	return
+}
+
+// Enabled.
+func (r *Notifier) Enabled() bool {
+	return r.Sink != ""
+}
+
+// Get a comma separated list from the environment.
+func getEnvList(name string) (list []string) {
+	s, found := os.LookupEnv(name)
+	if !found {
+		return
+	}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return
+}
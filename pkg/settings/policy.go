@@ -11,6 +11,7 @@ const (
 	PolicyAgentCA             = "POLICY_AGENT_CA"
 	PolicyAgentWorkerLimit    = "POLICY_AGENT_WORKER_LIMIT"
 	PolicyAgentSearchInterval = "POLICY_AGENT_SEARCH_INTERVAL"
+	PolicyRulesConfigMap      = "POLICY_RULES_CONFIGMAP"
 )
 
 // Policy agent settings.
@@ -24,6 +25,12 @@ type PolicyAgent struct {
 	}
 	// Search interval (seconds).
 	SearchInterval int
+	// Built-in rules engine.
+	Rules struct {
+		// Name of the ConfigMap containing the rules.
+		// The ConfigMap is in the controller namespace.
+		ConfigMap string
+	}
 	// Limits.
 	Limit struct {
 		// Number of workers.
@@ -42,6 +49,9 @@ func (r *PolicyAgent) Load() (err error) {
 	} else if _, err := os.Stat(ServiceCAFile); !errors.Is(err, os.ErrNotExist) {
 		r.TLS.CA = ServiceCAFile
 	}
+	if s, found := os.LookupEnv(PolicyRulesConfigMap); found {
+		r.Rules.ConfigMap = s
+	}
 	r.Limit.Worker, err = getPositiveEnvLimit(PolicyAgentWorkerLimit, 10)
 	if err != nil {
 		return err
@@ -58,3 +68,9 @@ func (r *PolicyAgent) Load() (err error) {
 func (r *PolicyAgent) Enabled() bool {
 	return r.URL != ""
 }
+
+// The built-in rules engine is enabled.
+// Takes precedence over the policy agent.
+func (r *PolicyAgent) RulesEnabled() bool {
+	return r.Rules.ConfigMap != ""
+}
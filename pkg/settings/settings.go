@@ -38,6 +38,8 @@ type ControllerSettings struct {
 	Features
 	// Provider settings.
 	Providers
+	// Notifier settings.
+	Notifier
 	OpenShift   bool
 	Development bool
 }
@@ -80,6 +82,10 @@ func (r *ControllerSettings) Load() error {
 	if err != nil {
 		return err
 	}
+	err = r.Notifier.Load()
+	if err != nil {
+		return err
+	}
 	r.OpenShift = getEnvBool(OpenShift, false)
 	r.Development = getEnvBool(Development, false)
 	return nil
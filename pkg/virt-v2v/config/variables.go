@@ -31,10 +31,12 @@ const (
 	EnvMultipleIpsPerNicName      = "V2V_multipleIPsPerNic"
 	EnvRemoteInspection           = "V2V_remoteInspection"
 	EnvRemoteInspectionDisk       = "V2V_remoteInspectDisk_"
+	EnvDisk                       = "V2V_disk_"
 )
 
 const (
 	OVA     = "ova"
+	HYPERV  = "hyperv"
 	VSPHERE = "vSphere"
 )
 
@@ -95,6 +97,10 @@ type AppConfig struct {
 	IsRemoteInspection bool
 	// RemoteInspectionDisks
 	RemoteInspectionDisks []string
+	// V2V_disk_<index>
+	// The disks converted, all disks when empty.
+	// The vSphere disks are the datastore files, the OVA disks the file names.
+	Disks []string
 
 	// V2V_multipleIPsPerNic
 	MultipleIpsPerNicName string
@@ -114,14 +120,14 @@ func (s *AppConfig) Load() (err error) {
 	flag.BoolVar(&s.IsLocalMigration, "local-migration", s.getEnvBool(EnvLocalMigrationName, true), "Migration is in local or remote cluster")
 	flag.BoolVar(&s.IsInPlace, "in-place", s.getEnvBool(EnvInPlaceName, false), "Run virt-v2v-in-place on already populated disks")
 	flag.BoolVar(&s.NbdeClevis, "nbde-clevis", s.getEnvBool(EnvNbdeClevis, false), "virt-v2v should unencrypt the disks via clevis client")
-	flag.StringVar(&s.Source, "source", os.Getenv(EnvSourceName), "Source of VM ['ova','vSphere']")
+	flag.StringVar(&s.Source, "source", os.Getenv(EnvSourceName), "Source of VM ['ova','hyperv','vSphere']")
 	flag.StringVar(&s.LibvirtUrl, "libvirt-url", os.Getenv(EnvLibvirtUrlName), "Libvirt domain to the vSphere")
 	flag.StringVar(&s.Fingerprint, "fingerprint", os.Getenv(EnvFingerprintName), "Fingerprint for the vddk")
 	flag.StringVar(&s.NewVmName, "new-vm-name", os.Getenv(EnvNewNameName), "Rename the VM in virt-v2v output")
 	flag.StringVar(&s.VmName, "vm-name", os.Getenv(EnvVmNameName), "Original VM name")
 	flag.StringVar(&s.RootDisk, "root-disk", os.Getenv(EnvRootDiskName), "Specify which disk should be converted (default \"first\")")
 	flag.StringVar(&s.StaticIPs, "static-ips", os.Getenv(EnvStaticIPsName), "Preserve static IPs, format <mac:network|bridge|ip:out>_<mac:network|bridge|ip:out>")
-	flag.StringVar(&s.DiskPath, "disk-path", os.Getenv(EnvDiskPathName), "Path to the OVA disk")
+	flag.StringVar(&s.DiskPath, "disk-path", os.Getenv(EnvDiskPathName), "Path to the OVA disk or the list of Hyper-V disks")
 	flag.StringVar(&s.AccessKeyId, "access-key", AccessKeyId, "Path to the Username for the vSphere")
 	flag.StringVar(&s.SecretKey, "secret-key", SecretKey, "Path to the secret to the vSphere")
 	flag.StringVar(&s.Luksdir, "luks-dir", Luksdir, "Directory path containing the luks keys")
@@ -130,12 +136,13 @@ func (s *AppConfig) Load() (err error) {
 	flag.StringVar(&s.VddkLibDir, "vddk-lib-dir", VddkLib, "Directory path containing the vddk library")
 	flag.StringVar(&s.VddkConfFile, "vddk-conf-file", VddkConfFile, "Path for additional vddk configuration")
 	flag.StringVar(&s.InspectionOutputFile, "inspection-output-file", InspectionOutputFile, "Path where the virt-v2v-inspector will output the metadata")
-	flag.StringVar(&s.LibvirtDomainFile, "libvirt-domain-file", V2vInPlaceLibvirtDomain, "Path to the libvirt domain used in the in-place conversion")
+	flag.StringVar(&s.LibvirtDomainFile, "libvirt-domain-file", V2vInPlaceLibvirtDomain, "Path to the libvirt domain used in the in-place conversion and as the disk input")
 	flag.StringVar(&s.VirtIoWinLegacyDrivers, "virtio-win-legacy-drivers", os.Getenv(EnvVirtIoWinLegacyDriversName), "Path to the virtio-win legacy drivers ISO")
 	flag.StringVar(&s.HostName, "hostname", os.Getenv(EnvHostName), "Hostname of the vm")
 	flag.StringVar(&s.MultipleIpsPerNicName, "multiple-ips-per-nic", os.Getenv(EnvMultipleIpsPerNicName), "Multiple IPs per NIC")
 	flag.BoolVar(&s.IsRemoteInspection, "remote-inspection", s.getEnvBool(EnvRemoteInspection, false), "Run virt-v2v-inspection on remote disks")
 	s.RemoteInspectionDisks = s.getRemoteInspectionDisks()
+	s.Disks = s.getDisks()
 	flag.Parse()
 
 	return s.validate()
@@ -169,6 +176,20 @@ func (s *AppConfig) getRemoteInspectionDisks() []string {
 	return disks
 }
 
+// The disks are listed by index.
+func (s *AppConfig) getDisks() []string {
+	var disks []string
+	for i := 0; ; i++ {
+		disk, found := os.LookupEnv(fmt.Sprintf("%s%d", EnvDisk, i))
+		if !found {
+			break
+		}
+		disks = append(disks, disk)
+	}
+
+	return disks
+}
+
 // Get boolean.
 func (s *AppConfig) getEnvBool(name string, def bool) bool {
 	if s, found := os.LookupEnv(name); found {
@@ -187,7 +208,7 @@ func (s *AppConfig) envMissingError(env string) error {
 func (s *AppConfig) validate() error {
 	if !s.IsInPlace {
 		switch s.Source {
-		case OVA:
+		case OVA, HYPERV:
 			if s.DiskPath == "" {
 				return s.envMissingError(EnvDiskPathName)
 			}
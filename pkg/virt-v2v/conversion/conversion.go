@@ -139,12 +139,24 @@ func (c *Conversion) addVirtV2vArgs(cmd utils.CommandBuilder) (err error) {
 		AddArg("-on", c.NewVmName)
 	switch c.Source {
 	case config.VSPHERE:
+		err = c.addVirtV2vVsphereDiskArgs(cmd)
+		if err != nil {
+			return err
+		}
 		err = c.addVirtV2vVsphereArgs(cmd)
 		if err != nil {
 			return err
 		}
 	case config.OVA:
-		c.virtV2vOVAArgs(cmd)
+		err = c.addVirtV2vOVAArgs(cmd)
+		if err != nil {
+			return err
+		}
+	case config.HYPERV:
+		err = c.addVirtV2vHyperVArgs(cmd)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -174,9 +186,43 @@ func (c *Conversion) addVirtV2vVsphereArgs(cmd utils.CommandBuilder) (err error)
 	return nil
 }
 
-func (c *Conversion) virtV2vOVAArgs(cmd utils.CommandBuilder) {
-	cmd.AddArg("-i", "ova")
-	cmd.AddPositional(c.DiskPath)
+// Only the selected disks are converted. The disks are
+// selected by file using the VDDK.
+func (c *Conversion) addVirtV2vVsphereDiskArgs(cmd utils.CommandBuilder) (err error) {
+	if len(c.Disks) == 0 {
+		return
+	}
+	if info, err := os.Stat(c.VddkLibDir); err != nil || !info.IsDir() {
+		return fmt.Errorf("the VDDK is required to convert the selected disks")
+	}
+	for _, disk := range c.Disks {
+		cmd.AddArg("-io", fmt.Sprintf("vddk-file=%s", disk))
+	}
+	return
+}
+
+// The OVA is converted as a whole unless disks are excluded.
+// The selected disks are passed to virt-v2v by the libvirt domain
+// listing them.
+func (c *Conversion) addVirtV2vOVAArgs(cmd utils.CommandBuilder) (err error) {
+	if len(c.Disks) == 0 {
+		cmd.AddArg("-i", "ova")
+		cmd.AddPositional(c.DiskPath)
+		return nil
+	}
+	paths, err := c.ovaDiskPaths()
+	if err != nil {
+		return err
+	}
+	return c.addDiskDomainArgs(cmd, paths)
+}
+
+// The Hyper-V disks (VHDX/VHD) are read directly from the share.
+// The DiskPath is the list of disks separated by the OS path list
+// separator, the root disk first. The disks are passed to virt-v2v
+// by the libvirt domain listing them.
+func (c *Conversion) addVirtV2vHyperVArgs(cmd utils.CommandBuilder) (err error) {
+	return c.addDiskDomainArgs(cmd, filepath.SplitList(c.DiskPath))
 }
 
 func (c *Conversion) RunVirtV2v() error {
@@ -1,7 +1,9 @@
 package conversion
 
 import (
+	"archive/tar"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/kubev2v/forklift/pkg/virt-v2v/config"
@@ -9,6 +11,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"go.uber.org/mock/gomock"
+	libvirtxml "libvirt.org/go/libvirtxml"
 )
 
 func TestConversion(t *testing.T) {
@@ -130,4 +133,140 @@ var _ = Describe("Conversion", func() {
 			Expect(err).ToNot(HaveOccurred())
 		},
 	)
+	It("adds vsphere args with the selected disks",
+		func() {
+			appConfig := config.AppConfig{
+				VddkLibDir: os.TempDir(),
+				Disks:      []string{"[ds1] vm/vm.vmdk", "[ds1] vm/vm_2.vmdk"},
+			}
+			conversion.AppConfig = &appConfig
+
+			mockCommandBuilder.EXPECT().AddArg("-io", "vddk-file=[ds1] vm/vm.vmdk").Return(mockCommandBuilder)
+			mockCommandBuilder.EXPECT().AddArg("-io", "vddk-file=[ds1] vm/vm_2.vmdk").Return(mockCommandBuilder)
+
+			err := conversion.addVirtV2vVsphereDiskArgs(mockCommandBuilder)
+			Expect(err).ToNot(HaveOccurred())
+		},
+	)
+	It("requires the vddk to select the vsphere disks",
+		func() {
+			appConfig := config.AppConfig{
+				VddkLibDir: "/nonexistent",
+				Disks:      []string{"[ds1] vm/vm.vmdk"},
+			}
+			conversion.AppConfig = &appConfig
+
+			err := conversion.addVirtV2vVsphereDiskArgs(mockCommandBuilder)
+			Expect(err).To(HaveOccurred())
+		},
+	)
+	It("adds ova args",
+		func() {
+			appConfig := config.AppConfig{
+				Source:   config.OVA,
+				DiskPath: "/ova/web.ova",
+			}
+			conversion.AppConfig = &appConfig
+
+			mockCommandBuilder.EXPECT().AddArg("-i", "ova").Return(mockCommandBuilder)
+			mockCommandBuilder.EXPECT().AddPositional("/ova/web.ova").Return(mockCommandBuilder)
+
+			err := conversion.addVirtV2vOVAArgs(mockCommandBuilder)
+			Expect(err).ToNot(HaveOccurred())
+		},
+	)
+	It("adds ova args with the domain listing the selected disks",
+		func() {
+			appConfig := config.AppConfig{
+				Source:            config.OVA,
+				VmName:            "web",
+				DiskPath:          "/ova/web",
+				Disks:             []string{"web-disk2.vmdk"},
+				LibvirtDomainFile: "/tmp/input.xml",
+			}
+			conversion.AppConfig = &appConfig
+
+			mockFileSystem.EXPECT().WriteFile("/tmp/input.xml", gomock.Any(), os.FileMode(0644)).Return(nil)
+			mockCommandBuilder.EXPECT().AddArg("-i", "libvirtxml").Return(mockCommandBuilder)
+			mockCommandBuilder.EXPECT().AddArg("--root", "first").Return(mockCommandBuilder)
+			mockCommandBuilder.EXPECT().AddPositional("/tmp/input.xml").Return(mockCommandBuilder)
+
+			err := conversion.addVirtV2vOVAArgs(mockCommandBuilder)
+			Expect(err).ToNot(HaveOccurred())
+		},
+	)
+	It("extracts the selected disks from the ova archive",
+		func() {
+			dir := GinkgoT().TempDir()
+			archive := filepath.Join(dir, "web.ova")
+			f, err := os.Create(archive)
+			Expect(err).ToNot(HaveOccurred())
+			w := tar.NewWriter(f)
+			for _, name := range []string{"web.ovf", "web-disk1.vmdk", "web-disk2.vmdk"} {
+				Expect(w.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(name))})).To(Succeed())
+				_, err = w.Write([]byte(name))
+				Expect(err).ToNot(HaveOccurred())
+			}
+			Expect(w.Close()).To(Succeed())
+			Expect(f.Close()).To(Succeed())
+			conversion.AppConfig = &config.AppConfig{
+				DiskPath: archive,
+				Disks:    []string{"web-disk2.vmdk"},
+				Workdir:  dir,
+			}
+
+			paths, err := conversion.ovaDiskPaths()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(paths).To(Equal([]string{filepath.Join(dir, "input-web-disk2.vmdk")}))
+			content, err := os.ReadFile(paths[0])
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("web-disk2.vmdk"))
+
+			conversion.Disks = []string{"missing.vmdk"}
+			_, err = conversion.ovaDiskPaths()
+			Expect(err).To(HaveOccurred())
+		},
+	)
+	It("adds hyperv args with the domain listing the disks",
+		func() {
+			appConfig := config.AppConfig{
+				Source:            config.HYPERV,
+				VmName:            "web",
+				DiskPath:          "/ova/web/Virtual Hard Disks/web.vhdx:/ova/web/Virtual Hard Disks/data.vhdx",
+				LibvirtDomainFile: "/tmp/input.xml",
+			}
+			conversion.AppConfig = &appConfig
+
+			mockFileSystem.EXPECT().WriteFile("/tmp/input.xml", gomock.Any(), os.FileMode(0644)).Return(nil)
+			mockCommandBuilder.EXPECT().AddArg("-i", "libvirtxml").Return(mockCommandBuilder)
+			mockCommandBuilder.EXPECT().AddArg("--root", "first").Return(mockCommandBuilder)
+			mockCommandBuilder.EXPECT().AddPositional("/tmp/input.xml").Return(mockCommandBuilder)
+
+			err := conversion.addVirtV2vHyperVArgs(mockCommandBuilder)
+			Expect(err).ToNot(HaveOccurred())
+		},
+	)
+	It("builds the domain listing the disks",
+		func() {
+			conversion.AppConfig = &config.AppConfig{VmName: "web"}
+
+			domainXML, err := conversion.diskDomainXML([]string{
+				"/ova/web/Virtual Hard Disks/web.vhdx",
+				"/ova/web/Virtual Hard Disks/data.vhd",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			domain := &libvirtxml.Domain{}
+			Expect(domain.Unmarshal(domainXML)).To(Succeed())
+			Expect(domain.Name).To(Equal("web"))
+			Expect(domain.Devices.Disks).To(HaveLen(2))
+			Expect(domain.Devices.Disks[0].Source.File.File).To(Equal("/ova/web/Virtual Hard Disks/web.vhdx"))
+			Expect(domain.Devices.Disks[0].Driver.Type).To(Equal("vhdx"))
+			Expect(domain.Devices.Disks[0].Target.Dev).To(Equal("sda"))
+			Expect(domain.Devices.Disks[1].Driver.Type).To(Equal("vpc"))
+			Expect(domain.Devices.Disks[1].Target.Dev).To(Equal("sdb"))
+
+			_, err = conversion.diskDomainXML(nil)
+			Expect(err).To(HaveOccurred())
+		},
+	)
 })
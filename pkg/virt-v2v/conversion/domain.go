@@ -0,0 +1,103 @@
+package conversion
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/kubev2v/forklift/pkg/virt-v2v/utils"
+	libvirtxml "libvirt.org/go/libvirtxml"
+)
+
+// Disk image formats by the file extension.
+var diskFormats = map[string]string{
+	".vhdx":  "vhdx",
+	".vhd":   "vpc",
+	".vmdk":  "vmdk",
+	".qcow2": "qcow2",
+}
+
+// Build the libvirt domain describing the disk images.
+// The virt-v2v `-i disk` takes a single disk image, so the VMs with
+// several disks are converted using `-i libvirtxml` with a domain
+// listing the disks in order. Only the disks are described, the VM
+// is built by the controller from the inventory and the firmware is
+// detected by virt-v2v.
+func (c *Conversion) diskDomainXML(paths []string) (string, error) {
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no disks were supplied")
+	}
+	domain := &libvirtxml.Domain{
+		Type: "kvm",
+		Name: c.VmName,
+		Memory: &libvirtxml.DomainMemory{
+			Value: 1,
+			Unit:  "GiB",
+		},
+		VCPU: &libvirtxml.DomainVCPU{
+			Value: 1,
+		},
+		OS: &libvirtxml.DomainOS{
+			Type: &libvirtxml.DomainOSType{
+				Type: "hvm",
+			},
+		},
+		Devices: &libvirtxml.DomainDeviceList{},
+	}
+	for i, path := range paths {
+		disk := &Disk{}
+		domain.Devices.Disks = append(
+			domain.Devices.Disks,
+			libvirtxml.DomainDisk{
+				Device: "disk",
+				Driver: &libvirtxml.DomainDiskDriver{
+					Name: "qemu",
+					Type: diskFormat(path),
+				},
+				Source: &libvirtxml.DomainDiskSource{
+					File: &libvirtxml.DomainDiskSourceFile{
+						File: path,
+					},
+				},
+				Target: &libvirtxml.DomainDiskTarget{
+					Dev: "sd" + disk.genName(i+1),
+					Bus: "scsi",
+				},
+			})
+	}
+	domainXML, err := domain.Marshal()
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the domain XML: %w", err)
+	}
+
+	return domainXML, nil
+}
+
+// Write the domain describing the disk images and add it
+// as the virt-v2v input.
+func (c *Conversion) addDiskDomainArgs(cmd utils.CommandBuilder, paths []string) error {
+	domainXML, err := c.diskDomainXML(paths)
+	if err != nil {
+		return err
+	}
+	err = c.fileSystem.WriteFile(c.LibvirtDomainFile, []byte(domainXML), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write domain XML file: %w", err)
+	}
+	cmd.AddArg("-i", "libvirtxml")
+	err = c.addCommonArgs(cmd)
+	if err != nil {
+		return err
+	}
+	cmd.AddPositional(c.LibvirtDomainFile)
+	return nil
+}
+
+// Format of the disk image.
+// Raw unless known by the file extension.
+func diskFormat(path string) string {
+	if format, found := diskFormats[strings.ToLower(filepath.Ext(path))]; found {
+		return format
+	}
+	return "raw"
+}
@@ -0,0 +1,90 @@
+package conversion
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Paths of the selected OVA disks.
+// The disks of an extracted OVA are read from the directory of
+// the OVF. The disks of an OVA archive are extracted to the work
+// directory; the other disks are not read.
+func (c *Conversion) ovaDiskPaths() (paths []string, err error) {
+	if !strings.HasSuffix(c.DiskPath, ".ova") {
+		for _, name := range c.Disks {
+			paths = append(paths, filepath.Join(c.DiskPath, name))
+		}
+		return
+	}
+	extracted, err := c.extractOvaDisks()
+	if err != nil {
+		return
+	}
+	for _, name := range c.Disks {
+		p, found := extracted[name]
+		if !found {
+			err = fmt.Errorf("disk %s not found in %s", name, c.DiskPath)
+			return
+		}
+		paths = append(paths, p)
+	}
+	return
+}
+
+// Extract the selected disks from the OVA archive.
+// Returns the extracted paths by disk (file) name.
+func (c *Conversion) extractOvaDisks() (extracted map[string]string, err error) {
+	selected := map[string]bool{}
+	for _, name := range c.Disks {
+		selected[name] = true
+	}
+	archive, err := os.Open(c.DiskPath)
+	if err != nil {
+		return
+	}
+	defer archive.Close()
+	extracted = map[string]string{}
+	reader := tar.NewReader(archive)
+	for {
+		header, nErr := reader.Next()
+		if nErr != nil {
+			if !errors.Is(nErr, io.EOF) {
+				err = nErr
+			}
+			return
+		}
+		name := path.Clean(header.Name)
+		if header.Typeflag != tar.TypeReg || !selected[name] {
+			continue
+		}
+		p := filepath.Join(c.Workdir, "input-"+path.Base(name))
+		err = extractFile(reader, p)
+		if err != nil {
+			return
+		}
+		fmt.Printf("Extracted disk %s to %s\n", name, p)
+		extracted[name] = p
+	}
+}
+
+// Write the archive entry to the file.
+func extractFile(reader io.Reader, p string) (err error) {
+	f, err := os.Create(p)
+	if err != nil {
+		return
+	}
+	defer func() {
+		cErr := f.Close()
+		if err == nil {
+			err = cErr
+		}
+	}()
+	_, err = io.Copy(f, reader)
+	return
+}
@@ -0,0 +1,7 @@
+package io.konveyor.forklift.hyperv
+
+import rego.v1
+
+debug if {
+	trace(sprintf("** debug ** vm name: %v", [input.name]))
+}
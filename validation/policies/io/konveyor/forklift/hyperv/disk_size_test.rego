@@ -0,0 +1,25 @@
+package io.konveyor.forklift.hyperv
+
+import rego.v1
+
+test_invalid_capacity_zero if {
+	test_input := {"disks": [{
+		"filePath": "/ova/vm/Virtual Hard Disks/disk1.vhdx",
+		"capacity": 0,
+		"format": "vhdx",
+	}]}
+
+	results := concerns with input as test_input
+	count(results) == 1
+}
+
+test_valid_capacity if {
+	test_input := {"disks": [{
+		"filePath": "/ova/vm/Virtual Hard Disks/disk1.vhdx",
+		"capacity": 17179869184,
+		"format": "vhdx",
+	}]}
+
+	results := concerns with input as test_input
+	count(results) == 0
+}
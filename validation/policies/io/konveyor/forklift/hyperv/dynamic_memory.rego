@@ -0,0 +1,19 @@
+package io.konveyor.forklift.hyperv
+
+import rego.v1
+
+default has_dynamic_memory := false
+
+has_dynamic_memory if {
+	input.dynamicMemory == true
+}
+
+concerns contains flag if {
+	has_dynamic_memory
+	flag := {
+		"id": "hyperv.memory.dynamic.enabled",
+		"category": "Warning",
+		"label": "Dynamic memory detected",
+		"assessment": "Dynamic memory is not currently supported by Migration Toolkit for Virtualization. The VM will be migrated with its startup memory.",
+	}
+}
@@ -0,0 +1,15 @@
+package io.konveyor.forklift.hyperv
+
+import rego.v1
+
+test_without_dynamic_memory if {
+	mock_vm := {"name": "test", "dynamicMemory": false}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_dynamic_memory if {
+	mock_vm := {"name": "test", "dynamicMemory": true}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}
@@ -0,0 +1,19 @@
+package io.konveyor.forklift.hyperv
+
+import rego.v1
+
+default has_secure_boot := false
+
+has_secure_boot if {
+	input.secureBoot == true
+}
+
+concerns contains flag if {
+	has_secure_boot
+	flag := {
+		"id": "hyperv.secure_boot.enabled",
+		"category": "Information",
+		"label": "UEFI Secure Boot enabled",
+		"assessment": "The VM will be migrated with Secure Boot enabled. Guests that rely on the Hyper-V specific Secure Boot template may not boot until Secure Boot is disabled.",
+	}
+}
@@ -0,0 +1,15 @@
+package io.konveyor.forklift.hyperv
+
+import rego.v1
+
+test_without_secure_boot if {
+	mock_vm := {"name": "test", "secureBoot": false}
+	results := concerns with input as mock_vm
+	count(results) == 0
+}
+
+test_with_secure_boot if {
+	mock_vm := {"name": "test", "secureBoot": true}
+	results := concerns with input as mock_vm
+	count(results) == 1
+}